@@ -18,9 +18,6 @@ type Config struct {
 	// Base de datos
 	DatabasePath string
 
-	// Bitcoin API
-	BitcoinAPIURL string
-
 	// Monitoreo - Intervalo único para todo (precio, porcentaje, backend y frontend)
 	CheckInterval time.Duration
 
@@ -58,6 +55,53 @@ type Config struct {
 	BinanceAPISecret      string
 	BinanceBaseURL        string   // Base URL for Binance API
 	BinanceDefaultSymbols []string // Default symbols to track
+	BinanceWeightLimit    int      // Request weight budget per minute
+
+	// Reintentos y circuit breaker de Binance
+	BinanceMaxRetries       int           // Retries for failed GET requests (0 disables)
+	BinanceRetryBaseDelay   time.Duration // Wait before the first retry
+	BinanceRetryMaxDelay    time.Duration // Upper bound for a retry wait
+	BinanceBreakerThreshold int           // Consecutive failures before the breaker opens
+	BinanceBreakerCooldown  time.Duration // Time the breaker stays open before a trial request
+
+	// Sincronización de hora con el servidor de Binance (endpoints firmados)
+	BinanceRecvWindow       time.Duration // Validity window of signed requests (max 60s)
+	BinanceTimeSyncInterval time.Duration // How often the server time offset is measured
+	TickerCacheTTL          time.Duration // How long bulk 24hr tickers are reused to value balances
+	ExchangeInfoRefresh     time.Duration // How often the symbol list (exchangeInfo) is refreshed
+
+	// Conversión de monedas (FX)
+	DisplayCurrency string             // Default currency balances are shown in (USD, COP, EUR...)
+	FXRatesTTL      time.Duration      // How long an exchange rate is reused
+	FXStaticRates   map[string]float64 // Fallback rates, units per USD, for currencies Binance doesn't list
+
+	// Trading
+	TradingDryRun       bool          // Only validate orders with /api/v3/order/test, never place them
+	TradeImportInterval time.Duration // How often account trades are imported for PnL (0 disables)
+
+	// Binance WebSocket stream
+	BinanceStreamEnabled bool   // Stream prices instead of polling (REST polling remains as fallback)
+	BinanceStreamURL     string // Base URL for Binance market data streams
+
+	// User data stream de Binance (balance y órdenes en vivo)
+	UserDataStreamEnabled  bool   // Keep the account balance up to date from the user data stream
+	OrderFillNotifications bool   // Notify order fills received from the user data stream
+	OrderFillEmail         string // Recipient of order fill emails (Telegram uses TelegramChatID)
+
+	// Velas OHLCV (stream de klines de Binance)
+	KlineStreamEnabled   bool          // Record closed candles from the Binance kline stream
+	KlineInterval        string        // Candle interval recorded from the stream (1m, 5m, 1h...)
+	CandleBackfillWindow time.Duration // How far back missing candles are fetched at startup (0 disables)
+
+	// Proveedores de precio (en orden de failover)
+	PriceProviders  []string // Provider names tried in order: binance, coinbase, kraken
+	CoinbaseBaseURL string   // Base URL for the Coinbase Exchange API
+	KrakenBaseURL   string   // Base URL for the Kraken API
+
+	// Agregación de precios (mediana de varios proveedores)
+	PriceAggregationEnabled bool    // Use the median of all providers instead of failover
+	PriceOutlierBandPercent float64 // Max deviation from the median, in percent, for a quote to count
+	PriceQuorum             int     // Min number of agreeing quotes required to emit a price
 }
 
 func Load() (*Config, error) {
@@ -71,6 +115,21 @@ func Load() (*Config, error) {
 
 	checkInterval, _ := time.ParseDuration(getEnv("CHECK_INTERVAL", "30s"))
 	smtpPort, _ := strconv.Atoi(getEnv("SMTP_PORT", "587"))
+	outlierBand, _ := strconv.ParseFloat(getEnv("PRICE_OUTLIER_BAND_PERCENT", "1.0"), 64)
+	priceQuorum, _ := strconv.Atoi(getEnv("PRICE_QUORUM", "2"))
+	weightLimit, _ := strconv.Atoi(getEnv("BINANCE_WEIGHT_LIMIT", "6000"))
+	maxRetries, _ := strconv.Atoi(getEnv("BINANCE_MAX_RETRIES", "2"))
+	retryBaseDelay, _ := time.ParseDuration(getEnv("BINANCE_RETRY_BASE_DELAY", "200ms"))
+	retryMaxDelay, _ := time.ParseDuration(getEnv("BINANCE_RETRY_MAX_DELAY", "2s"))
+	breakerThreshold, _ := strconv.Atoi(getEnv("BINANCE_BREAKER_THRESHOLD", "5"))
+	breakerCooldown, _ := time.ParseDuration(getEnv("BINANCE_BREAKER_COOLDOWN", "30s"))
+	recvWindow, _ := time.ParseDuration(getEnv("BINANCE_RECV_WINDOW", "5s"))
+	timeSyncInterval, _ := time.ParseDuration(getEnv("BINANCE_TIME_SYNC_INTERVAL", "10m"))
+	tradeImportInterval, _ := time.ParseDuration(getEnv("TRADE_IMPORT_INTERVAL", "1h"))
+	tickerCacheTTL, _ := time.ParseDuration(getEnv("TICKER_CACHE_TTL", "10s"))
+	exchangeInfoRefresh, _ := time.ParseDuration(getEnv("EXCHANGE_INFO_REFRESH", "1h"))
+	fxRatesTTL, _ := time.ParseDuration(getEnv("FX_RATES_TTL", "5m"))
+	candleBackfillWindow, _ := time.ParseDuration(getEnv("CANDLE_BACKFILL_WINDOW", "24h"))
 
 	// Load Binance API credentials
 	binanceKey := getEnv("BINANCE_API_KEY", "")
@@ -87,7 +146,6 @@ func Load() (*Config, error) {
 		Port:          getEnv("PORT", "8080"),
 		Environment:   getEnv("ENVIRONMENT", "development"),
 		DatabasePath:  getEnv("DATABASE_PATH", "btc_market_data.db"),
-		BitcoinAPIURL: getEnv("BITCOIN_API_URL", "https://api.coindesk.com/v1/bpi/currentprice.json"),
 		CheckInterval: checkInterval,
 
 		// Email configuration
@@ -124,6 +182,53 @@ func Load() (*Config, error) {
 		BinanceAPISecret:      binanceSecret,
 		BinanceBaseURL:        getEnv("BINANCE_BASE_URL", ""), // Empty string will use default in client
 		BinanceDefaultSymbols: strings.Split(getEnv("BINANCE_DEFAULT_SYMBOLS", "BTC,USDT"), ","),
+		BinanceWeightLimit:    weightLimit,
+
+		// Binance retry and circuit breaker configuration
+		BinanceMaxRetries:       maxRetries,
+		BinanceRetryBaseDelay:   retryBaseDelay,
+		BinanceRetryMaxDelay:    retryMaxDelay,
+		BinanceBreakerThreshold: breakerThreshold,
+		BinanceBreakerCooldown:  breakerCooldown,
+
+		// Binance server time synchronisation
+		BinanceRecvWindow:       recvWindow,
+		BinanceTimeSyncInterval: timeSyncInterval,
+		TickerCacheTTL:          tickerCacheTTL,
+		ExchangeInfoRefresh:     exchangeInfoRefresh,
+
+		// FX conversion configuration
+		DisplayCurrency: strings.ToUpper(getEnv("DISPLAY_CURRENCY", "USD")),
+		FXRatesTTL:      fxRatesTTL,
+		FXStaticRates:   getEnvRates("FX_STATIC_RATES"),
+
+		// Trading configuration (dry-run unless explicitly disabled)
+		TradingDryRun:       getEnvBool("TRADING_DRY_RUN", true),
+		TradeImportInterval: tradeImportInterval,
+
+		// Binance WebSocket stream configuration
+		BinanceStreamEnabled: getEnvBool("BINANCE_STREAM_ENABLED", true),
+		BinanceStreamURL:     getEnv("BINANCE_STREAM_URL", ""), // Empty string will use default in stream
+
+		// Binance user data stream configuration
+		UserDataStreamEnabled:  getEnvBool("USER_DATA_STREAM_ENABLED", true),
+		OrderFillNotifications: getEnvBool("ORDER_FILL_NOTIFICATIONS", false),
+		OrderFillEmail:         getEnv("ORDER_FILL_EMAIL", ""),
+
+		// Candle recording configuration
+		KlineStreamEnabled:   getEnvBool("KLINE_STREAM_ENABLED", true),
+		KlineInterval:        getEnv("KLINE_INTERVAL", "1m"),
+		CandleBackfillWindow: candleBackfillWindow,
+
+		// Price provider failover configuration
+		PriceProviders:  strings.Split(getEnv("PRICE_PROVIDERS", "binance,coinbase,kraken"), ","),
+		CoinbaseBaseURL: getEnv("COINBASE_BASE_URL", ""), // Empty string will use default in client
+		KrakenBaseURL:   getEnv("KRAKEN_BASE_URL", ""),   // Empty string will use default in client
+
+		// Price aggregation configuration
+		PriceAggregationEnabled: getEnvBool("PRICE_AGGREGATION_ENABLED", false),
+		PriceOutlierBandPercent: outlierBand,
+		PriceQuorum:             priceQuorum,
 	}, nil
 }
 
@@ -155,3 +260,21 @@ func getEnvBool(key string, defaultValue bool) bool {
 	}
 	return defaultValue
 }
+
+// getEnvRates parses a list of CURRENCY=rate pairs, e.g. "COP=4000,ARS=1000".
+// Malformed entries are skipped.
+func getEnvRates(key string) map[string]float64 {
+	rates := make(map[string]float64)
+	for _, entry := range strings.Split(os.Getenv(key), ",") {
+		currency, value, ok := strings.Cut(entry, "=")
+		if !ok {
+			continue
+		}
+		rate, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil || rate <= 0 {
+			continue
+		}
+		rates[strings.ToUpper(strings.TrimSpace(currency))] = rate
+	}
+	return rates
+}
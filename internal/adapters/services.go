@@ -2,11 +2,14 @@ package adapters
 
 import (
 	"fmt"
+	"log"
+	"strings"
 	"time"
 
 	"github.com/cgallonv/btc-alerta-de-precio/config"
 	"github.com/cgallonv/btc-alerta-de-precio/internal/bitcoin"
 	"github.com/cgallonv/btc-alerta-de-precio/internal/errors"
+	"github.com/cgallonv/btc-alerta-de-precio/internal/expression"
 	"github.com/cgallonv/btc-alerta-de-precio/internal/interfaces"
 	"github.com/cgallonv/btc-alerta-de-precio/internal/notifications"
 	"github.com/cgallonv/btc-alerta-de-precio/internal/storage"
@@ -19,11 +22,7 @@ type PriceClientAdapter struct {
 
 // NewPriceClientAdapter creates a new price client adapter
 func NewPriceClientAdapter(configProvider interfaces.ConfigProvider, tickerStorage *bitcoin.TickerStorage) *PriceClientAdapter {
-	apiKey := configProvider.GetString("binance.api_key")
-	apiSecret := configProvider.GetString("binance.api_secret")
-	baseURL := configProvider.GetString("binance.base_url")
-	client := bitcoin.NewBinanceClient(apiKey, apiSecret, baseURL, tickerStorage)
-	return &PriceClientAdapter{client: client}
+	return &PriceClientAdapter{client: newBinanceProvider(configProvider, tickerStorage)}
 }
 
 // GetCurrentPrice implements interfaces.PriceClient
@@ -37,6 +36,51 @@ func (a *PriceClientAdapter) GetPriceHistory(days int) ([]bitcoin.PriceData, err
 	return nil, fmt.Errorf("price history not implemented")
 }
 
+// NewPriceProviders builds the price providers listed in the configuration, in failover order.
+// Unknown names are logged and skipped; Binance is used when none are configured.
+//
+// Example usage:
+//
+//	providers := NewPriceProviders(configProvider, tickerStorage)
+//	chain := alerts.NewPriceProviderChain(providers...)
+func NewPriceProviders(configProvider interfaces.ConfigProvider, tickerStorage *bitcoin.TickerStorage) []interfaces.PriceProvider {
+	var providers []interfaces.PriceProvider
+	seen := make(map[string]bool)
+
+	for _, name := range configProvider.GetPriceProviders() {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+
+		switch name {
+		case "binance":
+			providers = append(providers, newBinanceProvider(configProvider, tickerStorage))
+		case "coinbase":
+			providers = append(providers, bitcoin.NewCoinbaseClient(configProvider.GetString("coinbase.base_url")))
+		case "kraken":
+			providers = append(providers, bitcoin.NewKrakenClient(configProvider.GetString("kraken.base_url")))
+		default:
+			log.Printf("⚠️ Unknown price provider %q, ignoring", name)
+		}
+	}
+
+	if len(providers) == 0 {
+		providers = append(providers, newBinanceProvider(configProvider, tickerStorage))
+	}
+
+	return providers
+}
+
+// newBinanceProvider creates a Binance client from the configured credentials.
+func newBinanceProvider(configProvider interfaces.ConfigProvider, tickerStorage *bitcoin.TickerStorage) *bitcoin.BinanceClient {
+	apiKey := configProvider.GetString("binance.api_key")
+	apiSecret := configProvider.GetString("binance.api_secret")
+	baseURL := configProvider.GetString("binance.base_url")
+	return bitcoin.NewBinanceClient(apiKey, apiSecret, baseURL, tickerStorage)
+}
+
 // NotificationServiceAdapter adapts notifications.Service to implement NotificationSender interface.
 //
 // Example usage:
@@ -75,7 +119,7 @@ func (a *NotificationServiceAdapter) TestTelegramNotification() error {
 // Example usage:
 //
 //	evaluator := NewAlertEvaluator()
-//	shouldTrigger := evaluator.ShouldTrigger(alert, priceData)
+//	shouldTrigger := evaluator.ShouldTrigger(alert, priceData, &interfaces.EvaluationContext{Previous: previousTick})
 type AlertEvaluatorImpl struct{}
 
 // NewAlertEvaluator creates a new AlertEvaluatorImpl.
@@ -87,13 +131,18 @@ func NewAlertEvaluator() interfaces.AlertEvaluator {
 	return &AlertEvaluatorImpl{}
 }
 
-func (e *AlertEvaluatorImpl) ShouldTrigger(alert *storage.Alert, priceData *bitcoin.PriceData) bool {
+func (e *AlertEvaluatorImpl) ShouldTrigger(alert *storage.Alert, priceData *bitcoin.PriceData, evalCtx *interfaces.EvaluationContext) bool {
 	if !alert.IsActive {
 		return false
 	}
 
-	// One-Shot: Only trigger if never activated before
-	if alert.LastTriggered != nil {
+	// Ticks only apply to alerts on the same trading pair
+	if !alert.MatchesSymbol(priceData.Symbol) {
+		return false
+	}
+
+	// Once, recurring after the cooldown, or up to MaxTriggers times
+	if !alert.CanTrigger(time.Now()) {
 		return false
 	}
 
@@ -102,12 +151,19 @@ func (e *AlertEvaluatorImpl) ShouldTrigger(alert *storage.Alert, priceData *bitc
 		return priceData.Price >= alert.TargetPrice
 	case "below":
 		return priceData.Price <= alert.TargetPrice
+	case "cross_up", "cross_down":
+		// Only an actual crossing between two ticks counts, not being past the level
+		previous := evalCtx.PreviousTick()
+		if previous == nil {
+			return false
+		}
+		return alert.IsCrossing(previous.Price, priceData.Price)
 	case "change":
-		// Use Binance API percentage directly (24h change)
-		// Only available when source is Binance, fallback for other sources
-		if priceData.Source != "Binance" {
-			// For non-Binance sources, we don't have percentage data
-			// This maintains compatibility when Binance is unavailable
+		// Use Binance API percentage directly (rolling 24h change).
+		// Fallback providers measure change over different windows (Kraken
+		// uses the daily open), so their ticks never trigger change alerts.
+		// Aggregated ticks qualify when an accepted Binance quote supplied the percentage.
+		if !priceData.UsesBinanceChange() {
 			return false
 		}
 
@@ -124,11 +180,203 @@ func (e *AlertEvaluatorImpl) ShouldTrigger(alert *storage.Alert, priceData *bitc
 			// Zero percentage: invalid, never trigger
 			return false
 		}
+	case "change_window":
+		// Compared against the window's low (rises) or high (drops), set by the alert manager
+		changePercent, ok := evalCtx.ChangePercent(priceData.Price)
+		if !ok {
+			return false
+		}
+		if alert.Percentage > 0 {
+			return changePercent >= alert.Percentage
+		}
+		return alert.Percentage < 0 && changePercent <= alert.Percentage
+	case "indicator":
+		if evalCtx == nil {
+			return false
+		}
+		return e.indicatorTriggers(alert, evalCtx.Indicator)
+	case "expression":
+		return e.expressionTriggers(alert, priceData, evalCtx)
+	case "imbalance", "spread", "liquidity":
+		if evalCtx == nil {
+			return false
+		}
+		return e.orderBookTriggers(alert, evalCtx.OrderBook)
+	default:
+		return false
+	}
+}
+
+// indicatorTriggers evaluates an indicator alert on the reading computed by the alert
+// manager. Crossings compare against the previous reading of the same alert.
+func (e *AlertEvaluatorImpl) indicatorTriggers(alert *storage.Alert, reading *bitcoin.IndicatorReading) bool {
+	if reading == nil {
+		return false
+	}
+
+	previous := reading.Previous
+	switch alert.IndicatorCondition {
+	case "above":
+		return reading.Value > reading.Level
+	case "below":
+		return reading.Value < reading.Level
+	case "cross_above":
+		return previous != nil && previous.Value <= previous.Level && reading.Value > reading.Level
+	case "cross_below":
+		return previous != nil && previous.Value >= previous.Level && reading.Value < reading.Level
+	default:
+		return false
+	}
+}
+
+// expressionTriggers evaluates an expression alert on the tick, the previous tick and
+// the compiled expression and indicator values in the evaluation context; without a
+// program in the context the expression is compiled here. Expressions using a value the
+// tick doesn't have, e.g. the 24h ticker of a Kraken tick, don't trigger.
+func (e *AlertEvaluatorImpl) expressionTriggers(alert *storage.Alert, priceData *bitcoin.PriceData, evalCtx *interfaces.EvaluationContext) bool {
+	var program *expression.Program
+	if evalCtx != nil {
+		program = evalCtx.Program
+	}
+	if program == nil {
+		var err error
+		if program, err = expression.Compile(alert.Expression); err != nil {
+			return false
+		}
+	}
+	triggered, err := program.Eval(expressionEnv(priceData, evalCtx))
+	return err == nil && triggered
+}
+
+// expressionEnv returns the variables of expression.Variables available on a tick.
+func expressionEnv(priceData *bitcoin.PriceData, evalCtx *interfaces.EvaluationContext) expression.Env {
+	at := priceData.Timestamp
+	if at.IsZero() {
+		at = time.Now()
+	}
+	at = at.UTC()
+
+	variables := map[string]float64{
+		"price":       priceData.Price,
+		"hour_utc":    float64(at.Hour()),
+		"minute_utc":  float64(at.Minute()),
+		"weekday_utc": float64(at.Weekday()),
+	}
+	if previous := evalCtx.PreviousTick(); previous != nil {
+		variables["prev_price"] = previous.Price
+	}
+	// Same rule as change alerts: only Binance's rolling 24h change is comparable
+	if priceData.UsesBinanceChange() {
+		variables["change_24h"] = priceData.PriceChangePercent
+	}
+	if ticker := priceData.Ticker; ticker != nil {
+		variables["price_change_24h"] = ticker.PriceChange
+		variables["open_24h"] = ticker.OpenPrice
+		variables["high_24h"] = ticker.HighPrice
+		variables["low_24h"] = ticker.LowPrice
+		variables["prev_close_24h"] = ticker.PrevClosePrice
+		variables["avg_price_24h"] = ticker.WeightedAvgPrice
+		variables["volume_24h"] = ticker.Volume
+		variables["quote_volume_24h"] = ticker.QuoteVolume
+		variables["trades_24h"] = float64(ticker.TotalTrades)
+	}
+
+	env := expression.Env{Variables: variables}
+	if evalCtx != nil {
+		env.Indicators = evalCtx.Values
+	}
+	return env
+}
+
+// orderBookTriggers evaluates an order book alert. Ticks of symbols whose book
+// isn't tracked or in sync carry no book and never trigger them.
+func (e *AlertEvaluatorImpl) orderBookTriggers(alert *storage.Alert, book *bitcoin.OrderBookSnapshot) bool {
+	if book == nil || book.MidPrice() == 0 {
+		return false
+	}
+
+	switch alert.Type {
+	case "imbalance":
+		// Positive thresholds watch for bid-heavy books, negative ones for ask-heavy books
+		imbalance := book.Imbalance(alert.GetDepthPercent())
+		if alert.Percentage > 0 {
+			return imbalance >= alert.Percentage
+		}
+		return alert.Percentage < 0 && imbalance <= alert.Percentage
+	case "spread":
+		return book.SpreadBps() >= alert.Threshold
+	case "liquidity":
+		return book.BidLiquidity(alert.GetDepthPercent()) <= alert.Threshold
 	default:
 		return false
 	}
 }
 
+// CompositeAlertEvaluator implements the AlertEvaluator interface for "composite"
+// alerts: it walks their AND/OR condition tree and evaluates each condition as an
+// alert of its type with the wrapped evaluator. Other alerts go to the wrapped
+// evaluator unchanged.
+//
+// Example usage:
+//
+//	evaluator := NewCompositeAlertEvaluator(NewAlertEvaluator())
+//	shouldTrigger := evaluator.ShouldTrigger(alert, priceData, evalCtx)
+type CompositeAlertEvaluator struct {
+	evaluator interfaces.AlertEvaluator
+}
+
+// NewCompositeAlertEvaluator creates a CompositeAlertEvaluator that evaluates simple
+// conditions and non-composite alerts with evaluator.
+//
+// Example usage:
+//
+//	evaluator := NewCompositeAlertEvaluator(NewAlertEvaluator())
+func NewCompositeAlertEvaluator(evaluator interfaces.AlertEvaluator) interfaces.AlertEvaluator {
+	return &CompositeAlertEvaluator{evaluator: evaluator}
+}
+
+func (e *CompositeAlertEvaluator) ShouldTrigger(alert *storage.Alert, priceData *bitcoin.PriceData, evalCtx *interfaces.EvaluationContext) bool {
+	if alert.Type != "composite" {
+		return e.evaluator.ShouldTrigger(alert, priceData, evalCtx)
+	}
+
+	if !alert.IsActive || alert.Conditions == nil || !alert.MatchesSymbol(priceData.Symbol) {
+		return false
+	}
+	if !alert.CanTrigger(time.Now()) {
+		return false
+	}
+	if evalCtx == nil {
+		evalCtx = &interfaces.EvaluationContext{}
+	}
+	return e.holds(alert, alert.Conditions, "", priceData, evalCtx)
+}
+
+// holds evaluates the condition at path of a composite alert's tree.
+func (e *CompositeAlertEvaluator) holds(alert *storage.Alert, condition *storage.Condition, path string, priceData *bitcoin.PriceData, evalCtx *interfaces.EvaluationContext) bool {
+	if !condition.IsGroup() {
+		conditionCtx := evalCtx
+		if condition.Type == "indicator" {
+			// Each indicator condition has its own reading, by condition path
+			withReading := *evalCtx
+			withReading.Indicator = evalCtx.Readings[path]
+			conditionCtx = &withReading
+		}
+		return e.evaluator.ShouldTrigger(condition.Alert(alert), priceData, conditionCtx)
+	}
+
+	for i := range condition.Conditions {
+		held := e.holds(alert, &condition.Conditions[i], storage.ConditionPath(path, i), priceData, evalCtx)
+		if condition.Op == "or" && held {
+			return true
+		}
+		if condition.Op == "and" && !held {
+			return false
+		}
+	}
+	return condition.Op == "and"
+}
+
 // ConfigAdapter adapts config.Config to implement ConfigProvider interface.
 //
 // Example usage:
@@ -160,6 +408,22 @@ func (a *ConfigAdapter) IsTelegramNotificationsEnabled() bool {
 	return a.config.EnableTelegramNotifications
 }
 
+func (a *ConfigAdapter) IsPriceStreamEnabled() bool {
+	return a.config.BinanceStreamEnabled
+}
+
+func (a *ConfigAdapter) IsPriceAggregationEnabled() bool {
+	return a.config.PriceAggregationEnabled
+}
+
+func (a *ConfigAdapter) GetPriceOutlierBand() float64 {
+	return a.config.PriceOutlierBandPercent
+}
+
+func (a *ConfigAdapter) GetPriceQuorum() int {
+	return a.config.PriceQuorum
+}
+
 func (a *ConfigAdapter) GetVAPIDPublicKey() string {
 	return a.config.VAPIDPublicKey
 }
@@ -172,6 +436,14 @@ func (a *ConfigAdapter) GetString(key string) string {
 		return a.config.BinanceAPISecret
 	case "binance.base_url":
 		return a.config.BinanceBaseURL
+	case "binance.stream_url":
+		return a.config.BinanceStreamURL
+	case "coinbase.base_url":
+		return a.config.CoinbaseBaseURL
+	case "kraken.base_url":
+		return a.config.KrakenBaseURL
+	case "display_currency":
+		return a.config.DisplayCurrency
 	default:
 		return ""
 	}
@@ -181,3 +453,8 @@ func (a *ConfigAdapter) GetString(key string) string {
 func (a *ConfigAdapter) GetDefaultSymbols() []string {
 	return a.config.BinanceDefaultSymbols
 }
+
+// GetPriceProviders returns the configured price provider names in failover order
+func (a *ConfigAdapter) GetPriceProviders() []string {
+	return a.config.PriceProviders
+}
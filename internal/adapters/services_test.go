@@ -7,7 +7,9 @@ import (
 
 	"github.com/cgallonv/btc-alerta-de-precio/internal/bitcoin"
 	apperrors "github.com/cgallonv/btc-alerta-de-precio/internal/errors"
+	"github.com/cgallonv/btc-alerta-de-precio/internal/interfaces"
 	"github.com/cgallonv/btc-alerta-de-precio/internal/storage"
+	"github.com/cgallonv/btc-alerta-de-precio/internal/storage/models"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -204,7 +206,7 @@ func TestAlertEvaluatorImpl_ShouldTrigger(t *testing.T) {
 			expected: false,
 		},
 		{
-			name: "change alert should not trigger for non-Binance sources",
+			name: "change alert should not trigger for fallback providers",
 			alert: &storage.Alert{
 				Type:       "change",
 				Percentage: 5.0,
@@ -212,11 +214,95 @@ func TestAlertEvaluatorImpl_ShouldTrigger(t *testing.T) {
 			},
 			priceData: &bitcoin.PriceData{
 				Price:              50000,
-				PriceChangePercent: 0.0, // CoinDesk/CoinGecko don't provide percentage
-				Source:             "CoinDesk",
+				PriceChangePercent: 6.0, // Kraken measures change from the daily open
+				Source:             bitcoin.SourceKraken,
 			},
 			expected: false,
 		},
+		{
+			name: "change alert should trigger for aggregated ticks with an accepted Binance quote",
+			alert: &storage.Alert{
+				Type:       "change",
+				Percentage: 5.0,
+				IsActive:   true,
+			},
+			priceData: &bitcoin.PriceData{
+				Price:              50000,
+				PriceChangePercent: 6.0,
+				Source:             bitcoin.SourceAggregate,
+				Quotes: []bitcoin.PriceQuote{
+					{Source: bitcoin.SourceBinance, Price: 50000, PriceChangePercent: 6.0},
+					{Source: bitcoin.SourceKraken, Price: 50010, PriceChangePercent: 4.0},
+				},
+			},
+			expected: true,
+		},
+		{
+			name: "change alert should not trigger for aggregated ticks without Binance",
+			alert: &storage.Alert{
+				Type:       "change",
+				Percentage: 5.0,
+				IsActive:   true,
+			},
+			priceData: &bitcoin.PriceData{
+				Price:              50000,
+				PriceChangePercent: 6.0,
+				Source:             bitcoin.SourceAggregate,
+				Quotes: []bitcoin.PriceQuote{
+					{Source: bitcoin.SourceBinance, Price: 40000, PriceChangePercent: -15.0, Rejected: true},
+					{Source: bitcoin.SourceCoinbase, Price: 50000, PriceChangePercent: 6.0},
+					{Source: bitcoin.SourceKraken, Price: 50010, PriceChangePercent: 4.0},
+				},
+			},
+			expected: false,
+		},
+		{
+			name: "alert should not trigger on ticks for another symbol",
+			alert: &storage.Alert{
+				Type:        "above",
+				Symbol:      "ETHUSDT",
+				TargetPrice: 3000,
+				IsActive:    true,
+			},
+			priceData: &bitcoin.PriceData{
+				Symbol:             "BTCUSDT",
+				Price:              50000,
+				PriceChangePercent: 1.0,
+				Source:             "Binance",
+			},
+			expected: false,
+		},
+		{
+			name: "alert should trigger on ticks for its own symbol",
+			alert: &storage.Alert{
+				Type:        "above",
+				Symbol:      "ethusdt",
+				TargetPrice: 3000,
+				IsActive:    true,
+			},
+			priceData: &bitcoin.PriceData{
+				Symbol:             "ETHUSDT",
+				Price:              3100,
+				PriceChangePercent: 1.0,
+				Source:             "Binance",
+			},
+			expected: true,
+		},
+		{
+			name: "alert without symbol defaults to BTCUSDT",
+			alert: &storage.Alert{
+				Type:        "above",
+				TargetPrice: 45000,
+				IsActive:    true,
+			},
+			priceData: &bitcoin.PriceData{
+				Symbol:             "BTCUSDT",
+				Price:              50000,
+				PriceChangePercent: 1.0,
+				Source:             "Binance",
+			},
+			expected: true,
+		},
 		{
 			name: "unknown alert type should not trigger",
 			alert: &storage.Alert{
@@ -234,12 +320,283 @@ func TestAlertEvaluatorImpl_ShouldTrigger(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := evaluator.ShouldTrigger(tt.alert, tt.priceData)
+			result := evaluator.ShouldTrigger(tt.alert, tt.priceData, nil)
 			assert.Equal(t, tt.expected, result)
 		})
 	}
 }
 
+func TestAlertEvaluatorImpl_OrderBookAlerts(t *testing.T) {
+	evaluator := NewAlertEvaluator()
+
+	// Mid price 100, spread 20 bps; within 1%: 5 bid vs 2 ask, a 42.9% bid imbalance
+	book := &bitcoin.OrderBookSnapshot{
+		Symbol: "BTCUSDT",
+		Bids:   []bitcoin.PriceLevel{{Price: 99.9, Quantity: 3}, {Price: 99.5, Quantity: 2}, {Price: 98, Quantity: 10}},
+		Asks:   []bitcoin.PriceLevel{{Price: 100.1, Quantity: 1}, {Price: 100.5, Quantity: 1}},
+	}
+
+	tests := []struct {
+		name     string
+		alert    storage.Alert
+		book     *bitcoin.OrderBookSnapshot
+		expected bool
+	}{
+		{"bid imbalance over threshold", storage.Alert{Type: "imbalance", Percentage: 40}, book, true},
+		{"bid imbalance under threshold", storage.Alert{Type: "imbalance", Percentage: 50}, book, false},
+		{"ask imbalance not reached", storage.Alert{Type: "imbalance", Percentage: -10}, book, false},
+		{"wider band includes deep bids", storage.Alert{Type: "imbalance", Percentage: 70, DepthPercent: 3}, book, true},
+		{"spread above threshold", storage.Alert{Type: "spread", Threshold: 15}, book, true},
+		{"spread below threshold", storage.Alert{Type: "spread", Threshold: 25}, book, false},
+		{"thin bid liquidity", storage.Alert{Type: "liquidity", Threshold: 5}, book, true},
+		{"enough bid liquidity", storage.Alert{Type: "liquidity", Threshold: 4}, book, false},
+		{"untracked book never triggers", storage.Alert{Type: "spread", Threshold: 1}, nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.alert.IsActive = true
+			priceData := &bitcoin.PriceData{Symbol: "BTCUSDT", Price: 100, Source: "Binance"}
+			evalCtx := &interfaces.EvaluationContext{OrderBook: tt.book}
+			assert.Equal(t, tt.expected, evaluator.ShouldTrigger(&tt.alert, priceData, evalCtx))
+		})
+	}
+}
+
+func TestAlertEvaluatorImpl_TriggerModes(t *testing.T) {
+	evaluator := NewAlertEvaluator()
+	recently := time.Now().Add(-10 * time.Minute)
+	longAgo := time.Now().Add(-2 * time.Hour)
+
+	tests := []struct {
+		name     string
+		alert    storage.Alert
+		expected bool
+	}{
+		{"once fires before its first trigger", storage.Alert{TriggerMode: storage.TriggerModeOnce}, true},
+		{"once doesn't fire again", storage.Alert{TriggerMode: storage.TriggerModeOnce, LastTriggered: &longAgo, TriggerCount: 1}, false},
+		{"recurring waits for the cooldown", storage.Alert{TriggerMode: storage.TriggerModeRecurring, CooldownSeconds: 3600, LastTriggered: &recently, TriggerCount: 5}, false},
+		{"recurring fires after the cooldown", storage.Alert{TriggerMode: storage.TriggerModeRecurring, CooldownSeconds: 3600, LastTriggered: &longAgo, TriggerCount: 5}, true},
+		{"max_count fires below the maximum", storage.Alert{TriggerMode: storage.TriggerModeMaxCount, MaxTriggers: 3, LastTriggered: &recently, TriggerCount: 2}, true},
+		{"max_count stops at the maximum", storage.Alert{TriggerMode: storage.TriggerModeMaxCount, MaxTriggers: 3, LastTriggered: &longAgo, TriggerCount: 3}, false},
+		{"max_count counts from the last reset", storage.Alert{TriggerMode: storage.TriggerModeMaxCount, MaxTriggers: 3, TriggerCount: 3, ResetTriggerCount: 3}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.alert.Type, tt.alert.TargetPrice, tt.alert.IsActive = "above", 45000, true
+			priceData := &bitcoin.PriceData{Symbol: "BTCUSDT", Price: 50000, Source: "Binance"}
+			assert.Equal(t, tt.expected, evaluator.ShouldTrigger(&tt.alert, priceData, nil))
+		})
+	}
+
+	// The UI is told when a cooling down alert can fire again
+	alert := storage.Alert{Type: "above", TargetPrice: 45000, IsActive: true, TriggerMode: storage.TriggerModeMaxCount, MaxTriggers: 2, CooldownSeconds: 60}
+	alert.MarkTriggered()
+	require.NotNil(t, alert.NextEligibleAt)
+	assert.WithinDuration(t, time.Now().Add(time.Minute), *alert.NextEligibleAt, time.Second)
+	alert.MarkTriggered()
+	assert.Nil(t, alert.NextEligibleAt)
+	assert.True(t, alert.Exhausted)
+}
+
+func TestAlertEvaluatorImpl_CrossAlerts(t *testing.T) {
+	evaluator := NewAlertEvaluator()
+	tick := func(price float64) *bitcoin.PriceData {
+		return &bitcoin.PriceData{Symbol: "BTCUSDT", Price: price, Source: "Binance"}
+	}
+
+	tests := []struct {
+		name      string
+		alertType string
+		previous  *bitcoin.PriceData
+		current   *bitcoin.PriceData
+		expected  bool
+	}{
+		{"cross_up fires on the crossing", "cross_up", tick(69900), tick(70100), true},
+		{"cross_up fires when reaching the level", "cross_up", tick(69900), tick(70000), true},
+		{"cross_up doesn't fire above the level", "cross_up", tick(70100), tick(70200), false},
+		{"cross_up doesn't fire on the first tick", "cross_up", nil, tick(70100), false},
+		{"cross_up ignores downward crossings", "cross_up", tick(70100), tick(69900), false},
+		{"cross_down fires on the crossing", "cross_down", tick(70100), tick(69900), true},
+		{"cross_down doesn't fire below the level", "cross_down", tick(69900), tick(69800), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			alert := &storage.Alert{Type: tt.alertType, TargetPrice: 70000, IsActive: true}
+			evalCtx := &interfaces.EvaluationContext{Previous: tt.previous}
+			assert.Equal(t, tt.expected, evaluator.ShouldTrigger(alert, tt.current, evalCtx))
+		})
+	}
+}
+
+func TestAlertEvaluatorImpl_ChangeWindow(t *testing.T) {
+	evaluator := NewAlertEvaluator()
+	reference := &bitcoin.PricePoint{Price: 70000, Timestamp: time.Now().Add(-15 * time.Minute)}
+
+	tests := []struct {
+		name       string
+		percentage float64
+		price      float64
+		reference  *bitcoin.PricePoint
+		expected   bool
+	}{
+		{"drop within the window triggers", -3, 67800, reference, true},
+		{"smaller drop doesn't trigger", -3, 68000, reference, false},
+		{"rise doesn't trigger a drop alert", -3, 72200, reference, false},
+		{"rise within the window triggers", 3, 72100, reference, true},
+		{"no reference price doesn't trigger", -3, 60000, nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			alert := &storage.Alert{Type: "change_window", Percentage: tt.percentage, WindowMinutes: 15, IsActive: true}
+			priceData := &bitcoin.PriceData{Symbol: "BTCUSDT", Price: tt.price}
+			evalCtx := &interfaces.EvaluationContext{Reference: tt.reference}
+			assert.Equal(t, tt.expected, evaluator.ShouldTrigger(alert, priceData, evalCtx))
+		})
+	}
+}
+
+func TestAlertEvaluatorImpl_IndicatorAlerts(t *testing.T) {
+	evaluator := NewAlertEvaluator()
+	// EMA(9) at 100 just went above EMA(21) at 99
+	crossed := &bitcoin.IndicatorReading{Value: 100, Level: 99, Previous: &bitcoin.IndicatorReading{Value: 98, Level: 99}}
+
+	tests := []struct {
+		name      string
+		condition string
+		reading   *bitcoin.IndicatorReading
+		expected  bool
+	}{
+		{"RSI below its level triggers", "below", &bitcoin.IndicatorReading{Value: 28, Level: 30}, true},
+		{"RSI above its level doesn't trigger a below alert", "below", &bitcoin.IndicatorReading{Value: 45, Level: 30}, false},
+		{"cross above triggers", "cross_above", crossed, true},
+		{"cross above needs a previous reading", "cross_above", &bitcoin.IndicatorReading{Value: 100, Level: 99}, false},
+		{"staying above isn't a crossing", "cross_above", &bitcoin.IndicatorReading{Value: 101, Level: 99, Previous: crossed}, false},
+		{"cross below triggers", "cross_below", &bitcoin.IndicatorReading{Value: 98, Level: 99, Previous: crossed}, true},
+		{"no reading doesn't trigger", "above", nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			alert := &storage.Alert{Type: "indicator", Indicator: "ema_cross", IndicatorCondition: tt.condition, IsActive: true}
+			priceData := &bitcoin.PriceData{Symbol: "BTCUSDT", Price: 70000}
+			evalCtx := &interfaces.EvaluationContext{Indicator: tt.reading}
+			assert.Equal(t, tt.expected, evaluator.ShouldTrigger(alert, priceData, evalCtx))
+		})
+	}
+}
+
+func TestCompositeAlertEvaluator(t *testing.T) {
+	evaluator := NewCompositeAlertEvaluator(NewAlertEvaluator())
+	alert := &storage.Alert{Name: "Dip with volume", Symbol: "BTCUSDT", Type: "composite", IsActive: true,
+		Conditions: &storage.Condition{Op: "and", Conditions: []storage.Condition{
+			{Type: "below", TargetPrice: 60000},
+			{Op: "or", Conditions: []storage.Condition{
+				{Type: "indicator", Indicator: "rsi", Interval: "1h", IndicatorCondition: "below", Threshold: 30},
+				{Type: "indicator", Indicator: "volume", Interval: "1h", IndicatorCondition: "above"},
+			}},
+		}}}
+	require.NoError(t, alert.Validate())
+	assert.Equal(t, "Bitcoin price below $60000.00 AND (RSI(14) on 1h below 30.00 OR volume above its 20-period average on 1h)",
+		alert.GetDescription())
+
+	oversold := map[string]*bitcoin.IndicatorReading{"1.0": {Value: 25, Level: 30}, "1.1": {Value: 80, Level: 100}}
+	highVolume := map[string]*bitcoin.IndicatorReading{"1.0": {Value: 45, Level: 30}, "1.1": {Value: 150, Level: 100}}
+	neither := map[string]*bitcoin.IndicatorReading{"1.0": {Value: 45, Level: 30}, "1.1": {Value: 80, Level: 100}}
+
+	tests := []struct {
+		name     string
+		price    float64
+		readings map[string]*bitcoin.IndicatorReading
+		expected bool
+	}{
+		{"price and RSI hold", 59000, oversold, true},
+		{"price and volume hold", 59000, highVolume, true},
+		{"price above the target", 61000, oversold, false},
+		{"neither indicator holds", 59000, neither, false},
+		{"indicators without readings don't hold", 59000, nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			priceData := &bitcoin.PriceData{Symbol: "BTCUSDT", Price: tt.price}
+			evalCtx := &interfaces.EvaluationContext{Readings: tt.readings}
+			assert.Equal(t, tt.expected, evaluator.ShouldTrigger(alert, priceData, evalCtx))
+		})
+	}
+
+	// Other alerts are evaluated as before
+	assert.True(t, evaluator.ShouldTrigger(&storage.Alert{Type: "below", TargetPrice: 60000, IsActive: true},
+		&bitcoin.PriceData{Symbol: "BTCUSDT", Price: 59000}, nil))
+
+	// Invalid trees are rejected on create
+	invalid := []*storage.Condition{
+		{Op: "xor", Conditions: []storage.Condition{{Type: "below", TargetPrice: 60000}}},
+		{Op: "and"},
+		{Op: "and", Conditions: []storage.Condition{{Type: "spread", Threshold: 5}}},
+		{Op: "and", Conditions: []storage.Condition{{Type: "indicator", Indicator: "rsi", IndicatorCondition: "below", Threshold: 130}}},
+	}
+	for _, conditions := range invalid {
+		composite := *alert
+		composite.Conditions = conditions
+		assert.Error(t, composite.Validate())
+	}
+}
+
+func TestAlertEvaluatorImpl_ExpressionAlerts(t *testing.T) {
+	evaluator := NewAlertEvaluator()
+	alert := &storage.Alert{Name: "Afternoon dip", Symbol: "BTCUSDT", Type: "expression", IsActive: true,
+		Expression: `price < 60000 && change_24h < -5 && hour_utc >= 13 && rsi(14, "1h") < 30`}
+	require.NoError(t, alert.Validate())
+	assert.Equal(t, `Bitcoin rule: price < 60000 && change_24h < -5 && hour_utc >= 13 && rsi(14, "1h") < 30`, alert.GetDescription())
+
+	afternoon := time.Date(2026, 3, 2, 14, 30, 0, 0, time.UTC)
+	oversold := map[string]float64{`rsi(14,"1h")`: 27}
+
+	tests := []struct {
+		name      string
+		priceData *bitcoin.PriceData
+		values    map[string]float64
+		expected  bool
+	}{
+		{"all conditions hold", &bitcoin.PriceData{Symbol: "BTCUSDT", Price: 59000, PriceChangePercent: -6,
+			Source: bitcoin.SourceBinance, Timestamp: afternoon}, oversold, true},
+		{"too early in the day", &bitcoin.PriceData{Symbol: "BTCUSDT", Price: 59000, PriceChangePercent: -6,
+			Source: bitcoin.SourceBinance, Timestamp: afternoon.Add(-4 * time.Hour)}, oversold, false},
+		{"RSI not oversold", &bitcoin.PriceData{Symbol: "BTCUSDT", Price: 59000, PriceChangePercent: -6,
+			Source: bitcoin.SourceBinance, Timestamp: afternoon}, map[string]float64{`rsi(14,"1h")`: 45}, false},
+		{"no RSI yet", &bitcoin.PriceData{Symbol: "BTCUSDT", Price: 59000, PriceChangePercent: -6,
+			Source: bitcoin.SourceBinance, Timestamp: afternoon}, nil, false},
+		// Kraken's change isn't Binance's rolling 24h change
+		{"Kraken ticks have no change_24h", &bitcoin.PriceData{Symbol: "BTCUSDT", Price: 59000, PriceChangePercent: -6,
+			Source: bitcoin.SourceKraken, Timestamp: afternoon}, oversold, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			evalCtx := &interfaces.EvaluationContext{Values: tt.values}
+			assert.Equal(t, tt.expected, evaluator.ShouldTrigger(alert, tt.priceData, evalCtx))
+		})
+	}
+
+	// Ticker and previous tick variables
+	volume := &storage.Alert{Symbol: "BTCUSDT", Type: "expression", IsActive: true,
+		Expression: "volume_24h > 1000 && price > prev_price && price > high_24h * 0.99"}
+	priceData := &bitcoin.PriceData{Symbol: "BTCUSDT", Price: 70000, Source: bitcoin.SourceBinance,
+		Ticker: &models.TickerData{Volume: 1500, HighPrice: 70500}}
+	previous := &bitcoin.PriceData{Symbol: "BTCUSDT", Price: 69900}
+	assert.True(t, evaluator.ShouldTrigger(volume, priceData, &interfaces.EvaluationContext{Previous: previous}))
+	assert.False(t, evaluator.ShouldTrigger(volume, priceData, nil))
+
+	// Invalid expressions are rejected on create, with the column of the problem
+	invalid := *alert
+	invalid.Expression = "price < 60000 && chnage_24h < -5"
+	assert.EqualError(t, invalid.Validate(), `invalid expression: column 18: unknown variable "chnage_24h"`)
+}
+
 func TestBitcoinClientAdapter_GetCurrentPrice(t *testing.T) {
 	t.Run("successful price retrieval", func(t *testing.T) {
 		// Setup
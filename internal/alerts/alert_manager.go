@@ -11,6 +11,7 @@ import (
 
 	"github.com/cgallonv/btc-alerta-de-precio/internal/bitcoin"
 	"github.com/cgallonv/btc-alerta-de-precio/internal/errors"
+	"github.com/cgallonv/btc-alerta-de-precio/internal/expression"
 	"github.com/cgallonv/btc-alerta-de-precio/internal/interfaces"
 	"github.com/cgallonv/btc-alerta-de-precio/internal/notifications"
 	"github.com/cgallonv/btc-alerta-de-precio/internal/storage"
@@ -22,7 +23,7 @@ import (
 //
 // Example usage:
 //
-//	manager, err := NewAlertManager(configProvider, notificationSender, alertEvaluator, alertRepo, notificationRepo)
+//	manager, err := NewAlertManager(configProvider, notificationSender, alertEvaluator, alertRepo, notificationRepo, priceProvider, tickerStorage)
 //	if err != nil {
 //	    log.Printf("Error: %v", err)
 //	    return
@@ -34,7 +35,7 @@ import (
 //	defer manager.Stop()
 type AlertManager struct {
 	// Dependencies
-	binanceClient      *bitcoin.BinanceClient
+	priceProvider      interfaces.PriceProvider
 	configProvider     interfaces.ConfigProvider
 	notificationSender interfaces.NotificationSender
 	alertEvaluator     interfaces.AlertEvaluator
@@ -43,14 +44,30 @@ type AlertManager struct {
 
 	// Price monitoring
 	priceMonitor *PriceMonitor
+	orderBooks   *bitcoin.OrderBooks // nil when order book alerts are disabled
+	fxRates      *FXRates            // nil when alerts can only be written in USD
+	indicators   *IndicatorEngine    // nil when indicator alerts are disabled
+
+	// Alert processing - used to prevent concurrent alert processing per symbol
+	processingMux sync.RWMutex                  // Protects isProcessing and previousTicks
+	isProcessing  map[string]bool               // Symbols whose alerts are currently being processed
+	previousTicks map[string]*bitcoin.PriceData // Last tick evaluated per symbol, for crossing alerts
+
+	// Compiled expressions of expression alerts, by alert ID
+	programsMux sync.Mutex
+	programs    map[uint]*compiledExpression
+}
 
-	// Alert processing - used to prevent concurrent alert processing
-	processingMux sync.RWMutex // Protects isProcessing
-	isProcessing  bool         // Indicates if alerts are currently being processed
+// compiledExpression is an alert's compiled expression and the text it was compiled
+// from, so it's only compiled again when the alert's expression changes.
+type compiledExpression struct {
+	source  string
+	program *expression.Program
+	err     error
 }
 
 // NewAlertManager creates a new alert manager with the provided dependencies.
-// It initializes the Binance client and price monitor, and sets up price update callbacks.
+// It initializes the price monitor on top of priceProvider and sets up price update callbacks.
 //
 // Example usage:
 //
@@ -60,6 +77,8 @@ type AlertManager struct {
 //	    alertEvaluator,
 //	    alertRepo,
 //	    notificationRepo,
+//	    NewPriceProviderChain(binanceClient, coinbaseClient),
+//	    tickerStorage,
 //	)
 //	if err != nil {
 //	    log.Printf("Error: %v", err)
@@ -71,25 +90,27 @@ func NewAlertManager(
 	alertEvaluator interfaces.AlertEvaluator,
 	alertRepo interfaces.AlertRepository,
 	notificationRepo interfaces.NotificationRepository,
+	priceProvider interfaces.PriceProvider,
 	tickerStorage *bitcoin.TickerStorage,
 ) (*AlertManager, error) {
-	// Create Binance client with API credentials
-	apiKey := configProvider.GetString("binance.api_key")
-	apiSecret := configProvider.GetString("binance.api_secret")
-	baseURL := configProvider.GetString("binance.base_url")
-	binanceClient := bitcoin.NewBinanceClient(apiKey, apiSecret, baseURL, tickerStorage)
+	if priceProvider == nil {
+		return nil, errors.NewAppError("PRICE_PROVIDER_MISSING", "A price provider is required")
+	}
 
 	// Create price monitor
-	priceMonitor := NewPriceMonitor(configProvider, 20, tickerStorage)
+	priceMonitor := NewPriceMonitor(configProvider, 20, priceProvider, tickerStorage)
 
 	manager := &AlertManager{
-		binanceClient:      binanceClient,
+		priceProvider:      priceProvider,
 		configProvider:     configProvider,
 		notificationSender: notificationSender,
 		alertEvaluator:     alertEvaluator,
 		alertRepo:          alertRepo,
 		notificationRepo:   notificationRepo,
 		priceMonitor:       priceMonitor,
+		isProcessing:       make(map[string]bool),
+		previousTicks:      make(map[string]*bitcoin.PriceData),
+		programs:           make(map[uint]*compiledExpression),
 	}
 
 	// Register for price updates
@@ -109,6 +130,7 @@ func NewAlertManager(
 //	}
 func (am *AlertManager) Start(ctx context.Context) error {
 	log.Printf("Starting Alert Manager...")
+	am.refreshTrackedSymbols()
 	return am.priceMonitor.Start(ctx)
 }
 
@@ -123,6 +145,40 @@ func (am *AlertManager) Stop() error {
 	return am.priceMonitor.Stop()
 }
 
+// SetOrderBooks enables order book alerts. The books of the symbols with active
+// order book alerts are tracked, and evaluated on the book at the time of each tick.
+//
+// Example usage:
+//
+//	books := bitcoin.NewOrderBooks(client, cfg.BinanceStreamURL)
+//	books.Start(ctx)
+//	manager.SetOrderBooks(books)
+func (am *AlertManager) SetOrderBooks(orderBooks *bitcoin.OrderBooks) {
+	am.orderBooks = orderBooks
+	am.refreshTrackedSymbols()
+}
+
+// SetFXRates enables alerts written in currencies other than USD. Their ticks are
+// converted with the current rate before evaluation.
+//
+// Example usage:
+//
+//	manager.SetFXRates(NewFXRates(cfg.FXRatesTTL, bitcoin.NewBinanceFXProvider(client)))
+func (am *AlertManager) SetFXRates(fxRates *FXRates) {
+	am.fxRates = fxRates
+}
+
+// SetIndicatorEngine enables technical indicator alerts and the indicator functions
+// of expression alerts. Each tick updates the indicators of the alert's symbol and
+// interval before evaluation.
+//
+// Example usage:
+//
+//	manager.SetIndicatorEngine(NewIndicatorEngine(candleStorage, client))
+func (am *AlertManager) SetIndicatorEngine(engine *IndicatorEngine) {
+	am.indicators = engine
+}
+
 // IsMonitoring returns true if alert monitoring is active.
 //
 // Example usage:
@@ -134,6 +190,17 @@ func (am *AlertManager) IsMonitoring() bool {
 	return am.priceMonitor.IsMonitoring()
 }
 
+// IsStreaming returns true if prices are arriving over the Binance WebSocket stream.
+//
+// Example usage:
+//
+//	if !manager.IsStreaming() {
+//	    log.Printf("Price feed is using REST polling")
+//	}
+func (am *AlertManager) IsStreaming() bool {
+	return am.priceMonitor.IsStreaming()
+}
+
 // checkAlerts evaluates all active alerts against the current price.
 func (am *AlertManager) checkAlerts(priceData *bitcoin.PriceData) {
 	if priceData == nil {
@@ -141,15 +208,31 @@ func (am *AlertManager) checkAlerts(priceData *bitcoin.PriceData) {
 		return
 	}
 
-	am.processingMux.Lock()
-	defer am.processingMux.Unlock()
+	symbol := priceData.Symbol
 
-	if am.isProcessing {
-		log.Printf("Alert processing already in progress, skipping")
+	am.processingMux.Lock()
+	if am.isProcessing[symbol] {
+		am.processingMux.Unlock()
+		log.Printf("Alert processing already in progress for %s, skipping", symbol)
+		return
+	}
+	// Callbacks run in goroutines, so an older tick can arrive after a newer one;
+	// evaluating it would see crossings backwards
+	previous := am.previousTicks[symbol]
+	if previous != nil && !priceData.Timestamp.After(previous.Timestamp) {
+		am.processingMux.Unlock()
+		log.Printf("Ignoring %s tick from %s, not newer than the last one", symbol, priceData.Timestamp.Format(time.RFC3339Nano))
 		return
 	}
-	am.isProcessing = true
-	defer func() { am.isProcessing = false }()
+	am.isProcessing[symbol] = true
+	am.previousTicks[symbol] = priceData
+	am.processingMux.Unlock()
+
+	defer func() {
+		am.processingMux.Lock()
+		delete(am.isProcessing, symbol)
+		am.processingMux.Unlock()
+	}()
 
 	alerts, err := am.alertRepo.GetActiveAlerts()
 	if err != nil {
@@ -157,29 +240,208 @@ func (am *AlertManager) checkAlerts(priceData *bitcoin.PriceData) {
 		return
 	}
 
+	// Order book alerts are evaluated on the book at the time of the tick
+	var book *bitcoin.OrderBookSnapshot
+	if am.orderBooks != nil {
+		if snapshot, ok := am.orderBooks.Snapshot(symbol); ok {
+			book = snapshot
+		}
+	}
+
+	now := time.Now()
+	converted := make(map[string]*bitcoin.PriceData)
+	convertedPrevious := make(map[string]*bitcoin.PriceData)
+	ranges := make(map[time.Duration]*bitcoin.PriceRange)
 	for _, alert := range alerts {
-		if am.alertEvaluator.ShouldTrigger(&alert, priceData) {
-			if err := am.triggerAlert(&alert, priceData); err != nil {
-				log.Printf("Error triggering alert %d: %v", alert.ID, err)
+		// Skip alerts cooling down or out of triggers before converting their tick,
+		// except disarmed ones, which need the price to re-arm
+		if !alert.MatchesSymbol(symbol) || (!alert.Disarmed && !alert.CanTrigger(now)) {
+			continue
+		}
+
+		tick, previousTick := priceData, previous
+		if currency := alert.GetCurrency(); currency != storage.DefaultAlertCurrency {
+			if tick, err = am.convertTick(priceData, currency, converted); err != nil {
+				log.Printf("⚠️ Skipping alert %d: %v", alert.ID, err)
+				continue
+			}
+			if previous != nil {
+				if previousTick, err = am.convertTick(previous, currency, convertedPrevious); err != nil {
+					previousTick = nil
+				}
+			}
+		}
+
+		// Re-arm alerts once the price has left the hysteresis band
+		if alert.Disarmed {
+			if alert.ShouldRearm(tick.Price) {
+				alert.Rearm()
+				if err := am.alertRepo.UpdateAlert(&alert); err != nil {
+					log.Printf("Error re-arming alert %d: %v", alert.ID, err)
+					continue
+				}
+				log.Printf("🔁 Alert %d re-armed at %.2f (band edge %.2f)", alert.ID, tick.Price, alert.RearmPrice())
+			}
+			continue
+		}
+
+		evalCtx := &interfaces.EvaluationContext{Previous: previousTick, OrderBook: book}
+
+		// Windowed change alerts compare rises against the window's low and drops
+		// against its high; they wait until the history covers the whole window,
+		// tolerating one missed check at its start
+		if alert.Type == "change_window" {
+			window := alert.GetWindow()
+			priceRange, ok := ranges[window]
+			if !ok {
+				priceRange = am.priceMonitor.PriceRange(symbol, now.Add(-window), now, 2*am.configProvider.GetCheckInterval())
+				ranges[window] = priceRange
+			}
+			if priceRange == nil {
+				continue
+			}
+			reference := &priceRange.High
+			if alert.Percentage > 0 {
+				reference = &priceRange.Low
+			}
+			rate := 1.0
+			if tick != priceData {
+				rate = tick.Price / priceData.Price
+			}
+			evalCtx.Reference = &bitcoin.PricePoint{Price: reference.Price * rate, Timestamp: reference.Timestamp}
+		}
+
+		// Indicators are computed on the symbol's own prices, whatever the alert currency
+		if alert.Type == "indicator" {
+			if am.indicators == nil {
+				continue
+			}
+			reading := am.indicators.Reading(&alert, priceData)
+			if reading == nil {
+				continue
+			}
+			evalCtx.Indicator = reading
+		}
+
+		// Composite alerts get the readings of their indicator conditions; conditions
+		// without a reading don't hold
+		if alert.Type == "composite" && am.indicators != nil {
+			evalCtx.Readings = am.indicators.ConditionReadings(&alert, priceData)
+		}
+
+		// Expression alerts get their compiled expression and the values of its indicator
+		// calls, also computed on the symbol's own prices; expressions using a missing
+		// value don't trigger
+		if alert.Type == "expression" {
+			program, err := am.program(&alert)
+			if err != nil {
+				log.Printf("⚠️ Skipping alert %d: invalid expression: %v", alert.ID, err)
 				continue
 			}
+			evalCtx.Program = program
+			if refs := program.Indicators(); len(refs) > 0 && am.indicators != nil {
+				evalCtx.Values = am.indicators.ExpressionValues(alert.GetSymbol(), refs, priceData)
+			}
+		}
 
-			// Mark alert as triggered
-			alert.MarkTriggered()
-			if err := am.alertRepo.UpdateAlert(&alert); err != nil {
-				log.Printf("Error updating alert %d: %v", alert.ID, err)
+		// Scheduled alerts only trigger inside their window. Indicators are still read
+		// outside it so crossings compare against the last tick, and deferring alerts
+		// keep the first condition met until the window opens
+		at := tickTime(priceData)
+		if !alert.InScheduleAt(at) {
+			if alert.DefersOutsideSchedule() && alert.DeferredAt == nil &&
+				am.alertEvaluator.ShouldTrigger(&alert, tick, evalCtx) {
+				alert.Defer(tick.Price, at)
+				if err := am.alertRepo.UpdateAlert(&alert); err != nil {
+					log.Printf("Error deferring alert %d: %v", alert.ID, err)
+					continue
+				}
+				if opens, ok := alert.Schedule.NextOpen(at); ok {
+					log.Printf("⏸️ Alert %d met outside its schedule, deferred until %s", alert.ID, opens.Format(time.RFC3339))
+				}
 			}
+			continue
+		}
+		if alert.DeferredAt != nil {
+			// Deliver the deferred notification with the price it was met at
+			deferred := *tick
+			deferred.Price = alert.DeferredPrice
+			tick = &deferred
+			evalCtx.Reference = nil
+		} else if !am.alertEvaluator.ShouldTrigger(&alert, tick, evalCtx) {
+			continue
+		}
+
+		if err := am.triggerAlert(&alert, tick, evalCtx); err != nil {
+			log.Printf("Error triggering alert %d: %v", alert.ID, err)
+			continue
+		}
+
+		// Mark alert as triggered
+		alert.MarkTriggered()
+		if err := am.alertRepo.UpdateAlert(&alert); err != nil {
+			log.Printf("Error updating alert %d: %v", alert.ID, err)
+		}
+		switch {
+		case alert.IsExhausted() && alert.GetTriggerMode() == storage.TriggerModeMaxCount:
+			log.Printf("🏁 Alert %d reached its %d triggers, reset it to re-arm", alert.ID, alert.MaxTriggers)
+		case alert.Disarmed:
+			log.Printf("🔒 Alert %d disarmed until the price crosses %.2f", alert.ID, alert.RearmPrice())
+		case alert.NextEligibleAt != nil:
+			log.Printf("⏳ Alert %d can trigger again after %s", alert.ID, alert.NextEligibleAt.Format(time.RFC3339))
 		}
 	}
 }
 
+// convertTick returns a copy of the tick priced in currency, reusing the conversions
+// already made for this tick. Only USD-quoted symbols can be converted, the price of
+// ETHBTC is in BTC and the rates are per US dollar.
+func (am *AlertManager) convertTick(priceData *bitcoin.PriceData, currency string, converted map[string]*bitcoin.PriceData) (*bitcoin.PriceData, error) {
+	if tick, ok := converted[currency]; ok {
+		return tick, nil
+	}
+	if !storage.IsUSDQuoted(priceData.Symbol) {
+		return nil, fmt.Errorf("%s is not quoted in US dollars, can't price it in %s", priceData.Symbol, currency)
+	}
+	if am.fxRates == nil {
+		return nil, fmt.Errorf("FX rates are not configured, can't price %s in %s", priceData.Symbol, currency)
+	}
+
+	rate, err := am.fxRates.Rate(currency)
+	if err != nil {
+		return nil, err
+	}
+
+	tick := *priceData
+	tick.Price *= rate.Rate
+	tick.Currency = currency
+	converted[currency] = &tick
+	return &tick, nil
+}
+
+// program returns the compiled expression of an expression alert, compiling it only
+// the first time and whenever the alert's expression changes.
+func (am *AlertManager) program(alert *storage.Alert) (*expression.Program, error) {
+	am.programsMux.Lock()
+	defer am.programsMux.Unlock()
+
+	if compiled, ok := am.programs[alert.ID]; ok && compiled.source == alert.Expression {
+		return compiled.program, compiled.err
+	}
+	program, err := expression.Compile(alert.Expression)
+	am.programs[alert.ID] = &compiledExpression{source: alert.Expression, program: program, err: err}
+	return program, err
+}
+
 // triggerAlert sends notifications for a triggered alert.
-func (am *AlertManager) triggerAlert(alert *storage.Alert, priceData *bitcoin.PriceData) error {
+// evalCtx is the context the alert was evaluated with.
+func (am *AlertManager) triggerAlert(alert *storage.Alert, priceData *bitcoin.PriceData, evalCtx *interfaces.EvaluationContext) error {
 	// Prepare notification data
 	notificationData := &notifications.NotificationData{
-		Title:       "🚨 Bitcoin Alert",
+		Title:       fmt.Sprintf("🚨 %s Alert", alert.GetSymbol()),
 		Message:     alert.GetDescription(),
 		Price:       priceData.Price,
+		Currency:    priceData.Currency,
 		Alert:       alert,
 		AlertID:     alert.ID,
 		AlertName:   alert.Name,
@@ -188,6 +450,13 @@ func (am *AlertManager) triggerAlert(alert *storage.Alert, priceData *bitcoin.Pr
 		Email:       alert.Email,
 		EnableEmail: alert.EnableEmail,
 	}
+	if evalCtx != nil && evalCtx.Reference != nil {
+		notificationData.ReferencePrice = evalCtx.Reference.Price
+		notificationData.ReferenceTime = evalCtx.Reference.Timestamp
+	}
+	if alert.DeferredAt != nil {
+		notificationData.DeferredAt = *alert.DeferredAt
+	}
 
 	// Send notification
 	if err := am.notificationSender.SendAlert(notificationData); err != nil {
@@ -246,6 +515,7 @@ func (am *AlertManager) CreateAlert(alert *storage.Alert) error {
 	if err := am.alertRepo.CreateAlert(alert); err != nil {
 		return errors.WrapError(err, "CREATE_ALERT_ERROR", "Failed to create alert")
 	}
+	am.refreshTrackedSymbols()
 	return nil
 }
 
@@ -300,6 +570,7 @@ func (am *AlertManager) UpdateAlert(alert *storage.Alert) error {
 	if err := am.alertRepo.UpdateAlert(alert); err != nil {
 		return errors.WrapError(err, "UPDATE_ALERT_ERROR", "Failed to update alert")
 	}
+	am.refreshTrackedSymbols()
 	return nil
 }
 
@@ -315,6 +586,7 @@ func (am *AlertManager) DeleteAlert(id uint) error {
 	if err := am.alertRepo.DeleteAlert(id); err != nil {
 		return errors.WrapError(err, "DELETE_ALERT_ERROR", "Failed to delete alert")
 	}
+	am.refreshTrackedSymbols()
 	return nil
 }
 
@@ -330,9 +602,50 @@ func (am *AlertManager) ToggleAlert(id uint) error {
 	if err := am.alertRepo.ToggleAlert(id); err != nil {
 		return errors.WrapError(err, "TOGGLE_ALERT_ERROR", "Failed to toggle alert")
 	}
+	am.refreshTrackedSymbols()
 	return nil
 }
 
+// refreshTrackedSymbols points the price monitor at every symbol referenced by an active alert.
+func (am *AlertManager) refreshTrackedSymbols() {
+	alerts, err := am.alertRepo.GetActiveAlerts()
+	if err != nil {
+		log.Printf("Error getting active alerts for symbol tracking: %v", err)
+		return
+	}
+
+	symbols := make([]string, 0, len(alerts))
+	var bookSymbols []string
+	for _, alert := range alerts {
+		symbols = append(symbols, alert.GetSymbol())
+		if alert.IsOrderBookAlert() {
+			bookSymbols = append(bookSymbols, alert.GetSymbol())
+		}
+	}
+	am.priceMonitor.SetSymbols(symbols)
+	if am.orderBooks != nil {
+		am.orderBooks.SetSymbols(bookSymbols)
+	}
+	if am.indicators != nil {
+		am.indicators.Retain(alerts)
+	}
+
+	// Forget the expressions of alerts that were deleted, disabled or changed type
+	expressions := make(map[uint]bool)
+	for _, alert := range alerts {
+		if alert.Type == "expression" {
+			expressions[alert.ID] = true
+		}
+	}
+	am.programsMux.Lock()
+	for id := range am.programs {
+		if !expressions[id] {
+			delete(am.programs, id)
+		}
+	}
+	am.programsMux.Unlock()
+}
+
 // ResetAlert resets an alert's trigger status.
 //
 // Example usage:
@@ -370,7 +683,7 @@ func (am *AlertManager) TestAlert(id uint) error {
 	}
 
 	// Get current price for test
-	priceData, err := am.GetCurrentPrice()
+	priceData, err := am.GetCurrentPrice(alert.GetSymbol())
 	if err != nil {
 		return errors.WrapError(err, "TEST_ALERT_ERROR", "Failed to get current price")
 	}
@@ -399,25 +712,45 @@ func (am *AlertManager) TestAlert(id uint) error {
 
 // Price-related methods
 
-// GetCurrentPrice returns the current Bitcoin price.
+// GetCurrentPrice returns the current price for a symbol.
+// An empty symbol returns the Bitcoin (BTCUSDT) price.
 //
 // Example usage:
 //
-//	price, err := manager.GetCurrentPrice()
+//	price, err := manager.GetCurrentPrice("ETHUSDT")
 //	if err != nil {
 //	    log.Printf("Error: %v", err)
 //	    return
 //	}
 //	log.Printf("Current price: $%.2f", price.Price)
-func (am *AlertManager) GetCurrentPrice() (*bitcoin.PriceData, error) {
-	return am.binanceClient.GetCurrentPrice()
+func (am *AlertManager) GetCurrentPrice(symbol string) (*bitcoin.PriceData, error) {
+	if symbol == "" {
+		symbol = bitcoin.DefaultSymbol
+	}
+	return am.priceProvider.GetSymbolPrice(symbol)
 }
 
-// GetPriceHistory returns the price history.
+// GetLastPrice returns the last tick received by the price monitor for a symbol,
+// or nil if none has arrived yet. Unlike GetCurrentPrice it never calls Binance.
 //
 // Example usage:
 //
-//	history, err := manager.GetPriceHistory(24)
+//	if last := manager.GetLastPrice("BTCUSDT"); last != nil {
+//	    log.Printf("Last tick %v ago", time.Since(last.Timestamp))
+//	}
+func (am *AlertManager) GetLastPrice(symbol string) *bitcoin.PriceData {
+	if symbol == "" {
+		symbol = bitcoin.DefaultSymbol
+	}
+	return am.priceMonitor.GetLastPrice(symbol)
+}
+
+// GetPriceHistory returns the cached price history for a symbol.
+// An empty symbol returns the Bitcoin (BTCUSDT) history.
+//
+// Example usage:
+//
+//	history, err := manager.GetPriceHistory("BTCUSDT", 24)
 //	if err != nil {
 //	    log.Printf("Error: %v", err)
 //	    return
@@ -425,8 +758,11 @@ func (am *AlertManager) GetCurrentPrice() (*bitcoin.PriceData, error) {
 //	for _, entry := range history {
 //	    log.Printf("Price at %s: $%.2f", entry.Timestamp, entry.Price)
 //	}
-func (am *AlertManager) GetPriceHistory(limit int) ([]interfaces.PriceCacheEntry, error) {
-	return am.priceMonitor.GetPriceHistory(limit), nil
+func (am *AlertManager) GetPriceHistory(symbol string, limit int) ([]interfaces.PriceCacheEntry, error) {
+	if symbol == "" {
+		symbol = bitcoin.DefaultSymbol
+	}
+	return am.priceMonitor.GetPriceHistory(symbol, limit), nil
 }
 
 // GetCurrentPercentage returns the current price change percentage.
@@ -455,7 +791,7 @@ func (am *AlertManager) GetStats() (map[string]interface{}, error) {
 	stats := make(map[string]interface{})
 
 	// Get current price
-	price, err := am.GetCurrentPrice()
+	price, err := am.GetCurrentPrice(bitcoin.DefaultSymbol)
 	if err == nil {
 		stats["current_price"] = price.Price
 		stats["price_change"] = price.PriceChangePercent
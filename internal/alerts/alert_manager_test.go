@@ -0,0 +1,84 @@
+package alerts
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cgallonv/btc-alerta-de-precio/internal/bitcoin"
+	"github.com/cgallonv/btc-alerta-de-precio/internal/expression"
+	"github.com/cgallonv/btc-alerta-de-precio/internal/interfaces"
+	"github.com/cgallonv/btc-alerta-de-precio/internal/mocks"
+	"github.com/cgallonv/btc-alerta-de-precio/internal/storage"
+)
+
+func TestAlertManager_IgnoresOutOfOrderTicks(t *testing.T) {
+	repo := &mocks.MockAlertRepository{}
+	repo.On("GetActiveAlerts").Return([]storage.Alert{
+		{ID: 1, Name: "BTC crosses 70k", Symbol: "BTCUSDT", Type: "cross_up", TargetPrice: 70000, IsActive: true},
+	}, nil)
+	evaluator := &mocks.MockAlertEvaluator{}
+	evaluator.On("ShouldTrigger", mock.Anything, mock.Anything, mock.Anything).Return(false)
+
+	am := &AlertManager{
+		alertRepo:      repo,
+		alertEvaluator: evaluator,
+		isProcessing:   make(map[string]bool),
+		previousTicks:  make(map[string]*bitcoin.PriceData),
+	}
+
+	now := time.Now()
+	first := &bitcoin.PriceData{Symbol: "BTCUSDT", Price: 69000, Timestamp: now}
+	stale := &bitcoin.PriceData{Symbol: "BTCUSDT", Price: 71000, Timestamp: now.Add(-time.Second)}
+	duplicate := &bitcoin.PriceData{Symbol: "BTCUSDT", Price: 71000, Timestamp: now}
+	next := &bitcoin.PriceData{Symbol: "BTCUSDT", Price: 70500, Timestamp: now.Add(time.Second)}
+	for _, tick := range []*bitcoin.PriceData{first, stale, duplicate, next} {
+		am.checkAlerts(tick)
+	}
+
+	// Only the ticks in order were evaluated, and the last one against the first
+	evaluator.AssertNumberOfCalls(t, "ShouldTrigger", 2)
+	last := evaluator.Calls[1]
+	assert.Equal(t, 70500.0, last.Arguments.Get(1).(*bitcoin.PriceData).Price)
+	assert.Same(t, first, last.Arguments.Get(2).(*interfaces.EvaluationContext).Previous)
+	assert.Same(t, next, am.previousTicks["BTCUSDT"])
+}
+
+func TestAlertManager_CachesCompiledExpressions(t *testing.T) {
+	alert := storage.Alert{ID: 2, Name: "Dip", Symbol: "BTCUSDT", Type: "expression", Expression: "price < 60000", IsActive: true}
+	repo := &mocks.MockAlertRepository{}
+	edited := alert
+	edited.Expression = "price < 58000"
+	repo.On("GetActiveAlerts").Return([]storage.Alert{alert}, nil).Twice()
+	repo.On("GetActiveAlerts").Return([]storage.Alert{edited}, nil).Once()
+	evaluator := &mocks.MockAlertEvaluator{}
+	evaluator.On("ShouldTrigger", mock.Anything, mock.Anything, mock.Anything).Return(false)
+
+	am := &AlertManager{
+		alertRepo:      repo,
+		alertEvaluator: evaluator,
+		isProcessing:   make(map[string]bool),
+		previousTicks:  make(map[string]*bitcoin.PriceData),
+		programs:       make(map[uint]*compiledExpression),
+	}
+	program := func(call int) *expression.Program {
+		return evaluator.Calls[call].Arguments.Get(2).(*interfaces.EvaluationContext).Program
+	}
+
+	now := time.Now()
+	am.checkAlerts(&bitcoin.PriceData{Symbol: "BTCUSDT", Price: 61000, Timestamp: now})
+	am.checkAlerts(&bitcoin.PriceData{Symbol: "BTCUSDT", Price: 59000, Timestamp: now.Add(time.Second)})
+
+	// The evaluator gets the same compiled program on every tick
+	require.NotNil(t, program(0))
+	assert.Same(t, program(0), program(1))
+	assert.Equal(t, "price < 60000", program(0).String())
+
+	// Editing the expression compiles it again
+	am.checkAlerts(&bitcoin.PriceData{Symbol: "BTCUSDT", Price: 59000, Timestamp: now.Add(2 * time.Second)})
+	assert.NotSame(t, program(0), program(2))
+	assert.Equal(t, "price < 58000", program(2).String())
+}
@@ -0,0 +1,148 @@
+// Package alerts provides functionality for monitoring Bitcoin prices
+// and managing price-based alerts.
+package alerts
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cgallonv/btc-alerta-de-precio/internal/interfaces"
+)
+
+// DefaultFXRatesTTL is how long an exchange rate is reused before it is fetched again.
+const DefaultFXRatesTTL = 5 * time.Minute
+
+// FXRate is the number of units of a currency one US dollar buys.
+type FXRate struct {
+	Currency  string    `json:"currency"`
+	Rate      float64   `json:"rate"`
+	Source    string    `json:"source"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// FXRates converts US dollar amounts to other currencies. Rates are cached for a TTL
+// and fetched from the providers in order; when every provider fails, the last known
+// rate is used so a brief outage doesn't silence alerts written in that currency.
+//
+// Example usage:
+//
+//	fx := NewFXRates(5*time.Minute, bitcoin.NewBinanceFXProvider(client), NewStaticFXProvider(cfg.FXStaticRates))
+//	price, err := fx.FromUSD(60000, "COP")
+//	if err != nil {
+//	    return err
+//	}
+//	log.Printf("BTC: %.0f COP", price)
+type FXRates struct {
+	providers []interfaces.FXRateProvider
+	ttl       time.Duration
+
+	rates    map[string]*FXRate
+	ratesMux sync.Mutex
+}
+
+// NewFXRates creates an FX rate cache over the given providers, in priority order.
+// A non-positive ttl uses DefaultFXRatesTTL.
+func NewFXRates(ttl time.Duration, providers ...interfaces.FXRateProvider) *FXRates {
+	if ttl <= 0 {
+		ttl = DefaultFXRatesTTL
+	}
+	return &FXRates{
+		providers: providers,
+		ttl:       ttl,
+		rates:     make(map[string]*FXRate),
+	}
+}
+
+// Rate returns the rate of currency against the US dollar. USD (and USDT) is always 1.
+func (f *FXRates) Rate(currency string) (*FXRate, error) {
+	currency = strings.ToUpper(strings.TrimSpace(currency))
+	if currency == "" || currency == "USD" || currency == "USDT" {
+		return &FXRate{Currency: "USD", Rate: 1, Source: "fixed", UpdatedAt: time.Now()}, nil
+	}
+
+	f.ratesMux.Lock()
+	defer f.ratesMux.Unlock()
+
+	cached := f.rates[currency]
+	if cached != nil && time.Since(cached.UpdatedAt) < f.ttl {
+		rate := *cached
+		return &rate, nil
+	}
+
+	var errs []string
+	for _, provider := range f.providers {
+		value, err := provider.GetUSDRate(currency)
+		if err == nil && value <= 0 {
+			err = fmt.Errorf("invalid rate %v", value)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", provider.Name(), err))
+			continue
+		}
+
+		rate := &FXRate{Currency: currency, Rate: value, Source: provider.Name(), UpdatedAt: time.Now()}
+		f.rates[currency] = rate
+		result := *rate
+		return &result, nil
+	}
+
+	if cached != nil {
+		log.Printf("⚠️ Using stale %s rate from %s: %s", currency, cached.UpdatedAt.Format(time.RFC3339), strings.Join(errs, "; "))
+		rate := *cached
+		return &rate, nil
+	}
+	if len(errs) == 0 {
+		return nil, fmt.Errorf("no FX rate providers configured for %s", currency)
+	}
+	return nil, fmt.Errorf("no FX rate available for %s: %s", currency, strings.Join(errs, "; "))
+}
+
+// FromUSD converts a US dollar amount to currency.
+func (f *FXRates) FromUSD(amount float64, currency string) (float64, error) {
+	rate, err := f.Rate(currency)
+	if err != nil {
+		return 0, err
+	}
+	return amount * rate.Rate, nil
+}
+
+// ToUSD converts an amount in currency to US dollars.
+func (f *FXRates) ToUSD(amount float64, currency string) (float64, error) {
+	rate, err := f.Rate(currency)
+	if err != nil {
+		return 0, err
+	}
+	return amount / rate.Rate, nil
+}
+
+// StaticFXProvider serves fixed exchange rates from configuration, as a fallback for
+// currencies no exchange lists (e.g. FX_STATIC_RATES=COP=4000).
+type StaticFXProvider struct {
+	rates map[string]float64
+}
+
+// NewStaticFXProvider creates a provider over rates in units of currency per US dollar.
+func NewStaticFXProvider(rates map[string]float64) *StaticFXProvider {
+	normalized := make(map[string]float64, len(rates))
+	for currency, rate := range rates {
+		normalized[strings.ToUpper(strings.TrimSpace(currency))] = rate
+	}
+	return &StaticFXProvider{rates: normalized}
+}
+
+// Name returns the provider name reported with each rate.
+func (p *StaticFXProvider) Name() string {
+	return "static"
+}
+
+// GetUSDRate returns the configured rate of currency.
+func (p *StaticFXProvider) GetUSDRate(currency string) (float64, error) {
+	rate, ok := p.rates[strings.ToUpper(currency)]
+	if !ok {
+		return 0, fmt.Errorf("no static rate for %s", currency)
+	}
+	return rate, nil
+}
@@ -0,0 +1,73 @@
+package alerts
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cgallonv/btc-alerta-de-precio/internal/bitcoin"
+	"github.com/cgallonv/btc-alerta-de-precio/internal/mocks"
+	"github.com/cgallonv/btc-alerta-de-precio/internal/storage"
+)
+
+func TestFXRates_FallsBackAndCaches(t *testing.T) {
+	binance := &mocks.MockFXRateProvider{}
+	binance.On("Name").Return(bitcoin.SourceBinance)
+	binance.On("GetUSDRate", "EUR").Return(0.9, nil).Once()
+	binance.On("GetUSDRate", "COP").Return(0.0, fmt.Errorf("no Binance market for COP"))
+	binance.On("GetUSDRate", "XYZ").Return(0.0, fmt.Errorf("no Binance market for XYZ"))
+
+	fx := NewFXRates(time.Hour, binance, NewStaticFXProvider(map[string]float64{"cop": 4000}))
+
+	rate, err := fx.Rate("eur")
+	require.NoError(t, err)
+	assert.Equal(t, "EUR", rate.Currency)
+	assert.Equal(t, bitcoin.SourceBinance, rate.Source)
+
+	// Cached for the TTL, so Binance is only asked once
+	amount, err := fx.FromUSD(100, "EUR")
+	require.NoError(t, err)
+	assert.InDelta(t, 90, amount, 1e-9)
+	binance.AssertNumberOfCalls(t, "GetUSDRate", 1)
+
+	// COP isn't listed on Binance, the static rate is used
+	rate, err = fx.Rate("COP")
+	require.NoError(t, err)
+	assert.Equal(t, "static", rate.Source)
+	amount, err = fx.ToUSD(250_000_000, "COP")
+	require.NoError(t, err)
+	assert.InDelta(t, 62500, amount, 1e-9)
+
+	usd, err := fx.Rate("USD")
+	require.NoError(t, err)
+	assert.Equal(t, 1.0, usd.Rate)
+
+	_, err = fx.Rate("XYZ")
+	assert.Error(t, err)
+}
+
+func TestAlertManager_ConvertTick(t *testing.T) {
+	am := &AlertManager{fxRates: NewFXRates(time.Hour, NewStaticFXProvider(map[string]float64{"COP": 4000}))}
+	tick := &bitcoin.PriceData{Symbol: "BTCUSDT", Price: 60000, Currency: "USD"}
+	converted := make(map[string]*bitcoin.PriceData)
+
+	cop, err := am.convertTick(tick, "COP", converted)
+	require.NoError(t, err)
+	assert.Equal(t, 240_000_000.0, cop.Price)
+	assert.Equal(t, "COP", cop.Currency)
+	assert.Equal(t, 60000.0, tick.Price, "the shared tick must not change")
+
+	_, err = (&AlertManager{}).convertTick(tick, "EUR", converted)
+	assert.Error(t, err)
+
+	// ETHBTC is priced in BTC, multiplying it by the USD rate would be wrong
+	_, err = am.convertTick(&bitcoin.PriceData{Symbol: "ETHBTC", Price: 0.05}, "COP", make(map[string]*bitcoin.PriceData))
+	assert.Error(t, err)
+	alert := &storage.Alert{Name: "ETH in COP", Type: "above", Symbol: "ETHBTC", TargetPrice: 1, Currency: "COP"}
+	assert.ErrorContains(t, alert.Validate(), "quoted in USDT")
+	alert.Symbol = "ETHUSDC"
+	assert.NoError(t, alert.Validate())
+}
@@ -0,0 +1,757 @@
+package alerts
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cgallonv/btc-alerta-de-precio/internal/bitcoin"
+	"github.com/cgallonv/btc-alerta-de-precio/internal/expression"
+	"github.com/cgallonv/btc-alerta-de-precio/internal/indicators"
+	"github.com/cgallonv/btc-alerta-de-precio/internal/storage"
+	"github.com/cgallonv/btc-alerta-de-precio/internal/storage/models"
+)
+
+const (
+	// DefaultIndicatorWarmup is how many candles seed each indicator series, enough
+	// for the longest period to settle.
+	DefaultIndicatorWarmup = 300
+
+	// indicatorRetryDelay is how long a series whose candles couldn't be loaded waits
+	// before trying again.
+	indicatorRetryDelay = time.Minute
+
+	// macdSignalPeriod is the signal line period of MACD alerts.
+	macdSignalPeriod = 9
+)
+
+// ErrIndicatorsLoading is returned by Snapshot while the series is loading in the background.
+var ErrIndicatorsLoading = errors.New("indicators are loading, try again shortly")
+
+// IndicatorEngine keeps the technical indicators of indicator alerts up to date.
+// Each symbol and interval is seeded once from the stored candles, backfilled from
+// Binance when missing, and then updated incrementally on every tick: the tick extends
+// the forming candle, closed candles are folded into the indicators, and readings
+// peek at the forming candle without recomputing the series. Seeding runs in the
+// background, so a tick never waits for the database or Binance; the series has no
+// readings until it's loaded. Crossings are judged on closed candles only, so a
+// forming candle that crosses and comes back doesn't trigger them.
+//
+// Example usage:
+//
+//	engine := NewIndicatorEngine(candleStorage, binanceClient)
+//	alertManager.SetIndicatorEngine(engine)
+type IndicatorEngine struct {
+	candles *bitcoin.CandleStorage // optional, stored candles
+	client  *bitcoin.BinanceClient // optional, backfills missing candles
+	warmup  int
+
+	mux       sync.Mutex
+	series    map[string]*indicatorSeries          // By symbol and interval
+	snapshots map[string]*indicatorSeries          // Series loaded for Snapshot only, by symbol and interval
+	loading   map[string]bool                      // Series being loaded in the background, by loadKey
+	failures  map[string]time.Time                 // Series that failed to load, until when to wait, by loadKey
+	previous  map[uint]map[string]*previousReading // Last reading of each alert, by condition path
+}
+
+// previousReading is the last reading of an alert or condition, and the open time of
+// the candle it was taken on.
+type previousReading struct {
+	reading  *bitcoin.IndicatorReading
+	openTime time.Time
+}
+
+// indicatorSeries is the candle series of a symbol and interval with its indicators.
+type indicatorSeries struct {
+	symbol     string
+	interval   string
+	duration   time.Duration
+	keep       int             // How many closed candles to keep
+	closed     []models.Candle // Last closed candles, oldest first
+	forming    *models.Candle  // Candle of the current interval, nil before the first tick
+	tickClosed bool            // Whether the last closed candle was closed from ticks, so its volume is partial
+	loadedAt   time.Time       // When a Snapshot series was loaded
+	indicators map[string]*seriesIndicator
+}
+
+// seriesIndicator is an indicator of a series, fed with closed candles and peeked
+// with the forming one. value is the indicator as of the last closed candle.
+type seriesIndicator struct {
+	update func(candle models.Candle)
+	peek   func(candle models.Candle) (indicatorValue, bool)
+	value  func() (indicatorValue, bool)
+}
+
+// indicatorValue is the output of any indicator; unused fields stay zero.
+type indicatorValue struct {
+	Value  float64 // SMA, EMA, RSI, ATR, MACD line, Bollinger middle band
+	Signal float64 // MACD signal line
+	Upper  float64 // Bollinger upper band
+	Lower  float64 // Bollinger lower band
+}
+
+// NewIndicatorEngine creates an indicator engine. Both candles and client are
+// optional: without candles the series are seeded from Binance alone, and without
+// client only the stored candles are used.
+//
+// Example usage:
+//
+//	engine := NewIndicatorEngine(candleStorage, bitcoin.NewBinanceClient("", "", cfg.BinanceBaseURL, nil))
+func NewIndicatorEngine(candles *bitcoin.CandleStorage, client *bitcoin.BinanceClient) *IndicatorEngine {
+	return &IndicatorEngine{
+		candles:   candles,
+		client:    client,
+		warmup:    DefaultIndicatorWarmup,
+		series:    make(map[string]*indicatorSeries),
+		snapshots: make(map[string]*indicatorSeries),
+		loading:   make(map[string]bool),
+		failures:  make(map[string]time.Time),
+		previous:  make(map[uint]map[string]*previousReading),
+	}
+}
+
+// Reading updates the alert's series with the tick and returns the alert's indicator
+// reading, with the previous reading of the same alert attached. It returns nil while
+// the series is loading, can't be loaded or doesn't have enough candles yet.
+//
+// Example usage:
+//
+//	if reading := engine.Reading(&alert, tick); reading != nil {
+//	    log.Printf("RSI %.1f (level %.1f)", reading.Value, reading.Level)
+//	}
+func (e *IndicatorEngine) Reading(alert *storage.Alert, tick *bitcoin.PriceData) *bitcoin.IndicatorReading {
+	e.mux.Lock()
+	defer e.mux.Unlock()
+
+	return e.read(alert, "", tick)
+}
+
+// ConditionReadings updates the series of a composite alert's indicator conditions with
+// the tick and returns their readings by condition path. Conditions without a reading
+// yet are left out.
+//
+// Example usage:
+//
+//	evalCtx.Readings = engine.ConditionReadings(&alert, tick)
+func (e *IndicatorEngine) ConditionReadings(alert *storage.Alert, tick *bitcoin.PriceData) map[string]*bitcoin.IndicatorReading {
+	if alert.Conditions == nil {
+		return nil
+	}
+
+	e.mux.Lock()
+	defer e.mux.Unlock()
+
+	var readings map[string]*bitcoin.IndicatorReading
+	alert.Conditions.Walk("", func(path string, condition *storage.Condition) {
+		if condition.Type != "indicator" {
+			return
+		}
+		if reading := e.read(condition.Alert(alert), path, tick); reading != nil {
+			if readings == nil {
+				readings = make(map[string]*bitcoin.IndicatorReading)
+			}
+			readings[path] = reading
+		}
+	})
+	return readings
+}
+
+// read updates the series of an indicator alert, or of a composite alert's condition
+// at path, and returns its reading. Crossing readings are taken on the last closed
+// candle, and only carry a different previous reading on the first tick after a
+// candle closes, so each crossing is seen once.
+func (e *IndicatorEngine) read(alert *storage.Alert, path string, tick *bitcoin.PriceData) *bitcoin.IndicatorReading {
+	series := e.tickSeries(alert.GetSymbol(), alert.GetInterval(), tick, alert.Indicator == "volume")
+	if series == nil {
+		return nil
+	}
+
+	crossing := strings.HasPrefix(alert.IndicatorCondition, "cross_")
+	reading, openTime, ok := series.reading(alert, crossing)
+	if !ok {
+		return nil
+	}
+	if e.previous[alert.ID] == nil {
+		e.previous[alert.ID] = make(map[string]*previousReading)
+	}
+	current := &bitcoin.IndicatorReading{Value: reading.Value, Level: reading.Level}
+	if previous := e.previous[alert.ID][path]; previous != nil {
+		reading.Previous = previous.reading
+		if crossing && !openTime.After(previous.openTime) {
+			// Same closed candle as the last tick: nothing new to cross
+			reading.Previous = current
+		}
+	}
+	e.previous[alert.ID][path] = &previousReading{reading: current, openTime: openTime}
+	return reading
+}
+
+// ExpressionValues updates the series of an expression alert's indicator calls with
+// the tick and returns their values by IndicatorRef.Key. Calls without a value yet
+// are left out, so the expression can't be evaluated until its series are warm.
+//
+// Example usage:
+//
+//	evalCtx.Values = engine.ExpressionValues(alert.GetSymbol(), program.Indicators(), tick)
+func (e *IndicatorEngine) ExpressionValues(symbol string, refs []expression.IndicatorRef, tick *bitcoin.PriceData) map[string]float64 {
+	e.mux.Lock()
+	defer e.mux.Unlock()
+
+	var values map[string]float64
+	for _, ref := range refs {
+		series := e.tickSeries(symbol, ref.Interval, tick, ref.Name == "volume_avg" || ref.Name == "candle_volume")
+		if series == nil {
+			continue
+		}
+		if value, ok := series.expressionValue(ref); ok {
+			if values == nil {
+				values = make(map[string]float64)
+			}
+			values[ref.Key()] = value
+		}
+	}
+	return values
+}
+
+// tickSeries returns the series of a symbol and interval updated with the tick. With
+// volume, a candle the ticks just closed is reloaded to get its volume.
+func (e *IndicatorEngine) tickSeries(symbol, interval string, tick *bitcoin.PriceData, volume bool) *indicatorSeries {
+	at := tickTime(tick)
+	key := seriesKey(symbol, interval)
+	if series, ok := e.series[key]; ok && series.missedCandles(at) {
+		// Reload after a gap in the ticks instead of skipping the candles never seen
+		delete(e.series, key)
+	}
+	series := e.seriesFor(symbol, interval, at)
+	if series == nil {
+		return nil
+	}
+	series.observe(tick.Price, at)
+
+	if volume && series.tickClosed {
+		// Ticks carry no candle volume, so reload the candle they just closed; volume
+		// readings wait for it while the other readings go on
+		e.startWarm(symbol, interval, at)
+	}
+	return series
+}
+
+// IndicatorSnapshot are the common indicators of a symbol and interval, on the
+// forming candle. Values that need more candles are left out.
+type IndicatorSnapshot struct {
+	Symbol    string                `json:"symbol"`
+	Interval  string                `json:"interval"`
+	Price     float64               `json:"price"`
+	OpenTime  time.Time             `json:"open_time"`
+	SMA       *float64              `json:"sma_20,omitempty"`
+	EMA       *float64              `json:"ema_21,omitempty"`
+	RSI       *float64              `json:"rsi_14,omitempty"`
+	MACD      *indicators.MACDValue `json:"macd,omitempty"`
+	Bollinger *indicators.Bands     `json:"bollinger_20,omitempty"`
+	ATR       *float64              `json:"atr_14,omitempty"`
+}
+
+// Snapshot returns SMA(20), EMA(21), RSI(14), MACD(12,26,9), Bollinger(20, 2) and
+// ATR(14) of a symbol and interval. It reads the series of the alerts when they tick
+// it; other series are loaded in the background, apart from the alert series, and
+// Snapshot returns ErrIndicatorsLoading until they're ready. Those are dropped once
+// their forming candle is over and loaded again on the next call.
+//
+// Example usage:
+//
+//	snapshot, err := engine.Snapshot("BTCUSDT", "1h")
+//	if errors.Is(err, ErrIndicatorsLoading) {
+//	    // try again shortly
+//	}
+func (e *IndicatorEngine) Snapshot(symbol, interval string) (*IndicatorSnapshot, error) {
+	if _, ok := bitcoin.IntervalDuration(interval); !ok {
+		return nil, fmt.Errorf("unsupported interval %q", interval)
+	}
+
+	now := time.Now()
+	key := seriesKey(symbol, interval)
+	e.mux.Lock()
+	defer e.mux.Unlock()
+
+	for snapshotKey, series := range e.snapshots {
+		if series.expired(now) {
+			delete(e.snapshots, snapshotKey)
+		}
+	}
+
+	series := e.series[key]
+	if series == nil || series.expired(now) {
+		series = e.snapshots[key]
+	}
+	if series == nil {
+		if until, failed := e.failures[loadKey(symbol, interval, true)]; failed && now.Before(until) {
+			return nil, fmt.Errorf("%s %s candles couldn't be loaded, retrying after %s", symbol, interval, until.Format(time.RFC3339))
+		}
+		e.startLoad(symbol, interval, now, true)
+		return nil, ErrIndicatorsLoading
+	}
+	if series.forming == nil {
+		return nil, fmt.Errorf("no %s %s candles available", symbol, interval)
+	}
+
+	forming := *series.forming
+	snapshot := &IndicatorSnapshot{Symbol: series.symbol, Interval: interval, Price: forming.Close, OpenTime: forming.OpenTime}
+	if value, ok := series.indicator("sma", 20, 0, 0).peek(forming); ok {
+		snapshot.SMA = &value.Value
+	}
+	if value, ok := series.indicator("ema", 21, 0, 0).peek(forming); ok {
+		snapshot.EMA = &value.Value
+	}
+	if value, ok := series.indicator("rsi", 14, 0, 0).peek(forming); ok {
+		snapshot.RSI = &value.Value
+	}
+	if value, ok := series.indicator("atr", 14, 0, 0).peek(forming); ok {
+		snapshot.ATR = &value.Value
+	}
+	if value, ok := series.indicator("macd", 12, 26, 0).peek(forming); ok {
+		snapshot.MACD = &indicators.MACDValue{MACD: value.Value, Signal: value.Signal, Histogram: value.Value - value.Signal}
+	}
+	if value, ok := series.indicator("bollinger", 20, 0, 2).peek(forming); ok {
+		snapshot.Bollinger = &indicators.Bands{Upper: value.Upper, Middle: value.Value, Lower: value.Lower}
+	}
+	return snapshot, nil
+}
+
+// Retain drops the series and readings no active indicator, composite or expression
+// alert uses anymore.
+func (e *IndicatorEngine) Retain(alerts []storage.Alert) {
+	e.mux.Lock()
+	defer e.mux.Unlock()
+
+	used := make(map[string]bool)
+	active := make(map[uint]bool)
+	for _, alert := range alerts {
+		switch {
+		case alert.Type == "indicator":
+			used[seriesKey(alert.GetSymbol(), alert.GetInterval())] = true
+			active[alert.ID] = true
+		case alert.Type == "composite" && alert.Conditions != nil:
+			alert.Conditions.Walk("", func(_ string, condition *storage.Condition) {
+				if condition.Type == "indicator" {
+					used[seriesKey(alert.GetSymbol(), condition.Alert(&alert).GetInterval())] = true
+					active[alert.ID] = true
+				}
+			})
+		case alert.Type == "expression":
+			if program, err := expression.Compile(alert.Expression); err == nil {
+				for _, ref := range program.Indicators() {
+					used[seriesKey(alert.GetSymbol(), ref.Interval)] = true
+				}
+			}
+		}
+	}
+	for key := range e.series {
+		if !used[key] {
+			delete(e.series, key)
+		}
+	}
+	for id := range e.previous {
+		if !active[id] {
+			delete(e.previous, id)
+		}
+	}
+}
+
+// seriesFor returns the series of a symbol and interval, or nil while it isn't loaded:
+// the first call starts loading it in the background. A series that failed to load is
+// retried after indicatorRetryDelay.
+func (e *IndicatorEngine) seriesFor(symbol, interval string, now time.Time) *indicatorSeries {
+	if series, ok := e.series[seriesKey(symbol, interval)]; ok {
+		return series
+	}
+	e.startWarm(symbol, interval, now)
+	return nil
+}
+
+// startWarm loads the series of a symbol and interval in the background, unless it's
+// already loading or waiting to retry. The caller must hold mux.
+func (e *IndicatorEngine) startWarm(symbol, interval string, now time.Time) {
+	e.startLoad(symbol, interval, now, false)
+}
+
+// startLoad loads a series in the background for the alerts, or for Snapshot when
+// snapshot is set, unless it's already loading or waiting to retry. The caller must
+// hold mux.
+func (e *IndicatorEngine) startLoad(symbol, interval string, now time.Time, snapshot bool) {
+	key := loadKey(symbol, interval, snapshot)
+	if e.loading[key] || now.Before(e.failures[key]) {
+		return
+	}
+	if _, ok := bitcoin.IntervalDuration(interval); !ok {
+		return
+	}
+	e.loading[key] = true
+	go e.warm(symbol, interval, now, snapshot)
+}
+
+// loadKey identifies a background load; loads for Snapshot are tracked apart from
+// loads of the same series for the alerts.
+func loadKey(symbol, interval string, snapshot bool) string {
+	if snapshot {
+		return "snapshot:" + seriesKey(symbol, interval)
+	}
+	return seriesKey(symbol, interval)
+}
+
+// warm loads the candles of a symbol and interval as of now and installs the series
+// in the alert series, or in the Snapshot series when snapshot is set, replacing the
+// current one. The candles are loaded without holding mux.
+func (e *IndicatorEngine) warm(symbol, interval string, now time.Time, snapshot bool) {
+	key := loadKey(symbol, interval, snapshot)
+	duration, ok := bitcoin.IntervalDuration(interval)
+	if !ok {
+		return
+	}
+	candles, err := e.loadCandles(strings.ToUpper(symbol), interval, duration, now)
+
+	e.mux.Lock()
+	defer e.mux.Unlock()
+
+	delete(e.loading, key)
+	if err != nil {
+		log.Printf("❌ Error loading %s %s candles for indicators, retrying in %s: %v", symbol, interval, indicatorRetryDelay, err)
+		e.failures[key] = now.Add(indicatorRetryDelay)
+		return
+	}
+	delete(e.failures, key)
+
+	series := &indicatorSeries{
+		symbol:     strings.ToUpper(symbol),
+		interval:   interval,
+		duration:   duration,
+		keep:       e.warmup,
+		indicators: make(map[string]*seriesIndicator),
+	}
+	current := bitcoin.CandleOpenTime(now, duration)
+	for i := range candles {
+		if candles[i].OpenTime.Before(current) {
+			series.commit(candles[i])
+		} else if candles[i].OpenTime.Equal(current) {
+			forming := candles[i]
+			series.forming = &forming
+		}
+	}
+	if len(candles) > 0 {
+		log.Printf("📈 Loaded %d %s %s candles for indicators", len(series.closed), series.symbol, interval)
+	}
+	if snapshot {
+		series.loadedAt = now
+		e.snapshots[seriesKey(symbol, interval)] = series
+		return
+	}
+	e.series[seriesKey(symbol, interval)] = series
+}
+
+// loadCandles returns the last warmup candles of a symbol and interval, oldest first,
+// backfilling the candle storage from Binance when possible.
+func (e *IndicatorEngine) loadCandles(symbol, interval string, duration time.Duration, now time.Time) ([]models.Candle, error) {
+	start := now.Add(-time.Duration(e.warmup) * duration)
+
+	if e.candles != nil {
+		if e.client != nil {
+			if _, err := e.candles.Backfill(e.client, symbol, interval, start, now); err != nil {
+				return nil, err
+			}
+		}
+		return e.candles.GetCandles(symbol, interval, start, now, e.warmup)
+	}
+	if e.client == nil {
+		return nil, nil
+	}
+
+	klines, err := e.client.GetHistoricalKlines(symbol, interval, start, now)
+	if err != nil {
+		return nil, err
+	}
+	candles := make([]models.Candle, 0, len(klines))
+	for i := range klines {
+		candles = append(candles, klines[i].Candle())
+	}
+	return candles, nil
+}
+
+// observe extends the forming candle with a tick, closing it first when the tick
+// belongs to a later interval. Ticks older than the forming candle are ignored.
+func (s *indicatorSeries) observe(price float64, at time.Time) {
+	openTime := bitcoin.CandleOpenTime(at, s.duration)
+	if s.forming != nil {
+		if openTime.Before(s.forming.OpenTime) {
+			return
+		}
+		if openTime.Equal(s.forming.OpenTime) {
+			s.forming.High = max(s.forming.High, price)
+			s.forming.Low = min(s.forming.Low, price)
+			s.forming.Close = price
+			return
+		}
+		s.commit(*s.forming)
+		s.tickClosed = true
+	}
+	s.forming = &models.Candle{
+		Symbol: s.symbol, Interval: s.interval, OpenTime: openTime, CloseTime: openTime.Add(s.duration - time.Millisecond),
+		Open: price, High: price, Low: price, Close: price,
+	}
+}
+
+// missedCandles reports whether at is more than one interval after the forming
+// candle, so whole candles went by without ticks.
+func (s *indicatorSeries) missedCandles(at time.Time) bool {
+	return s.forming != nil && bitcoin.CandleOpenTime(at, s.duration).After(s.forming.OpenTime.Add(s.duration))
+}
+
+// expired reports whether the series' forming candle is over, so a series no tick
+// updates is out of date. A series loaded without candles expires indicatorRetryDelay
+// after loading.
+func (s *indicatorSeries) expired(now time.Time) bool {
+	if s.forming == nil {
+		return now.Sub(s.loadedAt) >= indicatorRetryDelay
+	}
+	return bitcoin.CandleOpenTime(now, s.duration).After(s.forming.OpenTime)
+}
+
+// commit folds a closed candle into the series and its indicators.
+func (s *indicatorSeries) commit(candle models.Candle) {
+	s.closed = append(s.closed, candle)
+	if len(s.closed) > s.keep {
+		s.closed = s.closed[len(s.closed)-s.keep:]
+	}
+	for _, indicator := range s.indicators {
+		indicator.update(candle)
+	}
+}
+
+// indicator returns an indicator of the series, replaying the closed candles into it
+// the first time it's asked for. slow is only used by MACD and deviations by Bollinger.
+func (s *indicatorSeries) indicator(name string, period, slow int, deviations float64) *seriesIndicator {
+	key := fmt.Sprintf("%s:%d:%d:%g", name, period, slow, deviations)
+	if indicator, ok := s.indicators[key]; ok {
+		return indicator
+	}
+
+	indicator := newSeriesIndicator(name, period, slow, deviations)
+	for _, candle := range s.closed {
+		indicator.update(candle)
+	}
+	s.indicators[key] = indicator
+	return indicator
+}
+
+// reading returns an alert's indicator reading on the forming candle or, with closed,
+// on the last closed candle, and the open time of that candle.
+func (s *indicatorSeries) reading(alert *storage.Alert, closed bool) (*bitcoin.IndicatorReading, time.Time, bool) {
+	var candle models.Candle
+	var value func(indicator *seriesIndicator) (indicatorValue, bool)
+	switch {
+	case closed && len(s.closed) > 0:
+		candle = s.closed[len(s.closed)-1]
+		value = func(indicator *seriesIndicator) (indicatorValue, bool) { return indicator.value() }
+	case !closed && s.forming != nil:
+		candle = *s.forming
+		value = func(indicator *seriesIndicator) (indicatorValue, bool) { return indicator.peek(candle) }
+	default:
+		return nil, time.Time{}, false
+	}
+	period, slow := alert.GetPeriod(), alert.GetSlowPeriod()
+
+	var reading *bitcoin.IndicatorReading
+	var ok bool
+	switch alert.Indicator {
+	case "rsi", "atr":
+		var v indicatorValue
+		v, ok = value(s.indicator(alert.Indicator, period, 0, 0))
+		reading = &bitcoin.IndicatorReading{Value: v.Value, Level: alert.Threshold}
+	case "sma", "ema":
+		var v indicatorValue
+		v, ok = value(s.indicator(alert.Indicator, period, 0, 0))
+		reading = &bitcoin.IndicatorReading{Value: candle.Close, Level: v.Value}
+	case "sma_cross", "ema_cross":
+		average := strings.TrimSuffix(alert.Indicator, "_cross")
+		fast, fastOK := value(s.indicator(average, period, 0, 0))
+		slowValue, slowOK := value(s.indicator(average, slow, 0, 0))
+		reading, ok = &bitcoin.IndicatorReading{Value: fast.Value, Level: slowValue.Value}, fastOK && slowOK
+	case "macd":
+		var v indicatorValue
+		v, ok = value(s.indicator("macd", period, slow, 0))
+		reading = &bitcoin.IndicatorReading{Value: v.Value, Level: v.Signal}
+	case "bollinger":
+		var v indicatorValue
+		v, ok = value(s.indicator("bollinger", period, 0, alert.GetBollingerDeviations()))
+		level := v.Upper
+		if strings.HasSuffix(alert.IndicatorCondition, "below") {
+			level = v.Lower
+		}
+		reading = &bitcoin.IndicatorReading{Value: candle.Close, Level: level}
+	case "volume":
+		// The forming candle's volume isn't known from ticks, so the last closed candle
+		// is compared with the average of the closed candles up to it
+		if len(s.closed) == 0 || s.tickClosed {
+			return nil, time.Time{}, false
+		}
+		last := s.closed[len(s.closed)-1]
+		var v indicatorValue
+		v, ok = s.indicator("volume", period, 0, 0).value()
+		reading, candle = &bitcoin.IndicatorReading{Value: last.Volume, Level: v.Value}, last
+	default:
+		return nil, time.Time{}, false
+	}
+	return reading, candle.OpenTime, ok
+}
+
+// expressionValue returns the value of an expression's indicator call on the forming
+// candle. Volumes are those of the closed candles, as in volume alerts.
+func (s *indicatorSeries) expressionValue(ref expression.IndicatorRef) (float64, bool) {
+	if s.forming == nil {
+		return 0, false
+	}
+	forming := *s.forming
+	period := 0
+	if len(ref.Args) > 0 {
+		period = int(ref.Args[0])
+	}
+
+	switch ref.Name {
+	case "sma", "ema", "rsi", "atr":
+		value, ok := s.indicator(ref.Name, period, 0, 0).peek(forming)
+		return value.Value, ok
+	case "macd", "macd_signal", "macd_hist":
+		value, ok := s.indicator("macd", period, int(ref.Args[1]), 0).peek(forming)
+		switch ref.Name {
+		case "macd_signal":
+			return value.Signal, ok
+		case "macd_hist":
+			return value.Value - value.Signal, ok
+		}
+		return value.Value, ok
+	case "bb_upper", "bb_middle", "bb_lower":
+		value, ok := s.indicator("bollinger", period, 0, ref.Args[1]).peek(forming)
+		switch ref.Name {
+		case "bb_upper":
+			return value.Upper, ok
+		case "bb_lower":
+			return value.Lower, ok
+		}
+		return value.Value, ok
+	case "volume_avg":
+		if s.tickClosed {
+			return 0, false
+		}
+		value, ok := s.indicator("volume", period, 0, 0).peek(forming)
+		return value.Value, ok
+	case "candle_volume":
+		if len(s.closed) == 0 || s.tickClosed {
+			return 0, false
+		}
+		return s.closed[len(s.closed)-1].Volume, true
+	default:
+		return 0, false
+	}
+}
+
+// newSeriesIndicator wraps an indicator of the indicators package.
+func newSeriesIndicator(name string, period, slow int, deviations float64) *seriesIndicator {
+	switch name {
+	case "sma":
+		sma := indicators.NewSMA(period)
+		return closeIndicator(sma.Update, sma.Peek, sma.Value)
+	case "ema":
+		ema := indicators.NewEMA(period)
+		return closeIndicator(ema.Update, ema.Peek, ema.Value)
+	case "rsi":
+		rsi := indicators.NewRSI(period)
+		return closeIndicator(rsi.Update, rsi.Peek, rsi.Value)
+	case "macd":
+		macd := indicators.NewMACD(period, slow, macdSignalPeriod)
+		return &seriesIndicator{
+			update: func(candle models.Candle) { macd.Update(candle.Close) },
+			peek: func(candle models.Candle) (indicatorValue, bool) {
+				value, ok := macd.Peek(candle.Close)
+				return indicatorValue{Value: value.MACD, Signal: value.Signal}, ok
+			},
+			value: func() (indicatorValue, bool) {
+				value, ok := macd.Value()
+				return indicatorValue{Value: value.MACD, Signal: value.Signal}, ok
+			},
+		}
+	case "bollinger":
+		bollinger := indicators.NewBollinger(period, deviations)
+		return &seriesIndicator{
+			update: func(candle models.Candle) { bollinger.Update(candle.Close) },
+			peek: func(candle models.Candle) (indicatorValue, bool) {
+				bands, ok := bollinger.Peek(candle.Close)
+				return indicatorValue{Value: bands.Middle, Upper: bands.Upper, Lower: bands.Lower}, ok
+			},
+			value: func() (indicatorValue, bool) {
+				bands, ok := bollinger.Value()
+				return indicatorValue{Value: bands.Middle, Upper: bands.Upper, Lower: bands.Lower}, ok
+			},
+		}
+	case "volume":
+		sma := indicators.NewSMA(period)
+		value := func() (indicatorValue, bool) {
+			value, ok := sma.Value()
+			return indicatorValue{Value: value}, ok
+		}
+		return &seriesIndicator{
+			update: func(candle models.Candle) { sma.Update(candle.Volume) },
+			peek:   func(models.Candle) (indicatorValue, bool) { return value() },
+			value:  value,
+		}
+	case "atr":
+		atr := indicators.NewATR(period)
+		bar := func(candle models.Candle) indicators.Bar {
+			return indicators.Bar{High: candle.High, Low: candle.Low, Close: candle.Close}
+		}
+		return &seriesIndicator{
+			update: func(candle models.Candle) { atr.Update(bar(candle)) },
+			peek: func(candle models.Candle) (indicatorValue, bool) {
+				value, ok := atr.Peek(bar(candle))
+				return indicatorValue{Value: value}, ok
+			},
+			value: func() (indicatorValue, bool) {
+				value, ok := atr.Value()
+				return indicatorValue{Value: value}, ok
+			},
+		}
+	default:
+		return &seriesIndicator{
+			update: func(models.Candle) {},
+			peek:   func(models.Candle) (indicatorValue, bool) { return indicatorValue{}, false },
+			value:  func() (indicatorValue, bool) { return indicatorValue{}, false },
+		}
+	}
+}
+
+// closeIndicator wraps an indicator computed on candle closes.
+func closeIndicator(update func(float64), peek func(float64) (float64, bool), value func() (float64, bool)) *seriesIndicator {
+	return &seriesIndicator{
+		update: func(candle models.Candle) { update(candle.Close) },
+		peek: func(candle models.Candle) (indicatorValue, bool) {
+			v, ok := peek(candle.Close)
+			return indicatorValue{Value: v}, ok
+		},
+		value: func() (indicatorValue, bool) {
+			v, ok := value()
+			return indicatorValue{Value: v}, ok
+		},
+	}
+}
+
+func seriesKey(symbol, interval string) string {
+	return strings.ToUpper(symbol) + "|" + interval
+}
+
+// tickTime returns when a tick happened, falling back to now for ticks without a timestamp.
+func tickTime(tick *bitcoin.PriceData) time.Time {
+	if tick.Timestamp.IsZero() {
+		return time.Now()
+	}
+	return tick.Timestamp
+}
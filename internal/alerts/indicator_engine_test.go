@@ -0,0 +1,231 @@
+package alerts
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cgallonv/btc-alerta-de-precio/internal/bitcoin"
+	"github.com/cgallonv/btc-alerta-de-precio/internal/bitcoin/fakebinance"
+	"github.com/cgallonv/btc-alerta-de-precio/internal/expression"
+	"github.com/cgallonv/btc-alerta-de-precio/internal/indicators"
+	"github.com/cgallonv/btc-alerta-de-precio/internal/storage"
+)
+
+// TestIndicatorEngine_Reading checks that the engine seeds a series from Binance klines
+// in the background and then follows the ticks incrementally: the forming candle is
+// peeked, and a tick in the next interval closes it.
+func TestIndicatorEngine_Reading(t *testing.T) {
+	server := fakebinance.New()
+	defer server.Close()
+	server.AddSymbol(fakebinance.Symbol{Symbol: "BTCUSDT", BaseAsset: "BTC", QuoteAsset: "USDT", Price: 60000})
+
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	closes := make([]float64, 60)
+	for i := range closes {
+		closes[i] = 60000 + float64(i%7)*150 - float64(i%3)*200
+		server.AddKlines("BTCUSDT", "1m", fakebinance.Kline{OpenTime: start.Add(time.Duration(i) * time.Minute),
+			Open: closes[i], High: closes[i] + 50, Low: closes[i] - 50, Close: closes[i], Volume: 1})
+	}
+
+	engine := NewIndicatorEngine(nil, bitcoin.NewBinanceClient("", "", server.URL, nil))
+	alert := &storage.Alert{ID: 1, Symbol: "BTCUSDT", Type: "indicator", Indicator: "rsi", Interval: "1m", Period: 5,
+		IndicatorCondition: "below", Threshold: 30}
+
+	expected := indicators.NewRSI(5)
+	for _, v := range closes {
+		expected.Update(v)
+	}
+
+	// The first tick starts loading the series without waiting for it
+	first := start.Add(60*time.Minute + 10*time.Second)
+	assert.Nil(t, engine.Reading(alert, &bitcoin.PriceData{Symbol: "BTCUSDT", Price: 59000, Timestamp: first}))
+	waitForSeries(t, engine)
+
+	reading := engine.Reading(alert, &bitcoin.PriceData{Symbol: "BTCUSDT", Price: 59000, Timestamp: first})
+	require.NotNil(t, reading)
+	value, _ := expected.Peek(59000)
+	assert.InDelta(t, value, reading.Value, 1e-9)
+	assert.Equal(t, 30.0, reading.Level)
+	assert.Nil(t, reading.Previous)
+
+	// The next minute closes the 59,000 candle and the previous reading is kept
+	second := engine.Reading(alert, &bitcoin.PriceData{Symbol: "BTCUSDT", Price: 61000, Timestamp: first.Add(time.Minute)})
+	require.NotNil(t, second)
+	expected.Update(59000)
+	value, _ = expected.Peek(61000)
+	assert.InDelta(t, value, second.Value, 1e-9)
+	require.NotNil(t, second.Previous)
+	assert.InDelta(t, reading.Value, second.Previous.Value, 1e-9)
+
+	// The series was loaded once and then updated from the ticks alone
+	assert.Equal(t, 1, server.Requests("/api/v3/klines"))
+}
+
+// TestIndicatorEngine_ConditionReadings checks the readings of a composite alert's
+// indicator conditions, including volume, which is reloaded once a candle closes
+// because ticks carry no candle volume.
+func TestIndicatorEngine_ConditionReadings(t *testing.T) {
+	server := fakebinance.New()
+	defer server.Close()
+	server.AddSymbol(fakebinance.Symbol{Symbol: "BTCUSDT", BaseAsset: "BTC", QuoteAsset: "USDT", Price: 60000})
+
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < 30; i++ {
+		server.AddKlines("BTCUSDT", "1m", fakebinance.Kline{OpenTime: start.Add(time.Duration(i) * time.Minute),
+			Open: 60000, High: 60100, Low: 59900, Close: 60000 + float64(i), Volume: float64(10 + i%5)})
+	}
+
+	engine := NewIndicatorEngine(nil, bitcoin.NewBinanceClient("", "", server.URL, nil))
+	alert := &storage.Alert{ID: 7, Symbol: "BTCUSDT", Type: "composite", Conditions: &storage.Condition{Op: "and", Conditions: []storage.Condition{
+		{Type: "below", TargetPrice: 70000},
+		{Type: "indicator", Indicator: "volume", Interval: "1m", Period: 5, IndicatorCondition: "above"},
+		{Type: "indicator", Indicator: "sma", Interval: "1m", Period: 5, IndicatorCondition: "above"},
+	}}}
+
+	// The last closed candle (volume 14) against the average of the last 5 (12)
+	first := start.Add(30*time.Minute + 10*time.Second)
+	assert.Empty(t, engine.ConditionReadings(alert, &bitcoin.PriceData{Symbol: "BTCUSDT", Price: 60100, Timestamp: first}))
+	waitForSeries(t, engine)
+	readings := engine.ConditionReadings(alert, &bitcoin.PriceData{Symbol: "BTCUSDT", Price: 60100, Timestamp: first})
+	require.Len(t, readings, 2)
+	assert.Equal(t, 14.0, readings["1"].Value)
+	assert.Equal(t, 12.0, readings["1"].Level)
+	assert.Equal(t, 60100.0, readings["2"].Value)
+
+	// Binance has the full volume of the candle the ticks closed; the volume reading
+	// waits for it to be reloaded while the SMA goes on
+	server.AddKlines("BTCUSDT", "1m", fakebinance.Kline{OpenTime: start.Add(30 * time.Minute),
+		Open: 60000, High: 60100, Low: 60000, Close: 60100, Volume: 40})
+	readings = engine.ConditionReadings(alert, &bitcoin.PriceData{Symbol: "BTCUSDT", Price: 60200, Timestamp: first.Add(time.Minute)})
+	assert.Len(t, readings, 1)
+	assert.Contains(t, readings, "2")
+	waitForSeries(t, engine)
+	readings = engine.ConditionReadings(alert, &bitcoin.PriceData{Symbol: "BTCUSDT", Price: 60200, Timestamp: first.Add(time.Minute + time.Second)})
+	require.Len(t, readings, 2)
+	assert.Equal(t, 40.0, readings["1"].Value)
+	assert.InDelta(t, (11+12+13+14+40)/5.0, readings["1"].Level, 1e-9)
+	require.NotNil(t, readings["1"].Previous)
+	assert.Equal(t, 14.0, readings["1"].Previous.Value)
+	assert.Equal(t, 2, server.Requests("/api/v3/klines"))
+}
+
+// TestIndicatorEngine_ExpressionValues checks the values of an expression's indicator
+// calls, on several intervals of the same symbol.
+func TestIndicatorEngine_ExpressionValues(t *testing.T) {
+	server := fakebinance.New()
+	defer server.Close()
+	server.AddSymbol(fakebinance.Symbol{Symbol: "BTCUSDT", BaseAsset: "BTC", QuoteAsset: "USDT", Price: 60000})
+
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < 30; i++ {
+		server.AddKlines("BTCUSDT", "1m", fakebinance.Kline{OpenTime: start.Add(time.Duration(i) * time.Minute),
+			Open: 60000, High: 60100, Low: 59900, Close: 60000 + float64(i), Volume: float64(10 + i%5)})
+	}
+	for i := 0; i < 10; i++ {
+		server.AddKlines("BTCUSDT", "5m", fakebinance.Kline{OpenTime: start.Add(time.Duration(i) * 5 * time.Minute),
+			Open: 60000, High: 60100, Low: 59900, Close: 60000 + float64(10*i), Volume: 50})
+	}
+
+	program, err := expression.Compile(`sma(5, "1m") < sma(3, "5m") && candle_volume("1m") > volume_avg(5, "1m") && macd_hist(3, 6, "1m") > 0`)
+	require.NoError(t, err)
+
+	engine := NewIndicatorEngine(nil, bitcoin.NewBinanceClient("", "", server.URL, nil))
+	tick := &bitcoin.PriceData{Symbol: "BTCUSDT", Price: 60100, Timestamp: start.Add(50*time.Minute + 10*time.Second)}
+	assert.Empty(t, engine.ExpressionValues("BTCUSDT", program.Indicators(), tick))
+	waitForSeries(t, engine)
+	values := engine.ExpressionValues("BTCUSDT", program.Indicators(), tick)
+
+	// Moving averages include the forming candle at the tick's price
+	assert.InDelta(t, (60026+60027+60028+60029+60100)/5.0, values[`sma(5,"1m")`], 1e-9)
+	assert.InDelta(t, (60080+60090+60100)/3.0, values[`sma(3,"5m")`], 1e-9)
+	assert.Equal(t, 14.0, values[`candle_volume("1m")`])
+	assert.Equal(t, 12.0, values[`volume_avg(5,"1m")`])
+	assert.Contains(t, values, `macd_hist(3,6,"1m")`)
+
+	result, err := program.Eval(expression.Env{Indicators: values})
+	require.NoError(t, err)
+	assert.True(t, result)
+}
+
+// TestIndicatorEngine_CrossingOnClosedCandles checks that crossings are judged on
+// closed candles: a forming candle that crosses and comes back doesn't count, and a
+// candle that closes across is seen once.
+func TestIndicatorEngine_CrossingOnClosedCandles(t *testing.T) {
+	server := fakebinance.New()
+	defer server.Close()
+	server.AddSymbol(fakebinance.Symbol{Symbol: "BTCUSDT", BaseAsset: "BTC", QuoteAsset: "USDT", Price: 60000})
+
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < 10; i++ {
+		server.AddKlines("BTCUSDT", "1m", fakebinance.Kline{OpenTime: start.Add(time.Duration(i) * time.Minute),
+			Open: 60000, High: 60000, Low: 60000, Close: 60000, Volume: 1})
+	}
+
+	engine := NewIndicatorEngine(nil, bitcoin.NewBinanceClient("", "", server.URL, nil))
+	alert := &storage.Alert{ID: 3, Symbol: "BTCUSDT", Type: "indicator", Indicator: "sma", Interval: "1m", Period: 5,
+		IndicatorCondition: "cross_above"}
+	crossed := func(price float64, at time.Time) bool {
+		reading := engine.Reading(alert, &bitcoin.PriceData{Symbol: "BTCUSDT", Price: price, Timestamp: at})
+		require.NotNil(t, reading)
+		previous := reading.Previous
+		return previous != nil && previous.Value <= previous.Level && reading.Value > reading.Level
+	}
+
+	minute := start.Add(10 * time.Minute)
+	engine.Reading(alert, &bitcoin.PriceData{Symbol: "BTCUSDT", Price: 60000, Timestamp: minute})
+	waitForSeries(t, engine)
+	assert.False(t, crossed(60000, minute.Add(time.Second)))
+
+	// The forming candle spikes above its average and falls back before closing
+	assert.False(t, crossed(61000, minute.Add(20*time.Second)))
+	assert.False(t, crossed(60000, minute.Add(40*time.Second)))
+
+	// The next candle closes above, seen on the first tick after it closes only
+	assert.False(t, crossed(61000, minute.Add(70*time.Second)))
+	assert.False(t, crossed(61000, minute.Add(110*time.Second)))
+	assert.True(t, crossed(61000, minute.Add(2*time.Minute+time.Second)))
+	assert.False(t, crossed(61000, minute.Add(2*time.Minute+2*time.Second)))
+}
+
+// TestIndicatorEngine_Snapshot checks that snapshots load in the background and apart
+// from the series the alerts tick.
+func TestIndicatorEngine_Snapshot(t *testing.T) {
+	server := fakebinance.New()
+	defer server.Close()
+	server.AddSymbol(fakebinance.Symbol{Symbol: "BTCUSDT", BaseAsset: "BTC", QuoteAsset: "USDT", Price: 60000})
+
+	current := bitcoin.CandleOpenTime(time.Now(), time.Hour)
+	for i := 30; i >= 0; i-- {
+		server.AddKlines("BTCUSDT", "1h", fakebinance.Kline{OpenTime: current.Add(-time.Duration(i) * time.Hour),
+			Open: 60000, High: 60100, Low: 59900, Close: 60000 + float64(i%4)*100, Volume: 1})
+	}
+
+	engine := NewIndicatorEngine(nil, bitcoin.NewBinanceClient("", "", server.URL, nil))
+	_, err := engine.Snapshot("BTCUSDT", "1h")
+	require.ErrorIs(t, err, ErrIndicatorsLoading)
+	waitForSeries(t, engine)
+
+	snapshot, err := engine.Snapshot("BTCUSDT", "1h")
+	require.NoError(t, err)
+	assert.Equal(t, current, snapshot.OpenTime)
+	require.NotNil(t, snapshot.SMA)
+	assert.Nil(t, snapshot.MACD, "MACD needs more candles")
+
+	engine.mux.Lock()
+	defer engine.mux.Unlock()
+	assert.Empty(t, engine.series)
+	assert.Len(t, engine.snapshots, 1)
+}
+
+// waitForSeries waits until the series the engine started loading are installed.
+func waitForSeries(t *testing.T, engine *IndicatorEngine) {
+	t.Helper()
+	require.Eventually(t, func() bool {
+		engine.mux.Lock()
+		defer engine.mux.Unlock()
+		return len(engine.loading) == 0
+	}, 2*time.Second, 5*time.Millisecond)
+}
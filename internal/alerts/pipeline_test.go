@@ -0,0 +1,289 @@
+package alerts_test
+
+import (
+	"context"
+	"net/http"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cgallonv/btc-alerta-de-precio/config"
+	"github.com/cgallonv/btc-alerta-de-precio/internal/adapters"
+	"github.com/cgallonv/btc-alerta-de-precio/internal/alerts"
+	"github.com/cgallonv/btc-alerta-de-precio/internal/bitcoin"
+	"github.com/cgallonv/btc-alerta-de-precio/internal/bitcoin/fakebinance"
+	"github.com/cgallonv/btc-alerta-de-precio/internal/mocks"
+	"github.com/cgallonv/btc-alerta-de-precio/internal/notifications"
+	"github.com/cgallonv/btc-alerta-de-precio/internal/storage"
+)
+
+// TestAlertPipeline runs polled ticks from a fake Binance through the price monitor,
+// the alert evaluator and the notification sender, with a real database.
+func TestAlertPipeline(t *testing.T) {
+	server := fakebinance.New()
+	defer server.Close()
+	server.AddSymbol(fakebinance.Symbol{Symbol: "BTCUSDT", BaseAsset: "BTC", QuoteAsset: "USDT", Price: 60000})
+	server.AddSymbol(fakebinance.Symbol{Symbol: "ETHUSDT", BaseAsset: "ETH", QuoteAsset: "USDT", Price: 3000, Change24h: 1.5})
+	server.SetPricePath("ETHUSDT", 3000, 3100, 3300, 3400)
+
+	// A Binance hiccup is retried, not reported as a missing tick
+	server.FailNext("/api/v3/ticker/24hr", 1, fakebinance.Fault{Status: http.StatusInternalServerError, Code: -1000, Message: "Internal error"})
+
+	db, manager, sent := newPipeline(t, server)
+
+	alert := &storage.Alert{Name: "ETH breakout", Symbol: "ethusdt", Type: "above", TargetPrice: 3200, IsActive: true,
+		Email: "test@example.com", EnableEmail: true}
+	require.NoError(t, manager.CreateAlert(alert))
+
+	require.NoError(t, manager.Start(context.Background()))
+	defer manager.Stop()
+
+	select {
+	case data := <-sent:
+		assert.Equal(t, alert.ID, data.AlertID)
+		assert.Equal(t, 3300.0, data.Price)
+		assert.Equal(t, 1.5, data.Percentage)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for the alert notification")
+	}
+
+	// One-shot alerts fire once, even though the price stays above the target
+	require.Eventually(t, func() bool {
+		return server.Requests("/api/v3/ticker/24hr") > 8
+	}, 5*time.Second, 10*time.Millisecond)
+	manager.Stop()
+	assert.Empty(t, sent)
+
+	stored, err := db.GetAlert(alert.ID)
+	require.NoError(t, err)
+	assert.NotNil(t, stored.LastTriggered)
+	assert.Equal(t, 1, stored.TriggerCount)
+
+	logs, err := db.GetNotificationLogs(alert.ID, 10)
+	require.NoError(t, err)
+	require.Len(t, logs, 1)
+	assert.Equal(t, "sent", logs[0].Status)
+}
+
+// TestAlertPipeline_HysteresisRearms checks that a threshold alert fires once per
+// crossing: it stays disarmed while the price hovers around the target and re-arms
+// only after leaving the hysteresis band.
+func TestAlertPipeline_HysteresisRearms(t *testing.T) {
+	server := fakebinance.New()
+	defer server.Close()
+	server.AddSymbol(fakebinance.Symbol{Symbol: "BTCUSDT", BaseAsset: "BTC", QuoteAsset: "USDT", Price: 69000})
+	// Band edge at 69,650: 69,800 is still inside, 69,500 re-arms the alert
+	server.SetPricePath("BTCUSDT", 69000, 70100, 69800, 70200, 69500, 70300)
+
+	db, manager, sent := newPipeline(t, server)
+
+	alert := &storage.Alert{Name: "BTC 70k", Symbol: "BTCUSDT", Type: "above", TargetPrice: 70000, HysteresisPercent: 0.5,
+		IsActive: true, Email: "test@example.com", EnableEmail: true}
+	require.NoError(t, manager.CreateAlert(alert))
+
+	require.NoError(t, manager.Start(context.Background()))
+	defer manager.Stop()
+
+	for _, price := range []float64{70100, 70300} {
+		select {
+		case data := <-sent:
+			assert.Equal(t, price, data.Price)
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out waiting for the notification at %.0f", price)
+		}
+	}
+
+	require.Eventually(t, func() bool {
+		return server.Requests("/api/v3/ticker/24hr") > 10
+	}, 5*time.Second, 10*time.Millisecond)
+	manager.Stop()
+	assert.Empty(t, sent)
+
+	stored, err := db.GetAlert(alert.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 2, stored.TriggerCount)
+	assert.True(t, stored.Disarmed)
+	assert.False(t, stored.Exhausted)
+}
+
+// TestAlertPipeline_CrossUp checks that a crossing alert created with the price already
+// past its level waits for an actual crossing between two ticks.
+func TestAlertPipeline_CrossUp(t *testing.T) {
+	server := fakebinance.New()
+	defer server.Close()
+	server.AddSymbol(fakebinance.Symbol{Symbol: "BTCUSDT", BaseAsset: "BTC", QuoteAsset: "USDT", Price: 71000})
+	server.SetPricePath("BTCUSDT", 71000, 71500, 69000, 70500)
+
+	db, manager, sent := newPipeline(t, server)
+
+	alert := &storage.Alert{Name: "BTC crosses 70k", Symbol: "BTCUSDT", Type: "cross_up", TargetPrice: 70000,
+		TriggerMode: storage.TriggerModeRecurring, CooldownSeconds: 1,
+		IsActive: true, Email: "test@example.com", EnableEmail: true}
+	require.NoError(t, manager.CreateAlert(alert))
+
+	require.NoError(t, manager.Start(context.Background()))
+	defer manager.Stop()
+
+	select {
+	case data := <-sent:
+		assert.Equal(t, 70500.0, data.Price)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for the crossing notification")
+	}
+
+	require.Eventually(t, func() bool {
+		return server.Requests("/api/v3/ticker/24hr") > 8
+	}, 5*time.Second, 10*time.Millisecond)
+	manager.Stop()
+	assert.Empty(t, sent)
+
+	stored, err := db.GetAlert(alert.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 1, stored.TriggerCount)
+}
+
+// TestAlertPipeline_Composite checks that a composite alert's condition tree is stored
+// as JSON and triggers only once all of its conditions hold.
+func TestAlertPipeline_Composite(t *testing.T) {
+	server := fakebinance.New()
+	defer server.Close()
+	server.AddSymbol(fakebinance.Symbol{Symbol: "BTCUSDT", BaseAsset: "BTC", QuoteAsset: "USDT", Price: 61000, Change24h: -2.5})
+	server.SetPricePath("BTCUSDT", 61000, 59500)
+
+	db, manager, sent := newPipeline(t, server)
+
+	alert := &storage.Alert{Name: "BTC dip", Symbol: "BTCUSDT", Type: "composite",
+		Conditions: &storage.Condition{Op: "and", Conditions: []storage.Condition{
+			{Type: "below", TargetPrice: 60000},
+			{Op: "or", Conditions: []storage.Condition{
+				{Type: "change", Percentage: -2},
+				{Type: "change", Percentage: 5},
+			}},
+		}},
+		IsActive: true, Email: "test@example.com", EnableEmail: true}
+	require.NoError(t, manager.CreateAlert(alert))
+
+	stored, err := db.GetAlert(alert.ID)
+	require.NoError(t, err)
+	assert.Equal(t, alert.Conditions, stored.Conditions)
+
+	require.NoError(t, manager.Start(context.Background()))
+	defer manager.Stop()
+
+	select {
+	case data := <-sent:
+		assert.Equal(t, 59500.0, data.Price)
+		assert.Equal(t, "Bitcoin price below $60000.00 AND (price change of -2.00% OR price change of 5.00%)", data.Message)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for the composite notification")
+	}
+
+	// An invalid tree is rejected when the alert is created
+	invalid := &storage.Alert{Name: "Bad tree", Type: "composite", IsActive: true, Email: "test@example.com", EnableEmail: true,
+		Conditions: &storage.Condition{Op: "and", Conditions: []storage.Condition{{Type: "imbalance", Percentage: 20}}}}
+	assert.Error(t, manager.CreateAlert(invalid))
+}
+
+// TestAlertPipeline_Schedule checks that alerts outside their schedule don't trigger:
+// a "drop" alert ignores the condition, and a "defer" alert notifies it with the price
+// it was met at once its window opens.
+func TestAlertPipeline_Schedule(t *testing.T) {
+	server := fakebinance.New()
+	defer server.Close()
+	server.AddSymbol(fakebinance.Symbol{Symbol: "BTCUSDT", BaseAsset: "BTC", QuoteAsset: "USDT", Price: 61000})
+	server.SetPricePath("BTCUSDT", 61000, 59500, 59800)
+
+	db, manager, sent := newPipeline(t, server)
+
+	// A window two hours from now, which wraps past midnight when needed
+	now := time.Now().In(time.UTC)
+	later := storage.TimeWindow{Start: now.Add(2 * time.Hour).Format("15:04"), End: now.Add(3 * time.Hour).Format("15:04")}
+	newAlert := func(name, outside string) *storage.Alert {
+		return &storage.Alert{Name: name, Symbol: "BTCUSDT", Type: "below", TargetPrice: 60000,
+			Schedule: &storage.Schedule{Windows: []storage.TimeWindow{later}, TimeZone: "UTC", Outside: outside},
+			IsActive: true, Email: "test@example.com", EnableEmail: true}
+	}
+	dropped, deferred := newAlert("Dropped", storage.ScheduleDrop), newAlert("Deferred", storage.ScheduleDefer)
+	require.NoError(t, manager.CreateAlert(dropped))
+	require.NoError(t, manager.CreateAlert(deferred))
+
+	stored, err := db.GetAlert(deferred.ID)
+	require.NoError(t, err)
+	assert.Equal(t, deferred.Schedule, stored.Schedule)
+	assert.False(t, stored.InSchedule)
+	require.NotNil(t, stored.ScheduleOpensAt)
+	assert.WithinDuration(t, now.Add(2*time.Hour), *stored.ScheduleOpensAt, time.Minute)
+
+	require.NoError(t, manager.Start(context.Background()))
+	defer manager.Stop()
+
+	require.Eventually(t, func() bool {
+		stored, err = db.GetAlert(deferred.ID)
+		return err == nil && stored.DeferredAt != nil
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, 59500.0, stored.DeferredPrice)
+	assert.Empty(t, sent)
+
+	// Opening the window delivers the deferred notification on the next tick
+	stored.Schedule.Windows = []storage.TimeWindow{{Start: now.Add(-time.Hour).Format("15:04"), End: now.Add(time.Hour).Format("15:04")}}
+	require.NoError(t, manager.UpdateAlert(stored))
+
+	var data *notifications.NotificationData
+	select {
+	case data = <-sent:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for the deferred notification")
+	}
+
+	assert.Equal(t, deferred.ID, data.AlertID)
+	assert.Equal(t, 59500.0, data.Price)
+	require.False(t, data.DeferredAt.IsZero())
+	notification := &notifications.NotificationData{Alert: stored, DeferredAt: data.DeferredAt}
+	assert.Contains(t, notification.Condition(), "(met at "+data.DeferredAt.UTC().Format("2006-01-02 15:04")+" UTC, outside the alert's schedule)")
+
+	manager.Stop()
+	assert.Empty(t, sent)
+	stored, err = db.GetAlert(dropped.ID)
+	require.NoError(t, err)
+	assert.Nil(t, stored.DeferredAt)
+	assert.Nil(t, stored.LastTriggered)
+
+	// Invalid schedules are rejected when the alert is created
+	invalid := newAlert("Bad zone", storage.ScheduleDefer)
+	invalid.Schedule.TimeZone = "Mars/Olympus"
+	assert.Error(t, manager.CreateAlert(invalid))
+}
+
+// newPipeline wires an alert manager to the fake Binance, a temporary database and
+// a mock sender that reports every notification on the returned channel.
+func newPipeline(t *testing.T, server *fakebinance.Server) (*storage.Database, *alerts.AlertManager, chan *notifications.NotificationData) {
+	t.Helper()
+
+	db, err := storage.NewDatabase(filepath.Join(t.TempDir(), "alerts.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	cfg := &config.Config{CheckInterval: 20 * time.Millisecond, BinanceBaseURL: server.URL}
+
+	sent := make(chan *notifications.NotificationData, 4)
+	sender := &mocks.MockNotificationSender{}
+	sender.On("SendAlert", mock.Anything).Run(func(args mock.Arguments) {
+		sent <- args.Get(0).(*notifications.NotificationData)
+	}).Return(nil)
+
+	manager, err := alerts.NewAlertManager(
+		adapters.NewConfigAdapter(cfg),
+		sender,
+		adapters.NewCompositeAlertEvaluator(adapters.NewAlertEvaluator()),
+		db,
+		db,
+		bitcoin.NewBinanceClient("", "", server.URL, nil),
+		nil,
+	)
+	require.NoError(t, err)
+	return db, manager, sent
+}
@@ -0,0 +1,228 @@
+// Package alerts provides functionality for monitoring Bitcoin prices
+// and managing price-based alerts.
+package alerts
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cgallonv/btc-alerta-de-precio/internal/bitcoin"
+	"github.com/cgallonv/btc-alerta-de-precio/internal/errors"
+	"github.com/cgallonv/btc-alerta-de-precio/internal/interfaces"
+	"github.com/cgallonv/btc-alerta-de-precio/internal/storage/models"
+)
+
+// Default aggregation settings used when the configuration leaves them unset.
+const (
+	defaultOutlierBandPercent = 1.0
+	defaultPriceQuorum        = 2
+)
+
+// PriceAggregator is a price oracle that queries several providers in parallel
+// and emits the median of their prices. Quotes deviating more than bandPercent
+// from the median are rejected, and a price is only emitted when at least quorum
+// quotes remain, so a single bad print cannot fire an alert on its own.
+// The aggregator itself implements interfaces.PriceProvider.
+//
+// Example usage:
+//
+//	aggregator := NewPriceAggregator(providers, 1.0, 2, tickerStorage)
+//	price, err := aggregator.GetSymbolPrice("BTCUSDT")
+//	if err != nil {
+//	    log.Printf("Error: %v", err)
+//	    return
+//	}
+//	for _, quote := range price.Quotes {
+//	    log.Printf("%s: $%.2f (rejected: %v)", quote.Source, quote.Price, quote.Rejected)
+//	}
+type PriceAggregator struct {
+	providers     []interfaces.PriceProvider
+	bandPercent   float64
+	quorum        int
+	tickerStorage *bitcoin.TickerStorage // optional, stores the aggregate and its quotes
+}
+
+// NewPriceAggregator creates a median price oracle over the given providers.
+// bandPercent is the maximum deviation from the median, in percent, for a quote to be
+// accepted; quorum is the minimum number of accepted quotes. Non-positive values fall
+// back to 1% and 2 quotes. The quorum is capped at the number of providers.
+//
+// Example usage:
+//
+//	aggregator := NewPriceAggregator(providers, 0.5, 3, tickerStorage)
+func NewPriceAggregator(
+	providers []interfaces.PriceProvider,
+	bandPercent float64,
+	quorum int,
+	tickerStorage *bitcoin.TickerStorage,
+) *PriceAggregator {
+	if bandPercent <= 0 {
+		bandPercent = defaultOutlierBandPercent
+	}
+	if quorum <= 0 {
+		quorum = defaultPriceQuorum
+	}
+	if quorum > len(providers) {
+		quorum = len(providers)
+	}
+
+	return &PriceAggregator{
+		providers:     providers,
+		bandPercent:   bandPercent,
+		quorum:        quorum,
+		tickerStorage: tickerStorage,
+	}
+}
+
+// Name describes the aggregated providers.
+func (a *PriceAggregator) Name() string {
+	names := make([]string, 0, len(a.providers))
+	for _, provider := range a.providers {
+		names = append(names, provider.Name())
+	}
+	return fmt.Sprintf("Median(%s)", strings.Join(names, ", "))
+}
+
+// GetSymbolPrice queries every provider in parallel and returns the median price.
+// The returned PriceData has Source set to bitcoin.SourceAggregate and lists every
+// quote received in Quotes, with outliers marked as rejected. The 24h change comes
+// from the Binance quote when it was accepted, otherwise from the median of the
+// accepted quotes.
+//
+// Example usage:
+//
+//	price, err := aggregator.GetSymbolPrice("BTCUSDT")
+//	if err != nil {
+//	    return err
+//	}
+//	log.Printf("Median price: $%.2f", price.Price)
+func (a *PriceAggregator) GetSymbolPrice(symbol string) (*bitcoin.PriceData, error) {
+	quotes, providerErrors := a.fetchQuotes(symbol)
+
+	if len(quotes) == 0 {
+		return nil, errors.WrapError(errors.CombineErrors(providerErrors),
+			errors.ErrPriceAPIUnavailable.Code, errors.ErrPriceAPIUnavailable.Message)
+	}
+
+	prices := make([]float64, 0, len(quotes))
+	for _, quote := range quotes {
+		prices = append(prices, quote.Price)
+	}
+	median := medianOf(prices)
+
+	var accepted []bitcoin.PriceQuote
+	for i := range quotes {
+		deviation := math.Abs(quotes[i].Price-median) / median * 100
+		if deviation > a.bandPercent {
+			quotes[i].Rejected = true
+			log.Printf("⚠️ Rejecting %s %s quote $%.2f: %.2f%% from median $%.2f (band %.2f%%)",
+				quotes[i].Source, symbol, quotes[i].Price, deviation, median, a.bandPercent)
+			continue
+		}
+		accepted = append(accepted, quotes[i])
+	}
+
+	if len(accepted) < a.quorum {
+		return nil, errors.WrapError(errors.CombineErrors(providerErrors),
+			errors.ErrPriceQuorumNotMet.Code, errors.ErrPriceQuorumNotMet.Message).
+			WithField("symbol", symbol).
+			WithField("accepted", len(accepted)).
+			WithField("quorum", a.quorum)
+	}
+
+	acceptedPrices := make([]float64, 0, len(accepted))
+	acceptedPercents := make([]float64, 0, len(accepted))
+	for _, quote := range accepted {
+		acceptedPrices = append(acceptedPrices, quote.Price)
+		acceptedPercents = append(acceptedPercents, quote.PriceChangePercent)
+	}
+
+	priceChangePercent := medianOf(acceptedPercents)
+	var ticker *models.TickerData
+	for _, quote := range accepted {
+		if quote.Source == bitcoin.SourceBinance {
+			priceChangePercent = quote.PriceChangePercent
+			ticker = quote.Ticker
+			break
+		}
+	}
+
+	priceData := &bitcoin.PriceData{
+		Symbol:             strings.ToUpper(symbol),
+		Price:              medianOf(acceptedPrices),
+		PriceChangePercent: priceChangePercent,
+		Currency:           "USD",
+		Timestamp:          time.Now(),
+		Source:             bitcoin.SourceAggregate,
+		Quotes:             quotes,
+		Ticker:             ticker,
+	}
+
+	if a.tickerStorage != nil {
+		if err := a.tickerStorage.StoreAggregate(priceData); err != nil {
+			log.Printf("❌ Error storing aggregated price: %v", err)
+			// Don't return error here, continue with price update
+		}
+	}
+
+	return priceData, nil
+}
+
+// fetchQuotes queries all providers concurrently, keeping the provider order in the result.
+func (a *PriceAggregator) fetchQuotes(symbol string) ([]bitcoin.PriceQuote, []error) {
+	results := make([]*bitcoin.PriceData, len(a.providers))
+	failures := make([]error, len(a.providers))
+
+	var wg sync.WaitGroup
+	for i, provider := range a.providers {
+		wg.Add(1)
+		go func(i int, provider interfaces.PriceProvider) {
+			defer wg.Done()
+			results[i], failures[i] = provider.GetSymbolPrice(symbol)
+		}(i, provider)
+	}
+	wg.Wait()
+
+	var quotes []bitcoin.PriceQuote
+	var providerErrors []error
+	for i, provider := range a.providers {
+		if failures[i] != nil {
+			log.Printf("❌ %s failed to provide %s price: %v", provider.Name(), symbol, failures[i])
+			providerErrors = append(providerErrors, failures[i])
+			continue
+		}
+		if results[i] == nil || results[i].Price <= 0 {
+			providerErrors = append(providerErrors, fmt.Errorf("%s returned no price", provider.Name()))
+			continue
+		}
+		quotes = append(quotes, bitcoin.PriceQuote{
+			Source:             results[i].Source,
+			Price:              results[i].Price,
+			PriceChangePercent: results[i].PriceChangePercent,
+			Ticker:             results[i].Ticker,
+		})
+	}
+
+	return quotes, providerErrors
+}
+
+// medianOf returns the median of values, averaging the middle pair for even counts.
+func medianOf(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[mid-1] + sorted[mid]) / 2
+	}
+	return sorted[mid]
+}
@@ -0,0 +1,96 @@
+package alerts
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cgallonv/btc-alerta-de-precio/internal/bitcoin"
+	"github.com/cgallonv/btc-alerta-de-precio/internal/errors"
+	"github.com/cgallonv/btc-alerta-de-precio/internal/interfaces"
+	"github.com/cgallonv/btc-alerta-de-precio/internal/mocks"
+)
+
+func newQuotingProvider(source string, price, percent float64) *mocks.MockPriceProvider {
+	provider := newMockProvider(source)
+	provider.On("GetSymbolPrice", "BTCUSDT").Return(&bitcoin.PriceData{
+		Symbol:             "BTCUSDT",
+		Price:              price,
+		PriceChangePercent: percent,
+		Source:             source,
+	}, nil)
+	return provider
+}
+
+func TestPriceAggregator_GetSymbolPrice(t *testing.T) {
+	tests := []struct {
+		name          string
+		providers     []interfaces.PriceProvider
+		quorum        int
+		wantPrice     float64
+		wantPercent   float64
+		wantRejected  []string
+		wantErrorCode string
+	}{
+		{
+			name: "median of agreeing quotes uses Binance change",
+			providers: []interfaces.PriceProvider{
+				newQuotingProvider(bitcoin.SourceBinance, 50000, 2.5),
+				newQuotingProvider(bitcoin.SourceCoinbase, 50100, 3.0),
+				newQuotingProvider(bitcoin.SourceKraken, 49950, 1.0),
+			},
+			quorum:      2,
+			wantPrice:   50000,
+			wantPercent: 2.5,
+		},
+		{
+			name: "bad print is rejected",
+			providers: []interfaces.PriceProvider{
+				newQuotingProvider(bitcoin.SourceBinance, 50000, 2.5),
+				newQuotingProvider(bitcoin.SourceCoinbase, 50100, 3.0),
+				newQuotingProvider(bitcoin.SourceKraken, 35000, -30.0),
+			},
+			quorum:       2,
+			wantPrice:    50050,
+			wantPercent:  2.5,
+			wantRejected: []string{bitcoin.SourceKraken},
+		},
+		{
+			name: "quorum not met",
+			providers: []interfaces.PriceProvider{
+				newQuotingProvider(bitcoin.SourceBinance, 50000, 2.5),
+				newQuotingProvider(bitcoin.SourceCoinbase, 60000, 3.0),
+			},
+			quorum:        2,
+			wantErrorCode: errors.ErrPriceQuorumNotMet.Code,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			aggregator := NewPriceAggregator(tt.providers, 1.0, tt.quorum, nil)
+			price, err := aggregator.GetSymbolPrice("BTCUSDT")
+
+			if tt.wantErrorCode != "" {
+				require.Error(t, err)
+				assert.Equal(t, tt.wantErrorCode, errors.GetErrorCode(err))
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, bitcoin.SourceAggregate, price.Source)
+			assert.InDelta(t, tt.wantPrice, price.Price, 0.001)
+			assert.InDelta(t, tt.wantPercent, price.PriceChangePercent, 0.001)
+			assert.Len(t, price.Quotes, len(tt.providers))
+
+			var rejected []string
+			for _, quote := range price.Quotes {
+				if quote.Rejected {
+					rejected = append(rejected, quote.Source)
+				}
+			}
+			assert.Equal(t, tt.wantRejected, rejected)
+		})
+	}
+}
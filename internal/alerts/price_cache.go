@@ -61,6 +61,7 @@ func (pc *PriceCache) Add(priceData *bitcoin.PriceData) {
 	defer pc.mutex.Unlock()
 
 	entry := interfaces.PriceCacheEntry{
+		Symbol:             priceData.Symbol,
 		Price:              priceData.Price,
 		PriceChangePercent: priceData.PriceChangePercent,
 		Currency:           priceData.Currency,
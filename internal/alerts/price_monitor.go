@@ -5,6 +5,7 @@ package alerts
 import (
 	"context"
 	"log"
+	"strings"
 	"sync"
 	"time"
 
@@ -14,18 +15,19 @@ import (
 )
 
 var (
-	lastLogTime     time.Time
-	lastLoggedPrice float64
+	lastLogTime     = make(map[string]time.Time)
+	lastLoggedPrice = make(map[string]float64)
 	logMutex        sync.RWMutex
 )
 
 // PriceMonitor handles price monitoring and caching operations.
-// It fetches Bitcoin prices at regular intervals, maintains a price history cache,
-// and notifies registered callbacks when prices are updated.
+// It receives Bitcoin prices from the Binance WebSocket stream (falling back to
+// polling the price provider at regular intervals while the stream is down),
+// maintains a price history cache, and notifies registered callbacks when prices are updated.
 //
 // Example usage:
 //
-//	monitor := NewPriceMonitor(configProvider, 20)
+//	monitor := NewPriceMonitor(configProvider, 20, priceProvider, tickerStorage)
 //	monitor.AddPriceUpdateCallback(func(price *bitcoin.PriceData) {
 //	    log.Printf("New price: $%.2f", price.Price)
 //	})
@@ -35,17 +37,29 @@ var (
 //	}
 //	defer monitor.Stop()
 type PriceMonitor struct {
-	binanceClient  *bitcoin.BinanceClient
+	priceProvider  interfaces.PriceProvider
+	binanceStream  *bitcoin.BinanceStream // nil when streaming is disabled
+	tickerStorage  *bitcoin.TickerStorage
 	configProvider interfaces.ConfigProvider
 
 	// Monitoring state
 	isMonitoring  bool
 	stopChannel   chan struct{}
+	streamCancel  context.CancelFunc
 	monitoringMux sync.RWMutex
 
-	// Price data cache (replaces database storage)
-	priceCache   *PriceCache
-	lastPrice    *bitcoin.PriceData
+	// Symbols being tracked (always includes bitcoin.DefaultSymbol)
+	symbols    []string
+	symbolsMux sync.RWMutex
+
+	// Streamed tickers are persisted at most once per check interval per symbol
+	lastStoredTicker map[string]time.Time
+	storeMux         sync.Mutex
+
+	// Price data cache per symbol (replaces database storage)
+	cacheSize    int
+	priceCaches  map[string]*PriceCache
+	lastPrices   map[string]*bitcoin.PriceData
 	lastPriceMux sync.RWMutex
 
 	// Current percentage (updated with every price fetch)
@@ -58,7 +72,7 @@ type PriceMonitor struct {
 }
 
 // PriceUpdateCallback is called when price is updated.
-// The callback receives the current price data from Binance.
+// The callback receives the current price data; PriceData.Source tells which provider supplied it.
 //
 // Example usage:
 //
@@ -72,10 +86,12 @@ type PriceUpdateCallback func(current *bitcoin.PriceData)
 // NewPriceMonitor creates a new price monitoring service with cache.
 // The cacheSize parameter determines how many historical price entries to keep.
 // If cacheSize is <= 0, it defaults to 20 entries.
+// The priceProvider is polled while the stream is down; pass a PriceProviderChain
+// to fail over between exchanges.
 //
 // Example usage:
 //
-//	monitor := NewPriceMonitor(configProvider, 20)
+//	monitor := NewPriceMonitor(configProvider, 20, priceProvider, tickerStorage)
 //	if err := monitor.Start(context.Background()); err != nil {
 //	    log.Printf("Error: %v", err)
 //	    return
@@ -83,22 +99,31 @@ type PriceUpdateCallback func(current *bitcoin.PriceData)
 func NewPriceMonitor(
 	configProvider interfaces.ConfigProvider,
 	cacheSize int,
+	priceProvider interfaces.PriceProvider,
 	tickerStorage *bitcoin.TickerStorage,
 ) *PriceMonitor {
 	if cacheSize <= 0 {
 		cacheSize = 20 // Default to 20 entries
 	}
 
-	// Create Binance client with API credentials
-	apiKey := configProvider.GetString("binance.api_key")
-	apiSecret := configProvider.GetString("binance.api_secret")
-	baseURL := configProvider.GetString("binance.base_url")
-	binanceClient := bitcoin.NewBinanceClient(apiKey, apiSecret, baseURL, tickerStorage)
+	// Streamed ticks come from Binance alone, so they would bypass the median oracle
+	var binanceStream *bitcoin.BinanceStream
+	if configProvider.IsPriceAggregationEnabled() {
+		log.Printf("⚠️ Price aggregation enabled, Binance stream disabled")
+	} else if configProvider.IsPriceStreamEnabled() {
+		binanceStream = bitcoin.NewBinanceStream(configProvider.GetString("binance.stream_url"), []string{bitcoin.DefaultSymbol})
+	}
 
 	return &PriceMonitor{
-		binanceClient:        binanceClient,
+		priceProvider:        priceProvider,
+		binanceStream:        binanceStream,
+		tickerStorage:        tickerStorage,
 		configProvider:       configProvider,
-		priceCache:           NewPriceCache(cacheSize),
+		symbols:              []string{bitcoin.DefaultSymbol},
+		lastStoredTicker:     make(map[string]time.Time),
+		cacheSize:            cacheSize,
+		priceCaches:          make(map[string]*PriceCache),
+		lastPrices:           make(map[string]*bitcoin.PriceData),
 		stopChannel:          make(chan struct{}),
 		priceUpdateCallbacks: make([]PriceUpdateCallback, 0),
 	}
@@ -129,7 +154,14 @@ func (pm *PriceMonitor) Start(ctx context.Context) error {
 
 	log.Printf("🔄 Starting Bitcoin price monitoring (interval: %v)", interval)
 
-	go pm.monitoringLoop(ctx, interval)
+	if pm.binanceStream != nil {
+		streamCtx, cancel := context.WithCancel(ctx)
+		pm.streamCancel = cancel
+		log.Printf("🔌 Streaming prices from Binance WebSocket (polling %s as fallback)", pm.priceProvider.Name())
+		go pm.binanceStream.Run(streamCtx, pm.handleStreamTicker)
+	}
+
+	go pm.monitoringLoop(ctx, interval, pm.stopChannel)
 
 	return nil
 }
@@ -150,11 +182,26 @@ func (pm *PriceMonitor) Stop() error {
 	}
 
 	pm.isMonitoring = false
+	if pm.streamCancel != nil {
+		pm.streamCancel()
+		pm.streamCancel = nil
+	}
 	close(pm.stopChannel)
 	pm.stopChannel = make(chan struct{}) // Recreate channel for potential restart
 	return nil
 }
 
+// IsStreaming returns true if prices are currently being received over the WebSocket stream.
+//
+// Example usage:
+//
+//	if !monitor.IsStreaming() {
+//	    log.Printf("Using REST polling fallback")
+//	}
+func (pm *PriceMonitor) IsStreaming() bool {
+	return pm.binanceStream != nil && pm.binanceStream.IsConnected()
+}
+
 // IsMonitoring returns true if price monitoring is active.
 //
 // Example usage:
@@ -168,18 +215,56 @@ func (pm *PriceMonitor) IsMonitoring() bool {
 	return pm.isMonitoring
 }
 
-// GetLastPrice returns the last cached price data.
+// SetSymbols sets the symbols to monitor. bitcoin.DefaultSymbol is always tracked
+// so the dashboard price keeps updating. The stream resubscribes if the set changed.
+//
+// Example usage:
+//
+//	monitor.SetSymbols([]string{"ETHUSDT", "SOLUSDT"})
+func (pm *PriceMonitor) SetSymbols(symbols []string) {
+	tracked := []string{bitcoin.DefaultSymbol}
+	seen := map[string]bool{bitcoin.DefaultSymbol: true}
+	for _, symbol := range symbols {
+		symbol = strings.ToUpper(strings.TrimSpace(symbol))
+		if symbol == "" || seen[symbol] {
+			continue
+		}
+		seen[symbol] = true
+		tracked = append(tracked, symbol)
+	}
+
+	pm.symbolsMux.Lock()
+	pm.symbols = tracked
+	pm.symbolsMux.Unlock()
+
+	if pm.binanceStream != nil {
+		pm.binanceStream.SetSymbols(tracked)
+	}
+}
+
+// GetSymbols returns the symbols currently being monitored.
+//
+// Example usage:
+//
+//	log.Printf("Tracking %v", monitor.GetSymbols())
+func (pm *PriceMonitor) GetSymbols() []string {
+	pm.symbolsMux.RLock()
+	defer pm.symbolsMux.RUnlock()
+	return append([]string(nil), pm.symbols...)
+}
+
+// GetLastPrice returns the last cached price data for a symbol.
 // Returns nil if no price data is available.
 //
 // Example usage:
 //
-//	if price := monitor.GetLastPrice(); price != nil {
+//	if price := monitor.GetLastPrice("BTCUSDT"); price != nil {
 //	    log.Printf("Last price: $%.2f", price.Price)
 //	}
-func (pm *PriceMonitor) GetLastPrice() *bitcoin.PriceData {
+func (pm *PriceMonitor) GetLastPrice(symbol string) *bitcoin.PriceData {
 	pm.lastPriceMux.RLock()
 	defer pm.lastPriceMux.RUnlock()
-	return pm.lastPrice
+	return pm.lastPrices[strings.ToUpper(symbol)]
 }
 
 // GetCurrentPercentage returns the current price change percentage.
@@ -194,17 +279,74 @@ func (pm *PriceMonitor) GetCurrentPercentage() float64 {
 	return pm.currentPercentage
 }
 
-// GetPriceHistory returns cached price history.
+// GetPriceHistory returns cached price history for a symbol.
 // The limit parameter determines how many entries to return.
+// An empty slice is returned for symbols that are not being monitored.
 //
 // Example usage:
 //
-//	history := monitor.GetPriceHistory(24) // Get last 24 entries
+//	history := monitor.GetPriceHistory("BTCUSDT", 24) // Get last 24 entries
 //	for _, entry := range history {
 //	    log.Printf("Price at %s: $%.2f", entry.Timestamp, entry.Price)
 //	}
-func (pm *PriceMonitor) GetPriceHistory(limit int) []interfaces.PriceCacheEntry {
-	return pm.priceCache.GetHistory(limit)
+func (pm *PriceMonitor) GetPriceHistory(symbol string, limit int) []interfaces.PriceCacheEntry {
+	pm.lastPriceMux.RLock()
+	cache, ok := pm.priceCaches[strings.ToUpper(symbol)]
+	pm.lastPriceMux.RUnlock()
+
+	if !ok {
+		return []interfaces.PriceCacheEntry{}
+	}
+	return cache.GetHistory(limit)
+}
+
+// PriceRange returns the lowest and highest price of symbol between from and to.
+// Recent prices come from the in-memory cache; when it doesn't reach back to from,
+// the ticker history in the database fills in. It returns nil unless the history
+// has a point no later than maxGap after from, so a window isn't judged on a part of it.
+//
+// Example usage:
+//
+//	// Range of the last 15 minutes, with a point in the first minute
+//	now := time.Now()
+//	if r := monitor.PriceRange("BTCUSDT", now.Add(-15*time.Minute), now, time.Minute); r != nil {
+//	    log.Printf("BTC moved between $%.2f and $%.2f", r.Low.Price, r.High.Price)
+//	}
+func (pm *PriceMonitor) PriceRange(symbol string, from, to time.Time, maxGap time.Duration) *bitcoin.PriceRange {
+	symbol = strings.ToUpper(symbol)
+	covered := func(r *bitcoin.PriceRange) bool {
+		return !r.Oldest.Timestamp.IsZero() && !r.Oldest.Timestamp.After(from.Add(maxGap))
+	}
+
+	// The cache is newest first
+	result := &bitcoin.PriceRange{}
+	for _, entry := range pm.GetPriceHistory(symbol, pm.cacheSize) {
+		if entry.Timestamp.After(to) {
+			continue
+		}
+		if entry.Timestamp.Before(from) {
+			break
+		}
+		result.Include(bitcoin.PricePoint{Price: entry.Price, Timestamp: entry.Timestamp})
+	}
+
+	if !covered(result) && pm.tickerStorage != nil {
+		stored, err := pm.tickerStorage.PriceRange(symbol, from, to)
+		if err != nil {
+			log.Printf("❌ Error looking up %s prices since %s: %v", symbol, from.Format(time.RFC3339), err)
+			return nil
+		}
+		if stored != nil {
+			result.Include(stored.Oldest)
+			result.Include(stored.Low)
+			result.Include(stored.High)
+		}
+	}
+
+	if !covered(result) {
+		return nil
+	}
+	return result
 }
 
 // AddPriceUpdateCallback adds a callback for price updates.
@@ -224,8 +366,9 @@ func (pm *PriceMonitor) AddPriceUpdateCallback(callback PriceUpdateCallback) {
 }
 
 // monitoringLoop is the main price monitoring loop.
-// It fetches prices at regular intervals and notifies callbacks.
-func (pm *PriceMonitor) monitoringLoop(ctx context.Context, interval time.Duration) {
+// It polls prices at regular intervals while the stream is down and notifies callbacks.
+// stop is the channel of this run; Stop replaces pm.stopChannel for the next one.
+func (pm *PriceMonitor) monitoringLoop(ctx context.Context, interval time.Duration, stop <-chan struct{}) {
 	ticker := time.NewTicker(interval)
 	defer ticker.Stop()
 
@@ -235,8 +378,15 @@ func (pm *PriceMonitor) monitoringLoop(ctx context.Context, interval time.Durati
 	for {
 		select {
 		case <-ticker.C:
+			if pm.IsStreaming() {
+				continue
+			}
+			if pm.binanceStream != nil {
+				log.Printf("⚠️ Binance stream down since %s, polling %s",
+					pm.binanceStream.DownSince().Format(time.RFC3339), pm.priceProvider.Name())
+			}
 			pm.checkAndUpdatePrice()
-		case <-pm.stopChannel:
+		case <-stop:
 			return
 		case <-ctx.Done():
 			return
@@ -244,33 +394,78 @@ func (pm *PriceMonitor) monitoringLoop(ctx context.Context, interval time.Durati
 	}
 }
 
-// checkAndUpdatePrice fetches current price and updates both price and percentage.
+// checkAndUpdatePrice fetches current prices for all tracked symbols and updates them.
 func (pm *PriceMonitor) checkAndUpdatePrice() {
-	currentPrice, err := pm.binanceClient.GetCurrentPrice()
+	for _, symbol := range pm.GetSymbols() {
+		currentPrice, err := pm.priceProvider.GetSymbolPrice(symbol)
+		if err != nil {
+			log.Printf("❌ Error fetching %s price: %v", symbol, err)
+			continue
+		}
+
+		pm.updatePrice(currentPrice)
+	}
+}
+
+// handleStreamTicker processes a ticker received from the WebSocket stream.
+func (pm *PriceMonitor) handleStreamTicker(ticker *bitcoin.Ticker24hResponse) {
+	currentPrice, err := bitcoin.NewPriceDataFromTicker(ticker)
 	if err != nil {
-		log.Printf("❌ Error fetching Bitcoin price: %v", err)
+		log.Printf("❌ Error parsing streamed ticker: %v", err)
+		return
+	}
+
+	pm.storeStreamTicker(ticker)
+	pm.updatePrice(currentPrice)
+}
+
+// storeStreamTicker persists a streamed ticker, throttled to the check interval
+// so the database grows at the same rate as with REST polling.
+func (pm *PriceMonitor) storeStreamTicker(ticker *bitcoin.Ticker24hResponse) {
+	if pm.tickerStorage == nil {
+		return
+	}
+
+	pm.storeMux.Lock()
+	if time.Since(pm.lastStoredTicker[ticker.Symbol]) < pm.configProvider.GetCheckInterval() {
+		pm.storeMux.Unlock()
 		return
 	}
+	pm.lastStoredTicker[ticker.Symbol] = time.Now()
+	pm.storeMux.Unlock()
+
+	if err := pm.tickerStorage.StoreTicker24h(ticker.Symbol, ticker); err != nil {
+		log.Printf("❌ Error storing streamed ticker data: %v", err)
+	}
+}
 
-	// Add to cache (replaces database storage)
-	pm.priceCache.Add(currentPrice)
+// updatePrice caches a new price and notifies callbacks.
+// Both the stream and the polling fallback feed prices through here.
+func (pm *PriceMonitor) updatePrice(currentPrice *bitcoin.PriceData) {
+	symbol := currentPrice.Symbol
 
-	// Update cached price
+	// Update cached price and add to the symbol's cache (replaces database storage)
 	pm.lastPriceMux.Lock()
-	pm.lastPrice = currentPrice
+	cache, ok := pm.priceCaches[symbol]
+	if !ok {
+		cache = NewPriceCache(pm.cacheSize)
+		pm.priceCaches[symbol] = cache
+	}
+	pm.lastPrices[symbol] = currentPrice
 	pm.lastPriceMux.Unlock()
 
-	// Update percentage
-	pm.currentPercentageMux.Lock()
-	pm.currentPercentage = currentPrice.PriceChangePercent
-	pm.currentPercentageMux.Unlock()
+	cache.Add(currentPrice)
+
+	// Update percentage (the dashboard percentage follows the default symbol)
+	if symbol == bitcoin.DefaultSymbol {
+		pm.currentPercentageMux.Lock()
+		pm.currentPercentage = currentPrice.PriceChangePercent
+		pm.currentPercentageMux.Unlock()
+	}
 
 	// Log price update only when there's a significant change (>0.1%) or every 5 minutes
 	if shouldLogPrice(currentPrice) {
-		log.Printf("💰 BTC: $%.2f (%+.2f%%) [%s]",
-			currentPrice.Price,
-			currentPrice.PriceChangePercent,
-			currentPrice.Source)
+		log.Printf("💰 %s", currentPrice)
 	}
 
 	// Notify callbacks of price update
@@ -279,7 +474,7 @@ func (pm *PriceMonitor) checkAndUpdatePrice() {
 
 // shouldLogPrice determines if we should log the current price update.
 // Returns true if:
-// 1. First price update (lastLoggedPrice is 0)
+// 1. First price update for the symbol
 // 2. Price changed by more than 0.1%
 // 3. It's been more than 5 minutes since last log
 func shouldLogPrice(price *bitcoin.PriceData) bool {
@@ -287,16 +482,17 @@ func shouldLogPrice(price *bitcoin.PriceData) bool {
 	defer logMutex.Unlock()
 
 	now := time.Now()
+	lastPrice := lastLoggedPrice[price.Symbol]
 
-	significantChange := lastLoggedPrice != 0 &&
-		abs((price.Price-lastLoggedPrice)/lastLoggedPrice) > 0.001
-	timeToLog := now.Sub(lastLogTime) >= 5*time.Minute
+	significantChange := lastPrice != 0 &&
+		abs((price.Price-lastPrice)/lastPrice) > 0.001
+	timeToLog := now.Sub(lastLogTime[price.Symbol]) >= 5*time.Minute
 
-	shouldLog := lastLoggedPrice == 0 || significantChange || timeToLog
+	shouldLog := lastPrice == 0 || significantChange || timeToLog
 
 	if shouldLog {
-		lastLogTime = now
-		lastLoggedPrice = price.Price
+		lastLogTime[price.Symbol] = now
+		lastLoggedPrice[price.Symbol] = price.Price
 	}
 
 	return shouldLog
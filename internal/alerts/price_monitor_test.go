@@ -0,0 +1,65 @@
+package alerts
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cgallonv/btc-alerta-de-precio/internal/bitcoin"
+	"github.com/cgallonv/btc-alerta-de-precio/internal/mocks"
+	"github.com/cgallonv/btc-alerta-de-precio/internal/storage"
+	"github.com/cgallonv/btc-alerta-de-precio/internal/storage/migrations"
+	"github.com/cgallonv/btc-alerta-de-precio/internal/storage/models"
+	"github.com/cgallonv/btc-alerta-de-precio/internal/storage/repositories"
+)
+
+func TestPriceMonitor_PriceRange(t *testing.T) {
+	db, err := storage.NewDatabase(filepath.Join(t.TempDir(), "alerts.db"))
+	require.NoError(t, err)
+	defer db.Close()
+	require.NoError(t, migrations.MigrateTickerData(db.DB()))
+
+	now := time.Now()
+	tickerRepo := repositories.NewTickerRepository(db.DB())
+	for _, ticker := range []models.TickerData{
+		{Symbol: "BTCUSDT", Source: bitcoin.SourceBinance, LastPrice: 71000, Timestamp: now.Add(-40 * time.Minute)},
+		{Symbol: "BTCUSDT", Source: bitcoin.SourceBinance, LastPrice: 72000, Timestamp: now.Add(-30 * time.Minute)},
+		{Symbol: "BTCUSDT", Source: bitcoin.SourceBinance, LastPrice: 70000, Timestamp: now.Add(-20 * time.Minute)},
+		{Symbol: "BTCUSDT", Source: bitcoin.SourceBinance, LastPrice: 67000, Timestamp: now.Add(-10 * time.Minute)},
+	} {
+		require.NoError(t, tickerRepo.Store(&ticker))
+	}
+
+	config := &mocks.MockConfigProvider{}
+	config.On("IsPriceAggregationEnabled").Return(false)
+	config.On("IsPriceStreamEnabled").Return(false)
+	monitor := NewPriceMonitor(config, 20, &mocks.MockPriceProvider{}, bitcoin.NewTickerStorage(tickerRepo))
+
+	// The cache only holds the last few minutes
+	for _, minutesAgo := range []int{6, 4, 2, 0} {
+		monitor.updatePrice(&bitcoin.PriceData{Symbol: "BTCUSDT", Price: 68000 + float64(minutesAgo)*100,
+			Timestamp: now.Add(-time.Duration(minutesAgo) * time.Minute)})
+	}
+
+	// Recent windows are served from the cache
+	r := monitor.PriceRange("btcusdt", now.Add(-5*time.Minute), now, time.Minute)
+	require.NotNil(t, r)
+	assert.Equal(t, 68000.0, r.Low.Price)
+	assert.Equal(t, 68400.0, r.High.Price)
+
+	// Longer ones take the extremes of the ticker history too, not a single old point
+	r = monitor.PriceRange("BTCUSDT", now.Add(-35*time.Minute), now, 6*time.Minute)
+	require.NotNil(t, r)
+	assert.Equal(t, 67000.0, r.Low.Price)
+	assert.WithinDuration(t, now.Add(-10*time.Minute), r.Low.Timestamp, time.Second)
+	assert.Equal(t, 72000.0, r.High.Price)
+	assert.WithinDuration(t, now.Add(-30*time.Minute), r.High.Timestamp, time.Second)
+
+	// A window the history doesn't reach the start of is unknown
+	assert.Nil(t, monitor.PriceRange("BTCUSDT", now.Add(-60*time.Minute), now, 10*time.Minute))
+	assert.Nil(t, monitor.PriceRange("BTCUSDT", now.Add(-35*time.Minute), now, time.Minute))
+	assert.Nil(t, monitor.PriceRange("ETHUSDT", now.Add(-5*time.Minute), now, time.Minute))
+}
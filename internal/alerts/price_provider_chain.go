@@ -0,0 +1,127 @@
+// Package alerts provides functionality for monitoring Bitcoin prices
+// and managing price-based alerts.
+package alerts
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cgallonv/btc-alerta-de-precio/internal/bitcoin"
+	"github.com/cgallonv/btc-alerta-de-precio/internal/errors"
+	"github.com/cgallonv/btc-alerta-de-precio/internal/interfaces"
+)
+
+// defaultRateLimitCooldown is how long a provider is skipped after it rate-limits us.
+const defaultRateLimitCooldown = time.Minute
+
+// PriceProviderChain tries price providers in order and returns the first price obtained.
+// A provider that rate-limits us is skipped for a cooldown period so the next
+// provider supplies ticks without hammering the throttled API.
+// The chain itself implements interfaces.PriceProvider.
+//
+// Example usage:
+//
+//	chain := NewPriceProviderChain(binanceClient, bitcoin.NewCoinbaseClient(""), bitcoin.NewKrakenClient(""))
+//	price, err := chain.GetSymbolPrice("BTCUSDT")
+//	if err != nil {
+//	    log.Printf("Error: %v", err)
+//	    return
+//	}
+//	log.Printf("Price from %s: $%.2f", price.Source, price.Price)
+type PriceProviderChain struct {
+	providers []interfaces.PriceProvider
+	cooldown  time.Duration
+
+	// Providers skipped until the given time because they rate-limited us
+	skipUntil map[string]time.Time
+	skipMux   sync.Mutex
+}
+
+// NewPriceProviderChain creates a failover chain over the given providers, in priority order.
+//
+// Example usage:
+//
+//	chain := NewPriceProviderChain(binanceClient, coinbaseClient)
+func NewPriceProviderChain(providers ...interfaces.PriceProvider) *PriceProviderChain {
+	return &PriceProviderChain{
+		providers: providers,
+		cooldown:  defaultRateLimitCooldown,
+		skipUntil: make(map[string]time.Time),
+	}
+}
+
+// Name returns the provider names in failover order.
+func (c *PriceProviderChain) Name() string {
+	names := make([]string, 0, len(c.providers))
+	for _, provider := range c.providers {
+		names = append(names, provider.Name())
+	}
+	return strings.Join(names, " → ")
+}
+
+// GetSymbolPrice fetches a price from the first provider that succeeds.
+// PriceData.Source reports which provider supplied the price.
+// If every provider fails, an error wrapping errors.ErrPriceAPIUnavailable is returned.
+//
+// Example usage:
+//
+//	price, err := chain.GetSymbolPrice("ETHUSDT")
+//	if err != nil {
+//	    return err
+//	}
+func (c *PriceProviderChain) GetSymbolPrice(symbol string) (*bitcoin.PriceData, error) {
+	var providerErrors []error
+
+	for i, provider := range c.providers {
+		if until, skipped := c.skippedUntil(provider.Name()); skipped {
+			providerErrors = append(providerErrors,
+				fmt.Errorf("%s rate limited until %s", provider.Name(), until.Format(time.RFC3339)))
+			continue
+		}
+
+		price, err := provider.GetSymbolPrice(symbol)
+		if err == nil {
+			if i > 0 {
+				log.Printf("⚠️ %s price supplied by fallback provider %s", symbol, provider.Name())
+			}
+			return price, nil
+		}
+
+		if bitcoin.IsRateLimitError(err) {
+			c.skip(provider.Name())
+			log.Printf("⚠️ %s rate limited us, skipping it for %v", provider.Name(), c.cooldown)
+		} else {
+			log.Printf("❌ %s failed to provide %s price: %v", provider.Name(), symbol, err)
+		}
+		providerErrors = append(providerErrors, err)
+	}
+
+	return nil, errors.WrapError(errors.CombineErrors(providerErrors),
+		errors.ErrPriceAPIUnavailable.Code, errors.ErrPriceAPIUnavailable.Message)
+}
+
+// skippedUntil reports whether a provider is cooling down after a rate limit.
+func (c *PriceProviderChain) skippedUntil(name string) (time.Time, bool) {
+	c.skipMux.Lock()
+	defer c.skipMux.Unlock()
+
+	until, ok := c.skipUntil[name]
+	if !ok {
+		return time.Time{}, false
+	}
+	if time.Now().After(until) {
+		delete(c.skipUntil, name)
+		return time.Time{}, false
+	}
+	return until, true
+}
+
+// skip puts a provider on cooldown.
+func (c *PriceProviderChain) skip(name string) {
+	c.skipMux.Lock()
+	defer c.skipMux.Unlock()
+	c.skipUntil[name] = time.Now().Add(c.cooldown)
+}
@@ -0,0 +1,66 @@
+package alerts
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cgallonv/btc-alerta-de-precio/internal/bitcoin"
+	"github.com/cgallonv/btc-alerta-de-precio/internal/errors"
+	"github.com/cgallonv/btc-alerta-de-precio/internal/mocks"
+)
+
+func newMockProvider(name string) *mocks.MockPriceProvider {
+	provider := &mocks.MockPriceProvider{}
+	provider.On("Name").Return(name)
+	return provider
+}
+
+func TestPriceProviderChain_FailsOverInOrder(t *testing.T) {
+	binance := newMockProvider(bitcoin.SourceBinance)
+	coinbase := newMockProvider(bitcoin.SourceCoinbase)
+	kraken := newMockProvider(bitcoin.SourceKraken)
+
+	binance.On("GetSymbolPrice", "BTCUSDT").Return(nil, bitcoin.NewBinanceError(500, `{"code":-1000,"msg":"internal error"}`))
+	coinbase.On("GetSymbolPrice", "BTCUSDT").Return(&bitcoin.PriceData{Symbol: "BTCUSDT", Price: 50000, Source: bitcoin.SourceCoinbase}, nil)
+
+	chain := NewPriceProviderChain(binance, coinbase, kraken)
+	price, err := chain.GetSymbolPrice("BTCUSDT")
+
+	require.NoError(t, err)
+	assert.Equal(t, bitcoin.SourceCoinbase, price.Source)
+	kraken.AssertNotCalled(t, "GetSymbolPrice", "BTCUSDT")
+}
+
+func TestPriceProviderChain_SkipsRateLimitedProvider(t *testing.T) {
+	binance := newMockProvider(bitcoin.SourceBinance)
+	kraken := newMockProvider(bitcoin.SourceKraken)
+
+	binance.On("GetSymbolPrice", "BTCUSDT").Return(nil, bitcoin.NewBinanceError(429, `{"code":-1003,"msg":"Too many requests"}`)).Once()
+	kraken.On("GetSymbolPrice", "BTCUSDT").Return(&bitcoin.PriceData{Symbol: "BTCUSDT", Price: 50000, Source: bitcoin.SourceKraken}, nil)
+
+	chain := NewPriceProviderChain(binance, kraken)
+	for i := 0; i < 2; i++ {
+		price, err := chain.GetSymbolPrice("BTCUSDT")
+		require.NoError(t, err)
+		assert.Equal(t, bitcoin.SourceKraken, price.Source)
+	}
+
+	// Binance is cooling down after the 429, so it is only asked once
+	binance.AssertNumberOfCalls(t, "GetSymbolPrice", 1)
+}
+
+func TestPriceProviderChain_AllProvidersFail(t *testing.T) {
+	binance := newMockProvider(bitcoin.SourceBinance)
+	coinbase := newMockProvider(bitcoin.SourceCoinbase)
+
+	binance.On("GetSymbolPrice", "ETHUSDT").Return(nil, bitcoin.NewBinanceError(503, ""))
+	coinbase.On("GetSymbolPrice", "ETHUSDT").Return(nil, &bitcoin.ProviderError{Provider: bitcoin.SourceCoinbase, Status: 503})
+
+	chain := NewPriceProviderChain(binance, coinbase)
+	_, err := chain.GetSymbolPrice("ETHUSDT")
+
+	require.Error(t, err)
+	assert.Equal(t, errors.ErrPriceAPIUnavailable.Code, errors.GetErrorCode(err))
+}
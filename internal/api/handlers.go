@@ -1,14 +1,18 @@
 package api
 
 import (
+	stderrors "errors"
 	"fmt"
 	"html/template"
 	"log"
 	"net/http"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
 
+	"github.com/cgallonv/btc-alerta-de-precio/internal/alerts"
+	"github.com/cgallonv/btc-alerta-de-precio/internal/expression"
 	"github.com/cgallonv/btc-alerta-de-precio/internal/interfaces"
 	"github.com/cgallonv/btc-alerta-de-precio/internal/storage"
 
@@ -20,6 +24,12 @@ import (
 type Handler struct {
 	alertService   interfaces.AlertService
 	configProvider interfaces.ConfigProvider
+	tradeHistory   *bitcoin.TradeHistory   // optional, adds cost basis and PnL to balances
+	userStream     *bitcoin.UserDataStream // optional, serves balances without calling Binance
+	orderBooks     *bitcoin.OrderBooks     // optional, serves tracked order books without calling Binance
+	candles        *bitcoin.CandleStorage  // optional, serves stored OHLCV candles
+	indicators     *alerts.IndicatorEngine // optional, serves technical indicators
+	fxRates        *alerts.FXRates         // optional, converts balances and alerts to other currencies
 }
 
 type Response struct {
@@ -31,8 +41,43 @@ type Response struct {
 
 // AlertUpdateRequest para la funcionalidad de edición limitada
 type AlertUpdateRequest struct {
-	TargetPrice *float64 `json:"target_price,omitempty"`
-	Percentage  *float64 `json:"percentage,omitempty"`
+	TargetPrice   *float64 `json:"target_price,omitempty"`
+	Currency      *string  `json:"currency,omitempty"`
+	Percentage    *float64 `json:"percentage,omitempty"`
+	WindowMinutes *int     `json:"window_minutes,omitempty"`
+	Threshold     *float64 `json:"threshold,omitempty"`
+	DepthPercent  *float64 `json:"depth_percent,omitempty"`
+
+	TriggerMode       *string  `json:"trigger_mode,omitempty"`
+	CooldownSeconds   *int64   `json:"cooldown_seconds,omitempty"`
+	MaxTriggers       *int     `json:"max_triggers,omitempty"`
+	HysteresisPercent *float64 `json:"hysteresis_percent,omitempty"`
+
+	Interval           *string `json:"interval,omitempty"`
+	Period             *int    `json:"period,omitempty"`
+	SlowPeriod         *int    `json:"slow_period,omitempty"`
+	IndicatorCondition *string `json:"indicator_condition,omitempty"`
+
+	Conditions *storage.Condition `json:"conditions,omitempty"`
+
+	Expression *string `json:"expression,omitempty"`
+
+	// Schedule reemplaza el horario de la alerta; un horario vacío ({}) lo quita
+	Schedule *storage.Schedule `json:"schedule,omitempty"`
+}
+
+// ExpressionRequest is the body of POST /api/v1/alerts/validate-expression.
+type ExpressionRequest struct {
+	Expression string `json:"expression"`
+}
+
+// ExpressionValidation is the result of validating an expression: the compile errors
+// with their columns, or the variables and indicator calls a valid expression uses.
+type ExpressionValidation struct {
+	Valid      bool                      `json:"valid"`
+	Errors     []*expression.Error       `json:"errors,omitempty"`
+	Variables  []string                  `json:"variables,omitempty"`
+	Indicators []expression.IndicatorRef `json:"indicators,omitempty"`
 }
 
 // Add AccountData struct
@@ -43,6 +88,8 @@ type AccountData struct {
 	Assets           []Asset   `json:"assets"`
 	Orders           []Order   `json:"orders"`
 	Budget           Budget    `json:"budget"`
+	RealizedPnL      float64   `json:"realized_pnl"`
+	UnrealizedPnL    float64   `json:"unrealized_pnl"`
 
 	// Binance API fields
 	MakerCommission  int `json:"maker_commission"`
@@ -72,10 +119,18 @@ type Asset struct {
 	Total     float64 `json:"total"`
 	ValueUSD  float64 `json:"value_usd"`
 	Change24h float64 `json:"change_24h"`
+
+	PriceRoute string `json:"price_route,omitempty"` // Pairs used for ValueUSD, empty if the asset has no market
+
+	AvgCost       float64 `json:"avg_cost"`
+	CostBasis     float64 `json:"cost_basis"`
+	RealizedPnL   float64 `json:"realized_pnl"`
+	UnrealizedPnL float64 `json:"unrealized_pnl"`
 }
 
 type Order struct {
 	ID     string    `json:"id"`
+	Symbol string    `json:"symbol"`
 	Date   time.Time `json:"date"`
 	Type   string    `json:"type"`
 	Amount float64   `json:"amount"`
@@ -83,6 +138,13 @@ type Order struct {
 	Status string    `json:"status"`
 }
 
+// Order statuses shown on the account page.
+const (
+	orderStatusOpen      = "open"
+	orderStatusCompleted = "completed"
+	orderStatusCancelled = "cancelled"
+)
+
 type Budget struct {
 	Used            float64    `json:"used"`
 	Limit           float64    `json:"limit"`
@@ -97,10 +159,12 @@ type Category struct {
 }
 
 // NewHandler creates a new Handler with the given alert service and config provider.
-func NewHandler(alertService interfaces.AlertService, configProvider interfaces.ConfigProvider) *Handler {
+// tradeHistory may be nil, in which case balances are shown without cost basis and PnL.
+func NewHandler(alertService interfaces.AlertService, configProvider interfaces.ConfigProvider, tradeHistory *bitcoin.TradeHistory) *Handler {
 	return &Handler{
 		alertService:   alertService,
 		configProvider: configProvider,
+		tradeHistory:   tradeHistory,
 	}
 }
 
@@ -156,14 +220,27 @@ func (h *Handler) SetupRoutes(router *gin.Engine) {
 		api.GET("/price", h.getCurrentPrice)
 		api.GET("/price/history", h.getPriceHistory)
 		api.GET("/price/percentage", h.getCurrentPercentage)
+		api.GET("/orderbook", h.getOrderBook)
+		api.GET("/candles", h.getCandles)
+		api.GET("/indicators", h.getIndicators)
+		api.GET("/fx/rates", h.getFXRates)
+		api.GET("/symbols", h.getSymbols)
 
 		// Account
 		api.GET("/account/balance", h.GetAccountBalance)
+		api.GET("/account/orders", h.getAccountOrders)
+
+		// Orders
+		api.POST("/orders", h.placeOrder)
+		api.POST("/orders/test", h.testOrder)
+		api.POST("/orders/oco", h.placeOCOOrder)
+		api.DELETE("/orders/:id", h.cancelOrder)
 
 		// Alerts
 		api.GET("/alerts", h.getAlerts)
 		api.GET("/alerts/:id", h.getAlert)
 		api.POST("/alerts", h.createAlert)
+		api.POST("/alerts/validate-expression", h.validateExpression)
 		api.PUT("/alerts/:id", h.updateAlert)
 		api.DELETE("/alerts/:id", h.deleteAlert)
 		api.POST("/alerts/:id/toggle", h.toggleAlert)
@@ -253,7 +330,7 @@ func (h *Handler) accountPage(c *gin.Context) {
 	)
 
 	// Get account balance using default symbols from config
-	balance, err := binanceClient.GetAccountBalance(h.configProvider.GetDefaultSymbols())
+	balance, err := h.accountBalance(h.configProvider.GetDefaultSymbols())
 	if err != nil {
 		log.Printf("Error getting account balance: %v", err)
 		c.HTML(http.StatusInternalServerError, "layout", gin.H{
@@ -264,15 +341,19 @@ func (h *Handler) accountPage(c *gin.Context) {
 		})
 		return
 	}
+	h.applyFX(balance, h.configProvider.GetString("display_currency"))
+	h.applyPnL(balance)
 
 	// Prepare account data
 	accountData := AccountData{
 		TotalBalance:     balance.TotalBalance,
 		AvailableBalance: balance.AvailableBalance,
 		LastUpdated:      balance.LastUpdated,
+		RealizedPnL:      balance.RealizedPnL,
+		UnrealizedPnL:    balance.UnrealizedPnL,
 		Assets:           make([]Asset, 0, len(balance.Assets)),
-		Orders:           []Order{}, // TODO: Implement order history
-		Budget:           Budget{},  // TODO: Implement budget history
+		Orders:           []Order{},
+		Budget:           Budget{}, // TODO: Implement budget history
 
 		// Binance API fields
 		MakerCommission:            balance.MakerCommission,
@@ -290,6 +371,14 @@ func (h *Handler) accountPage(c *gin.Context) {
 		UpdateTime:                 balance.UpdateTime,
 	}
 
+	// Recent orders; the page still renders if they can't be loaded
+	orders, err := h.accountOrders(binanceClient, h.orderSymbols(), "", bitcoin.OrderQuery{Limit: 50})
+	if err != nil {
+		log.Printf("Error getting account orders: %v", err)
+	} else {
+		accountData.Orders = orders
+	}
+
 	// Convert assets
 	for _, asset := range balance.Assets {
 		accountData.Assets = append(accountData.Assets, Asset{
@@ -299,23 +388,34 @@ func (h *Handler) accountPage(c *gin.Context) {
 			Total:     asset.Total,
 			ValueUSD:  asset.ValueUSD,
 			Change24h: asset.Change24h,
+
+			PriceRoute: asset.PriceRoute,
+
+			AvgCost:       asset.AvgCost,
+			CostBasis:     asset.CostBasis,
+			RealizedPnL:   asset.RealizedPnL,
+			UnrealizedPnL: asset.UnrealizedPnL,
 		})
 	}
 
 	c.HTML(http.StatusOK, "layout", gin.H{
-		"PageTitle": "Account",
-		"Version":   time.Now().Unix(),
-		"content":   "account",
-		"account":   accountData,
+		"PageTitle":     "Account",
+		"Version":       time.Now().Unix(),
+		"content":       "account",
+		"account":       accountData,
+		"TradingDryRun": bitcoin.IsTradingDryRun(), // Cancels are refused in dry-run mode
 	})
 }
 
-// getCurrentPrice handles GET /api/v1/price and returns the current Bitcoin price as JSON.
+// getCurrentPrice handles GET /api/v1/price and returns the current price as JSON.
+// The optional symbol parameter defaults to BTCUSDT.
 // Example usage:
 //
-//	GET /api/v1/price
+//	GET /api/v1/price?symbol=ETHUSDT
 func (h *Handler) getCurrentPrice(c *gin.Context) {
-	price, err := h.alertService.GetCurrentPrice()
+	symbol := storage.NormalizeSymbol(c.DefaultQuery("symbol", bitcoin.DefaultSymbol))
+
+	price, err := h.alertService.GetCurrentPrice(symbol)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, Response{
 			Success: false,
@@ -330,11 +430,228 @@ func (h *Handler) getCurrentPrice(c *gin.Context) {
 	})
 }
 
+// getOrderBook handles GET /api/v1/orderbook and returns the spread, liquidity and
+// imbalance of a symbol's order book within depth_percent (default 1) of the mid price.
+// Books tracked for order book alerts are served from memory, others are fetched from Binance.
+// Example usage:
+//
+//	GET /api/v1/orderbook?symbol=BTCUSDT&depth_percent=0.5
+func (h *Handler) getOrderBook(c *gin.Context) {
+	symbol := storage.NormalizeSymbol(c.DefaultQuery("symbol", bitcoin.DefaultSymbol))
+
+	depthPercent, err := strconv.ParseFloat(c.DefaultQuery("depth_percent", "1"), 64)
+	if err != nil || depthPercent <= 0 || depthPercent > 50 {
+		c.JSON(http.StatusBadRequest, Response{
+			Success: false,
+			Error:   "depth_percent must be a number between 0 and 50",
+		})
+		return
+	}
+
+	var book *bitcoin.OrderBookSnapshot
+	if h.orderBooks != nil {
+		book, _ = h.orderBooks.Snapshot(symbol)
+	}
+	if book == nil {
+		book, err = h.newBinanceClient().GetOrderBook(symbol, bitcoin.DefaultOrderBookDepth)
+		if err != nil {
+			c.JSON(orderErrorStatus(err), Response{
+				Success: false,
+				Error:   err.Error(),
+			})
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, Response{
+		Success: true,
+		Data:    book.Stats(depthPercent),
+	})
+}
+
+// getCandles handles GET /api/v1/candles and returns stored OHLCV candles, oldest first.
+// from and to bound the open time; limit keeps the most recent candles of the range.
+// Example usage:
+//
+//	GET /api/v1/candles?symbol=BTCUSDT&interval=1m&from=2024-01-01&to=2024-01-31&limit=500
+func (h *Handler) getCandles(c *gin.Context) {
+	if h.candles == nil {
+		c.JSON(http.StatusServiceUnavailable, Response{
+			Success: false,
+			Error:   "Candle storage is not available",
+		})
+		return
+	}
+
+	symbol := storage.NormalizeSymbol(c.DefaultQuery("symbol", bitcoin.DefaultSymbol))
+	interval := c.DefaultQuery("interval", "1m")
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "500"))
+	if err != nil || limit < 0 {
+		c.JSON(http.StatusBadRequest, Response{
+			Success: false,
+			Error:   "Invalid limit parameter",
+		})
+		return
+	}
+
+	from, err := parseDateParam(c.Query("from"), false)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, Response{
+			Success: false,
+			Error:   "Invalid from parameter: " + err.Error(),
+		})
+		return
+	}
+	to, err := parseDateParam(c.Query("to"), true)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, Response{
+			Success: false,
+			Error:   "Invalid to parameter: " + err.Error(),
+		})
+		return
+	}
+
+	candles, err := h.candles.GetCandles(symbol, interval, from, to, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, Response{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, Response{
+		Success: true,
+		Data:    candles,
+	})
+}
+
+// getIndicators handles GET /api/v1/indicators and returns SMA(20), EMA(21), RSI(14),
+// MACD(12,26,9), Bollinger(20, 2) and ATR(14) of a symbol on the forming candle.
+// While the candles are loading it responds 503 with the error "loading" and a Retry-After.
+// Example usage:
+//
+//	GET /api/v1/indicators?symbol=BTCUSDT&interval=1h
+func (h *Handler) getIndicators(c *gin.Context) {
+	if h.indicators == nil {
+		c.JSON(http.StatusServiceUnavailable, Response{
+			Success: false,
+			Error:   "Indicators are not available",
+		})
+		return
+	}
+
+	symbol := storage.NormalizeSymbol(c.DefaultQuery("symbol", bitcoin.DefaultSymbol))
+	if err := h.checkSymbol(symbol); err != nil {
+		c.JSON(http.StatusBadRequest, Response{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	snapshot, err := h.indicators.Snapshot(symbol, c.DefaultQuery("interval", storage.DefaultIndicatorInterval))
+	if stderrors.Is(err, alerts.ErrIndicatorsLoading) {
+		c.Header("Retry-After", "2")
+		c.JSON(http.StatusServiceUnavailable, Response{
+			Success: false,
+			Error:   "loading",
+			Message: err.Error(),
+		})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, Response{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, Response{
+		Success: true,
+		Data:    snapshot,
+	})
+}
+
+// getFXRates handles GET /api/v1/fx/rates and returns the rate per USD of each currency.
+// Currencies default to the configured display currency.
+// Example usage:
+//
+//	GET /api/v1/fx/rates?currencies=COP,EUR
+func (h *Handler) getFXRates(c *gin.Context) {
+	if h.fxRates == nil {
+		c.JSON(http.StatusServiceUnavailable, Response{
+			Success: false,
+			Error:   "FX rates are not available",
+		})
+		return
+	}
+
+	currencies := strings.Split(c.DefaultQuery("currencies", h.configProvider.GetString("display_currency")), ",")
+	rates := make([]*alerts.FXRate, 0, len(currencies))
+	for _, currency := range currencies {
+		rate, err := h.fxRates.Rate(currency)
+		if err != nil {
+			c.JSON(http.StatusNotFound, Response{
+				Success: false,
+				Error:   err.Error(),
+			})
+			return
+		}
+		rates = append(rates, rate)
+	}
+
+	c.JSON(http.StatusOK, Response{
+		Success: true,
+		Data:    rates,
+	})
+}
+
+// getSymbols handles GET /api/v1/symbols and lists the trading symbols with their
+// base and quote assets, tick size and lot size, for autocompleting symbol inputs.
+// Example usage:
+//
+//	GET /api/v1/symbols?q=ETH&quote=USDT&limit=20
+func (h *Handler) getSymbols(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if err != nil || limit < 0 {
+		c.JSON(http.StatusBadRequest, Response{
+			Success: false,
+			Error:   "Invalid limit parameter",
+		})
+		return
+	}
+
+	symbols, err := h.newBinanceClient().GetSymbols(bitcoin.SymbolQuery{
+		Search:     c.Query("q"),
+		QuoteAsset: c.Query("quote"),
+		All:        c.Query("all") == "true",
+		Limit:      limit,
+	})
+	if err != nil {
+		c.JSON(http.StatusServiceUnavailable, Response{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, Response{
+		Success: true,
+		Data:    symbols,
+	})
+}
+
 // getPriceHistory handles GET /api/v1/price/history and returns the price history.
+// The optional symbol parameter defaults to BTCUSDT.
 // Example usage:
 //
-//	GET /api/v1/price/history?limit=24
+//	GET /api/v1/price/history?symbol=ETHUSDT&limit=24
 func (h *Handler) getPriceHistory(c *gin.Context) {
+	symbol := storage.NormalizeSymbol(c.DefaultQuery("symbol", bitcoin.DefaultSymbol))
+
 	limitStr := c.DefaultQuery("limit", "100")
 	limit, err := strconv.Atoi(limitStr)
 	if err != nil {
@@ -345,7 +662,7 @@ func (h *Handler) getPriceHistory(c *gin.Context) {
 		return
 	}
 
-	history, err := h.alertService.GetPriceHistory(limit)
+	history, err := h.alertService.GetPriceHistory(symbol, limit)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, Response{
 			Success: false,
@@ -428,6 +745,22 @@ func (h *Handler) createAlert(c *gin.Context) {
 		return
 	}
 
+	if err := h.checkCurrency(alert.GetCurrency()); err != nil {
+		c.JSON(http.StatusBadRequest, Response{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	if err := h.checkSymbol(alert.GetSymbol()); err != nil {
+		c.JSON(http.StatusBadRequest, Response{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
 	if err := h.alertService.CreateAlert(&alert); err != nil {
 		c.JSON(http.StatusBadRequest, Response{
 			Success: false,
@@ -443,6 +776,45 @@ func (h *Handler) createAlert(c *gin.Context) {
 	})
 }
 
+// validateExpression handles POST /api/v1/alerts/validate-expression and compiles the
+// rule of an expression alert without saving it. Invalid expressions still answer 200,
+// with valid false and the error's column.
+// Example usage:
+//
+//	POST /api/v1/alerts/validate-expression {"expression": "price < 60000 && hour_utc >= 13"}
+func (h *Handler) validateExpression(c *gin.Context) {
+	var req ExpressionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, Response{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	program, err := expression.Compile(req.Expression)
+	if err != nil {
+		var compileErr *expression.Error
+		if !stderrors.As(err, &compileErr) {
+			compileErr = &expression.Error{Column: 1, Message: err.Error()}
+		}
+		c.JSON(http.StatusOK, Response{
+			Success: true,
+			Data:    ExpressionValidation{Errors: []*expression.Error{compileErr}},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, Response{
+		Success: true,
+		Data: ExpressionValidation{
+			Valid:      true,
+			Variables:  program.Variables(),
+			Indicators: program.Indicators(),
+		},
+	})
+}
+
 // updateAlert handles PUT /api/v1/alerts/:id and updates an existing alert.
 func (h *Handler) updateAlert(c *gin.Context) {
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
@@ -475,7 +847,7 @@ func (h *Handler) updateAlert(c *gin.Context) {
 
 	// Solo actualizar el campo correspondiente según el tipo de alerta
 	switch alert.Type {
-	case "above", "below":
+	case "above", "below", "cross_up", "cross_down":
 		if updateReq.TargetPrice != nil {
 			alert.TargetPrice = *updateReq.TargetPrice
 		} else {
@@ -485,7 +857,17 @@ func (h *Handler) updateAlert(c *gin.Context) {
 			})
 			return
 		}
-	case "change":
+		if updateReq.Currency != nil {
+			alert.Currency = *updateReq.Currency
+			if err := h.checkCurrency(alert.GetCurrency()); err != nil {
+				c.JSON(http.StatusBadRequest, Response{
+					Success: false,
+					Error:   err.Error(),
+				})
+				return
+			}
+		}
+	case "change", "change_window", "imbalance":
 		if updateReq.Percentage != nil {
 			alert.Percentage = *updateReq.Percentage
 		} else {
@@ -495,10 +877,68 @@ func (h *Handler) updateAlert(c *gin.Context) {
 			})
 			return
 		}
+	case "spread", "liquidity":
+		if updateReq.Threshold != nil {
+			alert.Threshold = *updateReq.Threshold
+		} else {
+			c.JSON(http.StatusBadRequest, Response{
+				Success: false,
+				Error:   "threshold is required for spread and liquidity alerts",
+			})
+			return
+		}
+	case "indicator":
+		if updateReq.Threshold != nil {
+			alert.Threshold = *updateReq.Threshold
+		}
+		if updateReq.Interval != nil {
+			alert.Interval = *updateReq.Interval
+		}
+		if updateReq.Period != nil {
+			alert.Period = *updateReq.Period
+		}
+		if updateReq.SlowPeriod != nil {
+			alert.SlowPeriod = *updateReq.SlowPeriod
+		}
+		if updateReq.IndicatorCondition != nil {
+			alert.IndicatorCondition = *updateReq.IndicatorCondition
+		}
+	case "composite":
+		if updateReq.Conditions != nil {
+			alert.Conditions = updateReq.Conditions
+		}
+	case "expression":
+		if updateReq.Expression != nil {
+			alert.Expression = *updateReq.Expression
+		}
+	}
+	if updateReq.WindowMinutes != nil && alert.Type == "change_window" {
+		alert.WindowMinutes = *updateReq.WindowMinutes
+	}
+	if updateReq.DepthPercent != nil && alert.IsOrderBookAlert() {
+		alert.DepthPercent = *updateReq.DepthPercent
+	}
+	if updateReq.TriggerMode != nil {
+		alert.TriggerMode = *updateReq.TriggerMode
+	}
+	if updateReq.CooldownSeconds != nil {
+		alert.CooldownSeconds = *updateReq.CooldownSeconds
+	}
+	if updateReq.MaxTriggers != nil {
+		alert.MaxTriggers = *updateReq.MaxTriggers
+	}
+	if updateReq.HysteresisPercent != nil {
+		alert.HysteresisPercent = *updateReq.HysteresisPercent
+	}
+	if updateReq.Schedule != nil {
+		alert.Schedule = updateReq.Schedule
+		if alert.Schedule.IsEmpty() {
+			alert.Schedule = nil
+		}
 	}
 
 	// Si la alerta estaba disparada, resetearla para que pueda activarse de nuevo
-	if alert.LastTriggered != nil {
+	if alert.LastTriggered != nil || alert.Disarmed {
 		alert.Reset()
 	}
 
@@ -618,7 +1058,8 @@ func (h *Handler) resetAlert(c *gin.Context) {
 }
 
 // System endpoints
-// getStats handles GET /api/v1/stats and returns system statistics.
+// getStats handles GET /api/v1/stats and returns system statistics,
+// including the Binance request weight budget.
 func (h *Handler) getStats(c *gin.Context) {
 	stats, err := h.alertService.GetStats()
 	if err != nil {
@@ -629,6 +1070,8 @@ func (h *Handler) getStats(c *gin.Context) {
 		return
 	}
 
+	stats["binance_rate_limit"] = bitcoin.WeightLimiterFor(h.configProvider.GetString("binance.base_url")).Stats()
+
 	c.JSON(http.StatusOK, Response{
 		Success: true,
 		Data:    stats,
@@ -636,13 +1079,32 @@ func (h *Handler) getStats(c *gin.Context) {
 }
 
 // healthCheck handles GET/HEAD /api/v1/health and returns a health status for the service.
+// price_feed is "degraded" when the last Bitcoin tick is older than three check intervals,
+// whichever source (stream, REST failover or aggregator) delivered it, so the UI can flag
+// the displayed price as possibly stale.
 func (h *Handler) healthCheck(c *gin.Context) {
+	breaker := bitcoin.CircuitBreakerFor(h.configProvider.GetString("binance.base_url")).Stats()
+	staleAfter := 3 * h.configProvider.GetCheckInterval()
+
+	priceFeed := "degraded"
+	var lastTickAt *time.Time
+	if last := h.alertService.GetLastPrice(bitcoin.DefaultSymbol); last != nil {
+		lastTickAt = &last.Timestamp
+		if time.Since(last.Timestamp) <= staleAfter {
+			priceFeed = "live"
+		}
+	}
+
 	c.JSON(http.StatusOK, Response{
 		Success: true,
 		Message: "Service is healthy",
 		Data: gin.H{
-			"status":     "ok",
-			"monitoring": h.alertService.IsMonitoring(),
+			"status":          "ok",
+			"monitoring":      h.alertService.IsMonitoring(),
+			"streaming":       h.alertService.IsStreaming(),
+			"price_feed":      priceFeed,
+			"last_tick_at":    lastTickAt,
+			"binance_circuit": breaker,
 		},
 	})
 }
@@ -658,6 +1120,7 @@ func (h *Handler) getConfig(c *gin.Context) {
 		Message: "Configuration retrieved successfully",
 		Data: gin.H{
 			"check_interval_ms": checkIntervalMs,
+			"trading_dry_run":   bitcoin.IsTradingDryRun(),
 		},
 	})
 }
@@ -800,8 +1263,9 @@ func (h *Handler) deleteAllAlerts(c *gin.Context) {
 	})
 }
 
-// GetAccountBalance returns the current account balance
-// Example: GET /api/v1/account/balance?symbols=BTC,USDT,COP
+// GetAccountBalance returns the current account balance. Values are in USD; currency
+// (DISPLAY_CURRENCY by default) sets display_currency and fx_rate for the client.
+// Example: GET /api/v1/account/balance?symbols=BTC,USDT,COP&currency=COP
 func (h *Handler) GetAccountBalance(c *gin.Context) {
 	// Get symbols from query parameter or use defaults
 	symbolsParam := c.Query("symbols")
@@ -812,27 +1276,452 @@ func (h *Handler) GetAccountBalance(c *gin.Context) {
 		// Use default symbols from config if none provided
 		symbols = h.configProvider.GetDefaultSymbols()
 	}
-	// Create Binance client
-	binanceClient := bitcoin.NewBinanceClient(
+	// Get account balance
+	balance, err := h.accountBalance(symbols)
+	if err != nil {
+		log.Printf("Error getting account balance: %v", err)
+		c.JSON(http.StatusInternalServerError, Response{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+	h.applyFX(balance, c.DefaultQuery("currency", h.configProvider.GetString("display_currency")))
+	h.applyPnL(balance)
+
+	c.JSON(http.StatusOK, Response{
+		Success: true,
+		Data:    balance,
+	})
+}
+
+// SetUserDataStream makes the account balance come from the user data stream snapshot
+// while the stream is connected, instead of a GetAccountBalance request.
+func (h *Handler) SetUserDataStream(stream *bitcoin.UserDataStream) {
+	h.userStream = stream
+}
+
+// SetOrderBooks makes GET /api/v1/orderbook serve the books tracked for order book alerts from memory.
+func (h *Handler) SetOrderBooks(orderBooks *bitcoin.OrderBooks) {
+	h.orderBooks = orderBooks
+}
+
+// SetIndicatorEngine makes GET /api/v1/indicators serve technical indicators.
+func (h *Handler) SetIndicatorEngine(engine *alerts.IndicatorEngine) {
+	h.indicators = engine
+}
+
+// SetCandleStorage makes GET /api/v1/candles serve the recorded OHLCV candles.
+func (h *Handler) SetCandleStorage(candles *bitcoin.CandleStorage) {
+	h.candles = candles
+}
+
+// SetFXRates enables balances shown in other currencies and alerts written in them,
+// and values fiat balances, such as COP, that have no market on Binance.
+func (h *Handler) SetFXRates(fxRates *alerts.FXRates) {
+	h.fxRates = fxRates
+}
+
+// checkCurrency returns an error if prices can't be converted to currency.
+func (h *Handler) checkCurrency(currency string) error {
+	if currency == storage.DefaultAlertCurrency {
+		return nil
+	}
+	if h.fxRates == nil {
+		return fmt.Errorf("FX rates are not configured, alerts must be in %s", storage.DefaultAlertCurrency)
+	}
+	_, err := h.fxRates.Rate(currency)
+	return err
+}
+
+// checkSymbol returns an error if Binance doesn't list symbol or it isn't trading.
+// Alerts are still accepted when the exchange info can't be loaded.
+func (h *Handler) checkSymbol(symbol string) error {
+	filters, err := h.newBinanceClient().GetSymbolFilters(symbol)
+	if bitcoin.IsInvalidSymbolError(err) {
+		return fmt.Errorf("unknown symbol %s", symbol)
+	}
+	if err != nil {
+		log.Printf("⚠️ Could not validate symbol %s: %v", symbol, err)
+		return nil
+	}
+	if !filters.IsTrading() {
+		return fmt.Errorf("%s is not trading (status %s)", filters.Symbol, filters.Status)
+	}
+	return nil
+}
+
+// applyFX values fiat balances that have no market with their exchange rate, and sets
+// the currency the client should show the balance in. An unknown display currency
+// falls back to USD.
+func (h *Handler) applyFX(balance *bitcoin.AccountBalance, currency string) {
+	balance.DisplayCurrency, balance.FXRate = storage.DefaultAlertCurrency, 1
+	if h.fxRates == nil {
+		return
+	}
+
+	revalued := false
+	for i := range balance.Assets {
+		asset := &balance.Assets[i]
+		if asset.PriceRoute != "" || asset.Total <= 0 || len(asset.Symbol) != 3 {
+			continue
+		}
+		rate, err := h.fxRates.Rate(asset.Symbol)
+		if err != nil {
+			continue
+		}
+		asset.ValueUSD = asset.Total / rate.Rate
+		asset.PriceRoute = "fx:" + rate.Source
+		revalued = true
+	}
+	if revalued {
+		balance.TotalBalance, balance.AvailableBalance = 0, 0
+		for _, asset := range balance.Assets {
+			balance.TotalBalance += asset.ValueUSD
+			if asset.Total > 0 {
+				free, _ := strconv.ParseFloat(asset.Free, 64)
+				balance.AvailableBalance += asset.ValueUSD * free / asset.Total
+			}
+		}
+	}
+
+	rate, err := h.fxRates.Rate(currency)
+	if err != nil {
+		log.Printf("Error getting %s rate, showing balance in USD: %v", currency, err)
+		return
+	}
+	balance.DisplayCurrency, balance.FXRate = rate.Currency, rate.Rate
+}
+
+// accountBalance returns the balance of the given assets from the user data stream
+// snapshot when it is available, or from the Binance API otherwise.
+func (h *Handler) accountBalance(symbols []string) (*bitcoin.AccountBalance, error) {
+	if h.userStream != nil {
+		if balance, ok := h.userStream.Snapshot(symbols); ok {
+			return balance, nil
+		}
+	}
+	return h.newBinanceClient().GetAccountBalance(symbols)
+}
+
+// applyPnL adds cost basis and PnL from the imported trades to a balance.
+// The balance is still shown without them if they can't be computed.
+func (h *Handler) applyPnL(balance *bitcoin.AccountBalance) {
+	if h.tradeHistory == nil {
+		return
+	}
+	if err := h.tradeHistory.ApplyPnL(balance); err != nil {
+		log.Printf("Error computing PnL: %v", err)
+	}
+}
+
+// newBinanceClient creates a Binance client with the configured credentials and base URL.
+func (h *Handler) newBinanceClient() *bitcoin.BinanceClient {
+	return bitcoin.NewBinanceClient(
 		h.configProvider.GetString("binance.api_key"),
 		h.configProvider.GetString("binance.api_secret"),
 		h.configProvider.GetString("binance.base_url"),
-		nil, // No ticker storage needed for account balance
+		nil,
 	)
+}
 
-	// Get account balance
-	balance, err := binanceClient.GetAccountBalance(symbols)
+// orderErrorStatus maps an order error to an HTTP status: invalid orders and
+// orders Binance rejected are the client's fault, anything else is ours.
+func orderErrorStatus(err error) int {
+	var validationErr *bitcoin.OrderValidationError
+	var binanceErr *bitcoin.BinanceError
+	switch {
+	case stderrors.As(err, &validationErr):
+		return http.StatusBadRequest
+	case stderrors.Is(err, bitcoin.ErrTradingDryRun):
+		return http.StatusConflict
+	case bitcoin.IsRateLimitError(err):
+		return http.StatusTooManyRequests
+	case stderrors.As(err, &binanceErr) && binanceErr.Status >= 400 && binanceErr.Status < 500:
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// POST /api/v1/orders
+// placeOrder handles POST /api/v1/orders and places a market, limit or stop-limit order.
+// In dry-run mode the order is only validated and returned with status DRY_RUN.
+func (h *Handler) placeOrder(c *gin.Context) {
+	var request bitcoin.OrderRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, Response{
+			Success: false,
+			Error:   "Invalid request body: " + err.Error(),
+		})
+		return
+	}
+
+	order, err := h.newBinanceClient().PlaceOrder(&request)
 	if err != nil {
-		log.Printf("Error getting account balance: %v", err)
-		c.JSON(http.StatusInternalServerError, Response{
+		log.Printf("Error placing order: %v", err)
+		c.JSON(orderErrorStatus(err), Response{
 			Success: false,
 			Error:   err.Error(),
 		})
 		return
 	}
 
+	message := "Order placed successfully"
+	if order.DryRun {
+		message = "Dry run: order validated but not placed"
+	}
 	c.JSON(http.StatusOK, Response{
 		Success: true,
-		Data:    balance,
+		Data:    order,
+		Message: message,
+	})
+}
+
+// POST /api/v1/orders/test
+// testOrder handles POST /api/v1/orders/test and validates an order without placing it.
+func (h *Handler) testOrder(c *gin.Context) {
+	var request bitcoin.OrderRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, Response{
+			Success: false,
+			Error:   "Invalid request body: " + err.Error(),
+		})
+		return
+	}
+
+	if err := h.newBinanceClient().TestOrder(&request); err != nil {
+		c.JSON(orderErrorStatus(err), Response{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, Response{
+		Success: true,
+		Data:    request,
+		Message: "Order is valid",
 	})
 }
+
+// POST /api/v1/orders/oco
+// placeOCOOrder handles POST /api/v1/orders/oco and places a take profit / stop loss pair.
+func (h *Handler) placeOCOOrder(c *gin.Context) {
+	var request bitcoin.OCORequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, Response{
+			Success: false,
+			Error:   "Invalid request body: " + err.Error(),
+		})
+		return
+	}
+
+	list, err := h.newBinanceClient().PlaceOCOOrder(&request)
+	if err != nil {
+		log.Printf("Error placing OCO order: %v", err)
+		c.JSON(orderErrorStatus(err), Response{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	message := "OCO order placed successfully"
+	if list.DryRun {
+		message = "Dry run: OCO order validated but not placed"
+	}
+	c.JSON(http.StatusOK, Response{
+		Success: true,
+		Data:    list,
+		Message: message,
+	})
+}
+
+// DELETE /api/v1/orders/:id?symbol=BTCUSDT
+// cancelOrder handles DELETE /api/v1/orders/:id and cancels an open order.
+func (h *Handler) cancelOrder(c *gin.Context) {
+	orderID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, Response{
+			Success: false,
+			Error:   "Invalid order ID",
+		})
+		return
+	}
+
+	symbol := c.Query("symbol")
+	if symbol == "" {
+		c.JSON(http.StatusBadRequest, Response{
+			Success: false,
+			Error:   "symbol query parameter is required",
+		})
+		return
+	}
+
+	order, err := h.newBinanceClient().CancelOrder(symbol, orderID)
+	if err != nil {
+		log.Printf("Error cancelling order %d: %v", orderID, err)
+		c.JSON(orderErrorStatus(err), Response{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, Response{
+		Success: true,
+		Data:    order,
+		Message: "Order cancelled successfully",
+	})
+}
+
+// GET /api/v1/account/orders?status=open&from=2024-01-01&to=2024-01-31&symbol=BTCUSDT
+// getAccountOrders handles GET /api/v1/account/orders and returns the account's orders, newest first.
+// status is one of all (default), open, completed or cancelled; from and to accept
+// dates (YYYY-MM-DD, to is inclusive) or RFC3339 times. Without symbol, the pairs of
+// the default symbols against USDT are queried.
+func (h *Handler) getAccountOrders(c *gin.Context) {
+	status := strings.ToLower(c.DefaultQuery("status", "all"))
+	switch status {
+	case "all":
+		status = ""
+	case orderStatusOpen, orderStatusCompleted, orderStatusCancelled:
+	default:
+		c.JSON(http.StatusBadRequest, Response{
+			Success: false,
+			Error:   "Invalid status, must be all, open, completed or cancelled",
+		})
+		return
+	}
+
+	var query bitcoin.OrderQuery
+	var err error
+	if query.StartTime, err = parseDateParam(c.Query("from"), false); err != nil {
+		c.JSON(http.StatusBadRequest, Response{
+			Success: false,
+			Error:   "Invalid from date: " + err.Error(),
+		})
+		return
+	}
+	if query.EndTime, err = parseDateParam(c.Query("to"), true); err != nil {
+		c.JSON(http.StatusBadRequest, Response{
+			Success: false,
+			Error:   "Invalid to date: " + err.Error(),
+		})
+		return
+	}
+
+	symbols := h.orderSymbols()
+	if symbol := c.Query("symbol"); symbol != "" {
+		symbols = []string{strings.ToUpper(symbol)}
+	}
+
+	orders, err := h.accountOrders(h.newBinanceClient(), symbols, status, query)
+	if err != nil {
+		log.Printf("Error getting account orders: %v", err)
+		c.JSON(orderErrorStatus(err), Response{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, Response{
+		Success: true,
+		Data:    orders,
+	})
+}
+
+// accountOrders fetches the orders of the given symbols with the given page status
+// (empty for any), newest first. Open orders come from openOrders and the rest from
+// allOrders, whose executed quantities already give the average fill price.
+func (h *Handler) accountOrders(client *bitcoin.BinanceClient, symbols []string, status string, query bitcoin.OrderQuery) ([]Order, error) {
+	orders := []Order{}
+
+	for _, symbol := range symbols {
+		var binanceOrders []bitcoin.Order
+		var err error
+		if status == orderStatusOpen {
+			binanceOrders, err = client.GetOpenOrders(symbol)
+		} else {
+			query.Symbol = symbol
+			binanceOrders, err = client.GetAllOrders(query)
+		}
+		if err != nil {
+			if bitcoin.IsInvalidSymbolError(err) {
+				log.Printf("⚠️ Skipping orders for unknown symbol %s", symbol)
+				continue
+			}
+			return nil, err
+		}
+
+		for i := range binanceOrders {
+			order := newAccountOrder(&binanceOrders[i])
+			if status != "" && order.Status != status {
+				continue
+			}
+			orders = append(orders, order)
+		}
+	}
+
+	sort.Slice(orders, func(i, j int) bool {
+		return orders[i].Date.After(orders[j].Date)
+	})
+	return orders, nil
+}
+
+// newAccountOrder maps a Binance order to the account page's Order.
+func newAccountOrder(order *bitcoin.Order) Order {
+	status := orderStatusCancelled // CANCELED, REJECTED, EXPIRED and similar
+	switch {
+	case order.IsOpen():
+		status = orderStatusOpen
+	case order.Status == bitcoin.OrderStatusFilled:
+		status = orderStatusCompleted
+	}
+
+	amount, price := order.OrigQty, order.Price
+	if price == 0 {
+		price = order.AvgPrice // Market orders have no limit price
+	}
+	// Closed orders show what was executed, at the average fill price
+	// (CumulativeQuoteQty / ExecutedQty)
+	if status != orderStatusOpen && order.ExecutedQty > 0 {
+		amount, price = order.ExecutedQty, order.AvgPrice
+	}
+
+	return Order{
+		ID:     strconv.FormatInt(order.OrderID, 10),
+		Symbol: order.Symbol,
+		Date:   order.TransactTime,
+		Type:   order.Side + " " + order.Type,
+		Amount: amount,
+		Price:  price,
+		Status: status,
+	}
+}
+
+// orderSymbols returns the trading pairs of the default symbols against USDT.
+func (h *Handler) orderSymbols() []string {
+	return bitcoin.PairsFor(h.configProvider.GetDefaultSymbols(), "USDT")
+}
+
+// parseDateParam parses a YYYY-MM-DD date or RFC3339 time. With endOfDay, a plain
+// date is moved to its last instant so the range includes the whole day.
+func parseDateParam(value string, endOfDay bool) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	t, err := time.ParseInLocation("2006-01-02", value, time.Local)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("expected YYYY-MM-DD or RFC3339, got %q", value)
+	}
+	if endOfDay {
+		t = t.Add(24*time.Hour - time.Millisecond)
+	}
+	return t, nil
+}
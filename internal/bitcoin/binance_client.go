@@ -7,13 +7,19 @@ import (
 	"encoding/hex"
 	"fmt"
 	"log"
+	"net/url"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/go-resty/resty/v2"
+
+	"github.com/cgallonv/btc-alerta-de-precio/internal/storage/models"
 )
 
+// DefaultBinanceBaseURL is the production Binance REST endpoint.
+const DefaultBinanceBaseURL = "https://api.binance.com"
+
 // BinanceClient handles all Binance API operations including account information,
 // price data, and trading functionality.
 //
@@ -31,6 +37,9 @@ type BinanceClient struct {
 	apiKey        string
 	apiSecret     string
 	tickerStorage *TickerStorage
+	clock         *ServerClock  // Binance server time for signed requests
+	tickers       *TickerCache  // Bulk 24hr tickers used to value balances
+	exchangeInfo  *ExchangeInfo // Cached symbol list and trading filters
 }
 
 // AccountBalance represents account balance information from Binance API.
@@ -52,6 +61,14 @@ type AccountBalance struct {
 	Assets           []AssetBalance
 	LastUpdated      time.Time
 
+	// Profit and loss of the assets shown, from imported trades (see TradeHistory.ApplyPnL)
+	RealizedPnL   float64 `json:"realized_pnl"`
+	UnrealizedPnL float64 `json:"unrealized_pnl"`
+
+	// Currency the values are meant to be shown in, and how many units of it one USD buys
+	DisplayCurrency string  `json:"display_currency,omitempty"`
+	FXRate          float64 `json:"fx_rate,omitempty"`
+
 	// Additional fields from Binance API
 	MakerCommission  int `json:"makerCommission"`
 	TakerCommission  int `json:"takerCommission"`
@@ -95,6 +112,14 @@ type AssetBalance struct {
 	Free      string  `json:"free"`   // Original amount available
 	Locked    string  `json:"locked"` // Amount locked in orders
 	Total     float64 `json:"total"`  // Calculated total (free + locked)
+
+	PriceRoute string `json:"price_route,omitempty"` // Pairs used for ValueUSD, empty if the asset has no market
+
+	// Cost basis and profit and loss from imported trades, zero if the asset has none
+	AvgCost       float64 `json:"avg_cost"`       // Average cost per unit, fees included
+	CostBasis     float64 `json:"cost_basis"`     // AvgCost times the quantity covered by trades
+	RealizedPnL   float64 `json:"realized_pnl"`   // Profit from past sales
+	UnrealizedPnL float64 `json:"unrealized_pnl"` // Value of the covered quantity minus CostBasis
 }
 
 // PriceData represents current price information for an asset.
@@ -109,15 +134,115 @@ type AssetBalance struct {
 //	}
 //	fmt.Printf("BTC: $%.2f (%+.2f%%)\n", price.Price, price.PriceChangePercent)
 type PriceData struct {
+	Symbol             string    `json:"symbol"`
 	Price              float64   `json:"price"`
 	PriceChangePercent float64   `json:"price_change_percent"`
 	Currency           string    `json:"currency"`
 	Timestamp          time.Time `json:"timestamp"`
 	Source             string    `json:"source"`
+
+	// Quotes holds the per-provider prices behind an aggregated price (Source == SourceAggregate)
+	Quotes []PriceQuote `json:"quotes,omitempty"`
+
+	// Ticker is the Binance 24h ticker behind the tick, nil for other sources
+	Ticker *models.TickerData `json:"-"`
+}
+
+// IndicatorReading is the value of a technical indicator and the level it's compared
+// against (a threshold, a moving average or a band), on this tick and the previous one.
+type IndicatorReading struct {
+	Value    float64
+	Level    float64
+	Previous *IndicatorReading // nil on the first reading
+}
+
+// PricePoint is a symbol's price at a point in time.
+type PricePoint struct {
+	Price     float64   `json:"price"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// PriceRange is the lowest and highest price seen in a time window, and its oldest
+// point, which tells how far back the history of the window reaches.
+type PriceRange struct {
+	Oldest PricePoint `json:"oldest"`
+	Low    PricePoint `json:"low"`
+	High   PricePoint `json:"high"`
+}
+
+// Include widens the range to cover point.
+func (r *PriceRange) Include(point PricePoint) {
+	if r.Oldest.Timestamp.IsZero() || point.Timestamp.Before(r.Oldest.Timestamp) {
+		r.Oldest = point
+	}
+	if r.Low.Timestamp.IsZero() || point.Price < r.Low.Price {
+		r.Low = point
+	}
+	if r.High.Timestamp.IsZero() || point.Price > r.High.Price {
+		r.High = point
+	}
+}
+
+// PriceQuote is a single provider's price that went into an aggregated PriceData.
+// Rejected quotes deviated too far from the median and were left out of the result.
+//
+// Example usage:
+//
+//	for _, quote := range price.Quotes {
+//	    fmt.Printf("%s: $%.2f (rejected: %v)\n", quote.Source, quote.Price, quote.Rejected)
+//	}
+type PriceQuote struct {
+	Source             string  `json:"source"`
+	Price              float64 `json:"price"`
+	PriceChangePercent float64 `json:"price_change_percent"`
+	Rejected           bool    `json:"rejected"`
+
+	// Ticker is the provider's Binance 24h ticker, nil for other sources
+	Ticker *models.TickerData `json:"-"`
+}
+
+// UsesBinanceChange reports whether PriceChangePercent is Binance's rolling 24h change,
+// either because the price came from Binance or because an accepted Binance quote
+// supplied the percentage of an aggregated price.
+//
+// Example usage:
+//
+//	if price.UsesBinanceChange() {
+//	    log.Printf("24h change: %+.2f%%", price.PriceChangePercent)
+//	}
+func (p *PriceData) UsesBinanceChange() bool {
+	if p.Source == SourceBinance {
+		return true
+	}
+	if p.Source != SourceAggregate {
+		return false
+	}
+	for _, quote := range p.Quotes {
+		if quote.Source == SourceBinance && !quote.Rejected {
+			return true
+		}
+	}
+	return false
 }
 
+// DefaultSymbol is the trading pair used when no symbol is specified.
+const DefaultSymbol = "BTCUSDT"
+
+// Price sources reported in PriceData.Source.
+const (
+	SourceBinance  = "Binance"
+	SourceCoinbase = "Coinbase"
+	SourceKraken   = "Kraken"
+
+	// SourceAggregate marks a median price built from several providers
+	SourceAggregate = "Aggregate"
+)
+
 // NewBinanceClient creates a new Binance API client with the provided API credentials.
 // The client handles authentication and provides methods for accessing various Binance API endpoints.
+// Every request passes through the CircuitBreaker and WeightLimiter shared by all clients
+// of the same base URL, and failed GET requests are retried according to the RetryPolicy.
+// Signed requests are timestamped with the shared ServerClock of the base URL.
 //
 // Example usage:
 //
@@ -133,18 +258,24 @@ func NewBinanceClient(apiKey, apiSecret string, baseURL string, tickerStorage *T
 	client.SetHeader("X-MBX-APIKEY", apiKey)
 
 	if baseURL == "" {
-		baseURL = "https://api.binance.com" // Fallback to production if not specified
+		baseURL = DefaultBinanceBaseURL // Fallback to production if not specified
 		log.Printf("⚠️ No Binance URL specified, using default: %s", baseURL)
 	} else {
 		log.Printf("🔧 Using Binance API: %s", baseURL)
 	}
 	client.SetBaseURL(baseURL)
+	CircuitBreakerFor(baseURL).attach(client)
+	WeightLimiterFor(baseURL).attach(client)
+	currentRetryPolicy().attach(client)
 
 	return &BinanceClient{
 		httpClient:    client,
 		apiKey:        apiKey,
 		apiSecret:     apiSecret,
 		tickerStorage: tickerStorage,
+		clock:         ServerClockFor(baseURL),
+		tickers:       TickerCacheFor(baseURL),
+		exchangeInfo:  ExchangeInfoFor(baseURL),
 	}
 }
 
@@ -163,10 +294,6 @@ func NewBinanceClient(apiKey, apiSecret string, baseURL string, tickerStorage *T
 //	}
 //	fmt.Printf("Total Balance: $%.2f\n", balance.TotalBalance)
 func (c *BinanceClient) GetAccountBalance(symbols []string) (*AccountBalance, error) {
-	timestamp := time.Now().UnixMilli()
-	queryString := fmt.Sprintf("timestamp=%d", timestamp)
-	signature := c.generateSignature(queryString)
-
 	log.Printf("🔄 Fetching account balance from Binance API")
 
 	var response struct {
@@ -195,11 +322,7 @@ func (c *BinanceClient) GetAccountBalance(symbols []string) (*AccountBalance, er
 		Permissions []string `json:"permissions"`
 	}
 
-	resp, err := c.httpClient.R().
-		SetQueryParam("timestamp", fmt.Sprintf("%d", timestamp)).
-		SetQueryParam("signature", signature).
-		SetResult(&response).
-		Get("/api/v3/account")
+	resp, err := c.signedRequest(resty.MethodGet, "/api/v3/account", nil, &response)
 
 	if err != nil {
 		log.Printf("❌ Error fetching account balance: %v", err)
@@ -282,35 +405,21 @@ func (c *BinanceClient) GetAccountBalance(symbols []string) (*AccountBalance, er
 	}
 
 	// Log the filtered balances before processing
-	log.Printf("📋 Processing %d filtered assets: %v", len(validBalances), func() []string {
-		var assets []string
-		for _, b := range validBalances {
-			assets = append(assets, b.asset)
-		}
-		return assets
-	}())
+	assets := make([]string, 0, len(validBalances))
+	for _, b := range validBalances {
+		assets = append(assets, b.asset)
+	}
+	log.Printf("📋 Processing %d filtered assets: %v", len(validBalances), assets)
+
+	// Value every asset from a single bulk ticker request
+	quotes, err := c.GetAssetQuotes(assets)
+	if err != nil {
+		log.Printf("⚠️ Error getting prices: %v", err)
+	}
 
-	// Now process only the filtered balances
 	for _, balance := range validBalances {
-		// Skip price lookup for non-tradeable assets (like COP)
-		var price, change24h float64
-		if balance.asset != "COP" {
-			// Get current price for the asset
-			price, err = c.GetAssetPrice(balance.asset + "USDT")
-			if err != nil {
-				log.Printf("⚠️ Error getting price for %s: %v", balance.asset, err)
-				price = 0
-			}
-
-			// Get 24h change
-			change24h, err = c.Get24hChange(balance.asset + "USDT")
-			if err != nil {
-				log.Printf("⚠️ Error getting 24h change for %s: %v", balance.asset, err)
-				change24h = 0
-			}
-		} else {
-			log.Printf("ℹ️ Skipping price lookup for non-tradeable asset: %s", balance.asset)
-		}
+		quote := quotes[balance.asset]
+		price, change24h := quote.Price, quote.Change24h
 
 		valueUSD := balance.total * price
 		accountBalance.TotalBalance += valueUSD
@@ -323,6 +432,8 @@ func (c *BinanceClient) GetAccountBalance(symbols []string) (*AccountBalance, er
 			Total:     balance.total,
 			ValueUSD:  valueUSD,
 			Change24h: change24h,
+
+			PriceRoute: quote.Route,
 		})
 
 		log.Printf("📊 Asset %s: Free: %.8f, Locked: %.8f, Total: %.8f, Value: $%.2f, Change: %.2f%%",
@@ -350,12 +461,26 @@ func (c *BinanceClient) GetAccountBalance(symbols []string) (*AccountBalance, er
 //	}
 //	fmt.Printf("BTC: $%.2f (%+.2f%%)\n", price.Price, price.PriceChangePercent)
 func (c *BinanceClient) GetCurrentPrice() (*PriceData, error) {
-	log.Printf("🔄 Fetching BTC price from Binance API")
+	return c.GetSymbolPrice(DefaultSymbol)
+}
+
+// GetSymbolPrice fetches the current price and 24h change for a trading pair.
+//
+// Example usage:
+//
+//	price, err := client.GetSymbolPrice("ETHUSDT")
+//	if err != nil {
+//	    return nil, err
+//	}
+//	fmt.Println(price)
+func (c *BinanceClient) GetSymbolPrice(symbol string) (*PriceData, error) {
+	log.Printf("🔄 Fetching %s price from Binance API", symbol)
 
 	var response Ticker24hResponse
 	resp, err := c.httpClient.R().
+		SetQueryParam("symbol", symbol).
 		SetResult(&response).
-		Get("/api/v3/ticker/24hr?symbol=BTCUSDT")
+		Get("/api/v3/ticker/24hr")
 
 	if err != nil {
 		log.Printf("❌ Error fetching price: %v", err)
@@ -370,32 +495,63 @@ func (c *BinanceClient) GetCurrentPrice() (*PriceData, error) {
 
 	// Store ticker data if storage is configured
 	if c.tickerStorage != nil {
-		if err := c.tickerStorage.StoreTicker24h("BTCUSDT", &response); err != nil {
+		if err := c.tickerStorage.StoreTicker24h(symbol, &response); err != nil {
 			log.Printf("❌ Error storing ticker data: %v", err)
 			// Don't return error here, continue with price update
 		}
 	}
 
-	price, err := strconv.ParseFloat(response.LastPrice, 64)
+	priceData, err := NewPriceDataFromTicker(&response)
+	if err != nil {
+		log.Printf("❌ Error parsing ticker: %v", err)
+		return nil, err
+	}
+
+	log.Printf("✅ %s price fetched successfully: $%.2f (%+.2f%%)", symbol, priceData.Price, priceData.PriceChangePercent)
+
+	return priceData, nil
+}
+
+// Name returns the provider name reported in PriceData.Source.
+func (c *BinanceClient) Name() string {
+	return SourceBinance
+}
+
+// NewPriceDataFromTicker converts a Binance 24hr ticker into PriceData.
+// It is shared by the REST client and the WebSocket stream so both produce
+// identical price updates.
+//
+// Example usage:
+//
+//	priceData, err := NewPriceDataFromTicker(&ticker)
+//	if err != nil {
+//	    return err
+//	}
+//	fmt.Println(priceData)
+func NewPriceDataFromTicker(ticker *Ticker24hResponse) (*PriceData, error) {
+	price, err := strconv.ParseFloat(ticker.LastPrice, 64)
 	if err != nil {
-		log.Printf("❌ Error parsing price: %v", err)
 		return nil, fmt.Errorf("error parsing price from Binance: %w", err)
 	}
 
-	priceChangePercent, err := strconv.ParseFloat(response.PriceChangePercent, 64)
+	priceChangePercent, err := strconv.ParseFloat(ticker.PriceChangePercent, 64)
 	if err != nil {
-		log.Printf("❌ Error parsing price change: %v", err)
 		return nil, fmt.Errorf("error parsing price change percent from Binance: %w", err)
 	}
 
-	log.Printf("✅ BTC price fetched successfully: $%.2f (%+.2f%%)", price, priceChangePercent)
+	symbol := ticker.Symbol
+	if symbol == "" {
+		symbol = DefaultSymbol
+	}
 
 	return &PriceData{
+		Symbol:             symbol,
 		Price:              price,
 		PriceChangePercent: priceChangePercent,
 		Currency:           "USD",
 		Timestamp:          time.Now(),
-		Source:             "Binance",
+		Source:             SourceBinance,
+		Ticker:             NewTickerData(symbol, ticker),
 	}, nil
 }
 
@@ -499,6 +655,81 @@ func (c *BinanceClient) Get24hChange(symbol string) (float64, error) {
 	return change, nil
 }
 
+// getAssetTicker fetches the price and 24h change of a symbol with one 24hr ticker request,
+// instead of the two requests GetAssetPrice and Get24hChange would make.
+func (c *BinanceClient) getAssetTicker(symbol string) (price, change24h float64, err error) {
+	// Special case for USDT
+	if symbol == "USDTUSDT" {
+		return 1.0, 0.0, nil // USDT is stable, always 1:1 with USD
+	}
+
+	var response Ticker24hResponse
+	resp, err := c.httpClient.R().
+		SetQueryParam("symbol", symbol).
+		SetResult(&response).
+		Get("/api/v3/ticker/24hr")
+
+	if err != nil {
+		return 0, 0, fmt.Errorf("error fetching ticker from Binance: %w", err)
+	}
+
+	if resp.StatusCode() != 200 {
+		return 0, 0, NewBinanceError(resp.StatusCode(), resp.String())
+	}
+
+	price, err = strconv.ParseFloat(response.LastPrice, 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("error parsing price from Binance: %w", err)
+	}
+
+	return price, stringToFloat64(response.PriceChangePercent), nil
+}
+
+// signedRequest sends a request to a SIGNED (TRADE or USER_DATA) endpoint.
+// It adds timestamp and recvWindow to params, taking the timestamp from the server clock,
+// and signs the resulting query string. If Binance rejects the timestamp (-1021) the
+// clock is re-synced and the request is sent once more with a fresh timestamp.
+// The response is returned as is; callers check the status code.
+func (c *BinanceClient) signedRequest(method, path string, params url.Values, result interface{}) (*resty.Response, error) {
+	if c.clock.needsSync() {
+		if err := c.clock.sync(c.httpClient); err != nil {
+			// Keep signing with the last known offset, Binance will tell us if it is wrong
+			log.Printf("⚠️ Error syncing Binance server time: %v", err)
+		}
+	}
+
+	for attempt := 1; ; attempt++ {
+		query := url.Values{}
+		for key, values := range params {
+			query[key] = values
+		}
+		query.Set("timestamp", strconv.FormatInt(c.clock.Now().UnixMilli(), 10))
+		query.Set("recvWindow", strconv.FormatInt(c.clock.RecvWindow().Milliseconds(), 10))
+
+		// Sign and send the exact query string; letting resty re-encode it could reorder the signature
+		queryString := query.Encode()
+		signedURL := path + "?" + queryString + "&signature=" + c.generateSignature(queryString)
+
+		request := c.httpClient.R()
+		if result != nil {
+			request.SetResult(result)
+		}
+		resp, err := request.Execute(method, signedURL)
+		if err != nil || resp.StatusCode() == 200 || attempt > 1 {
+			return resp, err
+		}
+
+		if binanceErr := NewBinanceError(resp.StatusCode(), resp.String()); binanceErr.Code != ErrInvalidTimestamp {
+			return resp, nil
+		}
+		log.Printf("🕒 Binance rejected request timestamp (offset %v), re-syncing server time", c.clock.Offset())
+		if err := c.clock.sync(c.httpClient); err != nil {
+			log.Printf("⚠️ Error syncing Binance server time: %v", err)
+			return resp, nil
+		}
+	}
+}
+
 // generateSignature creates an HMAC SHA256 signature for Binance API authentication.
 func (c *BinanceClient) generateSignature(queryString string) string {
 	mac := hmac.New(sha256.New, []byte(c.apiSecret))
@@ -518,138 +749,17 @@ func (p *PriceData) FormatPriceChange() string {
 
 // String returns a string representation of the price data.
 func (p *PriceData) String() string {
-	return fmt.Sprintf("BTC: %s (%s) [%s]",
+	symbol := p.Symbol
+	if symbol == "" || symbol == DefaultSymbol {
+		symbol = "BTC"
+	}
+	return fmt.Sprintf("%s: %s (%s) [%s]",
+		symbol,
 		p.FormatPrice(),
 		p.FormatPriceChange(),
 		p.Source)
 }
 
-// GetHistoricalKlines fetches historical kline/candlestick data for a symbol with automatic pagination.
-// Interval can be: 1m,3m,5m,15m,30m,1h,2h,4h,6h,8h,12h,1d,3d,1w,1M
-// This method automatically handles pagination to fetch all data in the specified time range,
-// not limited by Binance API's 1000 record limit per request.
-//
-// Example usage:
-//
-//	klines, err := client.GetHistoricalKlines("BTCUSDT", "1m", time.Now().Add(-60*24*time.Hour), time.Now())
-//	if err != nil {
-//	    log.Printf("Error: %v", err)
-//	    return
-//	}
-//	fmt.Printf("Fetched %d historical records\n", len(klines))
-func (c *BinanceClient) GetHistoricalKlines(symbol, interval string, startTime, endTime time.Time) ([]Ticker24hResponse, error) {
-	log.Printf("🔄 Fetching historical klines for %s from %s to %s", symbol, startTime.Format(time.RFC3339), endTime.Format(time.RFC3339))
-
-	var allTickers []Ticker24hResponse
-	currentStartTime := startTime
-	requestCount := 0
-
-	for currentStartTime.Before(endTime) {
-		requestCount++
-		log.Printf("📥 Making request %d for time range %s to %s", requestCount, currentStartTime.Format(time.RFC3339), endTime.Format(time.RFC3339))
-
-		var klines [][]interface{}
-		resp, err := c.httpClient.R().
-			SetQueryParams(map[string]string{
-				"symbol":    symbol,
-				"interval":  interval,
-				"startTime": fmt.Sprintf("%d", currentStartTime.UnixMilli()),
-				"endTime":   fmt.Sprintf("%d", endTime.UnixMilli()),
-				"limit":     "1000", // Max limit per request
-			}).
-			SetResult(&klines).
-			Get("/api/v3/klines")
-
-		if err != nil {
-			log.Printf("❌ Error fetching historical klines (request %d): %v", requestCount, err)
-			return nil, fmt.Errorf("error fetching historical klines: %w", err)
-		}
-
-		if resp.StatusCode() != 200 {
-			binanceErr := NewBinanceError(resp.StatusCode(), resp.String())
-			log.Printf("❌ Binance API error (request %d): %v", requestCount, binanceErr)
-			return nil, binanceErr
-		}
-
-		// Si no hay más datos, terminar
-		if len(klines) == 0 {
-			log.Printf("📭 No more data available, ending pagination")
-			break
-		}
-
-		// Convertir klines a tickers usando función helper
-		batchTickers := c.convertKlinesToTickers(klines, symbol)
-		allTickers = append(allTickers, batchTickers...)
-
-		log.Printf("✅ Request %d completed: fetched %d records", requestCount, len(batchTickers))
-
-		// Actualizar currentStartTime para la siguiente página
-		// Usar el closeTime del último registro + 1ms para evitar duplicados
-		lastKline := klines[len(klines)-1]
-		lastCloseTime := time.UnixMilli(int64(lastKline[6].(float64)))
-		currentStartTime = lastCloseTime.Add(time.Millisecond)
-
-		// Si obtuvimos menos de 1000 registros, hemos llegado al final
-		if len(klines) < 1000 {
-			log.Printf("📋 Received %d records (less than 1000), pagination complete", len(klines))
-			break
-		}
-
-		// Rate limiting entre requests para evitar límites de API
-		time.Sleep(100 * time.Millisecond)
-	}
-
-	log.Printf("✅ Historical klines fetch completed: %d total records from %d API requests", len(allTickers), requestCount)
-	return allTickers, nil
-}
-
-// convertKlinesToTickers converts raw kline data from Binance API to Ticker24hResponse format.
-// This helper function extracts the conversion logic for reusability and cleaner code.
-//
-// Example usage:
-//
-//	tickers := client.convertKlinesToTickers(klines, "BTCUSDT")
-//	fmt.Printf("Converted %d klines to tickers\n", len(tickers))
-func (c *BinanceClient) convertKlinesToTickers(klines [][]interface{}, symbol string) []Ticker24hResponse {
-	var tickers []Ticker24hResponse
-
-	for _, k := range klines {
-		// Convert kline data to Ticker24hResponse format
-		openTime := k[0].(float64)
-		closeTime := k[6].(float64)
-		openPrice := k[1].(string)
-		highPrice := k[2].(string)
-		lowPrice := k[3].(string)
-		closePrice := k[4].(string)
-		volume := k[5].(string)
-		quoteVolume := k[7].(string)
-		trades := k[8].(float64)
-
-		priceChange := fmt.Sprintf("%f", stringToFloat64(closePrice)-stringToFloat64(openPrice))
-		priceChangePercent := fmt.Sprintf("%f", (stringToFloat64(closePrice)-stringToFloat64(openPrice))/stringToFloat64(openPrice)*100)
-
-		ticker := Ticker24hResponse{
-			Symbol:             symbol,
-			OpenTime:           int64(openTime),
-			CloseTime:          int64(closeTime),
-			OpenPrice:          openPrice,
-			HighPrice:          highPrice,
-			LowPrice:           lowPrice,
-			LastPrice:          closePrice,
-			Volume:             volume,
-			QuoteVolume:        quoteVolume,
-			Count:              int64(trades),
-			PriceChange:        priceChange,
-			PriceChangePercent: priceChangePercent,
-			WeightedAvgPrice:   closePrice, // Using close price as weighted avg
-			PrevClosePrice:     openPrice,  // Using open price as prev close
-		}
-		tickers = append(tickers, ticker)
-	}
-
-	return tickers
-}
-
 // Helper function to convert string to float64
 func stringToFloat64(s string) float64 {
 	f, _ := strconv.ParseFloat(s, 64)
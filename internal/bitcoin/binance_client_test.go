@@ -0,0 +1,62 @@
+package bitcoin
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cgallonv/btc-alerta-de-precio/internal/bitcoin/fakebinance"
+)
+
+func TestBinanceClient_AgainstFakeBinance(t *testing.T) {
+	server := fakebinance.New()
+	defer server.Close()
+	server.SetCredentials("key", "secret")
+	server.AddSymbol(fakebinance.Symbol{Symbol: "BTCUSDT", BaseAsset: "BTC", QuoteAsset: "USDT", Price: 50000, Change24h: 2})
+	server.AddSymbol(fakebinance.Symbol{Symbol: "ETHBTC", BaseAsset: "ETH", QuoteAsset: "BTC", Price: 0.05})
+	server.SetPricePath("BTCUSDT", 50000, 51000)
+	server.SetBalance("BTC", 0.5, 0.1)
+	server.SetBalance("ETH", 2, 0)
+
+	start := time.Now().Truncate(time.Minute).Add(-3 * time.Minute)
+	for i := 0; i < 3; i++ {
+		server.AddKlines("BTCUSDT", "1m", fakebinance.Kline{
+			OpenTime: start.Add(time.Duration(i) * time.Minute),
+			Open:     50000, High: 50100, Low: 49900, Close: 50050, Volume: 1,
+		})
+	}
+
+	client := NewBinanceClient("key", "secret", server.URL, nil)
+
+	// Scripted price path
+	for _, want := range []float64{50000, 51000, 51000} {
+		price, err := client.GetSymbolPrice("BTCUSDT")
+		require.NoError(t, err)
+		assert.Equal(t, want, price.Price)
+		assert.Equal(t, 2.0, price.PriceChangePercent)
+	}
+
+	// Signed request, valued from the bulk ticker with ETH routed through BTC
+	balance, err := client.GetAccountBalance(nil)
+	require.NoError(t, err)
+	assert.InDelta(t, 0.6*51000+2*0.05*51000, balance.TotalBalance, 1e-6)
+
+	klines, err := client.GetHistoricalKlines("BTCUSDT", "1m", start, time.Now())
+	require.NoError(t, err)
+	require.Len(t, klines, 3)
+	assert.Equal(t, 50050.0, klines[2].Close)
+
+	// A bad secret is rejected by the signature check
+	_, err = NewBinanceClient("key", "wrong", server.URL, nil).GetAccountBalance(nil)
+	var binanceErr *BinanceError
+	require.ErrorAs(t, err, &binanceErr)
+	assert.Equal(t, ErrInvalidSignature, binanceErr.Code)
+
+	// 429s are reported as rate limiting and put the client in back-off
+	server.RateLimitNext("/api/v3/ticker/24hr", 1, time.Second)
+	_, err = client.GetSymbolPrice("ETHBTC")
+	assert.True(t, IsRateLimitError(err))
+	assert.True(t, WeightLimiterFor(server.URL).Stats().BannedUntil.After(time.Now()))
+}
@@ -0,0 +1,386 @@
+// Package bitcoin provides functionality for interacting with cryptocurrency APIs.
+package bitcoin
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// DefaultBinanceStreamURL is the production Binance market data WebSocket endpoint.
+const DefaultBinanceStreamURL = "wss://stream.binance.com:9443"
+
+// TickerStreamHandler is called for every ticker event received from the stream.
+type TickerStreamHandler func(ticker *Ticker24hResponse)
+
+// BinanceStream consumes the Binance @ticker / @miniTicker WebSocket streams.
+// It keeps the connection alive with periodic pings and reconnects automatically
+// with exponential backoff when the socket drops.
+//
+// Example usage:
+//
+//	stream := NewBinanceStream("", []string{"BTCUSDT"})
+//	go stream.Run(ctx, func(ticker *Ticker24hResponse) {
+//	    log.Printf("%s: %s", ticker.Symbol, ticker.LastPrice)
+//	})
+type BinanceStream struct {
+	baseURL string
+	symbols []string
+	dialer  *websocket.Dialer
+
+	// Keepalive and reconnect settings
+	pingInterval      time.Duration
+	pongWait          time.Duration
+	reconnectDelay    time.Duration
+	maxReconnectDelay time.Duration
+
+	// Connection state
+	conn        *websocket.Conn
+	connected   bool
+	resubscribe bool
+	downSince   time.Time
+	lastMessage time.Time
+	stateMux    sync.RWMutex
+}
+
+// NewBinanceStream creates a new ticker stream for the given symbols.
+// If baseURL is empty, DefaultBinanceStreamURL is used.
+//
+// Example usage:
+//
+//	stream := NewBinanceStream("wss://stream.binance.com:9443", []string{"BTCUSDT"})
+func NewBinanceStream(baseURL string, symbols []string) *BinanceStream {
+	if baseURL == "" {
+		baseURL = DefaultBinanceStreamURL
+	}
+
+	return &BinanceStream{
+		baseURL:           strings.TrimRight(baseURL, "/"),
+		symbols:           symbols,
+		dialer:            websocket.DefaultDialer,
+		pingInterval:      20 * time.Second,
+		pongWait:          60 * time.Second,
+		reconnectDelay:    time.Second,
+		maxReconnectDelay: time.Minute,
+		downSince:         time.Now(),
+	}
+}
+
+// IsConnected returns true if the WebSocket connection is currently open.
+func (s *BinanceStream) IsConnected() bool {
+	s.stateMux.RLock()
+	defer s.stateMux.RUnlock()
+	return s.connected
+}
+
+// DownSince returns when the stream last lost its connection.
+// The zero time is returned while the stream is connected.
+func (s *BinanceStream) DownSince() time.Time {
+	s.stateMux.RLock()
+	defer s.stateMux.RUnlock()
+	if s.connected {
+		return time.Time{}
+	}
+	return s.downSince
+}
+
+// LastMessage returns the time the last ticker event was received.
+func (s *BinanceStream) LastMessage() time.Time {
+	s.stateMux.RLock()
+	defer s.stateMux.RUnlock()
+	return s.lastMessage
+}
+
+// Symbols returns the symbols the stream is subscribed to.
+func (s *BinanceStream) Symbols() []string {
+	s.stateMux.RLock()
+	defer s.stateMux.RUnlock()
+	return append([]string(nil), s.symbols...)
+}
+
+// SetSymbols changes the subscribed symbols.
+// If the set differs from the current one, the open connection is closed
+// and the stream reconnects immediately with the new subscription.
+//
+// Example usage:
+//
+//	stream.SetSymbols([]string{"BTCUSDT", "ETHUSDT"})
+func (s *BinanceStream) SetSymbols(symbols []string) {
+	s.stateMux.Lock()
+	defer s.stateMux.Unlock()
+
+	if sameSymbols(s.symbols, symbols) {
+		return
+	}
+
+	s.symbols = append([]string(nil), symbols...)
+	if s.conn != nil {
+		log.Printf("🔌 Binance stream symbols changed to %v, resubscribing", symbols)
+		s.resubscribe = true
+		s.conn.Close()
+	}
+}
+
+// URL returns the combined stream URL for the configured symbols.
+//
+// Example usage:
+//
+//	stream.URL() // wss://stream.binance.com:9443/stream?streams=btcusdt@ticker
+func (s *BinanceStream) URL() string {
+	symbols := s.Symbols()
+	streams := make([]string, 0, len(symbols))
+	for _, symbol := range symbols {
+		streams = append(streams, strings.ToLower(strings.TrimSpace(symbol))+"@ticker")
+	}
+	return fmt.Sprintf("%s/stream?streams=%s", s.baseURL, strings.Join(streams, "/"))
+}
+
+// Run connects to the stream and delivers ticker events to handler until ctx is cancelled.
+// Connection failures are retried with exponential backoff.
+//
+// Example usage:
+//
+//	ctx, cancel := context.WithCancel(context.Background())
+//	defer cancel()
+//	go stream.Run(ctx, handler)
+func (s *BinanceStream) Run(ctx context.Context, handler TickerStreamHandler) {
+	delay := s.reconnectDelay
+
+	for {
+		connectedAt := time.Now()
+		err := s.connectAndRead(ctx, handler)
+		s.setConnected(false)
+
+		if ctx.Err() != nil {
+			log.Printf("🔌 Binance stream stopped")
+			return
+		}
+
+		if s.consumeResubscribe() {
+			delay = s.reconnectDelay
+			continue
+		}
+
+		// Reset backoff after a connection that stayed up for a while
+		if time.Since(connectedAt) > s.maxReconnectDelay {
+			delay = s.reconnectDelay
+		}
+
+		log.Printf("⚠️ Binance stream disconnected: %v (reconnecting in %v)", err, delay)
+
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return
+		}
+
+		delay *= 2
+		if delay > s.maxReconnectDelay {
+			delay = s.maxReconnectDelay
+		}
+	}
+}
+
+// connectAndRead opens a single connection and reads messages until it fails.
+func (s *BinanceStream) connectAndRead(ctx context.Context, handler TickerStreamHandler) error {
+	conn, _, err := s.dialer.DialContext(ctx, s.URL(), nil)
+	if err != nil {
+		return fmt.Errorf("error connecting to Binance stream: %w", err)
+	}
+	defer conn.Close()
+
+	log.Printf("🔌 Connected to Binance stream: %s", s.URL())
+	s.setConn(conn)
+
+	conn.SetReadDeadline(time.Now().Add(s.pongWait))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(s.pongWait))
+	})
+
+	// Close the connection when the context is cancelled so ReadMessage unblocks
+	done := make(chan struct{})
+	defer close(done)
+	go s.keepAlive(ctx, conn, done)
+
+	for {
+		_, message, err := conn.ReadMessage()
+		if err != nil {
+			return fmt.Errorf("error reading from Binance stream: %w", err)
+		}
+		conn.SetReadDeadline(time.Now().Add(s.pongWait))
+
+		ticker, err := parseStreamTicker(message)
+		if err != nil {
+			log.Printf("⚠️ Ignoring Binance stream message: %v", err)
+			continue
+		}
+
+		s.stateMux.Lock()
+		s.lastMessage = time.Now()
+		s.stateMux.Unlock()
+
+		handler(ticker)
+	}
+}
+
+// keepAlive sends periodic pings and closes the connection when ctx is cancelled.
+func (s *BinanceStream) keepAlive(ctx context.Context, conn *websocket.Conn, done <-chan struct{}) {
+	ticker := time.NewTicker(s.pingInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			deadline := time.Now().Add(10 * time.Second)
+			if err := conn.WriteControl(websocket.PingMessage, nil, deadline); err != nil {
+				log.Printf("⚠️ Binance stream ping failed: %v", err)
+				conn.Close()
+				return
+			}
+		case <-ctx.Done():
+			conn.WriteControl(websocket.CloseMessage,
+				websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""),
+				time.Now().Add(time.Second))
+			conn.Close()
+			return
+		case <-done:
+			return
+		}
+	}
+}
+
+// setConn records the open connection so SetSymbols can interrupt it.
+func (s *BinanceStream) setConn(conn *websocket.Conn) {
+	s.stateMux.Lock()
+	defer s.stateMux.Unlock()
+	s.conn = conn
+	s.connected = true
+}
+
+// setConnected updates the connection state.
+func (s *BinanceStream) setConnected(connected bool) {
+	s.stateMux.Lock()
+	defer s.stateMux.Unlock()
+
+	if s.connected && !connected {
+		s.downSince = time.Now()
+	}
+	if !connected {
+		s.conn = nil
+	}
+	s.connected = connected
+}
+
+// consumeResubscribe reports and clears a pending resubscription request.
+func (s *BinanceStream) consumeResubscribe() bool {
+	s.stateMux.Lock()
+	defer s.stateMux.Unlock()
+	resubscribe := s.resubscribe
+	s.resubscribe = false
+	return resubscribe
+}
+
+// sameSymbols reports whether two symbol lists contain the same symbols, ignoring order.
+func sameSymbols(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	seen := make(map[string]int, len(a))
+	for _, symbol := range a {
+		seen[strings.ToUpper(symbol)]++
+	}
+	for _, symbol := range b {
+		symbol = strings.ToUpper(symbol)
+		if seen[symbol] == 0 {
+			return false
+		}
+		seen[symbol]--
+	}
+	return true
+}
+
+// streamTickerEvent represents a 24hrTicker or 24hrMiniTicker event payload.
+type streamTickerEvent struct {
+	EventType          string `json:"e"`
+	EventTime          int64  `json:"E"`
+	Symbol             string `json:"s"`
+	PriceChange        string `json:"p"`
+	PriceChangePercent string `json:"P"`
+	WeightedAvgPrice   string `json:"w"`
+	PrevClosePrice     string `json:"x"`
+	LastPrice          string `json:"c"`
+	LastQty            string `json:"Q"`
+	OpenPrice          string `json:"o"`
+	HighPrice          string `json:"h"`
+	LowPrice           string `json:"l"`
+	Volume             string `json:"v"`
+	QuoteVolume        string `json:"q"`
+	OpenTime           int64  `json:"O"`
+	CloseTime          int64  `json:"C"`
+	FirstID            int64  `json:"F"`
+	LastID             int64  `json:"L"`
+	Count              int64  `json:"n"`
+}
+
+// parseStreamTicker converts a raw stream message into a Ticker24hResponse.
+// It accepts both combined stream messages ({"stream":...,"data":{...}}) and raw events.
+func parseStreamTicker(message []byte) (*Ticker24hResponse, error) {
+	var envelope struct {
+		Stream string          `json:"stream"`
+		Data   json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(message, &envelope); err != nil {
+		return nil, fmt.Errorf("error decoding stream message: %w", err)
+	}
+
+	payload := message
+	if len(envelope.Data) > 0 {
+		payload = envelope.Data
+	}
+
+	var event streamTickerEvent
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return nil, fmt.Errorf("error decoding ticker event: %w", err)
+	}
+
+	switch event.EventType {
+	case "24hrTicker":
+	case "24hrMiniTicker":
+		// Mini tickers don't carry the change fields, derive them from open/close
+		open := stringToFloat64(event.OpenPrice)
+		last := stringToFloat64(event.LastPrice)
+		if open > 0 {
+			event.PriceChange = fmt.Sprintf("%f", last-open)
+			event.PriceChangePercent = fmt.Sprintf("%f", (last-open)/open*100)
+		}
+		event.CloseTime = event.EventTime
+	default:
+		return nil, fmt.Errorf("unsupported event type %q", event.EventType)
+	}
+
+	return &Ticker24hResponse{
+		Symbol:             event.Symbol,
+		PriceChange:        event.PriceChange,
+		PriceChangePercent: event.PriceChangePercent,
+		WeightedAvgPrice:   event.WeightedAvgPrice,
+		PrevClosePrice:     event.PrevClosePrice,
+		LastPrice:          event.LastPrice,
+		LastQty:            event.LastQty,
+		OpenPrice:          event.OpenPrice,
+		HighPrice:          event.HighPrice,
+		LowPrice:           event.LowPrice,
+		Volume:             event.Volume,
+		QuoteVolume:        event.QuoteVolume,
+		OpenTime:           event.OpenTime,
+		CloseTime:          event.CloseTime,
+		FirstID:            event.FirstID,
+		LastID:             event.LastID,
+		Count:              event.Count,
+	}, nil
+}
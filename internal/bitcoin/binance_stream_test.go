@@ -0,0 +1,130 @@
+package bitcoin
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testTickerEvent = `{"stream":"btcusdt@ticker","data":{"e":"24hrTicker","E":1700000000000,"s":"BTCUSDT","p":"1000.00","P":"1.50","w":"67000.00","x":"66000.00","c":"67500.50","Q":"0.01","o":"66500.50","h":"68000.00","l":"66000.00","v":"1234.5","q":"82000000.0","O":1699913600000,"C":1700000000000,"F":1,"L":100,"n":100}}`
+
+// newFakeStreamServer starts a WebSocket server that sends each connection the given
+// messages and then closes it, counting how many connections it accepted.
+func newFakeStreamServer(t *testing.T, messages []string, connections *int32) *httptest.Server {
+	upgrader := websocket.Upgrader{}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			t.Logf("upgrade failed: %v", err)
+			return
+		}
+		defer conn.Close()
+		atomic.AddInt32(connections, 1)
+
+		for _, message := range messages {
+			if err := conn.WriteMessage(websocket.TextMessage, []byte(message)); err != nil {
+				return
+			}
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func TestParseStreamTicker(t *testing.T) {
+	tests := []struct {
+		name        string
+		message     string
+		wantPrice   string
+		wantPercent float64
+		wantErr     bool
+	}{
+		{
+			name:        "combined stream ticker",
+			message:     testTickerEvent,
+			wantPrice:   "67500.50",
+			wantPercent: 1.5,
+		},
+		{
+			name:        "raw mini ticker derives percentage",
+			message:     `{"e":"24hrMiniTicker","E":1700000000000,"s":"BTCUSDT","c":"110.00","o":"100.00","h":"120.00","l":"90.00","v":"10","q":"1000"}`,
+			wantPrice:   "110.00",
+			wantPercent: 10,
+		},
+		{
+			name:    "unsupported event",
+			message: `{"e":"trade","s":"BTCUSDT"}`,
+			wantErr: true,
+		},
+		{
+			name:    "invalid json",
+			message: `not json`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ticker, err := parseStreamTicker([]byte(tt.message))
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, "BTCUSDT", ticker.Symbol)
+			assert.Equal(t, tt.wantPrice, ticker.LastPrice)
+
+			priceData, err := NewPriceDataFromTicker(ticker)
+			require.NoError(t, err)
+			assert.InDelta(t, tt.wantPercent, priceData.PriceChangePercent, 0.0001)
+			assert.Equal(t, "Binance", priceData.Source)
+		})
+	}
+}
+
+func TestBinanceStream_Run(t *testing.T) {
+	var connections int32
+	server := newFakeStreamServer(t, []string{testTickerEvent}, &connections)
+
+	stream := NewBinanceStream("ws"+strings.TrimPrefix(server.URL, "http"), []string{"BTCUSDT"})
+	stream.reconnectDelay = 10 * time.Millisecond
+	stream.maxReconnectDelay = 50 * time.Millisecond
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	received := make(chan *Ticker24hResponse, 10)
+	go stream.Run(ctx, func(ticker *Ticker24hResponse) {
+		received <- ticker
+	})
+
+	// The server closes every connection after one message, so a second
+	// ticker proves the stream reconnected on its own.
+	for i := 0; i < 2; i++ {
+		select {
+		case ticker := <-received:
+			assert.Equal(t, "67500.50", ticker.LastPrice)
+		case <-time.After(2 * time.Second):
+			t.Fatalf("timed out waiting for ticker %d", i+1)
+		}
+	}
+
+	assert.GreaterOrEqual(t, atomic.LoadInt32(&connections), int32(2))
+	assert.False(t, stream.LastMessage().IsZero())
+}
+
+func TestBinanceStream_URL(t *testing.T) {
+	stream := NewBinanceStream("wss://example.com/", []string{"BTCUSDT", " ethusdt "})
+	assert.Equal(t, "wss://example.com/stream?streams=btcusdt@ticker/ethusdt@ticker", stream.URL())
+}
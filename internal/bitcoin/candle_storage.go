@@ -0,0 +1,117 @@
+// Package bitcoin provides functionality for interacting with cryptocurrency APIs.
+package bitcoin
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/cgallonv/btc-alerta-de-precio/internal/storage/models"
+	"github.com/cgallonv/btc-alerta-de-precio/internal/storage/repositories"
+)
+
+// CandleStorage persists klines as OHLCV candles, from backfills and from the live kline stream.
+//
+// Example usage:
+//
+//	candles := NewCandleStorage(candleRepo)
+//	if _, err := candles.Backfill(client, "BTCUSDT", "1m", time.Now().Add(-24*time.Hour), time.Now()); err != nil {
+//	    log.Printf("Error backfilling candles: %v", err)
+//	}
+type CandleStorage struct {
+	repo *repositories.CandleRepository
+}
+
+// NewCandleStorage creates a new CandleStorage instance.
+func NewCandleStorage(repo *repositories.CandleRepository) *CandleStorage {
+	return &CandleStorage{repo: repo}
+}
+
+// StoreKlines saves klines as candles, replacing stored candles with the same open time.
+func (s *CandleStorage) StoreKlines(klines []Kline) error {
+	candles := make([]models.Candle, 0, len(klines))
+	for i := range klines {
+		candles = append(candles, klines[i].Candle())
+	}
+	if err := s.repo.StoreCandles(candles); err != nil {
+		return fmt.Errorf("error storing candles: %w", err)
+	}
+	return nil
+}
+
+// GetCandles returns the stored candles of a symbol and interval opened within [start, end],
+// oldest first. See CandleRepository.GetCandles.
+func (s *CandleStorage) GetCandles(symbol, interval string, start, end time.Time, limit int) ([]models.Candle, error) {
+	return s.repo.GetCandles(symbol, interval, start, end, limit)
+}
+
+// Backfill fetches and stores the candles of a symbol between start and end. It resumes
+// from the last stored candle when that is newer than start, re-fetching it in case it
+// was stored before it closed. It returns the number of candles stored.
+//
+// Example usage:
+//
+//	stored, err := candles.Backfill(client, "BTCUSDT", "1m", time.Now().Add(-60*24*time.Hour), time.Now())
+func (s *CandleStorage) Backfill(client *BinanceClient, symbol, interval string, start, end time.Time) (int, error) {
+	last, err := s.repo.GetLastCandle(symbol, interval)
+	if err != nil {
+		return 0, fmt.Errorf("error reading last %s candle: %w", symbol, err)
+	}
+	if last != nil && last.OpenTime.After(start) {
+		start = last.OpenTime
+	}
+	if !start.Before(end) {
+		return 0, nil
+	}
+
+	klines, err := client.GetHistoricalKlines(symbol, interval, start, end)
+	if err != nil {
+		return 0, err
+	}
+	if err := s.StoreKlines(klines); err != nil {
+		return 0, err
+	}
+	return len(klines), nil
+}
+
+// Start stores the closed candles of the kline stream and backfills, for each of its
+// symbols, the candles missed while the application was down (at most window back).
+// It returns immediately; the stream runs until ctx is cancelled.
+//
+// Example usage:
+//
+//	stream := NewKlineStream(cfg.BinanceStreamURL, PairsFor(cfg.BinanceDefaultSymbols, "USDT"), "1m")
+//	candles.Start(ctx, client, stream, 24*time.Hour)
+func (s *CandleStorage) Start(ctx context.Context, client *BinanceClient, stream *KlineStream, window time.Duration) {
+	log.Printf("🕯️ Recording %s candles for %v", stream.Interval(), stream.Symbols())
+
+	stream.OnKline(func(kline *Kline) {
+		if !kline.Closed {
+			return
+		}
+		if err := s.StoreKlines([]Kline{*kline}); err != nil {
+			log.Printf("❌ Error storing %s candle: %v", kline.Symbol, err)
+		}
+	})
+	go stream.Run(ctx)
+
+	// The stream is already recording, so overlapping candles are simply overwritten
+	if window <= 0 {
+		return
+	}
+	go func() {
+		end := time.Now()
+		for _, symbol := range stream.Symbols() {
+			if ctx.Err() != nil {
+				return
+			}
+			stored, err := s.Backfill(client, symbol, stream.Interval(), end.Add(-window), end)
+			if err != nil {
+				log.Printf("❌ Error backfilling %s candles: %v", symbol, err)
+				continue
+			}
+			log.Printf("✅ Backfilled %d %s %s candles", stored, symbol, stream.Interval())
+		}
+	}()
+}
@@ -0,0 +1,98 @@
+package bitcoin
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/cgallonv/btc-alerta-de-precio/internal/storage/migrations"
+	"github.com/cgallonv/btc-alerta-de-precio/internal/storage/repositories"
+)
+
+func TestCandleStorage_Backfill(t *testing.T) {
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	var startTimes []int64
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		start, _ := strconv.ParseInt(r.URL.Query().Get("startTime"), 10, 64)
+		startTimes = append(startTimes, start)
+
+		// Three one-minute candles from the requested start
+		fmt.Fprint(w, "[")
+		for i := 0; i < 3; i++ {
+			open := time.UnixMilli(start).Add(time.Duration(i) * time.Minute)
+			if i > 0 {
+				fmt.Fprint(w, ",")
+			}
+			fmt.Fprintf(w, `[%d,"100.0","110.0","90.0","105.0","2.5",%d,"262.5",%d,"1.5","157.5","0"]`,
+				open.UnixMilli(), open.Add(time.Minute-time.Millisecond).UnixMilli(), 10+i)
+		}
+		fmt.Fprint(w, "]")
+	}))
+	defer server.Close()
+
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	sqlDB.SetMaxOpenConns(1) // Every in-memory connection is a separate database
+	require.NoError(t, migrations.MigrateCandles(db))
+
+	candles := NewCandleStorage(repositories.NewCandleRepository(db))
+	client := NewBinanceClient("", "", server.URL, nil)
+
+	stored, err := candles.Backfill(client, "BTCUSDT", "1m", base, base.Add(10*time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, 3, stored)
+
+	rows, err := candles.GetCandles("BTCUSDT", "1m", time.Time{}, time.Time{}, 0)
+	require.NoError(t, err)
+	require.Len(t, rows, 3)
+	assert.True(t, rows[0].OpenTime.Equal(base))
+	assert.Equal(t, 105.0, rows[0].Close)
+	assert.Equal(t, 262.5, rows[0].QuoteVolume)
+	assert.Equal(t, int64(10), rows[0].Trades)
+	assert.Equal(t, 1.5, rows[0].TakerBuyVolume)
+	assert.Equal(t, 157.5, rows[0].TakerBuyQuoteVolume)
+
+	// A second run resumes from the last stored candle and overwrites it instead of duplicating it
+	stored, err = candles.Backfill(client, "BTCUSDT", "1m", base, base.Add(10*time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, 3, stored)
+	assert.Equal(t, base.Add(2*time.Minute).UnixMilli(), startTimes[len(startTimes)-1])
+
+	rows, err = candles.GetCandles("BTCUSDT", "1m", time.Time{}, time.Time{}, 0)
+	require.NoError(t, err)
+	assert.Len(t, rows, 5)
+
+	// limit keeps the most recent candles of the range, oldest first
+	rows, err = candles.GetCandles("BTCUSDT", "1m", base, base.Add(3*time.Minute), 2)
+	require.NoError(t, err)
+	require.Len(t, rows, 2)
+	assert.True(t, rows[0].OpenTime.Equal(base.Add(2*time.Minute)))
+	assert.True(t, rows[1].OpenTime.Equal(base.Add(3*time.Minute)))
+}
+
+func TestParseStreamKline(t *testing.T) {
+	message := `{"stream":"btcusdt@kline_1m","data":{"e":"kline","E":1704067260000,"s":"BTCUSDT","k":{"t":1704067200000,"T":1704067259999,"s":"BTCUSDT","i":"1m","f":100,"L":200,"o":"42000.00","c":"42100.00","h":"42150.00","l":"41990.00","v":"12.5","n":101,"x":true,"q":"525000.00","V":"7.5","Q":"315000.00","B":"0"}}}`
+
+	kline, err := parseStreamKline([]byte(message))
+	require.NoError(t, err)
+	assert.Equal(t, "BTCUSDT", kline.Symbol)
+	assert.Equal(t, "1m", kline.Interval)
+	assert.True(t, kline.Closed)
+	assert.Equal(t, 41990.0, kline.Low)
+	assert.Equal(t, 12.5, kline.Volume)
+	assert.Equal(t, 7.5, kline.TakerBuyVolume)
+	assert.Equal(t, 315000.0, kline.TakerBuyQuoteVolume)
+	assert.Equal(t, int64(1704067200000), kline.OpenTime.UnixMilli())
+}
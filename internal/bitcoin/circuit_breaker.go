@@ -0,0 +1,298 @@
+// Package bitcoin provides functionality for interacting with cryptocurrency APIs.
+package bitcoin
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+
+	apperrors "github.com/cgallonv/btc-alerta-de-precio/internal/errors"
+)
+
+// Circuit breaker states.
+const (
+	CircuitClosed   = "closed"    // Requests flow normally
+	CircuitOpen     = "open"      // Requests fail fast until the cooldown has passed
+	CircuitHalfOpen = "half_open" // Trial requests decide whether to close or reopen
+)
+
+// Default circuit breaker settings.
+const (
+	DefaultBreakerThreshold = 5
+	DefaultBreakerCooldown  = 30 * time.Second
+)
+
+// ErrCircuitOpen is the cause of errors returned while the circuit breaker is open.
+// The returned error itself is an AppError with the ErrPriceAPIUnavailable code.
+var ErrCircuitOpen = errors.New("Binance circuit breaker is open")
+
+var (
+	circuitBreakers    = make(map[string]*CircuitBreaker)
+	circuitBreakersMux sync.Mutex
+)
+
+// CircuitBreaker stops calling Binance after repeated failures.
+// After threshold consecutive failures (network errors or 5xx responses) it opens
+// and requests fail immediately with ErrPriceAPIUnavailable. Once the cooldown has
+// passed it lets a single trial request through: a success closes it, a failure
+// reopens it, and other requests keep failing fast until the trial finishes.
+// Like WeightLimiter, one breaker is shared by every client of the same base URL.
+//
+// Example usage:
+//
+//	breaker := CircuitBreakerFor("https://api.binance.com")
+//	if breaker.Stats().State != CircuitClosed {
+//	    log.Printf("Binance feed is degraded")
+//	}
+type CircuitBreaker struct {
+	threshold int
+	cooldown  time.Duration
+
+	state               string
+	consecutiveFailures int
+	openedAt            time.Time
+	lastError           string
+	timesOpened         int64
+	trialInFlight       bool
+
+	mux sync.Mutex
+}
+
+// CircuitBreakerStats is a snapshot of a CircuitBreaker's state.
+type CircuitBreakerStats struct {
+	State               string    `json:"state"`
+	ConsecutiveFailures int       `json:"consecutive_failures"`
+	Threshold           int       `json:"threshold"`
+	OpenedAt            time.Time `json:"opened_at,omitempty"`
+	RetryAt             time.Time `json:"retry_at,omitempty"`
+	LastError           string    `json:"last_error,omitempty"`
+	TimesOpened         int64     `json:"times_opened"`
+}
+
+// NewCircuitBreaker creates a closed circuit breaker.
+// Non-positive values fall back to DefaultBreakerThreshold and DefaultBreakerCooldown.
+//
+// Example usage:
+//
+//	breaker := NewCircuitBreaker(5, 30*time.Second)
+func NewCircuitBreaker(threshold int, cooldown time.Duration) *CircuitBreaker {
+	if threshold <= 0 {
+		threshold = DefaultBreakerThreshold
+	}
+	if cooldown <= 0 {
+		cooldown = DefaultBreakerCooldown
+	}
+	return &CircuitBreaker{
+		threshold: threshold,
+		cooldown:  cooldown,
+		state:     CircuitClosed,
+	}
+}
+
+// CircuitBreakerFor returns the shared breaker for a Binance base URL, creating it if needed.
+// An empty baseURL refers to the production API.
+//
+// Example usage:
+//
+//	stats := CircuitBreakerFor(cfg.BinanceBaseURL).Stats()
+func CircuitBreakerFor(baseURL string) *CircuitBreaker {
+	if baseURL == "" {
+		baseURL = DefaultBinanceBaseURL
+	}
+	baseURL = strings.TrimRight(baseURL, "/")
+
+	circuitBreakersMux.Lock()
+	defer circuitBreakersMux.Unlock()
+
+	breaker, ok := circuitBreakers[baseURL]
+	if !ok {
+		breaker = NewCircuitBreaker(DefaultBreakerThreshold, DefaultBreakerCooldown)
+		circuitBreakers[baseURL] = breaker
+	}
+	return breaker
+}
+
+// Configure changes the failure threshold and cooldown. Non-positive values are ignored.
+//
+// Example usage:
+//
+//	CircuitBreakerFor(baseURL).Configure(3, time.Minute)
+func (b *CircuitBreaker) Configure(threshold int, cooldown time.Duration) {
+	b.mux.Lock()
+	defer b.mux.Unlock()
+	if threshold > 0 {
+		b.threshold = threshold
+	}
+	if cooldown > 0 {
+		b.cooldown = cooldown
+	}
+}
+
+// Allow reports whether a request may be sent.
+// While the breaker is open, or half-open with a trial request in flight, it returns
+// an AppError with the ErrPriceAPIUnavailable code.
+//
+// Example usage:
+//
+//	if err := breaker.Allow(); err != nil {
+//	    return nil, err
+//	}
+func (b *CircuitBreaker) Allow() error {
+	b.mux.Lock()
+	defer b.mux.Unlock()
+
+	switch b.state {
+	case CircuitClosed:
+		return nil
+	case CircuitHalfOpen:
+		if b.trialInFlight {
+			return unavailableError()
+		}
+	default:
+		retryAt := b.openedAt.Add(b.cooldown)
+		if time.Now().Before(retryAt) {
+			return unavailableError().WithField("retry_at", retryAt)
+		}
+		log.Printf("🔌 Binance circuit breaker half-open, sending trial request")
+		b.state = CircuitHalfOpen
+	}
+
+	b.trialInFlight = true
+	return nil
+}
+
+// unavailableError builds the error returned for requests refused by the breaker.
+func unavailableError() *apperrors.AppError {
+	return apperrors.WrapError(ErrCircuitOpen,
+		apperrors.ErrPriceAPIUnavailable.Code, apperrors.ErrPriceAPIUnavailable.Message)
+}
+
+// RecordSuccess closes the breaker and resets the failure count.
+func (b *CircuitBreaker) RecordSuccess() {
+	b.mux.Lock()
+	defer b.mux.Unlock()
+
+	if b.state != CircuitClosed {
+		log.Printf("✅ Binance circuit breaker closed, API is responding again")
+	}
+	b.state = CircuitClosed
+	b.consecutiveFailures = 0
+	b.trialInFlight = false
+}
+
+// RecordFailure counts a failed request and opens the breaker when the threshold
+// is reached, or immediately if a trial request failed.
+func (b *CircuitBreaker) RecordFailure(err error) {
+	b.mux.Lock()
+	defer b.mux.Unlock()
+
+	b.consecutiveFailures++
+	b.trialInFlight = false
+	if err != nil {
+		b.lastError = err.Error()
+	}
+
+	if b.state == CircuitHalfOpen || (b.state == CircuitClosed && b.consecutiveFailures >= b.threshold) {
+		b.state = CircuitOpen
+		b.openedAt = time.Now()
+		b.timesOpened++
+		log.Printf("🚫 Binance circuit breaker opened after %d failures (last: %s), retrying in %v",
+			b.consecutiveFailures, b.lastError, b.cooldown)
+	}
+}
+
+// Stats returns a snapshot of the breaker state.
+//
+// Example usage:
+//
+//	stats := breaker.Stats()
+//	log.Printf("Breaker %s (%d failures)", stats.State, stats.ConsecutiveFailures)
+func (b *CircuitBreaker) Stats() CircuitBreakerStats {
+	b.mux.Lock()
+	defer b.mux.Unlock()
+
+	stats := CircuitBreakerStats{
+		State:               b.state,
+		ConsecutiveFailures: b.consecutiveFailures,
+		Threshold:           b.threshold,
+		LastError:           b.lastError,
+		TimesOpened:         b.timesOpened,
+	}
+	if b.state != CircuitClosed {
+		stats.OpenedAt = b.openedAt
+		stats.RetryAt = b.openedAt.Add(b.cooldown)
+	}
+	return stats
+}
+
+// releaseTrial lets another request become the half-open trial when the current
+// one was refused locally and never reached Binance.
+func (b *CircuitBreaker) releaseTrial() {
+	b.mux.Lock()
+	defer b.mux.Unlock()
+	b.trialInFlight = false
+}
+
+// attach makes every request of a resty client pass through the breaker.
+// Outcomes are recorded once per request, after retries: network errors and 5xx
+// responses count as failures; any other response, including 4xx, proves the API
+// is reachable and counts as a success. Order cancels bypass the breaker so open
+// orders can still be cancelled while market data polling is failing.
+func (b *CircuitBreaker) attach(client *resty.Client) {
+	client.OnBeforeRequest(func(_ *resty.Client, req *resty.Request) error {
+		// Retries belong to a request that was already let through
+		if req.Attempt > 1 || bypassesBreaker(req) {
+			return nil
+		}
+		return b.Allow()
+	})
+	client.OnSuccess(func(_ *resty.Client, resp *resty.Response) {
+		if bypassesBreaker(resp.Request) {
+			return
+		}
+		b.recordResponse(resp)
+	})
+	client.OnError(func(req *resty.Request, err error) {
+		if bypassesBreaker(req) || errors.Is(err, ErrCircuitOpen) {
+			return
+		}
+		// Requests refused locally never reached Binance
+		if IsRateLimitError(err) {
+			b.releaseTrial()
+			return
+		}
+		var responseErr *resty.ResponseError
+		if errors.As(err, &responseErr) && responseErr.Response != nil && responseErr.Response.StatusCode() > 0 {
+			b.recordResponse(responseErr.Response)
+			return
+		}
+		b.RecordFailure(err)
+	})
+}
+
+// recordResponse records the final response of a request.
+func (b *CircuitBreaker) recordResponse(resp *resty.Response) {
+	if resp.StatusCode() >= http.StatusInternalServerError {
+		b.RecordFailure(NewBinanceError(resp.StatusCode(), resp.String()))
+		return
+	}
+	b.RecordSuccess()
+}
+
+// bypassesBreaker reports whether a request is exempt from the breaker.
+func bypassesBreaker(req *resty.Request) bool {
+	if req == nil || req.Method != resty.MethodDelete {
+		return false
+	}
+	path := req.URL
+	if parsed, err := url.Parse(req.URL); err == nil {
+		path = parsed.Path
+	}
+	return strings.HasSuffix(path, "/api/v3/order")
+}
@@ -0,0 +1,95 @@
+package bitcoin
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	apperrors "github.com/cgallonv/btc-alerta-de-precio/internal/errors"
+)
+
+func TestCircuitBreaker_OpensAfterRepeatedFailures(t *testing.T) {
+	var requests int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	SetRetryPolicy(RetryPolicy{MaxRetries: 2, BaseDelay: time.Millisecond, MaxDelay: 5 * time.Millisecond})
+	defer SetRetryPolicy(DefaultRetryPolicy)
+	CircuitBreakerFor(server.URL).Configure(2, time.Hour)
+
+	client := NewBinanceClient("", "", server.URL, nil)
+
+	// Each call is retried twice but counts as a single failure
+	_, err := client.GetSymbolPrice("BTCUSDT")
+	require.Error(t, err)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&requests))
+	stats := CircuitBreakerFor(server.URL).Stats()
+	assert.Equal(t, CircuitClosed, stats.State)
+	assert.Equal(t, 1, stats.ConsecutiveFailures)
+
+	// The second failed call opens the breaker
+	_, err = client.GetSymbolPrice("BTCUSDT")
+	require.Error(t, err)
+	assert.Equal(t, int32(6), atomic.LoadInt32(&requests))
+
+	// Further calls fail fast without reaching Binance
+	_, err = client.GetSymbolPrice("BTCUSDT")
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, ErrCircuitOpen))
+	var appErr *apperrors.AppError
+	require.ErrorAs(t, err, &appErr)
+	assert.Equal(t, apperrors.ErrPriceAPIUnavailable.Code, appErr.Code)
+	assert.Equal(t, int32(6), atomic.LoadInt32(&requests))
+
+	stats = CircuitBreakerFor(server.URL).Stats()
+	assert.Equal(t, CircuitOpen, stats.State)
+	assert.Equal(t, int64(1), stats.TimesOpened)
+}
+
+func TestCircuitBreaker_HalfOpenTrial(t *testing.T) {
+	breaker := NewCircuitBreaker(1, 10*time.Millisecond)
+
+	breaker.RecordFailure(errors.New("connection refused"))
+	assert.Error(t, breaker.Allow())
+
+	time.Sleep(20 * time.Millisecond)
+	require.NoError(t, breaker.Allow())
+	assert.Equal(t, CircuitHalfOpen, breaker.Stats().State)
+
+	// Only one trial request is let through at a time
+	err := breaker.Allow()
+	assert.True(t, errors.Is(err, ErrCircuitOpen))
+
+	// A failed trial reopens the breaker, a successful one closes it
+	breaker.RecordFailure(errors.New("connection refused"))
+	assert.Equal(t, CircuitOpen, breaker.Stats().State)
+
+	time.Sleep(20 * time.Millisecond)
+	require.NoError(t, breaker.Allow())
+	breaker.RecordSuccess()
+	assert.Equal(t, CircuitClosed, breaker.Stats().State)
+}
+
+func TestRetryPolicy_Backoff(t *testing.T) {
+	policy := RetryPolicy{MaxRetries: 5, BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}
+
+	for retry := 1; retry <= 6; retry++ {
+		ceiling := policy.BaseDelay << (retry - 1)
+		if ceiling > policy.MaxDelay {
+			ceiling = policy.MaxDelay
+		}
+		delay := policy.Backoff(retry)
+		assert.GreaterOrEqual(t, delay, ceiling/2, "retry %d", retry)
+		assert.LessOrEqual(t, delay, ceiling, "retry %d", retry)
+	}
+}
@@ -0,0 +1,116 @@
+// Package bitcoin provides functionality for interacting with cryptocurrency APIs.
+package bitcoin
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+)
+
+// DefaultCoinbaseBaseURL is the public Coinbase Exchange REST endpoint.
+const DefaultCoinbaseBaseURL = "https://api.exchange.coinbase.com"
+
+// CoinbaseClient fetches public market prices from the Coinbase Exchange API.
+// It is used as a fallback price provider when Binance is unavailable.
+//
+// Example usage:
+//
+//	client := NewCoinbaseClient("")
+//	price, err := client.GetSymbolPrice("BTCUSDT")
+//	if err != nil {
+//	    log.Printf("Error: %v", err)
+//	    return
+//	}
+//	fmt.Println(price)
+type CoinbaseClient struct {
+	httpClient *resty.Client
+}
+
+// coinbaseStatsResponse represents the response from /products/{product}/stats.
+type coinbaseStatsResponse struct {
+	Open   string `json:"open"`
+	High   string `json:"high"`
+	Low    string `json:"low"`
+	Last   string `json:"last"`
+	Volume string `json:"volume"`
+}
+
+// NewCoinbaseClient creates a new Coinbase client.
+// If baseURL is empty, DefaultCoinbaseBaseURL is used.
+//
+// Example usage:
+//
+//	client := NewCoinbaseClient("https://api.exchange.coinbase.com")
+func NewCoinbaseClient(baseURL string) *CoinbaseClient {
+	if baseURL == "" {
+		baseURL = DefaultCoinbaseBaseURL
+	}
+
+	client := resty.New()
+	client.SetTimeout(10 * time.Second)
+	client.SetBaseURL(baseURL)
+
+	return &CoinbaseClient{httpClient: client}
+}
+
+// Name returns the provider name reported in PriceData.Source.
+func (c *CoinbaseClient) Name() string {
+	return SourceCoinbase
+}
+
+// GetSymbolPrice fetches the current price and 24h change for a Binance-style symbol.
+// The symbol is translated to a Coinbase product ID, e.g. "BTCUSDT" becomes "BTC-USDT".
+//
+// Example usage:
+//
+//	price, err := client.GetSymbolPrice("ETHUSDT")
+//	if err != nil {
+//	    return err
+//	}
+//	fmt.Printf("ETH: $%.2f (%+.2f%%)\n", price.Price, price.PriceChangePercent)
+func (c *CoinbaseClient) GetSymbolPrice(symbol string) (*PriceData, error) {
+	base, quote, err := SplitSymbol(symbol)
+	if err != nil {
+		return nil, err
+	}
+	product := base + "-" + quote
+
+	log.Printf("🔄 Fetching %s price from Coinbase API", product)
+
+	var response coinbaseStatsResponse
+	resp, err := c.httpClient.R().
+		SetPathParam("product", product).
+		SetResult(&response).
+		Get("/products/{product}/stats")
+
+	if err != nil {
+		return nil, fmt.Errorf("error fetching price from Coinbase: %w", err)
+	}
+
+	if resp.StatusCode() != 200 {
+		return nil, &ProviderError{Provider: SourceCoinbase, Status: resp.StatusCode(), Message: resp.String()}
+	}
+
+	price, err := strconv.ParseFloat(response.Last, 64)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing price from Coinbase: %w", err)
+	}
+
+	// Stats cover a rolling 24h window, like Binance's ticker
+	var priceChangePercent float64
+	if open := stringToFloat64(response.Open); open > 0 {
+		priceChangePercent = (price - open) / open * 100
+	}
+
+	return &PriceData{
+		Symbol:             base + quote,
+		Price:              price,
+		PriceChangePercent: priceChangePercent,
+		Currency:           "USD",
+		Timestamp:          time.Now(),
+		Source:             SourceCoinbase,
+	}, nil
+}
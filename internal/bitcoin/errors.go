@@ -3,6 +3,7 @@ package bitcoin
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 )
 
@@ -125,3 +126,82 @@ const (
 	// ErrInsufficientPermission indicates API key lacks required permissions
 	ErrInsufficientPermission = -2010
 )
+
+// ProviderError represents an HTTP error from a non-Binance price provider.
+//
+// Example usage:
+//
+//	if providerErr, ok := err.(*ProviderError); ok && providerErr.Status == 429 {
+//	    log.Printf("%s is rate limiting us", providerErr.Provider)
+//	}
+type ProviderError struct {
+	Provider string // Provider name, e.g. "Coinbase"
+	Status   int    // HTTP status code
+	Message  string // Error message from the provider
+}
+
+// Error implements the error interface.
+func (e *ProviderError) Error() string {
+	return fmt.Sprintf("%s API error (status %d): %s", e.Provider, e.Status, e.Message)
+}
+
+// OrderValidationError is returned when an order violates one of the symbol's
+// exchange filters, or misses fields its type requires (Filter "PARAMETERS"),
+// and is therefore not sent to Binance.
+//
+// Example usage:
+//
+//	var validationErr *OrderValidationError
+//	if errors.As(err, &validationErr) {
+//	    log.Printf("Order rejected by %s: %s", validationErr.Filter, validationErr.Message)
+//	}
+type OrderValidationError struct {
+	Filter  string // Violated filter, e.g. "LOT_SIZE"
+	Message string
+}
+
+// Error implements the error interface.
+func (e *OrderValidationError) Error() string {
+	return fmt.Sprintf("order rejected by %s filter: %s", e.Filter, e.Message)
+}
+
+// IsRateLimitError reports whether err means a provider is throttling or banning us.
+// Binance answers 429 when a limit is hit and 418 once the IP has been banned.
+//
+// Example usage:
+//
+//	if IsRateLimitError(err) {
+//	    log.Printf("Backing off: %v", err)
+//	}
+func IsRateLimitError(err error) bool {
+	var limitErr *RateLimitError
+	if errors.As(err, &limitErr) {
+		return true
+	}
+
+	var binanceErr *BinanceError
+	if errors.As(err, &binanceErr) {
+		return binanceErr.Status == 429 || binanceErr.Status == 418 ||
+			binanceErr.Code == ErrRateLimitExceeded || binanceErr.Code == ErrIPRateLimitExceeded ||
+			binanceErr.Code == ErrTooManyRequests
+	}
+
+	var providerErr *ProviderError
+	if errors.As(err, &providerErr) {
+		return providerErr.Status == 429
+	}
+
+	return false
+}
+
+// IsInvalidSymbolError reports whether err means Binance doesn't list the symbol.
+//
+// Example usage:
+//
+//	if IsInvalidSymbolError(err) {
+//	    log.Printf("⚠️ Skipping unknown symbol %s", symbol)
+//	}
+func IsInvalidSymbolError(err error) bool {
+	var binanceErr *BinanceError
+	return errors.As(err, &binanceErr) && binanceErr.Code == ErrInvalidSymbol
+}
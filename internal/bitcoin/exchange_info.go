@@ -0,0 +1,262 @@
+// Package bitcoin provides functionality for interacting with cryptocurrency APIs.
+package bitcoin
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultExchangeInfoRefresh is how often the cached /api/v3/exchangeInfo is refreshed.
+const DefaultExchangeInfoRefresh = time.Hour
+
+// symbolStatusTrading is the status of symbols that accept orders.
+const symbolStatusTrading = "TRADING"
+
+var (
+	exchangeInfos    = make(map[string]*ExchangeInfo)
+	exchangeInfosMux sync.Mutex
+)
+
+// ExchangeInfo caches the symbols listed in /api/v3/exchangeInfo with their status,
+// base and quote assets and trading filters, so a mistyped symbol is rejected when
+// an alert or order is created instead of failing later with a -1121 error. Like
+// TickerCache, one cache is shared by every client of the same base URL.
+//
+// Example usage:
+//
+//	info := ExchangeInfoFor(cfg.BinanceBaseURL)
+//	info.Start(ctx, client)
+//	filters, err := client.GetSymbolFilters("ETHUSDT")
+type ExchangeInfo struct {
+	refresh time.Duration
+
+	symbols   map[string]*SymbolFilters
+	fetchedAt time.Time
+	mux       sync.Mutex
+}
+
+// NewExchangeInfo creates an exchange info cache. A non-positive refresh uses
+// DefaultExchangeInfoRefresh.
+func NewExchangeInfo(refresh time.Duration) *ExchangeInfo {
+	if refresh <= 0 {
+		refresh = DefaultExchangeInfoRefresh
+	}
+	return &ExchangeInfo{refresh: refresh}
+}
+
+// ExchangeInfoFor returns the shared exchange info cache for a Binance base URL,
+// creating it if needed. An empty baseURL refers to the production API.
+func ExchangeInfoFor(baseURL string) *ExchangeInfo {
+	if baseURL == "" {
+		baseURL = DefaultBinanceBaseURL
+	}
+	baseURL = strings.TrimRight(baseURL, "/")
+
+	exchangeInfosMux.Lock()
+	defer exchangeInfosMux.Unlock()
+
+	info, ok := exchangeInfos[baseURL]
+	if !ok {
+		info = NewExchangeInfo(DefaultExchangeInfoRefresh)
+		exchangeInfos[baseURL] = info
+	}
+	return info
+}
+
+// SetRefreshInterval changes how long the symbol list is used before it is fetched
+// again. Non-positive values are ignored.
+func (e *ExchangeInfo) SetRefreshInterval(refresh time.Duration) {
+	if refresh <= 0 {
+		return
+	}
+	e.mux.Lock()
+	defer e.mux.Unlock()
+	e.refresh = refresh
+}
+
+// Start refreshes the symbol list in the background until ctx is cancelled.
+// The list is loaded immediately unless a fresh copy is already cached.
+func (e *ExchangeInfo) Start(ctx context.Context, client *BinanceClient) {
+	e.mux.Lock()
+	refresh := e.refresh
+	e.mux.Unlock()
+
+	go func() {
+		ticker := time.NewTicker(refresh)
+		defer ticker.Stop()
+
+		if _, err := e.get(client); err != nil {
+			log.Printf("❌ Error loading exchange info: %v", err)
+		}
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			if err := e.Refresh(client); err != nil {
+				log.Printf("❌ Error refreshing exchange info: %v", err)
+			}
+		}
+	}()
+}
+
+// Refresh fetches every symbol from /api/v3/exchangeInfo and replaces the cached list.
+// The previous list is kept if the request fails.
+func (e *ExchangeInfo) Refresh(client *BinanceClient) error {
+	e.mux.Lock()
+	defer e.mux.Unlock()
+	return e.fetch(client)
+}
+
+// fetch loads the symbol list. The caller must hold e.mux.
+func (e *ExchangeInfo) fetch(client *BinanceClient) error {
+	var response struct {
+		Symbols []symbolInfoResponse `json:"symbols"`
+	}
+
+	resp, err := client.httpClient.R().
+		SetResult(&response).
+		Get("/api/v3/exchangeInfo")
+	if err != nil {
+		return fmt.Errorf("error fetching exchange info: %w", err)
+	}
+	if resp.StatusCode() != 200 {
+		return NewBinanceError(resp.StatusCode(), resp.String())
+	}
+
+	symbols := make(map[string]*SymbolFilters, len(response.Symbols))
+	for i := range response.Symbols {
+		filters := newSymbolFilters(&response.Symbols[i])
+		symbols[filters.Symbol] = filters
+	}
+	e.symbols = symbols
+	e.fetchedAt = time.Now()
+	log.Printf("📋 Loaded %d symbols from Binance exchange info", len(symbols))
+	return nil
+}
+
+// get returns the cached symbols, fetching them with client when the list is missing
+// or older than the refresh interval. A stale list is used if the refresh fails.
+func (e *ExchangeInfo) get(client *BinanceClient) (map[string]*SymbolFilters, error) {
+	e.mux.Lock()
+	defer e.mux.Unlock()
+
+	if e.symbols != nil && time.Since(e.fetchedAt) < e.refresh {
+		return e.symbols, nil
+	}
+	if err := e.fetch(client); err != nil {
+		if e.symbols == nil {
+			return nil, err
+		}
+		log.Printf("⚠️ Using exchange info from %s: %v", e.fetchedAt.Format(time.RFC3339), err)
+	}
+	return e.symbols, nil
+}
+
+// Symbol returns the cached filters of a symbol without calling Binance.
+// ok is false if the symbol is unknown or the list hasn't been loaded yet.
+func (e *ExchangeInfo) Symbol(symbol string) (filters *SymbolFilters, ok bool) {
+	e.mux.Lock()
+	defer e.mux.Unlock()
+
+	filters, ok = e.symbols[strings.ToUpper(strings.TrimSpace(symbol))]
+	return filters, ok
+}
+
+// KnownAssets splits assets into those that appear as the base or quote asset of a
+// trading symbol and those that don't. With no symbol list loaded every asset is known.
+// Fiat currencies such as COP are usually unknown even though their balances are still
+// valued with FX rates, so callers should warn about unknown assets rather than drop them.
+//
+// Example usage:
+//
+//	_, unknown := info.KnownAssets(cfg.BinanceDefaultSymbols)
+//	for _, asset := range unknown {
+//	    log.Printf("⚠️ %s is not listed on Binance", asset)
+//	}
+func (e *ExchangeInfo) KnownAssets(assets []string) (known, unknown []string) {
+	e.mux.Lock()
+	defer e.mux.Unlock()
+
+	if e.symbols == nil {
+		return assets, nil
+	}
+
+	listed := make(map[string]bool)
+	for _, filters := range e.symbols {
+		if filters.IsTrading() {
+			listed[filters.BaseAsset] = true
+			listed[filters.QuoteAsset] = true
+		}
+	}
+	for _, asset := range assets {
+		if listed[strings.ToUpper(strings.TrimSpace(asset))] {
+			known = append(known, asset)
+		} else {
+			unknown = append(unknown, asset)
+		}
+	}
+	return known, unknown
+}
+
+// SymbolQuery selects symbols from the exchange info, see GetSymbols.
+type SymbolQuery struct {
+	Search     string // Prefix of the symbol or its base asset, e.g. "ETH"
+	QuoteAsset string // Only symbols quoted in this asset, e.g. "USDT"
+	All        bool   // Include symbols that are not trading (BREAK, HALT...)
+	Limit      int    // Maximum number of symbols, 0 for no limit
+}
+
+// GetSymbols returns the symbols matching query from the cached exchange info, sorted
+// with exact matches first and then alphabetically.
+//
+// Example usage:
+//
+//	symbols, err := client.GetSymbols(SymbolQuery{Search: "sol", QuoteAsset: "USDT", Limit: 10})
+//	if err != nil {
+//	    return err
+//	}
+//	for _, s := range symbols {
+//	    fmt.Printf("%s tick %g lot %g\n", s.Symbol, s.TickSize, s.StepSize)
+//	}
+func (c *BinanceClient) GetSymbols(query SymbolQuery) ([]SymbolFilters, error) {
+	symbols, err := c.exchangeInfo.get(c)
+	if err != nil {
+		return nil, err
+	}
+
+	search := strings.ToUpper(strings.TrimSpace(query.Search))
+	quote := strings.ToUpper(strings.TrimSpace(query.QuoteAsset))
+
+	var result []SymbolFilters
+	for _, filters := range symbols {
+		if !query.All && !filters.IsTrading() {
+			continue
+		}
+		if quote != "" && filters.QuoteAsset != quote {
+			continue
+		}
+		if search != "" && !strings.HasPrefix(filters.Symbol, search) && !strings.HasPrefix(filters.BaseAsset, search) {
+			continue
+		}
+		result = append(result, *filters)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if exactI, exactJ := result[i].BaseAsset == search, result[j].BaseAsset == search; exactI != exactJ {
+			return exactI
+		}
+		return result[i].Symbol < result[j].Symbol
+	})
+	if query.Limit > 0 && len(result) > query.Limit {
+		result = result[:query.Limit]
+	}
+	return result, nil
+}
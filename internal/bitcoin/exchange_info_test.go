@@ -0,0 +1,59 @@
+package bitcoin
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExchangeInfo_CachesAndValidatesSymbols(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path != "/api/v3/exchangeInfo" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		requests++
+		w.Write([]byte(`{"symbols":[
+			{"symbol":"BTCUSDT","status":"TRADING","baseAsset":"BTC","quoteAsset":"USDT","filters":[
+				{"filterType":"PRICE_FILTER","minPrice":"0.01","maxPrice":"1000000.00","tickSize":"0.01"},
+				{"filterType":"LOT_SIZE","minQty":"0.00001","maxQty":"9000.00","stepSize":"0.00001"}]},
+			{"symbol":"ETHUSDT","status":"TRADING","baseAsset":"ETH","quoteAsset":"USDT","filters":[]},
+			{"symbol":"ETHBTC","status":"TRADING","baseAsset":"ETH","quoteAsset":"BTC","filters":[]},
+			{"symbol":"LUNAUSDT","status":"BREAK","baseAsset":"LUNA","quoteAsset":"USDT","filters":[]}]}`))
+	}))
+	defer server.Close()
+
+	client := NewBinanceClient("", "", server.URL, nil)
+
+	filters, err := client.GetSymbolFilters("btcusdt")
+	require.NoError(t, err)
+	assert.Equal(t, "BTC", filters.BaseAsset)
+	assert.Equal(t, 0.01, filters.TickSize)
+	assert.Equal(t, 0.00001, filters.StepSize)
+	assert.True(t, filters.IsTrading())
+
+	_, err = client.GetSymbolFilters("BTCUSTD")
+	assert.True(t, IsInvalidSymbolError(err))
+
+	// Trading symbols only, exact base asset matches first
+	symbols, err := client.GetSymbols(SymbolQuery{Search: "eth"})
+	require.NoError(t, err)
+	require.Len(t, symbols, 2)
+	assert.Equal(t, "ETHBTC", symbols[0].Symbol)
+
+	symbols, err = client.GetSymbols(SymbolQuery{QuoteAsset: "USDT", All: true, Limit: 2})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"BTCUSDT", "ETHUSDT"}, []string{symbols[0].Symbol, symbols[1].Symbol})
+
+	// Every lookup above was served from one request
+	assert.Equal(t, 1, requests)
+
+	known, unknown := ExchangeInfoFor(server.URL).KnownAssets([]string{"BTC", "USDT", "LUNA", "BTX"})
+	assert.Equal(t, []string{"BTC", "USDT"}, known)
+	assert.Equal(t, []string{"LUNA", "BTX"}, unknown)
+}
@@ -0,0 +1,549 @@
+// Package fakebinance provides an in-process fake of the Binance spot REST API for
+// tests. It serves the endpoints the app uses (time, exchangeInfo, ticker/24hr,
+// ticker/price, klines and account) from state the test controls, so clients,
+// price monitors and alert managers can be exercised end to end without a network.
+//
+// Example usage:
+//
+//	server := fakebinance.New()
+//	defer server.Close()
+//	server.AddSymbol(fakebinance.Symbol{Symbol: "BTCUSDT", BaseAsset: "BTC", QuoteAsset: "USDT", Price: 50000})
+//	server.SetPricePath("BTCUSDT", 50000, 52000, 56000)
+//	client := bitcoin.NewBinanceClient("", "", server.URL, nil)
+package fakebinance
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Binance error codes returned by the fake.
+const (
+	codeUnknown          = -1000
+	codeTooManyRequests  = -1003
+	codeInvalidTimestamp = -1021
+	codeInvalidSignature = -1022
+	codeMandatoryParam   = -1102
+	codeInvalidInterval  = -1120
+	codeInvalidSymbol    = -1121
+	codeInvalidAPIKey    = -2015
+)
+
+// defaultRecvWindow is the recvWindow, in milliseconds, of signed requests that don't send one.
+const defaultRecvWindow = 5000
+
+// Symbol describes a market served by the fake. Zero filters get Binance-like defaults:
+// tick size 0.01, step size 0.00001 and a minimum notional of 5.
+type Symbol struct {
+	Symbol     string
+	BaseAsset  string
+	QuoteAsset string
+	Status     string // TRADING when empty
+
+	Price     float64 // Last price
+	Change24h float64 // 24h change in percent
+
+	TickSize    float64
+	StepSize    float64
+	MinNotional float64
+}
+
+// Kline is a candle served by /api/v3/klines.
+type Kline struct {
+	OpenTime time.Time
+	Open     float64
+	High     float64
+	Low      float64
+	Close    float64
+	Volume   float64
+}
+
+// Fault is an error response injected with FailNext.
+type Fault struct {
+	Status     int           // HTTP status, e.g. 500 or 429
+	Code       int           // Binance error code in the body
+	Message    string        // Binance error message in the body
+	RetryAfter time.Duration // Sent as Retry-After when set
+}
+
+// market is the state of a symbol.
+type market struct {
+	Symbol
+	path []float64 // Prices still to be served, see SetPricePath
+}
+
+// Server is a fake Binance REST API backed by httptest.Server. All methods are safe
+// for concurrent use, so state can be changed while clients are polling.
+type Server struct {
+	*httptest.Server
+
+	apiKey    string
+	apiSecret string
+	clockSkew time.Duration
+
+	markets  map[string]*market
+	klines   map[string][]Kline // By symbol and interval, see klineKey
+	balances map[string][2]float64
+	faults   map[string][]Fault // By path; "" matches every path
+	requests map[string]int
+
+	mux sync.Mutex
+}
+
+// New starts a fake Binance server with no symbols. Close it when done.
+func New() *Server {
+	s := &Server{
+		markets:  make(map[string]*market),
+		klines:   make(map[string][]Kline),
+		balances: make(map[string][2]float64),
+		faults:   make(map[string][]Fault),
+		requests: make(map[string]int),
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.handle))
+	return s
+}
+
+// SetCredentials sets the API key and secret signed endpoints are checked against.
+// Without credentials any key and signature are accepted.
+func (s *Server) SetCredentials(apiKey, apiSecret string) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	s.apiKey, s.apiSecret = apiKey, apiSecret
+}
+
+// SetClockSkew shifts the time reported by /api/v3/time and used to check
+// request timestamps, to test clients whose clock is off.
+func (s *Server) SetClockSkew(skew time.Duration) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	s.clockSkew = skew
+}
+
+// AddSymbol lists a market, replacing any previous one with the same name.
+func (s *Server) AddSymbol(symbol Symbol) {
+	symbol.Symbol = strings.ToUpper(symbol.Symbol)
+	if symbol.Status == "" {
+		symbol.Status = "TRADING"
+	}
+	if symbol.TickSize == 0 {
+		symbol.TickSize = 0.01
+	}
+	if symbol.StepSize == 0 {
+		symbol.StepSize = 0.00001
+	}
+	if symbol.MinNotional == 0 {
+		symbol.MinNotional = 5
+	}
+
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	s.markets[symbol.Symbol] = &market{Symbol: symbol}
+}
+
+// SetPrice sets the last price of a listed symbol and drops any scripted path.
+func (s *Server) SetPrice(symbol string, price float64) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	if m, ok := s.markets[strings.ToUpper(symbol)]; ok {
+		m.Price = price
+		m.path = nil
+	}
+}
+
+// SetPricePath scripts the prices of a listed symbol: each single-symbol ticker or
+// price request moves to the next one, and the last price is kept once the path
+// is exhausted. Bulk ticker requests report the current price without advancing.
+func (s *Server) SetPricePath(symbol string, prices ...float64) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	if m, ok := s.markets[strings.ToUpper(symbol)]; ok {
+		m.path = append([]float64(nil), prices...)
+	}
+}
+
+// AddKlines adds candles served for a symbol and interval, e.g. "1m".
+func (s *Server) AddKlines(symbol, interval string, klines ...Kline) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	key := klineKey(symbol, interval)
+	s.klines[key] = append(s.klines[key], klines...)
+	sort.Slice(s.klines[key], func(i, j int) bool {
+		return s.klines[key][i].OpenTime.Before(s.klines[key][j].OpenTime)
+	})
+}
+
+// SetBalance sets the free and locked balance of an asset in /api/v3/account.
+func (s *Server) SetBalance(asset string, free, locked float64) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	s.balances[strings.ToUpper(asset)] = [2]float64{free, locked}
+}
+
+// FailNext makes the next n requests to path (e.g. "/api/v3/ticker/24hr", or ""
+// for any path) fail with fault.
+func (s *Server) FailNext(path string, n int, fault Fault) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	for i := 0; i < n; i++ {
+		s.faults[path] = append(s.faults[path], fault)
+	}
+}
+
+// RateLimitNext makes the next n requests to path fail with 429 Too Many Requests
+// and the given Retry-After, as Binance does when the weight limit is exceeded.
+func (s *Server) RateLimitNext(path string, n int, retryAfter time.Duration) {
+	s.FailNext(path, n, Fault{
+		Status:     http.StatusTooManyRequests,
+		Code:       codeTooManyRequests,
+		Message:    "Too much request weight used; current limit is 6000 request weight per 1 MINUTE.",
+		RetryAfter: retryAfter,
+	})
+}
+
+// Requests returns how many requests were received for path, injected failures included.
+func (s *Server) Requests(path string) int {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	return s.requests[path]
+}
+
+// handle routes a request to its endpoint.
+func (s *Server) handle(w http.ResponseWriter, r *http.Request) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	s.requests[r.URL.Path]++
+	w.Header().Set("Content-Type", "application/json")
+
+	if s.injectFault(w, r.URL.Path) {
+		return
+	}
+
+	query := r.URL.Query()
+	switch r.URL.Path {
+	case "/api/v3/ping":
+		writeJSON(w, http.StatusOK, struct{}{})
+	case "/api/v3/time":
+		writeJSON(w, http.StatusOK, map[string]int64{"serverTime": s.now().UnixMilli()})
+	case "/api/v3/exchangeInfo":
+		s.handleExchangeInfo(w, query.Get("symbol"))
+	case "/api/v3/ticker/24hr":
+		s.handleTickers(w, query, s.ticker24h)
+	case "/api/v3/ticker/price":
+		s.handleTickers(w, query, s.tickerPrice)
+	case "/api/v3/klines":
+		s.handleKlines(w, query)
+	case "/api/v3/account":
+		if s.checkSigned(w, r) {
+			s.handleAccount(w)
+		}
+	default:
+		writeError(w, http.StatusNotFound, codeUnknown, "Unknown endpoint "+r.URL.Path)
+	}
+}
+
+// injectFault writes the next queued fault for path, if any. The caller holds s.mux.
+func (s *Server) injectFault(w http.ResponseWriter, path string) bool {
+	for _, key := range []string{path, ""} {
+		queue := s.faults[key]
+		if len(queue) == 0 {
+			continue
+		}
+		fault := queue[0]
+		s.faults[key] = queue[1:]
+
+		if fault.RetryAfter > 0 {
+			w.Header().Set("Retry-After", strconv.Itoa(int(fault.RetryAfter.Seconds())))
+		}
+		writeError(w, fault.Status, fault.Code, fault.Message)
+		return true
+	}
+	return false
+}
+
+// checkSigned verifies the API key, HMAC SHA256 signature and timestamp of a
+// SIGNED request, writing the Binance error if any check fails.
+func (s *Server) checkSigned(w http.ResponseWriter, r *http.Request) bool {
+	if s.apiKey == "" && s.apiSecret == "" {
+		return true
+	}
+
+	if r.Header.Get("X-MBX-APIKEY") != s.apiKey {
+		writeError(w, http.StatusUnauthorized, codeInvalidAPIKey, "Invalid API-key, IP, or permissions for action.")
+		return false
+	}
+
+	// The signature covers the query string exactly as sent, up to the signature itself
+	raw := r.URL.RawQuery
+	index := strings.LastIndex(raw, "signature=")
+	if index < 0 {
+		writeError(w, http.StatusBadRequest, codeMandatoryParam, "Mandatory parameter 'signature' was not sent, was empty/null, or malformed.")
+		return false
+	}
+	payload := strings.TrimSuffix(raw[:index], "&")
+	mac := hmac.New(sha256.New, []byte(s.apiSecret))
+	mac.Write([]byte(payload))
+	if !hmac.Equal([]byte(hex.EncodeToString(mac.Sum(nil))), []byte(raw[index+len("signature="):])) {
+		writeError(w, http.StatusBadRequest, codeInvalidSignature, "Signature for this request is not valid.")
+		return false
+	}
+
+	query := r.URL.Query()
+	timestamp, err := strconv.ParseInt(query.Get("timestamp"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, codeMandatoryParam, "Mandatory parameter 'timestamp' was not sent, was empty/null, or malformed.")
+		return false
+	}
+	recvWindow, err := strconv.ParseInt(query.Get("recvWindow"), 10, 64)
+	if err != nil {
+		recvWindow = defaultRecvWindow
+	}
+	now := s.now().UnixMilli()
+	if timestamp > now+1000 || now-timestamp > recvWindow {
+		writeError(w, http.StatusBadRequest, codeInvalidTimestamp, "Timestamp for this request is outside of the recvWindow.")
+		return false
+	}
+	return true
+}
+
+// handleExchangeInfo serves one symbol or every symbol with its filters.
+func (s *Server) handleExchangeInfo(w http.ResponseWriter, symbol string) {
+	markets, ok := s.selectMarkets(symbol, "")
+	if !ok {
+		writeError(w, http.StatusBadRequest, codeInvalidSymbol, "Invalid symbol.")
+		return
+	}
+
+	symbols := make([]map[string]interface{}, 0, len(markets))
+	for _, m := range markets {
+		symbols = append(symbols, map[string]interface{}{
+			"symbol":     m.Symbol.Symbol,
+			"status":     m.Status,
+			"baseAsset":  m.BaseAsset,
+			"quoteAsset": m.QuoteAsset,
+			"filters": []map[string]interface{}{
+				{"filterType": "PRICE_FILTER", "minPrice": formatFloat(m.TickSize), "maxPrice": "1000000.00000000", "tickSize": formatFloat(m.TickSize)},
+				{"filterType": "LOT_SIZE", "minQty": formatFloat(m.StepSize), "maxQty": "9000.00000000", "stepSize": formatFloat(m.StepSize)},
+				{"filterType": "NOTIONAL", "minNotional": formatFloat(m.MinNotional), "applyMinToMarket": true},
+			},
+		})
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"timezone":   "UTC",
+		"serverTime": s.now().UnixMilli(),
+		"symbols":    symbols,
+	})
+}
+
+// handleTickers serves a ticker endpoint for one symbol (an object, advancing the
+// price path), a list of symbols or every symbol (an array).
+func (s *Server) handleTickers(w http.ResponseWriter, query map[string][]string, ticker func(*market) interface{}) {
+	symbol := first(query["symbol"])
+	markets, ok := s.selectMarkets(symbol, first(query["symbols"]))
+	if !ok {
+		writeError(w, http.StatusBadRequest, codeInvalidSymbol, "Invalid symbol.")
+		return
+	}
+
+	if symbol != "" {
+		m := markets[0]
+		if len(m.path) > 0 {
+			m.Price = m.path[0]
+			m.path = m.path[1:]
+		}
+		writeJSON(w, http.StatusOK, ticker(m))
+		return
+	}
+
+	tickers := make([]interface{}, 0, len(markets))
+	for _, m := range markets {
+		tickers = append(tickers, ticker(m))
+	}
+	writeJSON(w, http.StatusOK, tickers)
+}
+
+// ticker24h builds a /api/v3/ticker/24hr entry.
+func (s *Server) ticker24h(m *market) interface{} {
+	open := m.Price / (1 + m.Change24h/100)
+	now := s.now()
+	return map[string]interface{}{
+		"symbol":             m.Symbol.Symbol,
+		"priceChange":        formatFloat(m.Price - open),
+		"priceChangePercent": formatFloat(m.Change24h),
+		"weightedAvgPrice":   formatFloat((m.Price + open) / 2),
+		"prevClosePrice":     formatFloat(open),
+		"lastPrice":          formatFloat(m.Price),
+		"lastQty":            "0.00100000",
+		"openPrice":          formatFloat(open),
+		"highPrice":          formatFloat(max(m.Price, open)),
+		"lowPrice":           formatFloat(min(m.Price, open)),
+		"volume":             "1000.00000000",
+		"quoteVolume":        formatFloat(1000 * m.Price),
+		"openTime":           now.Add(-24 * time.Hour).UnixMilli(),
+		"closeTime":          now.UnixMilli(),
+		"firstId":            1,
+		"lastId":             1000,
+		"count":              1000,
+	}
+}
+
+// tickerPrice builds a /api/v3/ticker/price entry.
+func (s *Server) tickerPrice(m *market) interface{} {
+	return map[string]string{"symbol": m.Symbol.Symbol, "price": formatFloat(m.Price)}
+}
+
+// selectMarkets returns the markets named by symbol or by the JSON array symbols,
+// or every market sorted by name when both are empty. ok is false if a name is unknown.
+func (s *Server) selectMarkets(symbol, symbols string) ([]*market, bool) {
+	var names []string
+	switch {
+	case symbol != "":
+		names = []string{symbol}
+	case symbols != "":
+		if err := json.Unmarshal([]byte(symbols), &names); err != nil {
+			return nil, false
+		}
+	default:
+		for name := range s.markets {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+	}
+
+	markets := make([]*market, 0, len(names))
+	for _, name := range names {
+		m, ok := s.markets[strings.ToUpper(name)]
+		if !ok {
+			return nil, false
+		}
+		markets = append(markets, m)
+	}
+	return markets, true
+}
+
+// handleKlines serves the stored candles of a symbol within startTime and endTime,
+// oldest first, up to limit (500 by default).
+func (s *Server) handleKlines(w http.ResponseWriter, query map[string][]string) {
+	symbol, interval := strings.ToUpper(first(query["symbol"])), first(query["interval"])
+	if _, ok := s.markets[symbol]; !ok {
+		writeError(w, http.StatusBadRequest, codeInvalidSymbol, "Invalid symbol.")
+		return
+	}
+	duration, ok := intervalDurations[interval]
+	if !ok {
+		writeError(w, http.StatusBadRequest, codeInvalidInterval, "Invalid interval.")
+		return
+	}
+
+	start, _ := strconv.ParseInt(first(query["startTime"]), 10, 64)
+	end, _ := strconv.ParseInt(first(query["endTime"]), 10, 64)
+	limit, err := strconv.Atoi(first(query["limit"]))
+	if err != nil || limit <= 0 {
+		limit = 500
+	}
+
+	rows := [][]interface{}{}
+	for _, k := range s.klines[klineKey(symbol, interval)] {
+		openTime := k.OpenTime.UnixMilli()
+		if openTime < start || (end > 0 && openTime > end) {
+			continue
+		}
+		rows = append(rows, []interface{}{
+			openTime, formatFloat(k.Open), formatFloat(k.High), formatFloat(k.Low), formatFloat(k.Close),
+			formatFloat(k.Volume), k.OpenTime.Add(duration).UnixMilli() - 1, formatFloat(k.Volume * k.Close),
+			10, formatFloat(k.Volume / 2), formatFloat(k.Volume * k.Close / 2), "0",
+		})
+		if len(rows) == limit {
+			break
+		}
+	}
+	writeJSON(w, http.StatusOK, rows)
+}
+
+// handleAccount serves /api/v3/account with the balances set by SetBalance.
+func (s *Server) handleAccount(w http.ResponseWriter) {
+	assets := make([]string, 0, len(s.balances))
+	for asset := range s.balances {
+		assets = append(assets, asset)
+	}
+	sort.Strings(assets)
+
+	balances := make([]map[string]string, 0, len(assets))
+	for _, asset := range assets {
+		balance := s.balances[asset]
+		balances = append(balances, map[string]string{
+			"asset":  asset,
+			"free":   formatFloat(balance[0]),
+			"locked": formatFloat(balance[1]),
+		})
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"makerCommission":  10,
+		"takerCommission":  10,
+		"buyerCommission":  0,
+		"sellerCommission": 0,
+		"commissionRates": map[string]string{
+			"maker": "0.00100000", "taker": "0.00100000", "buyer": "0.00000000", "seller": "0.00000000",
+		},
+		"canTrade":    true,
+		"canWithdraw": true,
+		"canDeposit":  true,
+		"updateTime":  s.now().UnixMilli(),
+		"accountType": "SPOT",
+		"balances":    balances,
+		"permissions": []string{"SPOT"},
+	})
+}
+
+// now returns the server time. The caller holds s.mux.
+func (s *Server) now() time.Time {
+	return time.Now().Add(s.clockSkew)
+}
+
+// intervalDurations maps the kline intervals the fake serves to their length.
+var intervalDurations = map[string]time.Duration{
+	"1m": time.Minute, "3m": 3 * time.Minute, "5m": 5 * time.Minute, "15m": 15 * time.Minute,
+	"30m": 30 * time.Minute, "1h": time.Hour, "2h": 2 * time.Hour, "4h": 4 * time.Hour,
+	"6h": 6 * time.Hour, "8h": 8 * time.Hour, "12h": 12 * time.Hour, "1d": 24 * time.Hour,
+}
+
+// klineKey is the key of the candles of a symbol and interval.
+func klineKey(symbol, interval string) string {
+	return strings.ToUpper(symbol) + "@" + interval
+}
+
+// first returns the first value of a query parameter, or "".
+func first(values []string) string {
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
+
+// formatFloat formats a number the way Binance does, as a string with 8 decimals.
+func formatFloat(value float64) string {
+	return strconv.FormatFloat(value, 'f', 8, 64)
+}
+
+// writeJSON writes a JSON response.
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
+
+// writeError writes a Binance error response.
+func writeError(w http.ResponseWriter, status, code int, message string) {
+	writeJSON(w, status, map[string]interface{}{"code": code, "msg": message})
+}
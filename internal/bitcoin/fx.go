@@ -0,0 +1,49 @@
+// Package bitcoin provides functionality for interacting with cryptocurrency APIs.
+package bitcoin
+
+import (
+	"fmt"
+	"strings"
+)
+
+// BinanceFXProvider derives fiat exchange rates from Binance fiat markets, such as
+// EURUSDT or USDTTRY, using the same cached bulk tickers that value account balances.
+// Currencies Binance doesn't list (COP, for instance) return an error so the next
+// provider can be tried.
+//
+// Example usage:
+//
+//	provider := NewBinanceFXProvider(NewBinanceClient("", "", cfg.BinanceBaseURL, nil))
+//	rate, err := provider.GetUSDRate("EUR") // ≈ 0.92
+type BinanceFXProvider struct {
+	client *BinanceClient
+}
+
+// NewBinanceFXProvider creates a Binance-backed FX rate provider.
+func NewBinanceFXProvider(client *BinanceClient) *BinanceFXProvider {
+	return &BinanceFXProvider{client: client}
+}
+
+// Name returns the provider name reported with each rate.
+func (p *BinanceFXProvider) Name() string {
+	return SourceBinance
+}
+
+// GetUSDRate returns how many units of currency one US dollar (taken as 1 USDT) buys.
+func (p *BinanceFXProvider) GetUSDRate(currency string) (float64, error) {
+	currency = strings.ToUpper(strings.TrimSpace(currency))
+
+	// Only direct and inverse USDT markets are used; routes through BTC would carry
+	// the crypto spread into the rate
+	tickers, err := p.client.tickers.get(p.client)
+	if err != nil {
+		return 0, err
+	}
+	if quote, ok := pairQuote(tickers, valuationQuote+currency); ok {
+		return quote.Price, nil
+	}
+	if quote, ok := pairQuote(tickers, currency+valuationQuote); ok {
+		return 1 / quote.Price, nil
+	}
+	return 0, fmt.Errorf("no Binance market for %s", currency)
+}
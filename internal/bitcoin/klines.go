@@ -0,0 +1,421 @@
+// Package bitcoin provides functionality for interacting with cryptocurrency APIs.
+package bitcoin
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/cgallonv/btc-alerta-de-precio/internal/storage/models"
+)
+
+// klinesPageSize is the largest page Binance returns from /api/v3/klines.
+const klinesPageSize = 1000
+
+// intervalDurations are the kline intervals of a fixed length. Binance aligns them on
+// the Unix epoch; 3d, 1w and 1M candles aren't, so they're left out.
+var intervalDurations = map[string]time.Duration{
+	"1m": time.Minute, "3m": 3 * time.Minute, "5m": 5 * time.Minute, "15m": 15 * time.Minute, "30m": 30 * time.Minute,
+	"1h": time.Hour, "2h": 2 * time.Hour, "4h": 4 * time.Hour, "6h": 6 * time.Hour, "8h": 8 * time.Hour, "12h": 12 * time.Hour,
+	"1d": 24 * time.Hour,
+}
+
+// IntervalDuration returns the length of a kline interval such as "15m" or "1h".
+// It returns false for unknown intervals and for those without a fixed length.
+func IntervalDuration(interval string) (time.Duration, bool) {
+	d, ok := intervalDurations[interval]
+	return d, ok
+}
+
+// CandleOpenTime returns the open time of the interval candle containing t.
+func CandleOpenTime(t time.Time, interval time.Duration) time.Time {
+	ms := t.UnixMilli()
+	return time.UnixMilli(ms - ms%interval.Milliseconds())
+}
+
+// Kline is an OHLCV candlestick of a symbol, from /api/v3/klines or the kline stream.
+type Kline struct {
+	Symbol              string
+	Interval            string
+	OpenTime            time.Time
+	CloseTime           time.Time
+	Open                float64
+	High                float64
+	Low                 float64
+	Close               float64
+	Volume              float64 // Base asset volume
+	QuoteVolume         float64 // Quote asset volume
+	Trades              int64
+	TakerBuyVolume      float64
+	TakerBuyQuoteVolume float64
+	Closed              bool // False while the candle is still forming
+}
+
+// Candle converts the kline into its database model.
+func (k *Kline) Candle() models.Candle {
+	return models.Candle{
+		Symbol:              k.Symbol,
+		Interval:            k.Interval,
+		OpenTime:            k.OpenTime,
+		CloseTime:           k.CloseTime,
+		Open:                k.Open,
+		High:                k.High,
+		Low:                 k.Low,
+		Close:               k.Close,
+		Volume:              k.Volume,
+		QuoteVolume:         k.QuoteVolume,
+		Trades:              k.Trades,
+		TakerBuyVolume:      k.TakerBuyVolume,
+		TakerBuyQuoteVolume: k.TakerBuyQuoteVolume,
+	}
+}
+
+// GetHistoricalKlines fetches historical kline/candlestick data for a symbol with automatic pagination.
+// Interval can be: 1m,3m,5m,15m,30m,1h,2h,4h,6h,8h,12h,1d,3d,1w,1M
+// This method automatically handles pagination to fetch all data in the specified time range,
+// not limited by Binance API's 1000 record limit per request.
+//
+// Example usage:
+//
+//	klines, err := client.GetHistoricalKlines("BTCUSDT", "1m", time.Now().Add(-60*24*time.Hour), time.Now())
+//	if err != nil {
+//	    log.Printf("Error: %v", err)
+//	    return
+//	}
+//	fmt.Printf("Fetched %d historical records\n", len(klines))
+func (c *BinanceClient) GetHistoricalKlines(symbol, interval string, startTime, endTime time.Time) ([]Kline, error) {
+	log.Printf("🔄 Fetching historical klines for %s from %s to %s", symbol, startTime.Format(time.RFC3339), endTime.Format(time.RFC3339))
+
+	var allKlines []Kline
+	currentStartTime := startTime
+	requestCount := 0
+
+	for currentStartTime.Before(endTime) {
+		requestCount++
+
+		var rows [][]json.RawMessage
+		resp, err := c.httpClient.R().
+			SetQueryParams(map[string]string{
+				"symbol":    symbol,
+				"interval":  interval,
+				"startTime": fmt.Sprintf("%d", currentStartTime.UnixMilli()),
+				"endTime":   fmt.Sprintf("%d", endTime.UnixMilli()),
+				"limit":     fmt.Sprintf("%d", klinesPageSize),
+			}).
+			SetResult(&rows).
+			Get("/api/v3/klines")
+
+		if err != nil {
+			log.Printf("❌ Error fetching historical klines (request %d): %v", requestCount, err)
+			return nil, fmt.Errorf("error fetching historical klines: %w", err)
+		}
+
+		if resp.StatusCode() != 200 {
+			binanceErr := NewBinanceError(resp.StatusCode(), resp.String())
+			log.Printf("❌ Binance API error (request %d): %v", requestCount, binanceErr)
+			return nil, binanceErr
+		}
+
+		// Si no hay más datos, terminar
+		if len(rows) == 0 {
+			break
+		}
+
+		for _, row := range rows {
+			kline, err := parseKline(symbol, interval, row)
+			if err != nil {
+				return nil, err
+			}
+			allKlines = append(allKlines, *kline)
+		}
+
+		// La siguiente página empieza después del closeTime del último registro
+		currentStartTime = allKlines[len(allKlines)-1].CloseTime.Add(time.Millisecond)
+
+		// Si obtuvimos menos de 1000 registros, hemos llegado al final
+		if len(rows) < klinesPageSize {
+			break
+		}
+
+		// Rate limiting entre requests para evitar límites de API
+		time.Sleep(100 * time.Millisecond)
+	}
+
+	log.Printf("✅ Historical klines fetch completed: %d total records from %d API requests", len(allKlines), requestCount)
+	return allKlines, nil
+}
+
+// parseKline decodes a /api/v3/klines row:
+// [openTime, open, high, low, close, volume, closeTime, quoteVolume, trades,
+// takerBuyBaseVolume, takerBuyQuoteVolume, ignore]
+func parseKline(symbol, interval string, row []json.RawMessage) (*Kline, error) {
+	if len(row) < 11 {
+		return nil, fmt.Errorf("error decoding kline: expected 11 fields, got %d", len(row))
+	}
+
+	var openTime, closeTime, trades int64
+	var open, high, low, closePrice, volume, quoteVolume, takerBuyVolume, takerBuyQuoteVolume string
+	targets := []interface{}{
+		&openTime, &open, &high, &low, &closePrice, &volume,
+		&closeTime, &quoteVolume, &trades, &takerBuyVolume, &takerBuyQuoteVolume,
+	}
+	for i, target := range targets {
+		if err := json.Unmarshal(row[i], target); err != nil {
+			return nil, fmt.Errorf("error decoding kline field %d: %w", i, err)
+		}
+	}
+
+	return &Kline{
+		Symbol:              symbol,
+		Interval:            interval,
+		OpenTime:            time.UnixMilli(openTime),
+		CloseTime:           time.UnixMilli(closeTime),
+		Open:                stringToFloat64(open),
+		High:                stringToFloat64(high),
+		Low:                 stringToFloat64(low),
+		Close:               stringToFloat64(closePrice),
+		Volume:              stringToFloat64(volume),
+		QuoteVolume:         stringToFloat64(quoteVolume),
+		Trades:              trades,
+		TakerBuyVolume:      stringToFloat64(takerBuyVolume),
+		TakerBuyQuoteVolume: stringToFloat64(takerBuyQuoteVolume),
+		// Binance only returns candles that have started; the last one may still be open
+		Closed: time.UnixMilli(closeTime).Before(time.Now()),
+	}, nil
+}
+
+// klineEvent is a kline stream event. encoding/json matches keys case-insensitively,
+// so both keys of each pair that differ only in case are declared.
+type klineEvent struct {
+	EventType string `json:"e"`
+	EventTime int64  `json:"E"`
+	Symbol    string `json:"s"`
+	Kline     struct {
+		OpenTime            int64  `json:"t"`
+		CloseTime           int64  `json:"T"`
+		Symbol              string `json:"s"`
+		Interval            string `json:"i"`
+		FirstTradeID        int64  `json:"f"`
+		LastTradeID         int64  `json:"L"`
+		Open                string `json:"o"`
+		Close               string `json:"c"`
+		High                string `json:"h"`
+		Low                 string `json:"l"`
+		Volume              string `json:"v"`
+		Trades              int64  `json:"n"`
+		Closed              bool   `json:"x"`
+		QuoteVolume         string `json:"q"`
+		TakerBuyVolume      string `json:"V"`
+		TakerBuyQuoteVolume string `json:"Q"`
+		Ignore              string `json:"B"`
+	} `json:"k"`
+}
+
+// parseStreamKline converts a kline stream message, combined or raw, into a Kline.
+func parseStreamKline(message []byte) (*Kline, error) {
+	var envelope struct {
+		Stream string          `json:"stream"`
+		Data   json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(message, &envelope); err != nil {
+		return nil, fmt.Errorf("error decoding stream message: %w", err)
+	}
+
+	payload := message
+	if len(envelope.Data) > 0 {
+		payload = envelope.Data
+	}
+
+	var event klineEvent
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return nil, fmt.Errorf("error decoding kline event: %w", err)
+	}
+	if event.EventType != "kline" {
+		return nil, fmt.Errorf("unsupported event type %q", event.EventType)
+	}
+
+	k := event.Kline
+	return &Kline{
+		Symbol:              event.Symbol,
+		Interval:            k.Interval,
+		OpenTime:            time.UnixMilli(k.OpenTime),
+		CloseTime:           time.UnixMilli(k.CloseTime),
+		Open:                stringToFloat64(k.Open),
+		High:                stringToFloat64(k.High),
+		Low:                 stringToFloat64(k.Low),
+		Close:               stringToFloat64(k.Close),
+		Volume:              stringToFloat64(k.Volume),
+		QuoteVolume:         stringToFloat64(k.QuoteVolume),
+		Trades:              k.Trades,
+		TakerBuyVolume:      stringToFloat64(k.TakerBuyVolume),
+		TakerBuyQuoteVolume: stringToFloat64(k.TakerBuyQuoteVolume),
+		Closed:              k.Closed,
+	}, nil
+}
+
+// KlineHandler receives each kline update from a KlineStream.
+type KlineHandler func(kline *Kline)
+
+// KlineStream subscribes to the Binance kline streams of a set of symbols at one interval.
+// Binance pushes the forming candle every couple of seconds and a final update with
+// Closed set when its interval ends. Connection failures are retried with exponential backoff.
+//
+// Example usage:
+//
+//	stream := NewKlineStream("", []string{"BTCUSDT"}, "1m")
+//	stream.OnKline(func(kline *Kline) {
+//	    if kline.Closed {
+//	        log.Printf("%s closed at %.2f", kline.Symbol, kline.Close)
+//	    }
+//	})
+//	go stream.Run(ctx)
+type KlineStream struct {
+	baseURL  string
+	symbols  []string
+	interval string
+	dialer   *websocket.Dialer
+
+	// Keepalive and reconnect settings
+	pongWait          time.Duration
+	reconnectDelay    time.Duration
+	maxReconnectDelay time.Duration
+
+	handlers   []KlineHandler
+	handlerMux sync.RWMutex
+}
+
+// NewKlineStream creates a kline stream for symbols at interval.
+// If baseURL is empty, DefaultBinanceStreamURL is used.
+func NewKlineStream(baseURL string, symbols []string, interval string) *KlineStream {
+	if baseURL == "" {
+		baseURL = DefaultBinanceStreamURL
+	}
+
+	normalized := make([]string, 0, len(symbols))
+	for _, symbol := range symbols {
+		if symbol = strings.ToUpper(strings.TrimSpace(symbol)); symbol != "" {
+			normalized = append(normalized, symbol)
+		}
+	}
+
+	return &KlineStream{
+		baseURL:           strings.TrimRight(baseURL, "/"),
+		symbols:           normalized,
+		interval:          interval,
+		dialer:            websocket.DefaultDialer,
+		pongWait:          60 * time.Second,
+		reconnectDelay:    time.Second,
+		maxReconnectDelay: time.Minute,
+	}
+}
+
+// Symbols returns the symbols the stream subscribes to.
+func (s *KlineStream) Symbols() []string {
+	return append([]string(nil), s.symbols...)
+}
+
+// Interval returns the candle interval of the stream.
+func (s *KlineStream) Interval() string {
+	return s.interval
+}
+
+// OnKline registers a handler for kline updates. Handlers run on the stream goroutine.
+func (s *KlineStream) OnKline(handler KlineHandler) {
+	s.handlerMux.Lock()
+	defer s.handlerMux.Unlock()
+	s.handlers = append(s.handlers, handler)
+}
+
+// URL returns the combined stream URL for the configured symbols.
+//
+// Example usage:
+//
+//	stream.URL() // wss://stream.binance.com:9443/stream?streams=btcusdt@kline_1m
+func (s *KlineStream) URL() string {
+	streams := make([]string, len(s.symbols))
+	for i, symbol := range s.symbols {
+		streams[i] = fmt.Sprintf("%s@kline_%s", strings.ToLower(symbol), s.interval)
+	}
+	return fmt.Sprintf("%s/stream?streams=%s", s.baseURL, strings.Join(streams, "/"))
+}
+
+// Run delivers kline updates to the registered handlers until ctx is cancelled.
+func (s *KlineStream) Run(ctx context.Context) {
+	delay := s.reconnectDelay
+
+	for {
+		connectedAt := time.Now()
+		err := s.connectAndRead(ctx)
+
+		if ctx.Err() != nil {
+			log.Printf("🔌 Kline stream stopped")
+			return
+		}
+
+		// Reset backoff after a connection that stayed up for a while
+		if time.Since(connectedAt) > s.maxReconnectDelay {
+			delay = s.reconnectDelay
+		}
+
+		log.Printf("⚠️ Kline stream disconnected: %v (reconnecting in %v)", err, delay)
+
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return
+		}
+
+		delay *= 2
+		if delay > s.maxReconnectDelay {
+			delay = s.maxReconnectDelay
+		}
+	}
+}
+
+// connectAndRead opens a single connection and reads kline events until it fails.
+func (s *KlineStream) connectAndRead(ctx context.Context) error {
+	conn, _, err := s.dialer.DialContext(ctx, s.URL(), nil)
+	if err != nil {
+		return fmt.Errorf("error connecting to kline stream: %w", err)
+	}
+	defer conn.Close()
+
+	log.Printf("🔌 Connected to kline stream: %s", s.URL())
+
+	// Close the connection when the context is cancelled so ReadMessage unblocks
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			conn.Close()
+		case <-done:
+		}
+	}()
+
+	for {
+		conn.SetReadDeadline(time.Now().Add(s.pongWait))
+		_, message, err := conn.ReadMessage()
+		if err != nil {
+			return fmt.Errorf("error reading from kline stream: %w", err)
+		}
+
+		kline, err := parseStreamKline(message)
+		if err != nil {
+			continue
+		}
+
+		s.handlerMux.RLock()
+		handlers := s.handlers
+		s.handlerMux.RUnlock()
+		for _, handler := range handlers {
+			handler(kline)
+		}
+	}
+}
@@ -0,0 +1,134 @@
+// Package bitcoin provides functionality for interacting with cryptocurrency APIs.
+package bitcoin
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+)
+
+// DefaultKrakenBaseURL is the public Kraken REST endpoint.
+const DefaultKrakenBaseURL = "https://api.kraken.com"
+
+// KrakenClient fetches public market prices from the Kraken API.
+// It is used as a fallback price provider when Binance is unavailable.
+//
+// Example usage:
+//
+//	client := NewKrakenClient("")
+//	price, err := client.GetSymbolPrice("BTCUSDT")
+//	if err != nil {
+//	    log.Printf("Error: %v", err)
+//	    return
+//	}
+//	fmt.Println(price)
+type KrakenClient struct {
+	httpClient *resty.Client
+}
+
+// krakenTickerResponse represents the response from /0/public/Ticker.
+// Kraken keys the result by its own pair name (e.g. "XBTUSDT"), and reports
+// errors in the body with a 200 status.
+type krakenTickerResponse struct {
+	Error  []string `json:"error"`
+	Result map[string]struct {
+		Last []string `json:"c"` // [price, lot volume]
+		Open string   `json:"o"` // Today's opening price (00:00 UTC)
+	} `json:"result"`
+}
+
+// NewKrakenClient creates a new Kraken client.
+// If baseURL is empty, DefaultKrakenBaseURL is used.
+//
+// Example usage:
+//
+//	client := NewKrakenClient("https://api.kraken.com")
+func NewKrakenClient(baseURL string) *KrakenClient {
+	if baseURL == "" {
+		baseURL = DefaultKrakenBaseURL
+	}
+
+	client := resty.New()
+	client.SetTimeout(10 * time.Second)
+	client.SetBaseURL(baseURL)
+
+	return &KrakenClient{httpClient: client}
+}
+
+// Name returns the provider name reported in PriceData.Source.
+func (c *KrakenClient) Name() string {
+	return SourceKraken
+}
+
+// GetSymbolPrice fetches the current price for a Binance-style symbol.
+// The symbol is translated to a Kraken pair, e.g. "BTCUSDT" becomes "XBTUSDT".
+// PriceChangePercent is measured from Kraken's daily open, not a rolling 24h window.
+//
+// Example usage:
+//
+//	price, err := client.GetSymbolPrice("BTCUSDT")
+//	if err != nil {
+//	    return err
+//	}
+//	fmt.Printf("BTC: $%.2f\n", price.Price)
+func (c *KrakenClient) GetSymbolPrice(symbol string) (*PriceData, error) {
+	base, quote, err := SplitSymbol(symbol)
+	if err != nil {
+		return nil, err
+	}
+	pair := base + quote
+	if base == "BTC" {
+		pair = "XBT" + quote
+	}
+
+	log.Printf("🔄 Fetching %s price from Kraken API", pair)
+
+	var response krakenTickerResponse
+	resp, err := c.httpClient.R().
+		SetQueryParam("pair", pair).
+		SetResult(&response).
+		Get("/0/public/Ticker")
+
+	if err != nil {
+		return nil, fmt.Errorf("error fetching price from Kraken: %w", err)
+	}
+
+	if resp.StatusCode() != 200 {
+		return nil, &ProviderError{Provider: SourceKraken, Status: resp.StatusCode(), Message: resp.String()}
+	}
+
+	if len(response.Error) > 0 {
+		return nil, &ProviderError{Provider: SourceKraken, Status: resp.StatusCode(), Message: strings.Join(response.Error, "; ")}
+	}
+
+	for _, ticker := range response.Result {
+		if len(ticker.Last) == 0 {
+			break
+		}
+
+		price, err := strconv.ParseFloat(ticker.Last[0], 64)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing price from Kraken: %w", err)
+		}
+
+		var priceChangePercent float64
+		if open := stringToFloat64(ticker.Open); open > 0 {
+			priceChangePercent = (price - open) / open * 100
+		}
+
+		return &PriceData{
+			Symbol:             base + quote,
+			Price:              price,
+			PriceChangePercent: priceChangePercent,
+			Currency:           "USD",
+			Timestamp:          time.Now(),
+			Source:             SourceKraken,
+		}, nil
+	}
+
+	return nil, fmt.Errorf("no ticker returned by Kraken for %s", pair)
+}
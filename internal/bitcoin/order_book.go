@@ -0,0 +1,543 @@
+// Package bitcoin provides functionality for interacting with cryptocurrency APIs.
+package bitcoin
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// DefaultOrderBookDepth is the number of levels per side requested for a depth snapshot.
+const DefaultOrderBookDepth = 1000
+
+// PriceLevel is the total quantity resting at a price.
+type PriceLevel struct {
+	Price    float64 `json:"price"`
+	Quantity float64 `json:"quantity"`
+}
+
+// OrderBookSnapshot is a point-in-time copy of an order book, with bids sorted from the
+// highest price and asks from the lowest. Its methods derive the liquidity measures
+// used by order book alerts.
+//
+// Example usage:
+//
+//	book, err := client.GetOrderBook("BTCUSDT", 1000)
+//	if err != nil {
+//	    return err
+//	}
+//	log.Printf("Spread %.1f bps, imbalance %+.1f%%", book.SpreadBps(), book.Imbalance(1))
+type OrderBookSnapshot struct {
+	Symbol       string       `json:"symbol"`
+	LastUpdateID int64        `json:"last_update_id"`
+	Time         time.Time    `json:"time"`
+	Bids         []PriceLevel `json:"bids"`
+	Asks         []PriceLevel `json:"asks"`
+}
+
+// OrderBookStats summarises an order book within a band around the mid price.
+type OrderBookStats struct {
+	Symbol       string    `json:"symbol"`
+	Time         time.Time `json:"time"`
+	BestBid      float64   `json:"best_bid"`
+	BestAsk      float64   `json:"best_ask"`
+	MidPrice     float64   `json:"mid_price"`
+	SpreadBps    float64   `json:"spread_bps"`
+	DepthPercent float64   `json:"depth_percent"`
+	BidLiquidity float64   `json:"bid_liquidity"` // Base quantity bid within DepthPercent below the mid price
+	AskLiquidity float64   `json:"ask_liquidity"` // Base quantity offered within DepthPercent above the mid price
+	Imbalance    float64   `json:"imbalance"`     // (bids - asks) / (bids + asks), in percent
+}
+
+// BestBid returns the highest bid price, or 0 if there are no bids.
+func (s *OrderBookSnapshot) BestBid() float64 {
+	if len(s.Bids) == 0 {
+		return 0
+	}
+	return s.Bids[0].Price
+}
+
+// BestAsk returns the lowest ask price, or 0 if there are no asks.
+func (s *OrderBookSnapshot) BestAsk() float64 {
+	if len(s.Asks) == 0 {
+		return 0
+	}
+	return s.Asks[0].Price
+}
+
+// MidPrice returns the average of the best bid and ask, or 0 if a side is empty.
+func (s *OrderBookSnapshot) MidPrice() float64 {
+	bid, ask := s.BestBid(), s.BestAsk()
+	if bid == 0 || ask == 0 {
+		return 0
+	}
+	return (bid + ask) / 2
+}
+
+// SpreadBps returns the spread between the best ask and bid in basis points of the mid price.
+func (s *OrderBookSnapshot) SpreadBps() float64 {
+	mid := s.MidPrice()
+	if mid == 0 {
+		return 0
+	}
+	return (s.BestAsk() - s.BestBid()) / mid * 10000
+}
+
+// BidLiquidity returns the base quantity bid within percent below the mid price.
+//
+// Example usage:
+//
+//	if book.BidLiquidity(1) < 5 {
+//	    log.Printf("Less than 5 BTC bid within 1%% of the price")
+//	}
+func (s *OrderBookSnapshot) BidLiquidity(percent float64) float64 {
+	floor := s.MidPrice() * (1 - percent/100)
+	var total float64
+	for _, level := range s.Bids {
+		if level.Price < floor {
+			break
+		}
+		total += level.Quantity
+	}
+	return total
+}
+
+// AskLiquidity returns the base quantity offered within percent above the mid price.
+func (s *OrderBookSnapshot) AskLiquidity(percent float64) float64 {
+	ceiling := s.MidPrice() * (1 + percent/100)
+	var total float64
+	for _, level := range s.Asks {
+		if level.Price > ceiling {
+			break
+		}
+		total += level.Quantity
+	}
+	return total
+}
+
+// Imbalance compares the bid and ask liquidity within percent of the mid price.
+// It ranges from +100 (only bids) to -100 (only asks); 0 means a balanced book.
+func (s *OrderBookSnapshot) Imbalance(percent float64) float64 {
+	bids, asks := s.BidLiquidity(percent), s.AskLiquidity(percent)
+	if bids+asks == 0 {
+		return 0
+	}
+	return (bids - asks) / (bids + asks) * 100
+}
+
+// Stats computes the OrderBookStats of the snapshot for a band of percent around the mid price.
+func (s *OrderBookSnapshot) Stats(percent float64) *OrderBookStats {
+	return &OrderBookStats{
+		Symbol:       s.Symbol,
+		Time:         s.Time,
+		BestBid:      s.BestBid(),
+		BestAsk:      s.BestAsk(),
+		MidPrice:     s.MidPrice(),
+		SpreadBps:    s.SpreadBps(),
+		DepthPercent: percent,
+		BidLiquidity: s.BidLiquidity(percent),
+		AskLiquidity: s.AskLiquidity(percent),
+		Imbalance:    s.Imbalance(percent),
+	}
+}
+
+// depthResponse is the /api/v3/depth response.
+type depthResponse struct {
+	LastUpdateID int64       `json:"lastUpdateId"`
+	Bids         [][2]string `json:"bids"`
+	Asks         [][2]string `json:"asks"`
+}
+
+// GetOrderBook fetches a depth snapshot of up to limit levels per side from /api/v3/depth.
+//
+// Example usage:
+//
+//	book, err := client.GetOrderBook("BTCUSDT", 100)
+//	if err != nil {
+//	    return err
+//	}
+//	log.Printf("Best bid $%.2f, best ask $%.2f", book.BestBid(), book.BestAsk())
+func (c *BinanceClient) GetOrderBook(symbol string, limit int) (*OrderBookSnapshot, error) {
+	symbol = strings.ToUpper(symbol)
+	if limit <= 0 {
+		limit = DefaultOrderBookDepth
+	}
+
+	var response depthResponse
+	resp, err := c.httpClient.R().
+		SetQueryParam("symbol", symbol).
+		SetQueryParam("limit", fmt.Sprintf("%d", limit)).
+		SetResult(&response).
+		Get("/api/v3/depth")
+
+	if err != nil {
+		return nil, fmt.Errorf("error fetching order book: %w", err)
+	}
+
+	if resp.StatusCode() != 200 {
+		return nil, NewBinanceError(resp.StatusCode(), resp.String())
+	}
+
+	book := newOrderBook(symbol)
+	book.load(&response)
+	return book.snapshot(), nil
+}
+
+// orderBook is a local copy of an order book, kept in sync with the diff depth stream.
+type orderBook struct {
+	symbol       string
+	lastUpdateID int64
+	updated      time.Time
+	bids         map[float64]float64
+	asks         map[float64]float64
+}
+
+// newOrderBook creates an empty order book.
+func newOrderBook(symbol string) *orderBook {
+	return &orderBook{
+		symbol: symbol,
+		bids:   make(map[float64]float64),
+		asks:   make(map[float64]float64),
+	}
+}
+
+// load replaces the book with a depth snapshot.
+func (b *orderBook) load(depth *depthResponse) {
+	b.bids = make(map[float64]float64, len(depth.Bids))
+	b.asks = make(map[float64]float64, len(depth.Asks))
+	setLevels(b.bids, depth.Bids)
+	setLevels(b.asks, depth.Asks)
+	b.lastUpdateID = depth.LastUpdateID
+	b.updated = time.Now()
+}
+
+// apply applies a diff depth event. It returns false if the event doesn't follow the
+// last applied update, in which case the book must be reloaded from a snapshot.
+// Events the snapshot already contains are ignored.
+func (b *orderBook) apply(event *depthEvent) bool {
+	if event.FinalUpdateID <= b.lastUpdateID {
+		return true
+	}
+	if event.FirstUpdateID > b.lastUpdateID+1 {
+		return false
+	}
+
+	setLevels(b.bids, event.Bids)
+	setLevels(b.asks, event.Asks)
+	b.lastUpdateID = event.FinalUpdateID
+	b.updated = time.UnixMilli(event.EventTime)
+	return true
+}
+
+// snapshot returns a sorted copy of the book.
+func (b *orderBook) snapshot() *OrderBookSnapshot {
+	snapshot := &OrderBookSnapshot{
+		Symbol:       b.symbol,
+		LastUpdateID: b.lastUpdateID,
+		Time:         b.updated,
+		Bids:         make([]PriceLevel, 0, len(b.bids)),
+		Asks:         make([]PriceLevel, 0, len(b.asks)),
+	}
+	for price, quantity := range b.bids {
+		snapshot.Bids = append(snapshot.Bids, PriceLevel{Price: price, Quantity: quantity})
+	}
+	for price, quantity := range b.asks {
+		snapshot.Asks = append(snapshot.Asks, PriceLevel{Price: price, Quantity: quantity})
+	}
+	sort.Slice(snapshot.Bids, func(i, j int) bool { return snapshot.Bids[i].Price > snapshot.Bids[j].Price })
+	sort.Slice(snapshot.Asks, func(i, j int) bool { return snapshot.Asks[i].Price < snapshot.Asks[j].Price })
+	return snapshot
+}
+
+// setLevels updates price levels from [price, quantity] pairs; a zero quantity removes the level.
+func setLevels(levels map[float64]float64, updates [][2]string) {
+	for _, update := range updates {
+		price, quantity := stringToFloat64(update[0]), stringToFloat64(update[1])
+		if quantity == 0 {
+			delete(levels, price)
+			continue
+		}
+		levels[price] = quantity
+	}
+}
+
+// depthEvent is a depthUpdate event of the diff depth stream.
+type depthEvent struct {
+	EventType     string      `json:"e"`
+	EventTime     int64       `json:"E"`
+	Symbol        string      `json:"s"`
+	FirstUpdateID int64       `json:"U"`
+	FinalUpdateID int64       `json:"u"`
+	Bids          [][2]string `json:"b"`
+	Asks          [][2]string `json:"a"`
+}
+
+// OrderBookStream keeps a local order book of one symbol in sync with the Binance
+// diff depth stream, following Binance's procedure: the stream is opened first, then
+// a REST snapshot is loaded and the buffered events newer than it are applied. A gap
+// in update IDs reloads the book. Connection failures are retried with exponential backoff.
+//
+// Example usage:
+//
+//	stream := NewOrderBookStream(client, "", "BTCUSDT")
+//	go stream.Run(ctx)
+//	if book, ok := stream.Snapshot(); ok {
+//	    log.Printf("Spread: %.1f bps", book.SpreadBps())
+//	}
+type OrderBookStream struct {
+	client  *BinanceClient
+	baseURL string
+	symbol  string
+	dialer  *websocket.Dialer
+
+	// Keepalive and reconnect settings
+	pongWait          time.Duration
+	reconnectDelay    time.Duration
+	maxReconnectDelay time.Duration
+
+	book    *orderBook
+	synced  bool
+	bookMux sync.RWMutex
+}
+
+// NewOrderBookStream creates an order book stream for symbol.
+// If baseURL is empty, DefaultBinanceStreamURL is used.
+func NewOrderBookStream(client *BinanceClient, baseURL, symbol string) *OrderBookStream {
+	if baseURL == "" {
+		baseURL = DefaultBinanceStreamURL
+	}
+
+	return &OrderBookStream{
+		client:            client,
+		baseURL:           strings.TrimRight(baseURL, "/"),
+		symbol:            strings.ToUpper(symbol),
+		dialer:            websocket.DefaultDialer,
+		pongWait:          60 * time.Second,
+		reconnectDelay:    time.Second,
+		maxReconnectDelay: time.Minute,
+	}
+}
+
+// Snapshot returns a copy of the order book. ok is false until the book is in sync.
+func (s *OrderBookStream) Snapshot() (*OrderBookSnapshot, bool) {
+	s.bookMux.RLock()
+	defer s.bookMux.RUnlock()
+	if !s.synced {
+		return nil, false
+	}
+	return s.book.snapshot(), true
+}
+
+// Run maintains the order book until ctx is cancelled.
+func (s *OrderBookStream) Run(ctx context.Context) {
+	delay := s.reconnectDelay
+
+	for {
+		connectedAt := time.Now()
+		err := s.connectAndRead(ctx)
+		s.setSynced(false)
+
+		if ctx.Err() != nil {
+			log.Printf("🔌 %s order book stream stopped", s.symbol)
+			return
+		}
+
+		// Reset backoff after a connection that stayed up for a while
+		if time.Since(connectedAt) > s.maxReconnectDelay {
+			delay = s.reconnectDelay
+		}
+
+		log.Printf("⚠️ %s order book stream disconnected: %v (reconnecting in %v)", s.symbol, err, delay)
+
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return
+		}
+
+		delay *= 2
+		if delay > s.maxReconnectDelay {
+			delay = s.maxReconnectDelay
+		}
+	}
+}
+
+// connectAndRead opens the diff stream, loads a snapshot and applies events until the connection fails.
+func (s *OrderBookStream) connectAndRead(ctx context.Context) error {
+	url := fmt.Sprintf("%s/ws/%s@depth@100ms", s.baseURL, strings.ToLower(s.symbol))
+	conn, _, err := s.dialer.DialContext(ctx, url, nil)
+	if err != nil {
+		return fmt.Errorf("error connecting to order book stream: %w", err)
+	}
+	defer conn.Close()
+
+	// Close the connection when the context is cancelled so ReadMessage unblocks
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			conn.Close()
+		case <-done:
+		}
+	}()
+
+	// Events received while the snapshot loads wait in the connection buffer
+	if err := s.loadSnapshot(); err != nil {
+		return err
+	}
+	log.Printf("📚 %s order book synced", s.symbol)
+
+	for {
+		conn.SetReadDeadline(time.Now().Add(s.pongWait))
+		_, message, err := conn.ReadMessage()
+		if err != nil {
+			return fmt.Errorf("error reading from order book stream: %w", err)
+		}
+
+		var event depthEvent
+		if err := json.Unmarshal(message, &event); err != nil || event.EventType != "depthUpdate" {
+			continue
+		}
+
+		s.bookMux.Lock()
+		applied := s.book.apply(&event)
+		s.bookMux.Unlock()
+
+		if !applied {
+			log.Printf("⚠️ %s order book missed updates, reloading snapshot", s.symbol)
+			s.setSynced(false)
+			if err := s.loadSnapshot(); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// loadSnapshot replaces the book with a REST depth snapshot.
+func (s *OrderBookStream) loadSnapshot() error {
+	var response depthResponse
+	resp, err := s.client.httpClient.R().
+		SetQueryParam("symbol", s.symbol).
+		SetQueryParam("limit", fmt.Sprintf("%d", DefaultOrderBookDepth)).
+		SetResult(&response).
+		Get("/api/v3/depth")
+	if err != nil {
+		return fmt.Errorf("error fetching order book: %w", err)
+	}
+	if resp.StatusCode() != 200 {
+		return NewBinanceError(resp.StatusCode(), resp.String())
+	}
+
+	s.bookMux.Lock()
+	defer s.bookMux.Unlock()
+	s.book = newOrderBook(s.symbol)
+	s.book.load(&response)
+	s.synced = true
+	return nil
+}
+
+// setSynced updates whether the book is in sync.
+func (s *OrderBookStream) setSynced(synced bool) {
+	s.bookMux.Lock()
+	defer s.bookMux.Unlock()
+	s.synced = synced
+}
+
+// OrderBooks runs an OrderBookStream for each symbol that needs one.
+//
+// Example usage:
+//
+//	books := NewOrderBooks(client, cfg.BinanceStreamURL)
+//	books.Start(ctx)
+//	books.SetSymbols([]string{"BTCUSDT"})
+//	if book, ok := books.Snapshot("BTCUSDT"); ok {
+//	    log.Printf("Imbalance: %+.1f%%", book.Imbalance(1))
+//	}
+type OrderBooks struct {
+	client  *BinanceClient
+	baseURL string
+
+	ctx     context.Context
+	symbols []string
+	streams map[string]*orderBookEntry
+	mux     sync.Mutex
+}
+
+// orderBookEntry is a running OrderBookStream and the function that stops it.
+type orderBookEntry struct {
+	stream *OrderBookStream
+	cancel context.CancelFunc
+}
+
+// NewOrderBooks creates an OrderBooks for the market data of client.
+// If baseURL is empty, DefaultBinanceStreamURL is used.
+func NewOrderBooks(client *BinanceClient, baseURL string) *OrderBooks {
+	return &OrderBooks{
+		client:  client,
+		baseURL: baseURL,
+		streams: make(map[string]*orderBookEntry),
+	}
+}
+
+// Start starts the streams of the current symbols; they stop when ctx is cancelled.
+func (b *OrderBooks) Start(ctx context.Context) {
+	b.mux.Lock()
+	defer b.mux.Unlock()
+	b.ctx = ctx
+	b.sync()
+}
+
+// SetSymbols starts streams for new symbols and stops those of symbols no longer listed.
+func (b *OrderBooks) SetSymbols(symbols []string) {
+	b.mux.Lock()
+	defer b.mux.Unlock()
+	b.symbols = symbols
+	b.sync()
+}
+
+// sync makes the running streams match the symbols. The caller must hold mux.
+func (b *OrderBooks) sync() {
+	if b.ctx == nil {
+		return
+	}
+
+	wanted := make(map[string]bool, len(b.symbols))
+	for _, symbol := range b.symbols {
+		symbol = strings.ToUpper(strings.TrimSpace(symbol))
+		wanted[symbol] = true
+		if _, ok := b.streams[symbol]; ok {
+			continue
+		}
+
+		ctx, cancel := context.WithCancel(b.ctx)
+		stream := NewOrderBookStream(b.client, b.baseURL, symbol)
+		b.streams[symbol] = &orderBookEntry{stream: stream, cancel: cancel}
+		go stream.Run(ctx)
+	}
+
+	for symbol, entry := range b.streams {
+		if !wanted[symbol] {
+			entry.cancel()
+			delete(b.streams, symbol)
+		}
+	}
+}
+
+// Snapshot returns the order book of symbol, if it is tracked and in sync.
+func (b *OrderBooks) Snapshot(symbol string) (*OrderBookSnapshot, bool) {
+	b.mux.Lock()
+	entry, ok := b.streams[strings.ToUpper(symbol)]
+	b.mux.Unlock()
+	if !ok {
+		return nil, false
+	}
+	return entry.stream.Snapshot()
+}
@@ -0,0 +1,35 @@
+package bitcoin
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOrderBook_Apply(t *testing.T) {
+	book := newOrderBook("BTCUSDT")
+	book.load(&depthResponse{
+		LastUpdateID: 100,
+		Bids:         [][2]string{{"99.00", "1.0"}, {"98.00", "2.0"}},
+		Asks:         [][2]string{{"101.00", "1.0"}},
+	})
+
+	// Already contained in the snapshot
+	assert.True(t, book.apply(&depthEvent{FirstUpdateID: 90, FinalUpdateID: 100, Bids: [][2]string{{"99.00", "0"}}}))
+	assert.Equal(t, 99.0, book.snapshot().BestBid())
+
+	// Straddles the snapshot: applied, a zero quantity removes the level
+	assert.True(t, book.apply(&depthEvent{FirstUpdateID: 95, FinalUpdateID: 105,
+		Bids: [][2]string{{"99.00", "0"}, {"99.50", "3.0"}},
+		Asks: [][2]string{{"100.50", "0.5"}},
+	}))
+	snapshot := book.snapshot()
+	assert.Equal(t, int64(105), snapshot.LastUpdateID)
+	assert.Equal(t, []PriceLevel{{99.5, 3}, {98, 2}}, snapshot.Bids)
+	assert.Equal(t, []PriceLevel{{100.5, 0.5}, {101, 1}}, snapshot.Asks)
+	assert.InDelta(t, 100, snapshot.MidPrice(), 1e-9)
+	assert.InDelta(t, 100, snapshot.SpreadBps(), 1e-9)
+
+	// A gap in update IDs means events were missed
+	assert.False(t, book.apply(&depthEvent{FirstUpdateID: 107, FinalUpdateID: 110}))
+}
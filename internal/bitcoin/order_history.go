@@ -0,0 +1,285 @@
+// Package bitcoin provides functionality for interacting with cryptocurrency APIs.
+package bitcoin
+
+import (
+	"fmt"
+	"log"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+)
+
+// Limits of Binance's allOrders and myTrades endpoints.
+const (
+	maxHistoryWindow = 24 * time.Hour // Longest startTime-endTime span accepted
+	historyPageSize  = 1000           // Most entries returned per request
+)
+
+// OrderQuery filters the order and trade history of a symbol.
+// Zero times and limit leave the filter unset; Binance then returns the most recent
+// 500 entries. With a StartTime the whole range up to EndTime (or now) is fetched,
+// in windows of at most 24 hours since Binance rejects longer ones, and page by page
+// inside each window. FromID pages through trades by ID and replaces the time range.
+//
+// Example usage:
+//
+//	orders, err := client.GetAllOrders(OrderQuery{
+//	    Symbol:    "BTCUSDT",
+//	    StartTime: time.Now().AddDate(0, 0, -7),
+//	})
+type OrderQuery struct {
+	Symbol    string
+	StartTime time.Time
+	EndTime   time.Time
+	Limit     int   // Maximum entries, up to 1000
+	FromID    int64 // GetMyTrades only: return trades with this ID or later
+}
+
+// params returns the Binance request parameters of the query.
+func (q OrderQuery) params() url.Values {
+	params := url.Values{}
+	params.Set("symbol", strings.ToUpper(q.Symbol))
+	if q.FromID > 0 {
+		params.Set("fromId", strconv.FormatInt(q.FromID, 10))
+	} else if !q.StartTime.IsZero() {
+		params.Set("startTime", strconv.FormatInt(q.StartTime.UnixMilli(), 10))
+	}
+	if q.FromID == 0 && !q.EndTime.IsZero() {
+		params.Set("endTime", strconv.FormatInt(q.EndTime.UnixMilli(), 10))
+	}
+	if q.Limit > 0 {
+		params.Set("limit", strconv.Itoa(q.Limit))
+	}
+	return params
+}
+
+// includes reports whether t falls within the query's time range.
+func (q OrderQuery) includes(t time.Time) bool {
+	return (q.StartTime.IsZero() || !t.Before(q.StartTime)) && (q.EndTime.IsZero() || !t.After(q.EndTime))
+}
+
+// pages requests the query's time range page by page. Ranges with a StartTime are split
+// into windows of at most maxHistoryWindow, and a full page continues the window from
+// the time of its last entry, which fetch returns along with the page's size; entries
+// at that time come back again, so callers drop duplicates. Paging stops at EndTime, or
+// once Limit entries were fetched. Queries without a StartTime, or by FromID, are a
+// single request.
+func (q OrderQuery) pages(fetch func(page OrderQuery) (int, time.Time, error)) error {
+	if q.FromID > 0 || q.StartTime.IsZero() {
+		_, _, err := fetch(q)
+		return err
+	}
+
+	end := q.EndTime
+	if end.IsZero() {
+		end = time.Now()
+	}
+	page := q
+	page.Limit = historyPageSize
+	fetched := 0
+	for start := q.StartTime; !start.After(end); {
+		page.StartTime = start
+		page.EndTime = start.Add(maxHistoryWindow - time.Millisecond)
+		if page.EndTime.After(end) {
+			page.EndTime = end
+		}
+
+		count, last, err := fetch(page)
+		if err != nil {
+			return err
+		}
+		fetched += count
+		if q.Limit > 0 && fetched >= q.Limit {
+			return nil
+		}
+
+		switch {
+		case count < page.Limit:
+			start = page.EndTime.Add(time.Millisecond)
+		case last.After(start):
+			start = last
+		default:
+			// A full page within one millisecond, skip past it
+			start = start.Add(time.Millisecond)
+		}
+	}
+	return nil
+}
+
+// Trade is an execution of one of the account's orders.
+type Trade struct {
+	ID              int64     `json:"id"`
+	OrderID         int64     `json:"order_id"`
+	Symbol          string    `json:"symbol"`
+	Price           float64   `json:"price"`
+	Qty             float64   `json:"qty"`
+	QuoteQty        float64   `json:"quote_qty"`
+	Commission      float64   `json:"commission"`
+	CommissionAsset string    `json:"commission_asset"`
+	Time            time.Time `json:"time"`
+	IsBuyer         bool      `json:"is_buyer"`
+	IsMaker         bool      `json:"is_maker"`
+}
+
+// GetOpenOrders fetches the open orders of a symbol, or of every symbol if symbol is empty.
+// Querying all symbols costs 80 request weight instead of 6.
+//
+// Example usage:
+//
+//	orders, err := client.GetOpenOrders("BTCUSDT")
+//	if err != nil {
+//	    return err
+//	}
+//	log.Printf("%d open orders", len(orders))
+func (c *BinanceClient) GetOpenOrders(symbol string) ([]Order, error) {
+	params := url.Values{}
+	if symbol != "" {
+		params.Set("symbol", strings.ToUpper(symbol))
+	}
+
+	var response []orderResponse
+	resp, err := c.signedRequest(resty.MethodGet, "/api/v3/openOrders", params, &response)
+	if err != nil {
+		log.Printf("❌ Error fetching open orders: %v", err)
+		return nil, fmt.Errorf("error fetching open orders: %w", err)
+	}
+
+	if resp.StatusCode() != 200 {
+		binanceErr := NewBinanceError(resp.StatusCode(), resp.String())
+		log.Printf("❌ Binance API error: %v", binanceErr)
+		return nil, binanceErr
+	}
+
+	orders := make([]Order, 0, len(response))
+	for i := range response {
+		orders = append(orders, response[i].toOrder())
+	}
+	return orders, nil
+}
+
+// GetAllOrders fetches the orders of a symbol in any status, oldest first.
+//
+// Example usage:
+//
+//	orders, err := client.GetAllOrders(OrderQuery{Symbol: "BTCUSDT", Limit: 50})
+//	if err != nil {
+//	    return err
+//	}
+//	for _, order := range orders {
+//	    log.Printf("%d %s %s: %s", order.OrderID, order.Side, order.Type, order.Status)
+//	}
+func (c *BinanceClient) GetAllOrders(query OrderQuery) ([]Order, error) {
+	orders := []Order{}
+	seen := make(map[int64]bool)
+	err := query.pages(func(page OrderQuery) (int, time.Time, error) {
+		var response []orderResponse
+		resp, err := c.signedRequest(resty.MethodGet, "/api/v3/allOrders", page.params(), &response)
+		if err != nil {
+			log.Printf("❌ Error fetching order history: %v", err)
+			return 0, time.Time{}, fmt.Errorf("error fetching order history: %w", err)
+		}
+
+		if resp.StatusCode() != 200 {
+			binanceErr := NewBinanceError(resp.StatusCode(), resp.String())
+			log.Printf("❌ Binance API error: %v", binanceErr)
+			return 0, time.Time{}, binanceErr
+		}
+
+		var last time.Time
+		for i := range response {
+			order := response[i].toOrder()
+			last = order.TransactTime
+			if !seen[order.OrderID] && query.includes(order.TransactTime) {
+				seen[order.OrderID] = true
+				orders = append(orders, order)
+			}
+		}
+		return len(response), last, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if query.Limit > 0 && len(orders) > query.Limit {
+		orders = orders[:query.Limit]
+	}
+	return orders, nil
+}
+
+// GetMyTrades fetches the account's trades on a symbol, oldest first.
+//
+// Example usage:
+//
+//	trades, err := client.GetMyTrades(OrderQuery{Symbol: "BTCUSDT"})
+//	if err != nil {
+//	    return err
+//	}
+//	for _, trade := range trades {
+//	    log.Printf("Order %d filled %.8f at $%.2f", trade.OrderID, trade.Qty, trade.Price)
+//	}
+func (c *BinanceClient) GetMyTrades(query OrderQuery) ([]Trade, error) {
+	var response []struct {
+		ID              int64  `json:"id"`
+		OrderID         int64  `json:"orderId"`
+		Symbol          string `json:"symbol"`
+		Price           string `json:"price"`
+		Qty             string `json:"qty"`
+		QuoteQty        string `json:"quoteQty"`
+		Commission      string `json:"commission"`
+		CommissionAsset string `json:"commissionAsset"`
+		Time            int64  `json:"time"`
+		IsBuyer         bool   `json:"isBuyer"`
+		IsMaker         bool   `json:"isMaker"`
+	}
+
+	trades := []Trade{}
+	seen := make(map[int64]bool)
+	err := query.pages(func(page OrderQuery) (int, time.Time, error) {
+		response = nil
+		resp, err := c.signedRequest(resty.MethodGet, "/api/v3/myTrades", page.params(), &response)
+		if err != nil {
+			log.Printf("❌ Error fetching trades: %v", err)
+			return 0, time.Time{}, fmt.Errorf("error fetching trades: %w", err)
+		}
+
+		if resp.StatusCode() != 200 {
+			binanceErr := NewBinanceError(resp.StatusCode(), resp.String())
+			log.Printf("❌ Binance API error: %v", binanceErr)
+			return 0, time.Time{}, binanceErr
+		}
+
+		var last time.Time
+		for _, t := range response {
+			trade := Trade{
+				ID:              t.ID,
+				OrderID:         t.OrderID,
+				Symbol:          t.Symbol,
+				Price:           stringToFloat64(t.Price),
+				Qty:             stringToFloat64(t.Qty),
+				QuoteQty:        stringToFloat64(t.QuoteQty),
+				Commission:      stringToFloat64(t.Commission),
+				CommissionAsset: t.CommissionAsset,
+				Time:            time.UnixMilli(t.Time),
+				IsBuyer:         t.IsBuyer,
+				IsMaker:         t.IsMaker,
+			}
+			last = trade.Time
+			if !seen[trade.ID] && query.includes(trade.Time) {
+				seen[trade.ID] = true
+				trades = append(trades, trade)
+			}
+		}
+		return len(response), last, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if query.Limit > 0 && len(trades) > query.Limit {
+		trades = trades[:query.Limit]
+	}
+	return trades, nil
+}
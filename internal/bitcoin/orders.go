@@ -0,0 +1,645 @@
+// Package bitcoin provides functionality for interacting with cryptocurrency APIs.
+package bitcoin
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+)
+
+// Order sides, types and time in force values accepted by Binance spot.
+const (
+	SideBuy  = "BUY"
+	SideSell = "SELL"
+
+	OrderTypeMarket        = "MARKET"
+	OrderTypeLimit         = "LIMIT"
+	OrderTypeStopLossLimit = "STOP_LOSS_LIMIT"
+	OrderTypeLimitMaker    = "LIMIT_MAKER"
+
+	TimeInForceGTC = "GTC" // Good till cancelled
+	TimeInForceIOC = "IOC" // Immediate or cancel
+	TimeInForceFOK = "FOK" // Fill or kill
+)
+
+// Order statuses reported by Binance.
+const (
+	OrderStatusNew             = "NEW"
+	OrderStatusPartiallyFilled = "PARTIALLY_FILLED"
+	OrderStatusFilled          = "FILLED"
+	OrderStatusCanceled        = "CANCELED"
+	OrderStatusPendingCancel   = "PENDING_CANCEL"
+	OrderStatusRejected        = "REJECTED"
+	OrderStatusExpired         = "EXPIRED"
+)
+
+// OrderStatusDryRun is the status of orders that were only validated because trading is in dry-run mode.
+const OrderStatusDryRun = "DRY_RUN"
+
+// ErrTradingDryRun is returned by operations that cannot be simulated while trading is in dry-run mode.
+var ErrTradingDryRun = errors.New("trading is in dry-run mode")
+
+// tradingLive is false until SetTradingDryRun(false) is called, so no order
+// reaches the exchange unless live trading was explicitly enabled.
+var tradingLive atomic.Bool
+
+// SetTradingDryRun switches every BinanceClient between dry-run and live trading.
+// In dry-run mode orders are checked against the exchange filters and validated with
+// /api/v3/order/test, but never placed.
+//
+// Example usage:
+//
+//	bitcoin.SetTradingDryRun(cfg.TradingDryRun)
+func SetTradingDryRun(dryRun bool) {
+	tradingLive.Store(!dryRun)
+	if dryRun {
+		log.Printf("🧪 Trading in dry-run mode, orders are validated but not placed")
+	} else {
+		log.Printf("⚠️ Live trading enabled, orders will be placed on Binance")
+	}
+}
+
+// IsTradingDryRun reports whether orders are only validated instead of placed.
+func IsTradingDryRun() bool {
+	return !tradingLive.Load()
+}
+
+// OrderRequest describes a spot order to place on Binance.
+// MARKET orders take Quantity or QuoteOrderQty; LIMIT orders take Quantity and Price;
+// STOP_LOSS_LIMIT orders also take StopPrice.
+//
+// Example usage:
+//
+//	order, err := client.PlaceOrder(&OrderRequest{
+//	    Symbol:   "BTCUSDT",
+//	    Side:     SideBuy,
+//	    Type:     OrderTypeLimit,
+//	    Quantity: 0.001,
+//	    Price:    50000,
+//	})
+type OrderRequest struct {
+	Symbol        string  `json:"symbol"`
+	Side          string  `json:"side"`
+	Type          string  `json:"type"`
+	Quantity      float64 `json:"quantity,omitempty"`       // Base asset quantity
+	QuoteOrderQty float64 `json:"quote_quantity,omitempty"` // MARKET only: quote asset amount to spend or receive
+	Price         float64 `json:"price,omitempty"`
+	StopPrice     float64 `json:"stop_price,omitempty"`
+	TimeInForce   string  `json:"time_in_force,omitempty"` // Defaults to GTC for limit orders
+	ClientOrderID string  `json:"client_order_id,omitempty"`
+}
+
+// Validate normalises the request and checks the fields required by its order type.
+func (r *OrderRequest) Validate() error {
+	r.Symbol = strings.ToUpper(strings.TrimSpace(r.Symbol))
+	r.Side = strings.ToUpper(r.Side)
+	r.Type = strings.ToUpper(r.Type)
+	r.TimeInForce = strings.ToUpper(r.TimeInForce)
+
+	if r.Symbol == "" {
+		return &OrderValidationError{Filter: "PARAMETERS", Message: "symbol is required"}
+	}
+	if r.Side != SideBuy && r.Side != SideSell {
+		return &OrderValidationError{Filter: "PARAMETERS", Message: fmt.Sprintf("invalid side %q, must be BUY or SELL", r.Side)}
+	}
+
+	switch r.Type {
+	case OrderTypeMarket:
+		if (r.Quantity > 0) == (r.QuoteOrderQty > 0) {
+			return &OrderValidationError{Filter: "PARAMETERS", Message: "market orders need either quantity or quote_quantity"}
+		}
+		if r.Price != 0 || r.StopPrice != 0 {
+			return &OrderValidationError{Filter: "PARAMETERS", Message: "market orders don't take a price"}
+		}
+		r.TimeInForce = ""
+		return nil
+	case OrderTypeLimit, OrderTypeLimitMaker, OrderTypeStopLossLimit:
+	default:
+		return &OrderValidationError{Filter: "PARAMETERS", Message: fmt.Sprintf("unsupported order type %q", r.Type)}
+	}
+
+	if r.Quantity <= 0 || r.QuoteOrderQty != 0 {
+		return &OrderValidationError{Filter: "PARAMETERS", Message: "limit orders need a positive quantity"}
+	}
+	if r.Price <= 0 {
+		return &OrderValidationError{Filter: "PARAMETERS", Message: "limit orders need a positive price"}
+	}
+	if (r.Type == OrderTypeStopLossLimit) != (r.StopPrice > 0) {
+		return &OrderValidationError{Filter: "PARAMETERS", Message: "stop_price is required for, and only allowed on, STOP_LOSS_LIMIT orders"}
+	}
+
+	if r.Type == OrderTypeLimitMaker {
+		r.TimeInForce = "" // Binance rejects timeInForce on LIMIT_MAKER
+	} else if r.TimeInForce == "" {
+		r.TimeInForce = TimeInForceGTC
+	}
+	return nil
+}
+
+// params returns the Binance request parameters of the order.
+func (r *OrderRequest) params() url.Values {
+	params := url.Values{}
+	params.Set("symbol", r.Symbol)
+	params.Set("side", r.Side)
+	params.Set("type", r.Type)
+	params.Set("newOrderRespType", "FULL")
+	if r.Quantity > 0 {
+		params.Set("quantity", formatDecimal(r.Quantity))
+	}
+	if r.QuoteOrderQty > 0 {
+		params.Set("quoteOrderQty", formatDecimal(r.QuoteOrderQty))
+	}
+	if r.Price > 0 {
+		params.Set("price", formatDecimal(r.Price))
+	}
+	if r.StopPrice > 0 {
+		params.Set("stopPrice", formatDecimal(r.StopPrice))
+	}
+	if r.TimeInForce != "" {
+		params.Set("timeInForce", r.TimeInForce)
+	}
+	if r.ClientOrderID != "" {
+		params.Set("newClientOrderId", r.ClientOrderID)
+	}
+	return params
+}
+
+// OCORequest describes a one-cancels-the-other order: a LIMIT_MAKER leg at Price
+// (take profit) and a STOP_LOSS_LIMIT leg triggered at StopPrice (stop loss).
+// For a SELL, Price must be above StopPrice; for a BUY, below it.
+//
+// Example usage:
+//
+//	list, err := client.PlaceOCOOrder(&OCORequest{
+//	    Symbol:         "BTCUSDT",
+//	    Side:           SideSell,
+//	    Quantity:       0.001,
+//	    Price:          55000,
+//	    StopPrice:      48000,
+//	    StopLimitPrice: 47900,
+//	})
+type OCORequest struct {
+	Symbol         string  `json:"symbol"`
+	Side           string  `json:"side"`
+	Quantity       float64 `json:"quantity"`
+	Price          float64 `json:"price"`                      // Take profit limit price
+	StopPrice      float64 `json:"stop_price"`                 // Stop loss trigger price
+	StopLimitPrice float64 `json:"stop_limit_price,omitempty"` // Stop loss limit price, defaults to StopPrice
+}
+
+// Validate normalises the request and checks that the legs are on the right side of each other.
+func (r *OCORequest) Validate() error {
+	r.Symbol = strings.ToUpper(strings.TrimSpace(r.Symbol))
+	r.Side = strings.ToUpper(r.Side)
+	if r.StopLimitPrice == 0 {
+		r.StopLimitPrice = r.StopPrice
+	}
+	for _, leg := range r.legs() {
+		if err := leg.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if r.Side == SideSell && r.Price <= r.StopPrice {
+		return &OrderValidationError{Filter: "PARAMETERS", Message: "for a SELL OCO the price must be above the stop price"}
+	}
+	if r.Side == SideBuy && r.Price >= r.StopPrice {
+		return &OrderValidationError{Filter: "PARAMETERS", Message: "for a BUY OCO the price must be below the stop price"}
+	}
+	return nil
+}
+
+// legs returns the take profit and stop loss legs as individual orders.
+func (r *OCORequest) legs() []*OrderRequest {
+	return []*OrderRequest{
+		{Symbol: r.Symbol, Side: r.Side, Type: OrderTypeLimitMaker, Quantity: r.Quantity, Price: r.Price},
+		{Symbol: r.Symbol, Side: r.Side, Type: OrderTypeStopLossLimit, Quantity: r.Quantity,
+			Price: r.StopLimitPrice, StopPrice: r.StopPrice, TimeInForce: TimeInForceGTC},
+	}
+}
+
+// params returns the /api/v3/orderList/oco parameters. The leg above the market
+// is the take profit for a SELL and the stop loss for a BUY.
+func (r *OCORequest) params() url.Values {
+	limitLeg, stopLeg := "above", "below"
+	if r.Side == SideBuy {
+		limitLeg, stopLeg = "below", "above"
+	}
+
+	params := url.Values{}
+	params.Set("symbol", r.Symbol)
+	params.Set("side", r.Side)
+	params.Set("quantity", formatDecimal(r.Quantity))
+	params.Set("newOrderRespType", "FULL")
+	params.Set(limitLeg+"Type", OrderTypeLimitMaker)
+	params.Set(limitLeg+"Price", formatDecimal(r.Price))
+	params.Set(stopLeg+"Type", OrderTypeStopLossLimit)
+	params.Set(stopLeg+"Price", formatDecimal(r.StopLimitPrice))
+	params.Set(stopLeg+"StopPrice", formatDecimal(r.StopPrice))
+	params.Set(stopLeg+"TimeInForce", TimeInForceGTC)
+	return params
+}
+
+// Order is an order as reported by Binance, with numeric fields parsed.
+type Order struct {
+	Symbol             string      `json:"symbol"`
+	OrderID            int64       `json:"order_id"`
+	OrderListID        int64       `json:"order_list_id"` // -1 unless the order belongs to an OCO
+	ClientOrderID      string      `json:"client_order_id"`
+	Side               string      `json:"side"`
+	Type               string      `json:"type"`
+	Status             string      `json:"status"`
+	TimeInForce        string      `json:"time_in_force,omitempty"`
+	Price              float64     `json:"price"`
+	StopPrice          float64     `json:"stop_price,omitempty"`
+	OrigQty            float64     `json:"orig_qty"`
+	ExecutedQty        float64     `json:"executed_qty"`
+	CumulativeQuoteQty float64     `json:"cumulative_quote_qty"`
+	AvgPrice           float64     `json:"avg_price"`             // Average fill price, 0 if nothing was filled
+	TransactTime       time.Time   `json:"transact_time"`         // When the order was placed
+	UpdateTime         time.Time   `json:"update_time,omitempty"` // Last status change, only set by order queries
+	Fills              []OrderFill `json:"fills,omitempty"`
+	DryRun             bool        `json:"dry_run"`
+}
+
+// IsOpen reports whether the order can still be (further) filled or cancelled.
+func (o *Order) IsOpen() bool {
+	return o.Status == OrderStatusNew || o.Status == OrderStatusPartiallyFilled
+}
+
+// OrderFill is a partial execution of an order.
+type OrderFill struct {
+	Price           float64 `json:"price"`
+	Qty             float64 `json:"qty"`
+	Commission      float64 `json:"commission"`
+	CommissionAsset string  `json:"commission_asset"`
+}
+
+// OrderList is an OCO order and its two legs.
+type OrderList struct {
+	OrderListID     int64     `json:"order_list_id"`
+	Symbol          string    `json:"symbol"`
+	ListStatus      string    `json:"list_status"`
+	ListOrderStatus string    `json:"list_order_status"`
+	TransactionTime time.Time `json:"transaction_time"`
+	Orders          []Order   `json:"orders"`
+	DryRun          bool      `json:"dry_run"`
+}
+
+// orderResponse is an order in Binance's response format.
+type orderResponse struct {
+	Symbol              string `json:"symbol"`
+	OrderID             int64  `json:"orderId"`
+	OrderListID         int64  `json:"orderListId"`
+	ClientOrderID       string `json:"clientOrderId"`
+	TransactTime        int64  `json:"transactTime"`
+	Time                int64  `json:"time"`       // Order queries report the creation time here
+	UpdateTime          int64  `json:"updateTime"` // Order queries only
+	Price               string `json:"price"`
+	StopPrice           string `json:"stopPrice"`
+	OrigQty             string `json:"origQty"`
+	ExecutedQty         string `json:"executedQty"`
+	CummulativeQuoteQty string `json:"cummulativeQuoteQty"` // sic
+	Status              string `json:"status"`
+	TimeInForce         string `json:"timeInForce"`
+	Type                string `json:"type"`
+	Side                string `json:"side"`
+	Fills               []struct {
+		Price           string `json:"price"`
+		Qty             string `json:"qty"`
+		Commission      string `json:"commission"`
+		CommissionAsset string `json:"commissionAsset"`
+	} `json:"fills"`
+}
+
+// toOrder converts a Binance order response to an Order.
+func (r *orderResponse) toOrder() Order {
+	order := Order{
+		Symbol:             r.Symbol,
+		OrderID:            r.OrderID,
+		OrderListID:        r.OrderListID,
+		ClientOrderID:      r.ClientOrderID,
+		Side:               r.Side,
+		Type:               r.Type,
+		Status:             r.Status,
+		TimeInForce:        r.TimeInForce,
+		Price:              stringToFloat64(r.Price),
+		StopPrice:          stringToFloat64(r.StopPrice),
+		OrigQty:            stringToFloat64(r.OrigQty),
+		ExecutedQty:        stringToFloat64(r.ExecutedQty),
+		CumulativeQuoteQty: stringToFloat64(r.CummulativeQuoteQty),
+		TransactTime:       time.UnixMilli(r.TransactTime),
+	}
+	if r.TransactTime == 0 && r.Time != 0 {
+		order.TransactTime = time.UnixMilli(r.Time)
+	}
+	if r.UpdateTime != 0 {
+		order.UpdateTime = time.UnixMilli(r.UpdateTime)
+	}
+	if order.ExecutedQty > 0 {
+		order.AvgPrice = order.CumulativeQuoteQty / order.ExecutedQty
+	}
+	for _, fill := range r.Fills {
+		order.Fills = append(order.Fills, OrderFill{
+			Price:           stringToFloat64(fill.Price),
+			Qty:             stringToFloat64(fill.Qty),
+			Commission:      stringToFloat64(fill.Commission),
+			CommissionAsset: fill.CommissionAsset,
+		})
+	}
+	return order
+}
+
+// PlaceMarketOrder buys or sells quantity of the base asset at the market price.
+//
+// Example usage:
+//
+//	order, err := client.PlaceMarketOrder("BTCUSDT", SideBuy, 0.001)
+func (c *BinanceClient) PlaceMarketOrder(symbol, side string, quantity float64) (*Order, error) {
+	return c.PlaceOrder(&OrderRequest{Symbol: symbol, Side: side, Type: OrderTypeMarket, Quantity: quantity})
+}
+
+// PlaceLimitOrder places a good-till-cancelled limit order.
+//
+// Example usage:
+//
+//	order, err := client.PlaceLimitOrder("BTCUSDT", SideSell, 0.001, 60000)
+func (c *BinanceClient) PlaceLimitOrder(symbol, side string, quantity, price float64) (*Order, error) {
+	return c.PlaceOrder(&OrderRequest{Symbol: symbol, Side: side, Type: OrderTypeLimit, Quantity: quantity, Price: price})
+}
+
+// PlaceStopLimitOrder places a limit order at price that becomes active once stopPrice is reached.
+//
+// Example usage:
+//
+//	order, err := client.PlaceStopLimitOrder("BTCUSDT", SideSell, 0.001, 47900, 48000)
+func (c *BinanceClient) PlaceStopLimitOrder(symbol, side string, quantity, price, stopPrice float64) (*Order, error) {
+	return c.PlaceOrder(&OrderRequest{
+		Symbol:    symbol,
+		Side:      side,
+		Type:      OrderTypeStopLossLimit,
+		Quantity:  quantity,
+		Price:     price,
+		StopPrice: stopPrice,
+	})
+}
+
+// PlaceOrder checks an order against the symbol filters and places it.
+// In dry-run mode the order is only validated with /api/v3/order/test and
+// returned with status OrderStatusDryRun.
+//
+// Example usage:
+//
+//	order, err := client.PlaceOrder(request)
+//	if err != nil {
+//	    var validationErr *OrderValidationError
+//	    if errors.As(err, &validationErr) {
+//	        log.Printf("Fix the order: %v", validationErr)
+//	    }
+//	    return err
+//	}
+//	log.Printf("Order %d: %s", order.OrderID, order.Status)
+func (c *BinanceClient) PlaceOrder(request *OrderRequest) (*Order, error) {
+	if IsTradingDryRun() {
+		if err := c.TestOrder(request); err != nil {
+			return nil, err
+		}
+		log.Printf("🧪 Dry run: %s %s %s order validated, not placed", request.Side, request.Type, request.Symbol)
+		return &Order{
+			Symbol:       request.Symbol,
+			OrderListID:  -1,
+			Side:         request.Side,
+			Type:         request.Type,
+			Status:       OrderStatusDryRun,
+			TimeInForce:  request.TimeInForce,
+			Price:        request.Price,
+			StopPrice:    request.StopPrice,
+			OrigQty:      request.Quantity,
+			TransactTime: time.Now(),
+			DryRun:       true,
+		}, nil
+	}
+
+	if err := c.checkOrder(request); err != nil {
+		return nil, err
+	}
+
+	log.Printf("📤 Placing %s %s order on %s", request.Side, request.Type, request.Symbol)
+
+	var response orderResponse
+	resp, err := c.signedRequest(resty.MethodPost, "/api/v3/order", request.params(), &response)
+	if err != nil {
+		log.Printf("❌ Error placing order: %v", err)
+		return nil, fmt.Errorf("error placing order: %w", err)
+	}
+
+	if resp.StatusCode() != 200 {
+		binanceErr := NewBinanceError(resp.StatusCode(), resp.String())
+		log.Printf("❌ Binance API error: %v", binanceErr)
+		return nil, binanceErr
+	}
+
+	order := response.toOrder()
+	log.Printf("✅ Order %d placed: %s %s %.8f %s (%s)",
+		order.OrderID, order.Side, order.Type, order.OrigQty, order.Symbol, order.Status)
+	return &order, nil
+}
+
+// TestOrder checks an order against the symbol filters and validates it with
+// /api/v3/order/test, without placing it.
+//
+// Example usage:
+//
+//	if err := client.TestOrder(request); err != nil {
+//	    return err
+//	}
+func (c *BinanceClient) TestOrder(request *OrderRequest) error {
+	if err := c.checkOrder(request); err != nil {
+		return err
+	}
+
+	resp, err := c.signedRequest(resty.MethodPost, "/api/v3/order/test", request.params(), nil)
+	if err != nil {
+		return fmt.Errorf("error testing order: %w", err)
+	}
+
+	if resp.StatusCode() != 200 {
+		binanceErr := NewBinanceError(resp.StatusCode(), resp.String())
+		log.Printf("❌ Binance rejected test order: %v", binanceErr)
+		return binanceErr
+	}
+	return nil
+}
+
+// PlaceOCOOrder places a one-cancels-the-other order after checking both legs
+// against the symbol filters. In dry-run mode each leg is validated with
+// /api/v3/order/test instead.
+//
+// Example usage:
+//
+//	list, err := client.PlaceOCOOrder(request)
+//	if err != nil {
+//	    return err
+//	}
+//	log.Printf("OCO %d placed with %d orders", list.OrderListID, len(list.Orders))
+func (c *BinanceClient) PlaceOCOOrder(request *OCORequest) (*OrderList, error) {
+	if err := request.Validate(); err != nil {
+		return nil, err
+	}
+
+	filters, err := c.GetSymbolFilters(request.Symbol)
+	if err != nil {
+		return nil, err
+	}
+	legs := request.legs()
+	for _, leg := range legs {
+		if err := leg.Validate(); err != nil {
+			return nil, err
+		}
+		if err := filters.Check(leg, 0); err != nil {
+			return nil, err
+		}
+	}
+
+	if IsTradingDryRun() {
+		list := &OrderList{
+			OrderListID:     -1,
+			Symbol:          request.Symbol,
+			ListStatus:      OrderStatusDryRun,
+			ListOrderStatus: OrderStatusDryRun,
+			TransactionTime: time.Now(),
+			DryRun:          true,
+		}
+		for _, leg := range legs {
+			resp, err := c.signedRequest(resty.MethodPost, "/api/v3/order/test", leg.params(), nil)
+			if err != nil {
+				return nil, fmt.Errorf("error testing OCO order: %w", err)
+			}
+			if resp.StatusCode() != 200 {
+				return nil, NewBinanceError(resp.StatusCode(), resp.String())
+			}
+			list.Orders = append(list.Orders, Order{
+				Symbol:      leg.Symbol,
+				OrderListID: -1,
+				Side:        leg.Side,
+				Type:        leg.Type,
+				Status:      OrderStatusDryRun,
+				TimeInForce: leg.TimeInForce,
+				Price:       leg.Price,
+				StopPrice:   leg.StopPrice,
+				OrigQty:     leg.Quantity,
+				DryRun:      true,
+			})
+		}
+		log.Printf("🧪 Dry run: %s OCO order on %s validated, not placed", request.Side, request.Symbol)
+		return list, nil
+	}
+
+	log.Printf("📤 Placing %s OCO order on %s", request.Side, request.Symbol)
+
+	var response struct {
+		OrderListID     int64           `json:"orderListId"`
+		Symbol          string          `json:"symbol"`
+		ListStatusType  string          `json:"listStatusType"`
+		ListOrderStatus string          `json:"listOrderStatus"`
+		TransactionTime int64           `json:"transactionTime"`
+		OrderReports    []orderResponse `json:"orderReports"`
+	}
+	resp, err := c.signedRequest(resty.MethodPost, "/api/v3/orderList/oco", request.params(), &response)
+	if err != nil {
+		log.Printf("❌ Error placing OCO order: %v", err)
+		return nil, fmt.Errorf("error placing OCO order: %w", err)
+	}
+
+	if resp.StatusCode() != 200 {
+		binanceErr := NewBinanceError(resp.StatusCode(), resp.String())
+		log.Printf("❌ Binance API error: %v", binanceErr)
+		return nil, binanceErr
+	}
+
+	list := &OrderList{
+		OrderListID:     response.OrderListID,
+		Symbol:          response.Symbol,
+		ListStatus:      response.ListStatusType,
+		ListOrderStatus: response.ListOrderStatus,
+		TransactionTime: time.UnixMilli(response.TransactionTime),
+	}
+	for i := range response.OrderReports {
+		list.Orders = append(list.Orders, response.OrderReports[i].toOrder())
+	}
+	log.Printf("✅ OCO order list %d placed on %s", list.OrderListID, list.Symbol)
+	return list, nil
+}
+
+// CancelOrder cancels an open order. It returns ErrTradingDryRun in dry-run mode.
+//
+// Example usage:
+//
+//	order, err := client.CancelOrder("BTCUSDT", 12345)
+//	if err != nil {
+//	    return err
+//	}
+//	log.Printf("Order %d is now %s", order.OrderID, order.Status)
+func (c *BinanceClient) CancelOrder(symbol string, orderID int64) (*Order, error) {
+	if IsTradingDryRun() {
+		return nil, ErrTradingDryRun
+	}
+
+	params := url.Values{}
+	params.Set("symbol", strings.ToUpper(symbol))
+	params.Set("orderId", strconv.FormatInt(orderID, 10))
+
+	log.Printf("🗑️ Cancelling order %d on %s", orderID, symbol)
+
+	var response orderResponse
+	resp, err := c.signedRequest(resty.MethodDelete, "/api/v3/order", params, &response)
+	if err != nil {
+		log.Printf("❌ Error cancelling order: %v", err)
+		return nil, fmt.Errorf("error cancelling order: %w", err)
+	}
+
+	if resp.StatusCode() != 200 {
+		binanceErr := NewBinanceError(resp.StatusCode(), resp.String())
+		log.Printf("❌ Binance API error: %v", binanceErr)
+		return nil, binanceErr
+	}
+
+	order := response.toOrder()
+	log.Printf("✅ Order %d cancelled (%s)", order.OrderID, order.Status)
+	return &order, nil
+}
+
+// checkOrder validates an order and checks it against the symbol filters.
+// Market orders in base quantity are valued at the last price when the
+// notional filter applies to them.
+func (c *BinanceClient) checkOrder(request *OrderRequest) error {
+	if err := request.Validate(); err != nil {
+		return err
+	}
+
+	filters, err := c.GetSymbolFilters(request.Symbol)
+	if err != nil {
+		return err
+	}
+
+	var marketPrice float64
+	if request.Type == OrderTypeMarket && request.Quantity > 0 && filters.ApplyMinToMarket && filters.MinNotional > 0 {
+		if marketPrice, _, err = c.getAssetTicker(request.Symbol); err != nil {
+			return err
+		}
+	}
+
+	return filters.Check(request, marketPrice)
+}
+
+// formatDecimal formats a quantity or price without exponent or trailing zeros, as Binance expects.
+func formatDecimal(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
@@ -0,0 +1,168 @@
+package bitcoin
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSymbolFilters_Check(t *testing.T) {
+	filters := &SymbolFilters{
+		Symbol:      "BTCUSDT",
+		Status:      "TRADING",
+		QuoteAsset:  "USDT",
+		MinPrice:    0.01,
+		MaxPrice:    1000000,
+		TickSize:    0.01,
+		MinQty:      0.00001,
+		MaxQty:      9000,
+		StepSize:    0.00001,
+		MinNotional: 5,
+	}
+
+	tests := []struct {
+		name       string
+		order      OrderRequest
+		wantFilter string
+	}{
+		{"valid limit order", OrderRequest{Type: OrderTypeLimit, Quantity: 0.001, Price: 50000.01}, ""},
+		{"quantity off step", OrderRequest{Type: OrderTypeLimit, Quantity: 0.000015, Price: 50000}, "LOT_SIZE"},
+		{"quantity below minimum", OrderRequest{Type: OrderTypeLimit, Quantity: 0.000001, Price: 50000}, "LOT_SIZE"},
+		{"price off tick", OrderRequest{Type: OrderTypeLimit, Quantity: 0.001, Price: 50000.005}, "PRICE_FILTER"},
+		{"stop price off tick", OrderRequest{Type: OrderTypeStopLossLimit, Quantity: 0.001, Price: 48000, StopPrice: 48000.001}, "PRICE_FILTER"},
+		{"notional too small", OrderRequest{Type: OrderTypeLimit, Quantity: 0.00001, Price: 50000}, "MIN_NOTIONAL"},
+		{"market order notional not applied", OrderRequest{Type: OrderTypeMarket, Quantity: 0.00001}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := filters.Check(&tt.order, 50000)
+			if tt.wantFilter == "" {
+				assert.NoError(t, err)
+				return
+			}
+			var validationErr *OrderValidationError
+			require.ErrorAs(t, err, &validationErr)
+			assert.Equal(t, tt.wantFilter, validationErr.Filter)
+		})
+	}
+}
+
+func TestPlaceOrder_DryRunOnlyValidates(t *testing.T) {
+	requests := make(map[string]int)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests[r.Method+" "+r.URL.Path]++
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.URL.Path {
+		case "/api/v3/time":
+			fmt.Fprintf(w, `{"serverTime":%d}`, time.Now().UnixMilli())
+		case "/api/v3/exchangeInfo":
+			w.Write([]byte(`{"symbols":[{"symbol":"BTCUSDT","status":"TRADING","baseAsset":"BTC","quoteAsset":"USDT","filters":[
+				{"filterType":"PRICE_FILTER","minPrice":"0.01","maxPrice":"1000000.00","tickSize":"0.01"},
+				{"filterType":"LOT_SIZE","minQty":"0.00001","maxQty":"9000.00","stepSize":"0.00001"},
+				{"filterType":"NOTIONAL","minNotional":"5.00","applyMinToMarket":true}]}]}`))
+		case "/api/v3/order/test":
+			w.Write([]byte(`{}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	SetTradingDryRun(true)
+	client := NewBinanceClient("key", "secret", server.URL, nil)
+
+	order, err := client.PlaceLimitOrder("btcusdt", SideBuy, 0.001, 50000)
+	require.NoError(t, err)
+	assert.True(t, order.DryRun)
+	assert.Equal(t, OrderStatusDryRun, order.Status)
+	assert.Equal(t, TimeInForceGTC, order.TimeInForce)
+	assert.Equal(t, 1, requests["POST /api/v3/order/test"])
+	assert.Zero(t, requests["POST /api/v3/order"])
+
+	// Filter violations never reach Binance
+	_, err = client.PlaceLimitOrder("BTCUSDT", SideBuy, 0.0000123, 50000)
+	var validationErr *OrderValidationError
+	require.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, "LOT_SIZE", validationErr.Filter)
+	assert.Equal(t, 1, requests["POST /api/v3/order/test"])
+
+	_, err = client.CancelOrder("BTCUSDT", 1)
+	assert.ErrorIs(t, err, ErrTradingDryRun)
+}
+
+func TestOrderQuery_Params(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	// Ranges within 24 hours are sent as is
+	params := OrderQuery{Symbol: "btcusdt", StartTime: start, EndTime: start.Add(12 * time.Hour), Limit: 50}.params()
+	assert.Equal(t, "BTCUSDT", params.Get("symbol"))
+	assert.Equal(t, "1704067200000", params.Get("startTime"))
+	assert.Equal(t, "1704110400000", params.Get("endTime"))
+	assert.Equal(t, "50", params.Get("limit"))
+
+	query := OrderQuery{Symbol: "BTCUSDT", StartTime: start, EndTime: start.AddDate(0, 0, 7)}
+	assert.True(t, query.includes(start.AddDate(0, 0, 3)))
+	assert.False(t, query.includes(start.AddDate(0, 0, 8)))
+}
+
+func TestGetAllOrders_PagesThroughLongRanges(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 0, 3)
+
+	// One order a minute, more than a page per day
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/v3/time":
+			fmt.Fprintf(w, `{"serverTime":%d}`, time.Now().UnixMilli())
+		case "/api/v3/allOrders":
+			requests++
+			from, _ := strconv.ParseInt(r.URL.Query().Get("startTime"), 10, 64)
+			to, _ := strconv.ParseInt(r.URL.Query().Get("endTime"), 10, 64)
+			limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+			if to-from >= maxHistoryWindow.Milliseconds() {
+				w.WriteHeader(http.StatusBadRequest)
+				w.Write([]byte(`{"code":-1127,"msg":"More than 24 hours between startTime and endTime."}`))
+				return
+			}
+
+			orders := []string{}
+			for at := start; !at.After(end) && len(orders) < limit; at = at.Add(time.Minute) {
+				if ms := at.UnixMilli(); ms >= from && ms <= to {
+					orders = append(orders, fmt.Sprintf(`{"symbol":"BTCUSDT","orderId":%d,"status":"FILLED","time":%d}`,
+						at.Sub(start)/time.Minute+1, ms))
+				}
+			}
+			w.Write([]byte("[" + strings.Join(orders, ",") + "]"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := NewBinanceClient("key", "secret", server.URL, nil)
+	orders, err := client.GetAllOrders(OrderQuery{Symbol: "BTCUSDT", StartTime: start, EndTime: end})
+	require.NoError(t, err)
+
+	require.Len(t, orders, 3*24*60+1)
+	for i, order := range orders {
+		assert.Equal(t, int64(i+1), order.OrderID)
+	}
+	assert.Greater(t, requests, 3)
+
+	// A limit stops paging early
+	orders, err = client.GetAllOrders(OrderQuery{Symbol: "BTCUSDT", StartTime: start, EndTime: end, Limit: 50})
+	require.NoError(t, err)
+	assert.Len(t, orders, 50)
+}
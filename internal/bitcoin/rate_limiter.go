@@ -0,0 +1,364 @@
+// Package bitcoin provides functionality for interacting with cryptocurrency APIs.
+package bitcoin
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+)
+
+// DefaultBinanceWeightLimit is Binance's REQUEST_WEIGHT limit per minute for /api/v3 endpoints.
+const DefaultBinanceWeightLimit = 6000
+
+// Default limiter behaviour: requests are shed once this share of the budget is used,
+// unless the window resets within maxWait, in which case they block until it does.
+const (
+	defaultWeightReserveRatio = 0.9
+	defaultWeightMaxWait      = 5 * time.Second
+)
+
+// bannedUntilPattern extracts the ban expiry Binance includes in 418 responses,
+// e.g. "Way too much request weight used; IP banned until 1700000000000."
+var bannedUntilPattern = regexp.MustCompile(`banned until (\d+)`)
+
+var (
+	weightLimiters    = make(map[string]*WeightLimiter)
+	weightLimitersMux sync.Mutex
+)
+
+// WeightLimiter keeps Binance requests within the per-minute request weight budget.
+// Binance limits are per IP, so a single limiter is shared by every BinanceClient
+// that talks to the same base URL (see WeightLimiterFor).
+//
+// Each request reserves its endpoint weight before it is sent. The budget is corrected
+// from the X-MBX-USED-WEIGHT-1m header of every response, and 429/418 responses block
+// all requests until the Retry-After or ban-until time has passed.
+//
+// Example usage:
+//
+//	limiter := WeightLimiterFor("https://api.binance.com")
+//	stats := limiter.Stats()
+//	log.Printf("Used %d/%d weight", stats.Used, stats.Limit)
+type WeightLimiter struct {
+	limit        int
+	reserveRatio float64
+	maxWait      time.Duration
+
+	windowStart time.Time
+	used        int
+	bannedUntil time.Time
+	banReason   string
+
+	// Counters for /api/v1/stats
+	blocked int64
+	shed    int64
+
+	mux sync.Mutex
+}
+
+// WeightLimiterStats is a snapshot of a WeightLimiter's state.
+type WeightLimiterStats struct {
+	Limit          int       `json:"limit"`
+	Used           int       `json:"used"`
+	Remaining      int       `json:"remaining"`
+	WindowResetsAt time.Time `json:"window_resets_at"`
+	Banned         bool      `json:"banned"`
+	BannedUntil    time.Time `json:"banned_until,omitempty"`
+	BanReason      string    `json:"ban_reason,omitempty"`
+	Blocked        int64     `json:"blocked_requests"`
+	Shed           int64     `json:"shed_requests"`
+}
+
+// RateLimitError is returned when a request is refused locally to protect the weight budget,
+// or because Binance told us to back off.
+type RateLimitError struct {
+	Until  time.Time // When requests may resume
+	Reason string
+}
+
+// Error implements the error interface.
+func (e *RateLimitError) Error() string {
+	return fmt.Sprintf("Binance rate limit: %s (retry after %s)", e.Reason, e.Until.Format(time.RFC3339))
+}
+
+// NewWeightLimiter creates a limiter with the given per-minute weight budget.
+// If limit is <= 0, DefaultBinanceWeightLimit is used.
+//
+// Example usage:
+//
+//	limiter := NewWeightLimiter(1200)
+func NewWeightLimiter(limit int) *WeightLimiter {
+	if limit <= 0 {
+		limit = DefaultBinanceWeightLimit
+	}
+	return &WeightLimiter{
+		limit:        limit,
+		reserveRatio: defaultWeightReserveRatio,
+		maxWait:      defaultWeightMaxWait,
+	}
+}
+
+// WeightLimiterFor returns the shared limiter for a Binance base URL, creating it if needed.
+// An empty baseURL refers to the production API.
+//
+// Example usage:
+//
+//	stats := WeightLimiterFor(cfg.BinanceBaseURL).Stats()
+func WeightLimiterFor(baseURL string) *WeightLimiter {
+	if baseURL == "" {
+		baseURL = DefaultBinanceBaseURL
+	}
+	baseURL = strings.TrimRight(baseURL, "/")
+
+	weightLimitersMux.Lock()
+	defer weightLimitersMux.Unlock()
+
+	limiter, ok := weightLimiters[baseURL]
+	if !ok {
+		limiter = NewWeightLimiter(DefaultBinanceWeightLimit)
+		weightLimiters[baseURL] = limiter
+	}
+	return limiter
+}
+
+// SetLimit changes the per-minute weight budget.
+//
+// Example usage:
+//
+//	WeightLimiterFor(baseURL).SetLimit(1200)
+func (l *WeightLimiter) SetLimit(limit int) {
+	if limit <= 0 {
+		return
+	}
+	l.mux.Lock()
+	defer l.mux.Unlock()
+	l.limit = limit
+}
+
+// Acquire reserves weight for a request.
+// It blocks when the budget will be available again within the max wait, and
+// returns a *RateLimitError when the request must be shed instead.
+//
+// Example usage:
+//
+//	if err := limiter.Acquire(20); err != nil {
+//	    return err
+//	}
+func (l *WeightLimiter) Acquire(weight int) error {
+	counted := false
+
+	for {
+		l.mux.Lock()
+		now := time.Now()
+		l.rollWindow(now)
+
+		if now.Before(l.bannedUntil) {
+			l.shed++
+			err := &RateLimitError{Until: l.bannedUntil, Reason: l.banReason}
+			l.mux.Unlock()
+			return err
+		}
+
+		budget := int(float64(l.limit) * l.reserveRatio)
+		if l.used+weight <= budget {
+			l.used += weight
+			l.mux.Unlock()
+			return nil
+		}
+
+		windowEnd := l.windowStart.Add(time.Minute)
+		wait := windowEnd.Sub(now)
+		if wait > l.maxWait {
+			l.shed++
+			l.mux.Unlock()
+			return &RateLimitError{
+				Until:  windowEnd,
+				Reason: fmt.Sprintf("request weight budget exhausted (%d/%d used)", l.used, l.limit),
+			}
+		}
+
+		if !counted {
+			l.blocked++
+			counted = true
+		}
+		l.mux.Unlock()
+
+		log.Printf("⏳ Binance weight budget nearly exhausted, waiting %v", wait)
+		time.Sleep(wait)
+	}
+}
+
+// Record updates the limiter from a Binance response.
+// The used weight header is authoritative; 429 and 418 responses start a back-off
+// lasting until Retry-After or the ban-until time in the body.
+func (l *WeightLimiter) Record(status int, header http.Header, body string) {
+	l.mux.Lock()
+	defer l.mux.Unlock()
+
+	now := time.Now()
+	l.rollWindow(now)
+
+	if used, err := strconv.Atoi(header.Get("X-MBX-USED-WEIGHT-1m")); err == nil {
+		l.used = used
+	}
+
+	if status != http.StatusTooManyRequests && status != http.StatusTeapot {
+		return
+	}
+
+	until := now.Add(time.Minute) // Binance always sends Retry-After, this is a safety net
+	if seconds, err := strconv.Atoi(header.Get("Retry-After")); err == nil {
+		until = now.Add(time.Duration(seconds) * time.Second)
+	}
+	if match := bannedUntilPattern.FindStringSubmatch(body); match != nil {
+		if ms, err := strconv.ParseInt(match[1], 10, 64); err == nil {
+			until = time.UnixMilli(ms)
+		}
+	}
+
+	reason := "too many requests (429)"
+	if status == http.StatusTeapot {
+		reason = "IP banned (418)"
+	}
+
+	if until.After(l.bannedUntil) {
+		l.bannedUntil = until
+		l.banReason = reason
+	}
+	log.Printf("🚫 Binance %s, backing off until %s", reason, until.Format(time.RFC3339))
+}
+
+// Stats returns a snapshot of the limiter state.
+//
+// Example usage:
+//
+//	stats := limiter.Stats()
+//	if stats.Banned {
+//	    log.Printf("Banned until %s", stats.BannedUntil)
+//	}
+func (l *WeightLimiter) Stats() WeightLimiterStats {
+	l.mux.Lock()
+	defer l.mux.Unlock()
+
+	now := time.Now()
+	l.rollWindow(now)
+
+	stats := WeightLimiterStats{
+		Limit:          l.limit,
+		Used:           l.used,
+		Remaining:      l.limit - l.used,
+		WindowResetsAt: l.windowStart.Add(time.Minute),
+		Blocked:        l.blocked,
+		Shed:           l.shed,
+	}
+	if stats.Remaining < 0 {
+		stats.Remaining = 0
+	}
+	if now.Before(l.bannedUntil) {
+		stats.Banned = true
+		stats.BannedUntil = l.bannedUntil
+		stats.BanReason = l.banReason
+	}
+	return stats
+}
+
+// rollWindow resets the used weight when a new minute starts. Must be called with mux held.
+func (l *WeightLimiter) rollWindow(now time.Time) {
+	windowStart := now.Truncate(time.Minute)
+	if windowStart.After(l.windowStart) {
+		l.windowStart = windowStart
+		l.used = 0
+	}
+}
+
+// attach makes every request of a resty client pass through the limiter.
+func (l *WeightLimiter) attach(client *resty.Client) {
+	client.OnBeforeRequest(func(_ *resty.Client, req *resty.Request) error {
+		return l.Acquire(requestWeight(req))
+	})
+	client.OnAfterResponse(func(_ *resty.Client, resp *resty.Response) error {
+		l.Record(resp.StatusCode(), resp.Header(), resp.String())
+		return nil
+	})
+}
+
+// requestWeight returns the Binance request weight of a resty request.
+func requestWeight(req *resty.Request) int {
+	path := req.URL
+	params := url.Values{}
+	if parsed, err := url.Parse(req.URL); err == nil {
+		path = parsed.Path
+		params = parsed.Query()
+	}
+	for key, values := range req.QueryParam {
+		params[key] = values
+	}
+	for key, value := range req.FormData {
+		params[key] = value
+	}
+	return EndpointWeight(req.Method, path, params)
+}
+
+// EndpointWeight returns the request weight Binance charges for an endpoint.
+// Weights follow the Binance spot API documentation; unknown endpoints count as 1.
+//
+// Example usage:
+//
+//	weight := EndpointWeight(http.MethodGet, "/api/v3/ticker/24hr", url.Values{"symbol": {"BTCUSDT"}}) // 2
+func EndpointWeight(method, path string, params url.Values) int {
+	switch path {
+	case "/api/v3/ticker/24hr":
+		if params.Get("symbol") != "" {
+			return 2
+		}
+		if symbols := params.Get("symbols"); symbols != "" {
+			switch count := strings.Count(symbols, ",") + 1; {
+			case count <= 20:
+				return 2
+			case count <= 100:
+				return 40
+			}
+		}
+		return 80
+	case "/api/v3/ticker/price":
+		if params.Get("symbol") != "" {
+			return 2
+		}
+		return 4
+	case "/api/v3/depth":
+		limit, _ := strconv.Atoi(params.Get("limit"))
+		switch {
+		case limit <= 100:
+			return 5
+		case limit <= 500:
+			return 25
+		case limit <= 1000:
+			return 50
+		}
+		return 250
+	case "/api/v3/openOrders":
+		if params.Get("symbol") != "" {
+			return 6
+		}
+		return 80
+	case "/api/v3/order":
+		if method == http.MethodGet {
+			return 4
+		}
+		return 1
+	case "/api/v3/account", "/api/v3/allOrders", "/api/v3/myTrades", "/api/v3/exchangeInfo":
+		return 20
+	case "/api/v3/klines", "/api/v3/userDataStream":
+		return 2
+	default:
+		return 1
+	}
+}
@@ -0,0 +1,92 @@
+package bitcoin
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEndpointWeight(t *testing.T) {
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		params url.Values
+		want   int
+	}{
+		{"24hr ticker for one symbol", http.MethodGet, "/api/v3/ticker/24hr", url.Values{"symbol": {"BTCUSDT"}}, 2},
+		{"24hr ticker for all symbols", http.MethodGet, "/api/v3/ticker/24hr", url.Values{}, 80},
+		{"24hr ticker for a symbol list", http.MethodGet, "/api/v3/ticker/24hr", url.Values{"symbols": {`["BTCUSDT","ETHUSDT"]`}}, 2},
+		{"account", http.MethodGet, "/api/v3/account", url.Values{}, 20},
+		{"depth with large limit", http.MethodGet, "/api/v3/depth", url.Values{"limit": {"5000"}}, 250},
+		{"query order", http.MethodGet, "/api/v3/order", url.Values{}, 4},
+		{"place order", http.MethodPost, "/api/v3/order", url.Values{}, 1},
+		{"unknown endpoint", http.MethodGet, "/api/v3/ping", url.Values{}, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, EndpointWeight(tt.method, tt.path, tt.params))
+		})
+	}
+}
+
+func TestWeightLimiter_HonoursBan(t *testing.T) {
+	var requests int32
+	bannedUntil := time.Now().Add(time.Hour)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.Header().Set("Retry-After", "60")
+		w.WriteHeader(http.StatusTeapot)
+		fmt.Fprintf(w, `{"code":-1003,"msg":"Way too much request weight used; IP banned until %d."}`, bannedUntil.UnixMilli())
+	}))
+	defer server.Close()
+
+	client := NewBinanceClient("", "", server.URL, nil)
+
+	_, err := client.GetSymbolPrice("BTCUSDT")
+	require.Error(t, err)
+	assert.True(t, IsRateLimitError(err))
+
+	// The second request is refused locally without reaching Binance
+	_, err = client.GetSymbolPrice("BTCUSDT")
+	var limitErr *RateLimitError
+	require.ErrorAs(t, err, &limitErr)
+	assert.WithinDuration(t, bannedUntil, limitErr.Until, time.Second)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&requests))
+
+	stats := WeightLimiterFor(server.URL).Stats()
+	assert.True(t, stats.Banned)
+	assert.Equal(t, int64(1), stats.Shed)
+}
+
+func TestWeightLimiter_ShedsWhenBudgetUsed(t *testing.T) {
+	var requests int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-MBX-USED-WEIGHT-1m", "5990")
+		w.Write([]byte(`{"symbol":"BTCUSDT","lastPrice":"50000.00","priceChangePercent":"1.00"}`))
+	}))
+	defer server.Close()
+
+	client := NewBinanceClient("", "", server.URL, nil)
+	WeightLimiterFor(server.URL).maxWait = 0 // Shed instead of waiting for the next window
+
+	_, err := client.GetSymbolPrice("BTCUSDT")
+	require.NoError(t, err)
+	assert.Equal(t, 5990, WeightLimiterFor(server.URL).Stats().Used)
+
+	_, err = client.GetSymbolPrice("BTCUSDT")
+	assert.True(t, IsRateLimitError(err))
+	assert.Equal(t, int32(1), atomic.LoadInt32(&requests))
+}
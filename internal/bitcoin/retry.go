@@ -0,0 +1,117 @@
+// Package bitcoin provides functionality for interacting with cryptocurrency APIs.
+package bitcoin
+
+import (
+	"errors"
+	"math/rand"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+)
+
+// RetryPolicy configures how idempotent GET requests to Binance are retried
+// after network errors and 5xx responses. Waits grow exponentially from BaseDelay
+// up to MaxDelay, with random jitter so clients don't retry in lockstep.
+//
+// Example usage:
+//
+//	SetRetryPolicy(RetryPolicy{MaxRetries: 3, BaseDelay: 200 * time.Millisecond, MaxDelay: 2 * time.Second})
+type RetryPolicy struct {
+	MaxRetries int           // Retries after the first attempt; 0 disables retries
+	BaseDelay  time.Duration // Wait before the first retry
+	MaxDelay   time.Duration // Upper bound for any single wait
+}
+
+// DefaultRetryPolicy is used by new clients until SetRetryPolicy is called.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxRetries: 2,
+	BaseDelay:  200 * time.Millisecond,
+	MaxDelay:   2 * time.Second,
+}
+
+var (
+	retryPolicy    = DefaultRetryPolicy
+	retryPolicyMux sync.RWMutex
+)
+
+// SetRetryPolicy sets the retry policy applied to Binance clients created afterwards.
+//
+// Example usage:
+//
+//	bitcoin.SetRetryPolicy(bitcoin.RetryPolicy{MaxRetries: 0}) // Disable retries
+func SetRetryPolicy(policy RetryPolicy) {
+	if policy.MaxRetries < 0 {
+		policy.MaxRetries = 0
+	}
+	if policy.BaseDelay <= 0 {
+		policy.BaseDelay = DefaultRetryPolicy.BaseDelay
+	}
+	if policy.MaxDelay < policy.BaseDelay {
+		policy.MaxDelay = policy.BaseDelay
+	}
+
+	retryPolicyMux.Lock()
+	defer retryPolicyMux.Unlock()
+	retryPolicy = policy
+}
+
+// currentRetryPolicy returns the policy for new clients.
+func currentRetryPolicy() RetryPolicy {
+	retryPolicyMux.RLock()
+	defer retryPolicyMux.RUnlock()
+	return retryPolicy
+}
+
+// Backoff returns how long to wait before the given retry (1 for the first retry).
+// The wait is a random duration between half and all of BaseDelay*2^(retry-1),
+// capped at MaxDelay.
+//
+// Example usage:
+//
+//	time.Sleep(policy.Backoff(1))
+func (p RetryPolicy) Backoff(retry int) time.Duration {
+	if retry < 1 {
+		retry = 1
+	}
+
+	delay := p.BaseDelay
+	for i := 1; i < retry && delay < p.MaxDelay; i++ {
+		delay *= 2
+	}
+	if delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+
+	half := delay / 2
+	if half <= 0 {
+		return delay
+	}
+	return half + time.Duration(rand.Int63n(int64(half)+1))
+}
+
+// attach configures a resty client to retry according to the policy.
+// Only GET requests are retried, and requests refused by the weight limiter
+// or the circuit breaker are never retried.
+func (p RetryPolicy) attach(client *resty.Client) {
+	if p.MaxRetries <= 0 {
+		return
+	}
+
+	client.SetRetryCount(p.MaxRetries)
+	client.SetRetryWaitTime(p.BaseDelay)
+	client.SetRetryMaxWaitTime(p.MaxDelay)
+	client.SetRetryAfter(func(_ *resty.Client, resp *resty.Response) (time.Duration, error) {
+		return p.Backoff(resp.Request.Attempt), nil
+	})
+	client.AddRetryCondition(func(resp *resty.Response, err error) bool {
+		if resp == nil || resp.Request == nil || resp.Request.Method != http.MethodGet {
+			return false
+		}
+		if err != nil {
+			return !IsRateLimitError(err) && !errors.Is(err, ErrCircuitOpen)
+		}
+		return resp.StatusCode() >= http.StatusInternalServerError
+	})
+}
@@ -0,0 +1,168 @@
+// Package bitcoin provides functionality for interacting with cryptocurrency APIs.
+package bitcoin
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+)
+
+// Default settings for signed Binance requests.
+const (
+	DefaultRecvWindow       = 5 * time.Second  // Binance's own default
+	MaxRecvWindow           = 60 * time.Second // Largest recvWindow Binance accepts
+	DefaultTimeSyncInterval = 10 * time.Minute
+)
+
+var (
+	serverClocks    = make(map[string]*ServerClock)
+	serverClocksMux sync.Mutex
+)
+
+// ServerClock tracks the offset between the local clock and Binance's server time,
+// so signed requests carry a timestamp Binance accepts even if the host clock drifts.
+// The offset is measured against /api/v3/time when it is older than the sync interval,
+// and again whenever Binance rejects a request with ErrInvalidTimestamp (-1021).
+// Like WeightLimiter, one clock is shared by every client of the same base URL.
+//
+// Example usage:
+//
+//	clock := ServerClockFor("https://api.binance.com")
+//	log.Printf("Local clock is %v behind Binance", clock.Offset())
+type ServerClock struct {
+	recvWindow   time.Duration
+	syncInterval time.Duration
+
+	offset   time.Duration // Server time minus local time
+	lastSync time.Time
+
+	mux sync.Mutex
+}
+
+// NewServerClock creates a clock with no offset that syncs on first use.
+// Non-positive values fall back to DefaultRecvWindow and DefaultTimeSyncInterval.
+//
+// Example usage:
+//
+//	clock := NewServerClock(10*time.Second, 5*time.Minute)
+func NewServerClock(recvWindow, syncInterval time.Duration) *ServerClock {
+	clock := &ServerClock{
+		recvWindow:   DefaultRecvWindow,
+		syncInterval: DefaultTimeSyncInterval,
+	}
+	clock.Configure(recvWindow, syncInterval)
+	return clock
+}
+
+// ServerClockFor returns the shared clock for a Binance base URL, creating it if needed.
+// An empty baseURL refers to the production API.
+//
+// Example usage:
+//
+//	offset := ServerClockFor(cfg.BinanceBaseURL).Offset()
+func ServerClockFor(baseURL string) *ServerClock {
+	if baseURL == "" {
+		baseURL = DefaultBinanceBaseURL
+	}
+	baseURL = strings.TrimRight(baseURL, "/")
+
+	serverClocksMux.Lock()
+	defer serverClocksMux.Unlock()
+
+	clock, ok := serverClocks[baseURL]
+	if !ok {
+		clock = NewServerClock(DefaultRecvWindow, DefaultTimeSyncInterval)
+		serverClocks[baseURL] = clock
+	}
+	return clock
+}
+
+// Configure changes the recvWindow sent with signed requests and how often the
+// offset is measured. Non-positive values are ignored and the recvWindow is capped
+// at MaxRecvWindow.
+//
+// Example usage:
+//
+//	ServerClockFor(baseURL).Configure(10*time.Second, 5*time.Minute)
+func (c *ServerClock) Configure(recvWindow, syncInterval time.Duration) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	if recvWindow > 0 {
+		if recvWindow > MaxRecvWindow {
+			log.Printf("⚠️ recvWindow %v exceeds Binance's maximum, using %v", recvWindow, MaxRecvWindow)
+			recvWindow = MaxRecvWindow
+		}
+		c.recvWindow = recvWindow
+	}
+	if syncInterval > 0 {
+		c.syncInterval = syncInterval
+	}
+}
+
+// Now returns the current time on Binance's clock.
+func (c *ServerClock) Now() time.Time {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	return time.Now().Add(c.offset)
+}
+
+// Offset returns how far Binance's clock is ahead of the local clock.
+func (c *ServerClock) Offset() time.Duration {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	return c.offset
+}
+
+// RecvWindow returns the recvWindow sent with signed requests.
+func (c *ServerClock) RecvWindow() time.Duration {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	return c.recvWindow
+}
+
+// needsSync reports whether the offset has never been measured or is older than the sync interval.
+func (c *ServerClock) needsSync() bool {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	return c.lastSync.IsZero() || time.Since(c.lastSync) > c.syncInterval
+}
+
+// sync measures the offset against /api/v3/time, assuming the server read its
+// clock halfway through the round trip.
+func (c *ServerClock) sync(client *resty.Client) error {
+	var response struct {
+		ServerTime int64 `json:"serverTime"`
+	}
+
+	sent := time.Now()
+	resp, err := client.R().
+		SetResult(&response).
+		Get("/api/v3/time")
+	received := time.Now()
+
+	if err != nil {
+		return fmt.Errorf("error fetching Binance server time: %w", err)
+	}
+	if resp.StatusCode() != 200 {
+		return NewBinanceError(resp.StatusCode(), resp.String())
+	}
+	if response.ServerTime == 0 {
+		return fmt.Errorf("error fetching Binance server time: empty response")
+	}
+
+	localTime := sent.Add(received.Sub(sent) / 2)
+	offset := time.UnixMilli(response.ServerTime).Sub(localTime)
+
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	if c.lastSync.IsZero() || (offset-c.offset).Abs() > time.Second {
+		log.Printf("🕒 Binance server time offset: %v (round trip %v)", offset, received.Sub(sent))
+	}
+	c.offset = offset
+	c.lastSync = received
+	return nil
+}
@@ -0,0 +1,66 @@
+package bitcoin
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignedRequest_ResyncsOnInvalidTimestamp(t *testing.T) {
+	var skew, timeRequests int64 // skew of the fake server clock, in milliseconds
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		serverTime := time.Now().UnixMilli() + atomic.LoadInt64(&skew)
+
+		switch r.URL.Path {
+		case "/api/v3/time":
+			atomic.AddInt64(&timeRequests, 1)
+			fmt.Fprintf(w, `{"serverTime":%d}`, serverTime)
+		case "/api/v3/account":
+			query, signature, _ := strings.Cut(r.URL.RawQuery, "&signature=")
+			mac := hmac.New(sha256.New, []byte("secret"))
+			mac.Write([]byte(query))
+			if signature != hex.EncodeToString(mac.Sum(nil)) {
+				w.WriteHeader(http.StatusBadRequest)
+				w.Write([]byte(`{"code":-1022,"msg":"Signature for this request is not valid."}`))
+				return
+			}
+
+			timestamp, _ := strconv.ParseInt(r.URL.Query().Get("timestamp"), 10, 64)
+			if r.URL.Query().Get("recvWindow") != "5000" || timestamp > serverTime+1000 || serverTime-timestamp > 5000 {
+				w.WriteHeader(http.StatusBadRequest)
+				w.Write([]byte(`{"code":-1021,"msg":"Timestamp for this request is outside of the recvWindow."}`))
+				return
+			}
+			w.Write([]byte(`{"canTrade":true,"balances":[]}`))
+		}
+	}))
+	defer server.Close()
+
+	client := NewBinanceClient("key", "secret", server.URL, nil)
+
+	// The first signed request measures the offset
+	_, err := client.GetAccountBalance(nil)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), atomic.LoadInt64(&timeRequests))
+
+	// The local clock now lags an hour behind: -1021 triggers a re-sync and a second attempt
+	atomic.StoreInt64(&skew, time.Hour.Milliseconds())
+	balance, err := client.GetAccountBalance(nil)
+	require.NoError(t, err)
+	assert.True(t, balance.CanTrade)
+	assert.Equal(t, int64(2), atomic.LoadInt64(&timeRequests))
+	assert.InDelta(t, time.Hour.Seconds(), ServerClockFor(server.URL).Offset().Seconds(), 1)
+}
@@ -0,0 +1,215 @@
+// Package bitcoin provides functionality for interacting with cryptocurrency APIs.
+package bitcoin
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// SymbolFilters holds the exchange filters Binance applies to orders on a symbol.
+// Orders are checked against them before they are sent, so a bad quantity or price
+// is reported with a clear message instead of a Binance -1013 error.
+//
+// Example usage:
+//
+//	filters, err := client.GetSymbolFilters("BTCUSDT")
+//	if err != nil {
+//	    return err
+//	}
+//	if err := filters.Check(order, lastPrice); err != nil {
+//	    log.Printf("Invalid order: %v", err)
+//	}
+type SymbolFilters struct {
+	Symbol     string `json:"symbol"`
+	Status     string `json:"status"`
+	BaseAsset  string `json:"base_asset"`
+	QuoteAsset string `json:"quote_asset"`
+
+	// PRICE_FILTER
+	MinPrice float64 `json:"min_price"`
+	MaxPrice float64 `json:"max_price"`
+	TickSize float64 `json:"tick_size"`
+
+	// LOT_SIZE
+	MinQty   float64 `json:"min_qty"`
+	MaxQty   float64 `json:"max_qty"`
+	StepSize float64 `json:"step_size"`
+
+	// MIN_NOTIONAL or NOTIONAL
+	MinNotional      float64 `json:"min_notional"`
+	ApplyMinToMarket bool    `json:"apply_min_to_market"`
+}
+
+// symbolInfoResponse is a symbol entry of the /api/v3/exchangeInfo response.
+type symbolInfoResponse struct {
+	Symbol     string `json:"symbol"`
+	Status     string `json:"status"`
+	BaseAsset  string `json:"baseAsset"`
+	QuoteAsset string `json:"quoteAsset"`
+	Filters    []struct {
+		FilterType       string `json:"filterType"`
+		MinPrice         string `json:"minPrice"`
+		MaxPrice         string `json:"maxPrice"`
+		TickSize         string `json:"tickSize"`
+		MinQty           string `json:"minQty"`
+		MaxQty           string `json:"maxQty"`
+		StepSize         string `json:"stepSize"`
+		MinNotional      string `json:"minNotional"`
+		ApplyToMarket    *bool  `json:"applyToMarket"`    // MIN_NOTIONAL
+		ApplyMinToMarket *bool  `json:"applyMinToMarket"` // NOTIONAL
+	} `json:"filters"`
+}
+
+// GetSymbolFilters returns the trading filters of a symbol from the cached exchange info
+// (see ExchangeInfo). Unknown symbols return a *BinanceError with code ErrInvalidSymbol.
+//
+// Example usage:
+//
+//	filters, err := client.GetSymbolFilters("BTCUSDT")
+//	if err != nil {
+//	    return err
+//	}
+//	log.Printf("Minimum order: %.8f BTC", filters.MinQty)
+func (c *BinanceClient) GetSymbolFilters(symbol string) (*SymbolFilters, error) {
+	symbols, err := c.exchangeInfo.get(c)
+	if err != nil {
+		return nil, err
+	}
+
+	filters, ok := symbols[strings.ToUpper(strings.TrimSpace(symbol))]
+	if !ok {
+		return nil, &BinanceError{Status: 400, Code: ErrInvalidSymbol, Message: "Invalid symbol: " + symbol}
+	}
+	return filters, nil
+}
+
+// IsTrading reports whether the symbol currently accepts orders.
+func (f *SymbolFilters) IsTrading() bool {
+	return f.Status == symbolStatusTrading
+}
+
+// newSymbolFilters converts an exchangeInfo symbol entry to SymbolFilters.
+func newSymbolFilters(info *symbolInfoResponse) *SymbolFilters {
+	filters := &SymbolFilters{
+		Symbol:     info.Symbol,
+		Status:     info.Status,
+		BaseAsset:  info.BaseAsset,
+		QuoteAsset: info.QuoteAsset,
+	}
+
+	for _, filter := range info.Filters {
+		switch filter.FilterType {
+		case "PRICE_FILTER":
+			filters.MinPrice = stringToFloat64(filter.MinPrice)
+			filters.MaxPrice = stringToFloat64(filter.MaxPrice)
+			filters.TickSize = stringToFloat64(filter.TickSize)
+		case "LOT_SIZE":
+			filters.MinQty = stringToFloat64(filter.MinQty)
+			filters.MaxQty = stringToFloat64(filter.MaxQty)
+			filters.StepSize = stringToFloat64(filter.StepSize)
+		case "MIN_NOTIONAL":
+			filters.MinNotional = stringToFloat64(filter.MinNotional)
+			filters.ApplyMinToMarket = filter.ApplyToMarket != nil && *filter.ApplyToMarket
+		case "NOTIONAL":
+			filters.MinNotional = stringToFloat64(filter.MinNotional)
+			filters.ApplyMinToMarket = filter.ApplyMinToMarket != nil && *filter.ApplyMinToMarket
+		}
+	}
+
+	return filters
+}
+
+// Check validates an order against the LOT_SIZE, PRICE_FILTER and MIN_NOTIONAL filters.
+// marketPrice is used to estimate the notional of market orders given in base quantity.
+// It returns an *OrderValidationError describing the first violated filter.
+//
+// Example usage:
+//
+//	if err := filters.Check(&OrderRequest{Symbol: "BTCUSDT", Side: SideBuy, Type: OrderTypeLimit,
+//	    Quantity: 0.001, Price: 50000}, 0); err != nil {
+//	    return err
+//	}
+func (f *SymbolFilters) Check(order *OrderRequest, marketPrice float64) error {
+	if f.Status != "" && !f.IsTrading() {
+		return &OrderValidationError{Filter: "STATUS", Message: fmt.Sprintf("%s is not trading (status %s)", f.Symbol, f.Status)}
+	}
+
+	if order.Quantity > 0 {
+		if err := f.checkQuantity(order.Quantity); err != nil {
+			return err
+		}
+	}
+
+	for _, price := range []struct {
+		name  string
+		value float64
+	}{{"price", order.Price}, {"stop price", order.StopPrice}} {
+		if price.value <= 0 {
+			continue
+		}
+		if err := f.checkPrice(price.name, price.value); err != nil {
+			return err
+		}
+	}
+
+	// Binance doesn't apply the notional filter to market orders unless the filter says so
+	if f.MinNotional <= 0 || (order.Type == OrderTypeMarket && !f.ApplyMinToMarket) {
+		return nil
+	}
+
+	notional := order.QuoteOrderQty
+	if notional == 0 {
+		price := order.Price
+		if order.Type == OrderTypeMarket {
+			price = marketPrice
+		}
+		notional = order.Quantity * price
+	}
+	if notional > 0 && notional < f.MinNotional {
+		return &OrderValidationError{
+			Filter:  "MIN_NOTIONAL",
+			Message: fmt.Sprintf("order value %.8g %s is below the minimum of %.8g", notional, f.QuoteAsset, f.MinNotional),
+		}
+	}
+
+	return nil
+}
+
+// checkQuantity validates a quantity against LOT_SIZE.
+func (f *SymbolFilters) checkQuantity(quantity float64) error {
+	if f.MinQty > 0 && quantity < f.MinQty {
+		return &OrderValidationError{Filter: "LOT_SIZE", Message: fmt.Sprintf("quantity %.8g is below the minimum of %.8g", quantity, f.MinQty)}
+	}
+	if f.MaxQty > 0 && quantity > f.MaxQty {
+		return &OrderValidationError{Filter: "LOT_SIZE", Message: fmt.Sprintf("quantity %.8g is above the maximum of %.8g", quantity, f.MaxQty)}
+	}
+	if !isStepMultiple(quantity, f.MinQty, f.StepSize) {
+		return &OrderValidationError{Filter: "LOT_SIZE", Message: fmt.Sprintf("quantity %.8g is not a multiple of the step size %.8g", quantity, f.StepSize)}
+	}
+	return nil
+}
+
+// checkPrice validates a price against PRICE_FILTER.
+func (f *SymbolFilters) checkPrice(name string, price float64) error {
+	if f.MinPrice > 0 && price < f.MinPrice {
+		return &OrderValidationError{Filter: "PRICE_FILTER", Message: fmt.Sprintf("%s %.8g is below the minimum of %.8g", name, price, f.MinPrice)}
+	}
+	if f.MaxPrice > 0 && price > f.MaxPrice {
+		return &OrderValidationError{Filter: "PRICE_FILTER", Message: fmt.Sprintf("%s %.8g is above the maximum of %.8g", name, price, f.MaxPrice)}
+	}
+	if !isStepMultiple(price, f.MinPrice, f.TickSize) {
+		return &OrderValidationError{Filter: "PRICE_FILTER", Message: fmt.Sprintf("%s %.8g is not a multiple of the tick size %.8g", name, price, f.TickSize)}
+	}
+	return nil
+}
+
+// isStepMultiple reports whether (value - min) is a whole number of steps,
+// allowing for floating point error. A zero step disables the check.
+func isStepMultiple(value, min, step float64) bool {
+	if step <= 0 {
+		return true
+	}
+	steps := (value - min) / step
+	return math.Abs(steps-math.Round(steps)) < 1e-6
+}
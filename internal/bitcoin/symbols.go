@@ -0,0 +1,48 @@
+// Package bitcoin provides functionality for interacting with cryptocurrency APIs.
+package bitcoin
+
+import (
+	"fmt"
+	"strings"
+)
+
+// quoteAssets lists the quote assets recognised when splitting Binance symbols.
+// Longer assets come first so "FDUSD" and "USDT" are not mistaken for "USD".
+var quoteAssets = []string{"FDUSD", "USDT", "USDC", "BUSD", "TUSD", "USD", "EUR", "GBP", "TRY", "BRL", "BTC", "ETH", "BNB"}
+
+// PairsFor returns the trading pairs of assets against quote, skipping the quote asset itself.
+//
+// Example usage:
+//
+//	symbols := PairsFor([]string{"BTC", "ETH", "USDT"}, "USDT") // "BTCUSDT", "ETHUSDT"
+func PairsFor(assets []string, quote string) []string {
+	var pairs []string
+	for _, asset := range assets {
+		asset = strings.ToUpper(strings.TrimSpace(asset))
+		if asset == "" || asset == quote {
+			continue
+		}
+		pairs = append(pairs, asset+quote)
+	}
+	return pairs
+}
+
+// SplitSymbol splits a Binance trading pair into its base and quote assets.
+// Other exchanges name their markets differently, so providers use it to
+// translate symbols such as "BTCUSDT" into "BTC-USDT" or "XBTUSDT".
+//
+// Example usage:
+//
+//	base, quote, err := SplitSymbol("ETHUSDT") // "ETH", "USDT"
+//	if err != nil {
+//	    return err
+//	}
+func SplitSymbol(symbol string) (base, quote string, err error) {
+	symbol = strings.ToUpper(strings.TrimSpace(symbol))
+	for _, asset := range quoteAssets {
+		if strings.HasSuffix(symbol, asset) && len(symbol) > len(asset) {
+			return strings.TrimSuffix(symbol, asset), asset, nil
+		}
+	}
+	return "", "", fmt.Errorf("unknown quote asset in symbol %q", symbol)
+}
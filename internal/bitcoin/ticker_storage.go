@@ -2,6 +2,7 @@
 package bitcoin
 
 import (
+	"fmt"
 	"log"
 	"strconv"
 	"time"
@@ -30,6 +31,28 @@ func NewTickerStorage(repo *repositories.TickerRepository) *TickerStorage {
 
 // StoreTicker24h stores the response from /api/v3/ticker/24hr endpoint.
 func (s *TickerStorage) StoreTicker24h(symbol string, response *Ticker24hResponse) error {
+	ticker := NewTickerData(symbol, response)
+	lastPrice, priceChangePercent := ticker.LastPrice, ticker.PriceChangePercent
+
+	// Store in database
+	if err := s.repo.Store(ticker); err != nil {
+		log.Printf("❌ Error storing ticker data: %v", err)
+		return err
+	}
+
+	log.Printf("✅ Stored ticker data for %s: $%.2f (%+.2f%%)",
+		symbol, lastPrice, priceChangePercent)
+	return nil
+}
+
+// NewTickerData converts a Binance 24hr ticker into the TickerData stored in ticker_data.
+// Fields that don't parse are left at zero.
+//
+// Example usage:
+//
+//	ticker := NewTickerData("BTCUSDT", &response)
+//	fmt.Printf("24h volume: %.2f\n", ticker.Volume)
+func NewTickerData(symbol string, response *Ticker24hResponse) *models.TickerData {
 	// Parse numeric values
 	lastPrice, _ := strconv.ParseFloat(response.LastPrice, 64)
 	priceChange, _ := strconv.ParseFloat(response.PriceChange, 64)
@@ -43,11 +66,10 @@ func (s *TickerStorage) StoreTicker24h(symbol string, response *Ticker24hRespons
 	volume, _ := strconv.ParseFloat(response.Volume, 64)
 	quoteVolume, _ := strconv.ParseFloat(response.QuoteVolume, 64)
 
-	// Create ticker data model
-	ticker := &models.TickerData{
+	return &models.TickerData{
 		Symbol:             symbol,
 		Timestamp:          time.Now(),
-		Source:             "Binance",
+		Source:             SourceBinance,
 		Interval:           "1m",
 		LastPrice:          lastPrice,
 		PriceChange:        priceChange,
@@ -66,15 +88,40 @@ func (s *TickerStorage) StoreTicker24h(symbol string, response *Ticker24hRespons
 		LastTradeID:        response.LastID,
 		TotalTrades:        response.Count,
 	}
+}
 
-	// Store in database
-	if err := s.repo.Store(ticker); err != nil {
-		log.Printf("❌ Error storing ticker data: %v", err)
+// StoreAggregate stores an aggregated price together with its per-source quotes,
+// so it is possible to audit which providers an alert was based on.
+func (s *TickerStorage) StoreAggregate(price *PriceData) error {
+	aggregate := &models.TickerData{
+		Symbol:             price.Symbol,
+		Timestamp:          price.Timestamp,
+		Source:             SourceAggregate,
+		Interval:           "1m",
+		LastPrice:          price.Price,
+		PriceChangePercent: price.PriceChangePercent,
+	}
+
+	quotes := make([]models.TickerData, 0, len(price.Quotes))
+	for _, quote := range price.Quotes {
+		quotes = append(quotes, models.TickerData{
+			Symbol:             price.Symbol,
+			Timestamp:          price.Timestamp,
+			Source:             quote.Source,
+			Interval:           "1m",
+			LastPrice:          quote.Price,
+			PriceChangePercent: quote.PriceChangePercent,
+			Rejected:           quote.Rejected,
+		})
+	}
+
+	if err := s.repo.StoreAggregate(aggregate, quotes); err != nil {
+		log.Printf("❌ Error storing aggregated ticker data: %v", err)
 		return err
 	}
 
-	log.Printf("✅ Stored ticker data for %s: $%.2f (%+.2f%%)",
-		symbol, lastPrice, priceChangePercent)
+	log.Printf("✅ Stored aggregated ticker data for %s: $%.2f from %d quotes",
+		price.Symbol, price.Price, len(quotes))
 	return nil
 }
 
@@ -98,3 +145,20 @@ type Ticker24hResponse struct {
 	LastID             int64  `json:"lastId"`
 	Count              int64  `json:"count"`
 }
+
+// PriceRange returns the oldest, lowest and highest stored prices of symbol between
+// from and to, or nil when nothing was stored in that range.
+func (s *TickerStorage) PriceRange(symbol string, from, to time.Time) (*PriceRange, error) {
+	oldest, lowest, highest, err := s.repo.GetPriceExtremes(symbol, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("error getting %s price history: %w", symbol, err)
+	}
+	if oldest == nil {
+		return nil, nil
+	}
+	return &PriceRange{
+		Oldest: PricePoint{Price: oldest.LastPrice, Timestamp: oldest.Timestamp},
+		Low:    PricePoint{Price: lowest.LastPrice, Timestamp: lowest.Timestamp},
+		High:   PricePoint{Price: highest.LastPrice, Timestamp: highest.Timestamp},
+	}, nil
+}
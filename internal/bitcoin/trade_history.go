@@ -0,0 +1,314 @@
+// Package bitcoin provides functionality for interacting with cryptocurrency APIs.
+package bitcoin
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/cgallonv/btc-alerta-de-precio/internal/storage/models"
+	"github.com/cgallonv/btc-alerta-de-precio/internal/storage/repositories"
+)
+
+// TradeHistory imports the account's Binance trades into the database and derives
+// cost basis and profit and loss from them.
+//
+// Example usage:
+//
+//	history := NewTradeHistory(tradeRepo)
+//	if _, err := history.Import(client, []string{"BTCUSDT"}); err != nil {
+//	    log.Printf("Error importing trades: %v", err)
+//	}
+//	if err := history.ApplyPnL(balance); err != nil {
+//	    log.Printf("Error computing PnL: %v", err)
+//	}
+type TradeHistory struct {
+	repo *repositories.TradeRepository
+}
+
+// NewTradeHistory creates a new TradeHistory instance.
+func NewTradeHistory(repo *repositories.TradeRepository) *TradeHistory {
+	return &TradeHistory{repo: repo}
+}
+
+// usdQuotes are the quote assets whose positions are costed in US dollars, taken as
+// 1:1 like the USDT account balances are valued in.
+var usdQuotes = map[string]bool{"USDT": true, "USDC": true, "BUSD": true, "FDUSD": true, "TUSD": true, "USD": true}
+
+// AssetPosition is the cost basis and profit and loss of an asset bought with one quote
+// asset, computed from its trades with the average cost method. Amounts are in the
+// quote asset of the trades.
+type AssetPosition struct {
+	Asset       string  `json:"asset"`
+	QuoteAsset  string  `json:"quote_asset"`
+	Quantity    float64 `json:"quantity"`     // Quantity bought minus sold
+	AvgCost     float64 `json:"avg_cost"`     // Average cost per unit, fees included
+	RealizedPnL float64 `json:"realized_pnl"` // Profit from sales, net of fees
+	Fees        float64 `json:"fees"`         // Commissions paid, valued in the quote asset
+	Trades      int     `json:"trades"`
+}
+
+// Import fetches the trades of each symbol that are newer than the last stored one,
+// paging through myTrades, and stores them. It returns the number of new trades.
+//
+// Example usage:
+//
+//	imported, err := history.Import(client, PairsFor(cfg.BinanceDefaultSymbols, "USDT"))
+func (h *TradeHistory) Import(client *BinanceClient, symbols []string) (int64, error) {
+	var imported int64
+
+	for _, symbol := range symbols {
+		base, quote, err := SplitSymbol(symbol)
+		if err != nil {
+			return imported, err
+		}
+
+		lastID, err := h.repo.GetLastTradeID(symbol)
+		if err != nil {
+			return imported, fmt.Errorf("error reading last %s trade: %w", symbol, err)
+		}
+
+		for {
+			trades, err := client.GetMyTrades(OrderQuery{Symbol: symbol, FromID: lastID + 1, Limit: historyPageSize})
+			if err != nil {
+				return imported, fmt.Errorf("error importing %s trades: %w", symbol, err)
+			}
+
+			rows := make([]models.Trade, 0, len(trades))
+			for _, trade := range trades {
+				rows = append(rows, models.Trade{
+					TradeID:         trade.ID,
+					Symbol:          symbol,
+					OrderID:         trade.OrderID,
+					BaseAsset:       base,
+					QuoteAsset:      quote,
+					Price:           trade.Price,
+					Qty:             trade.Qty,
+					QuoteQty:        trade.QuoteQty,
+					Commission:      trade.Commission,
+					CommissionAsset: trade.CommissionAsset,
+					IsBuyer:         trade.IsBuyer,
+					IsMaker:         trade.IsMaker,
+					Time:            trade.Time,
+				})
+				if trade.ID > lastID {
+					lastID = trade.ID
+				}
+			}
+
+			stored, err := h.repo.StoreTrades(rows)
+			if err != nil {
+				return imported, fmt.Errorf("error storing %s trades: %w", symbol, err)
+			}
+			imported += stored
+
+			if len(trades) < historyPageSize {
+				break
+			}
+		}
+	}
+
+	if imported > 0 {
+		log.Printf("✅ Imported %d new trades", imported)
+	}
+	return imported, nil
+}
+
+// Start imports trades immediately and then every interval until ctx is cancelled.
+//
+// Example usage:
+//
+//	history.Start(ctx, client, symbols, time.Hour)
+func (h *TradeHistory) Start(ctx context.Context, client *BinanceClient, symbols []string, interval time.Duration) {
+	log.Printf("📥 Importing trades for %v every %v", symbols, interval)
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			if _, err := h.Import(client, symbols); err != nil {
+				log.Printf("❌ Error importing trades: %v", err)
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Positions computes the position of every traded asset and quote asset from the
+// stored trades, keyed by PositionKey.
+// Commissions paid in a third asset, such as BNB, are estimated from the account's
+// maker and taker rates (as fractions, e.g. 0.001).
+func (h *TradeHistory) Positions(makerRate, takerRate float64) (map[string]*AssetPosition, error) {
+	trades, err := h.repo.GetTrades()
+	if err != nil {
+		return nil, fmt.Errorf("error loading trades: %w", err)
+	}
+	return ComputePositions(trades, makerRate, takerRate), nil
+}
+
+// ApplyPnL fills in the cost basis and profit and loss of each asset in the balance,
+// and the account totals, using the commission rates the balance reports.
+//
+// Example usage:
+//
+//	balance, err := client.GetAccountBalance(symbols)
+//	if err != nil {
+//	    return err
+//	}
+//	if err := history.ApplyPnL(balance); err != nil {
+//	    log.Printf("PnL unavailable: %v", err)
+//	}
+func (h *TradeHistory) ApplyPnL(balance *AccountBalance) error {
+	positions, err := h.Positions(
+		stringToFloat64(balance.CommissionRates.Maker),
+		stringToFloat64(balance.CommissionRates.Taker),
+	)
+	if err != nil {
+		return err
+	}
+
+	applyPositions(balance, positions)
+	return nil
+}
+
+// applyPositions fills in the PnL of the balance from the positions. Balances are valued
+// in USD, so only positions quoted in US dollars count, merged per asset; BTCEUR or
+// ETHBTC trades have costs in another currency. Only the quantity covered by both the
+// trades and the balance has a known cost: coins deposited on top of what was bought,
+// or a balance short of the position (withdrawals), don't count.
+func applyPositions(balance *AccountBalance, positions map[string]*AssetPosition) {
+	positions = usdPositions(positions)
+	balance.RealizedPnL, balance.UnrealizedPnL = 0, 0
+	for i := range balance.Assets {
+		asset := &balance.Assets[i]
+		position, ok := positions[asset.Symbol]
+		if !ok {
+			continue
+		}
+
+		covered := min(position.Quantity, asset.Total)
+		asset.AvgCost = position.AvgCost
+		asset.CostBasis = position.AvgCost * covered
+		asset.RealizedPnL = position.RealizedPnL
+		asset.UnrealizedPnL = 0
+		if asset.ValueUSD > 0 && covered > 0 {
+			asset.UnrealizedPnL = asset.ValueUSD*covered/asset.Total - asset.CostBasis
+		}
+
+		balance.RealizedPnL += asset.RealizedPnL
+		balance.UnrealizedPnL += asset.UnrealizedPnL
+	}
+}
+
+// usdPositions merges the positions quoted in US dollars by asset, leaving out the rest.
+func usdPositions(positions map[string]*AssetPosition) map[string]*AssetPosition {
+	merged := make(map[string]*AssetPosition)
+	for _, position := range positions {
+		if !usdQuotes[position.QuoteAsset] {
+			continue
+		}
+		total, ok := merged[position.Asset]
+		if !ok {
+			copied := *position
+			merged[position.Asset] = &copied
+			continue
+		}
+
+		if quantity := total.Quantity + position.Quantity; quantity > 0 {
+			total.AvgCost = (total.AvgCost*total.Quantity + position.AvgCost*position.Quantity) / quantity
+		}
+		total.QuoteAsset = "USD"
+		total.Quantity += position.Quantity
+		total.RealizedPnL += position.RealizedPnL
+		total.Fees += position.Fees
+		total.Trades += position.Trades
+	}
+	return merged
+}
+
+// PositionKey returns the key of the position in base bought with quote, e.g. "BTC/USDT".
+func PositionKey(base, quote string) string {
+	return base + "/" + quote
+}
+
+// ComputePositions replays trades, in execution order, with the average cost method.
+// Each base and quote asset pair is a separate position, keyed by PositionKey, since
+// its amounts are in the quote asset.
+// Buys add their cost and fees to the position; sells realise the difference between
+// their net proceeds and the average cost of the quantity sold. Quantity sold beyond
+// what the trades bought (e.g. deposited coins) has an unknown cost and isn't realised.
+//
+// Example usage:
+//
+//	positions := ComputePositions(trades, 0.001, 0.001)
+//	btc := positions[PositionKey("BTC", "USDT")]
+//	log.Printf("BTC avg cost $%.2f, realized $%.2f", btc.AvgCost, btc.RealizedPnL)
+func ComputePositions(trades []models.Trade, makerRate, takerRate float64) map[string]*AssetPosition {
+	positions := make(map[string]*AssetPosition)
+
+	for _, trade := range trades {
+		key := PositionKey(trade.BaseAsset, trade.QuoteAsset)
+		position, ok := positions[key]
+		if !ok {
+			position = &AssetPosition{Asset: trade.BaseAsset, QuoteAsset: trade.QuoteAsset}
+			positions[key] = position
+		}
+		position.Trades++
+
+		fee := tradeFee(trade, makerRate, takerRate)
+		position.Fees += fee
+
+		if trade.IsBuyer {
+			received := trade.Qty
+			cost := trade.QuoteQty + fee
+			if trade.CommissionAsset == trade.BaseAsset {
+				// The fee was taken from the coins received, so it is already in a higher unit cost
+				received -= trade.Commission
+				cost = trade.QuoteQty
+			}
+			if position.Quantity+received > 0 {
+				position.AvgCost = (position.AvgCost*position.Quantity + cost) / (position.Quantity + received)
+			}
+			position.Quantity += received
+			continue
+		}
+
+		sold := trade.Qty
+		if sold > position.Quantity {
+			sold = position.Quantity
+		}
+		if trade.Qty > 0 {
+			proceeds := (trade.QuoteQty - fee) * sold / trade.Qty
+			position.RealizedPnL += proceeds - position.AvgCost*sold
+		}
+		position.Quantity -= sold
+		if position.Quantity <= 0 {
+			position.Quantity, position.AvgCost = 0, 0
+		}
+	}
+
+	return positions
+}
+
+// tradeFee returns the commission of a trade valued in its quote asset.
+func tradeFee(trade models.Trade, makerRate, takerRate float64) float64 {
+	switch trade.CommissionAsset {
+	case trade.QuoteAsset:
+		return trade.Commission
+	case trade.BaseAsset:
+		return trade.Commission * trade.Price
+	}
+
+	// Paid in another asset (usually BNB): estimate it from the account's rates
+	if trade.IsMaker {
+		return trade.QuoteQty * makerRate
+	}
+	return trade.QuoteQty * takerRate
+}
@@ -0,0 +1,79 @@
+package bitcoin
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cgallonv/btc-alerta-de-precio/internal/storage/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestComputePositions(t *testing.T) {
+	now := time.Now()
+	trades := []models.Trade{
+		// Buy 1 BTC at 100 paying 0.1 USDT
+		{TradeID: 1, BaseAsset: "BTC", QuoteAsset: "USDT", Price: 100, Qty: 1, QuoteQty: 100,
+			Commission: 0.1, CommissionAsset: "USDT", IsBuyer: true, Time: now},
+		// Buy 1 BTC at 200 paying 0.01 BTC, so 0.99 BTC is received
+		{TradeID: 2, BaseAsset: "BTC", QuoteAsset: "USDT", Price: 200, Qty: 1, QuoteQty: 200,
+			Commission: 0.01, CommissionAsset: "BTC", IsBuyer: true, Time: now.Add(time.Minute)},
+		// Sell 1 BTC at 300 paying the fee in BNB, estimated with the 0.1% taker rate
+		{TradeID: 3, BaseAsset: "BTC", QuoteAsset: "USDT", Price: 300, Qty: 1, QuoteQty: 300,
+			Commission: 0.0005, CommissionAsset: "BNB", Time: now.Add(2 * time.Minute)},
+	}
+
+	positions := ComputePositions(trades, 0.001, 0.001)
+	btc := positions[PositionKey("BTC", "USDT")]
+	if assert.NotNil(t, btc) {
+		avgCost := 300.1 / 1.99
+		assert.Equal(t, 3, btc.Trades)
+		assert.InDelta(t, 0.99, btc.Quantity, 1e-9)
+		assert.InDelta(t, avgCost, btc.AvgCost, 1e-9)
+		assert.InDelta(t, 299.7-avgCost, btc.RealizedPnL, 1e-9)
+		assert.InDelta(t, 0.1+2+0.3, btc.Fees, 1e-9)
+	}
+
+	// Selling more than was bought only realises the known quantity
+	positions = ComputePositions([]models.Trade{
+		{TradeID: 1, BaseAsset: "ETH", QuoteAsset: "USDT", Price: 10, Qty: 1, QuoteQty: 10, IsBuyer: true},
+		{TradeID: 2, BaseAsset: "ETH", QuoteAsset: "USDT", Price: 20, Qty: 2, QuoteQty: 40},
+	}, 0, 0)
+	eth := positions[PositionKey("ETH", "USDT")]
+	assert.InDelta(t, 10, eth.RealizedPnL, 1e-9)
+	assert.Zero(t, eth.Quantity)
+	assert.Zero(t, eth.AvgCost)
+
+	// Fills in different quote assets are separate positions
+	positions = ComputePositions([]models.Trade{
+		{TradeID: 1, BaseAsset: "BTC", QuoteAsset: "USDT", Price: 100, Qty: 1, QuoteQty: 100, IsBuyer: true},
+		{TradeID: 2, BaseAsset: "BTC", QuoteAsset: "EUR", Price: 90, Qty: 1, QuoteQty: 90, IsBuyer: true},
+	}, 0, 0)
+	require.Len(t, positions, 2)
+	assert.InDelta(t, 100, positions[PositionKey("BTC", "USDT")].AvgCost, 1e-9)
+	assert.InDelta(t, 90, positions[PositionKey("BTC", "EUR")].AvgCost, 1e-9)
+}
+
+func TestApplyPositions(t *testing.T) {
+	balance := &AccountBalance{Assets: []AssetBalance{
+		// 1.5 BTC held but only 1 BTC bought, the rest was deposited
+		{Symbol: "BTC", Total: 1.5, ValueUSD: 150},
+		// 2 ETH bought but only 0.5 ETH left after a withdrawal
+		{Symbol: "ETH", Total: 0.5, ValueUSD: 10},
+	}}
+	applyPositions(balance, map[string]*AssetPosition{
+		// USDT and USDC positions are merged, EUR ones have no USD cost and are left out
+		"BTC/USDT": {Asset: "BTC", QuoteAsset: "USDT", Quantity: 0.5, AvgCost: 60},
+		"BTC/USDC": {Asset: "BTC", QuoteAsset: "USDC", Quantity: 0.5, AvgCost: 100},
+		"BTC/EUR":  {Asset: "BTC", QuoteAsset: "EUR", Quantity: 0.5, AvgCost: 50, RealizedPnL: 40},
+		"ETH/USDT": {Asset: "ETH", QuoteAsset: "USDT", Quantity: 2, AvgCost: 15, RealizedPnL: 5},
+	})
+
+	btc, eth := balance.Assets[0], balance.Assets[1]
+	assert.InDelta(t, 80, btc.CostBasis, 1e-9)
+	assert.InDelta(t, 100-80, btc.UnrealizedPnL, 1e-9)
+	assert.InDelta(t, 7.5, eth.CostBasis, 1e-9)
+	assert.InDelta(t, 10-7.5, eth.UnrealizedPnL, 1e-9)
+	assert.InDelta(t, 22.5, balance.UnrealizedPnL, 1e-9)
+	assert.InDelta(t, 5, balance.RealizedPnL, 1e-9)
+}
@@ -0,0 +1,611 @@
+// Package bitcoin provides functionality for interacting with cryptocurrency APIs.
+package bitcoin
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/gorilla/websocket"
+)
+
+// DefaultListenKeyKeepAlive is how often the listenKey is refreshed.
+// Binance expires a listenKey that isn't kept alive for 60 minutes.
+const DefaultListenKeyKeepAlive = 30 * time.Minute
+
+// Execution types of an executionReport event.
+const (
+	ExecutionTypeNew      = "NEW"
+	ExecutionTypeCanceled = "CANCELED"
+	ExecutionTypeReplaced = "REPLACED"
+	ExecutionTypeRejected = "REJECTED"
+	ExecutionTypeTrade    = "TRADE"
+	ExecutionTypeExpired  = "EXPIRED"
+)
+
+// OrderUpdate is an order event received from the user data stream.
+type OrderUpdate struct {
+	Symbol        string    `json:"symbol"`
+	OrderID       int64     `json:"order_id"`
+	ClientOrderID string    `json:"client_order_id"`
+	Side          string    `json:"side"`
+	Type          string    `json:"type"`
+	Quantity      float64   `json:"quantity"`
+	Price         float64   `json:"price"`
+	ExecutionType string    `json:"execution_type"` // NEW, TRADE, CANCELED, ...
+	Status        string    `json:"status"`
+	RejectReason  string    `json:"reject_reason,omitempty"`
+	EventTime     time.Time `json:"event_time"`
+
+	// Set when ExecutionType is TRADE
+	TradeID            int64   `json:"trade_id,omitempty"`
+	LastQty            float64 `json:"last_qty"`
+	LastPrice          float64 `json:"last_price"`
+	CumulativeQty      float64 `json:"cumulative_qty"`
+	CumulativeQuoteQty float64 `json:"cumulative_quote_qty"`
+	Commission         float64 `json:"commission"`
+	CommissionAsset    string  `json:"commission_asset,omitempty"`
+}
+
+// IsFill reports whether the update is a trade, i.e. the order was partially or fully filled.
+func (u *OrderUpdate) IsFill() bool {
+	return u.ExecutionType == ExecutionTypeTrade
+}
+
+// OrderUpdateHandler is called for every executionReport received from the user data stream.
+type OrderUpdateHandler func(update *OrderUpdate)
+
+// UserDataStream consumes the Binance user data stream of the account. It creates a
+// listenKey, keeps it alive and applies outboundAccountPosition events to an in-memory
+// snapshot of the account balance, so the balance can be served without calling
+// /api/v3/account on each request; assets are priced when read, from the shared
+// TickerCache. executionReport events are delivered to the OrderUpdateHandler.
+//
+// Each connection starts from a fresh GetAccountBalance, so events missed while the
+// stream was down are never lost. Connection failures are retried with exponential backoff.
+//
+// Example usage:
+//
+//	stream := NewUserDataStream(client, "", []string{"BTC", "USDT"})
+//	stream.OnOrderUpdate(func(update *OrderUpdate) {
+//	    if update.IsFill() {
+//	        log.Printf("%s %s filled %.8f", update.Side, update.Symbol, update.LastQty)
+//	    }
+//	})
+//	go stream.Run(ctx)
+//	if balance, ok := stream.Snapshot(nil); ok {
+//	    log.Printf("Total: $%.2f", balance.TotalBalance)
+//	}
+type UserDataStream struct {
+	client  *BinanceClient
+	baseURL string
+	assets  []string
+	dialer  *websocket.Dialer
+	handler OrderUpdateHandler
+
+	// Keepalive and reconnect settings
+	listenKeyKeepAlive time.Duration
+	pingInterval       time.Duration
+	pongWait           time.Duration
+	reconnectDelay     time.Duration
+	maxReconnectDelay  time.Duration
+
+	// Account snapshot, valid while connected
+	snapshot  *AccountBalance
+	prices    map[string]float64 // Last known USD price per asset
+	connected bool
+	stateMux  sync.RWMutex
+}
+
+// NewUserDataStream creates a user data stream for the account of client.
+// assets limits the balance snapshot to those assets, like GetAccountBalance does.
+// If baseURL is empty, DefaultBinanceStreamURL is used.
+//
+// Example usage:
+//
+//	stream := NewUserDataStream(client, cfg.BinanceStreamURL, cfg.BinanceDefaultSymbols)
+func NewUserDataStream(client *BinanceClient, baseURL string, assets []string) *UserDataStream {
+	if baseURL == "" {
+		baseURL = DefaultBinanceStreamURL
+	}
+
+	return &UserDataStream{
+		client:             client,
+		baseURL:            strings.TrimRight(baseURL, "/"),
+		assets:             assets,
+		dialer:             websocket.DefaultDialer,
+		listenKeyKeepAlive: DefaultListenKeyKeepAlive,
+		pingInterval:       20 * time.Second,
+		pongWait:           60 * time.Second,
+		reconnectDelay:     time.Second,
+		maxReconnectDelay:  time.Minute,
+		prices:             make(map[string]float64),
+	}
+}
+
+// OnOrderUpdate sets the handler for order events. It must be called before Run.
+func (s *UserDataStream) OnOrderUpdate(handler OrderUpdateHandler) {
+	s.handler = handler
+}
+
+// IsConnected returns true if the stream is connected and the snapshot is current.
+func (s *UserDataStream) IsConnected() bool {
+	s.stateMux.RLock()
+	defer s.stateMux.RUnlock()
+	return s.connected
+}
+
+// Snapshot returns a copy of the account balance kept up to date by the stream,
+// restricted to the given assets and valued at current prices (see revalue). ok is
+// false while the stream is disconnected or if an asset isn't tracked by the stream;
+// the caller should then use GetAccountBalance.
+//
+// Example usage:
+//
+//	balance, ok := stream.Snapshot([]string{"BTC"})
+//	if !ok {
+//	    balance, err = client.GetAccountBalance([]string{"BTC"})
+//	}
+func (s *UserDataStream) Snapshot(assets []string) (*AccountBalance, bool) {
+	balance, ok := s.copySnapshot(assets)
+	if !ok {
+		return nil, false
+	}
+	s.revalue(balance)
+	return balance, true
+}
+
+// copySnapshot returns a copy of the account snapshot restricted to assets.
+func (s *UserDataStream) copySnapshot(assets []string) (*AccountBalance, bool) {
+	s.stateMux.RLock()
+	defer s.stateMux.RUnlock()
+
+	if !s.connected || s.snapshot == nil {
+		return nil, false
+	}
+
+	wanted := make(map[string]bool, len(assets))
+	for _, asset := range assets {
+		asset = strings.ToUpper(strings.TrimSpace(asset))
+		if !s.tracks(asset) {
+			return nil, false
+		}
+		wanted[asset] = true
+	}
+
+	balance := *s.snapshot
+	balance.Assets = make([]AssetBalance, 0, len(s.snapshot.Assets))
+	for _, asset := range s.snapshot.Assets {
+		if len(wanted) == 0 || wanted[asset.Symbol] {
+			balance.Assets = append(balance.Assets, asset)
+		}
+	}
+	balance.TotalBalance, balance.AvailableBalance = balanceTotals(balance.Assets)
+	return &balance, true
+}
+
+// revalue prices the balance at the current market prices from the shared ticker cache,
+// so its value follows the market between account events instead of staying at the
+// prices of the last connection. Assets without a market, or every asset if the
+// tickers can't be fetched, keep their last known price.
+func (s *UserDataStream) revalue(balance *AccountBalance) {
+	symbols := make([]string, 0, len(balance.Assets))
+	for _, asset := range balance.Assets {
+		if asset.Total > 0 {
+			symbols = append(symbols, asset.Symbol)
+		}
+	}
+	if len(symbols) == 0 {
+		return
+	}
+
+	quotes, err := s.client.GetAssetQuotes(symbols)
+	if err != nil {
+		log.Printf("⚠️ Valuing the streamed balance at last known prices: %v", err)
+		return
+	}
+
+	s.stateMux.Lock()
+	defer s.stateMux.Unlock()
+
+	for i := range balance.Assets {
+		asset := &balance.Assets[i]
+		quote, ok := quotes[asset.Symbol]
+		if !ok || asset.Total <= 0 {
+			continue
+		}
+		asset.ValueUSD = asset.Total * quote.Price
+		asset.Change24h = quote.Change24h
+		asset.PriceRoute = quote.Route
+		s.prices[asset.Symbol] = quote.Price
+	}
+	balance.TotalBalance, balance.AvailableBalance = balanceTotals(balance.Assets)
+}
+
+// tracks reports whether the snapshot holds the balance of an asset. The caller must hold stateMux.
+func (s *UserDataStream) tracks(asset string) bool {
+	if len(s.assets) == 0 {
+		return true
+	}
+	for _, tracked := range s.assets {
+		if strings.EqualFold(strings.TrimSpace(tracked), asset) {
+			return true
+		}
+	}
+	return false
+}
+
+// Run connects to the user data stream until ctx is cancelled.
+//
+// Example usage:
+//
+//	go stream.Run(ctx)
+func (s *UserDataStream) Run(ctx context.Context) {
+	delay := s.reconnectDelay
+
+	for {
+		connectedAt := time.Now()
+		err := s.connectAndRead(ctx)
+		s.setConnected(false)
+
+		if ctx.Err() != nil {
+			log.Printf("🔌 Binance user data stream stopped")
+			return
+		}
+
+		// Reset backoff after a connection that stayed up for a while
+		if time.Since(connectedAt) > s.maxReconnectDelay {
+			delay = s.reconnectDelay
+		}
+
+		log.Printf("⚠️ Binance user data stream disconnected: %v (reconnecting in %v)", err, delay)
+
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return
+		}
+
+		delay *= 2
+		if delay > s.maxReconnectDelay {
+			delay = s.maxReconnectDelay
+		}
+	}
+}
+
+// connectAndRead creates a listenKey, loads the account snapshot and reads events until the connection fails.
+func (s *UserDataStream) connectAndRead(ctx context.Context) error {
+	listenKey, err := s.client.CreateListenKey()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := s.client.CloseListenKey(listenKey); err != nil {
+			log.Printf("⚠️ Error closing listenKey: %v", err)
+		}
+	}()
+
+	conn, _, err := s.dialer.DialContext(ctx, s.baseURL+"/ws/"+listenKey, nil)
+	if err != nil {
+		return fmt.Errorf("error connecting to Binance user data stream: %w", err)
+	}
+	defer conn.Close()
+
+	// Load the snapshot after connecting, so no event between the two is missed
+	balance, err := s.client.GetAccountBalance(s.assets)
+	if err != nil {
+		return fmt.Errorf("error loading account snapshot: %w", err)
+	}
+	s.setSnapshot(balance)
+
+	log.Printf("🔌 Connected to Binance user data stream")
+
+	conn.SetReadDeadline(time.Now().Add(s.pongWait))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(s.pongWait))
+	})
+
+	done := make(chan struct{})
+	defer close(done)
+	go s.keepAlive(ctx, conn, listenKey, done)
+
+	for {
+		_, message, err := conn.ReadMessage()
+		if err != nil {
+			return fmt.Errorf("error reading from Binance user data stream: %w", err)
+		}
+		conn.SetReadDeadline(time.Now().Add(s.pongWait))
+
+		if err := s.handleMessage(message); err != nil {
+			return err
+		}
+	}
+}
+
+// keepAlive pings the connection, refreshes the listenKey and closes the connection when ctx is cancelled.
+func (s *UserDataStream) keepAlive(ctx context.Context, conn *websocket.Conn, listenKey string, done <-chan struct{}) {
+	ping := time.NewTicker(s.pingInterval)
+	defer ping.Stop()
+	refresh := time.NewTicker(s.listenKeyKeepAlive)
+	defer refresh.Stop()
+
+	for {
+		select {
+		case <-ping.C:
+			deadline := time.Now().Add(10 * time.Second)
+			if err := conn.WriteControl(websocket.PingMessage, nil, deadline); err != nil {
+				log.Printf("⚠️ Binance user data stream ping failed: %v", err)
+				conn.Close()
+				return
+			}
+		case <-refresh.C:
+			if err := s.client.KeepAliveListenKey(listenKey); err != nil {
+				// The key expires after 60 minutes, so a few failed refreshes are harmless
+				log.Printf("⚠️ Error refreshing listenKey: %v", err)
+			}
+		case <-ctx.Done():
+			conn.WriteControl(websocket.CloseMessage,
+				websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""),
+				time.Now().Add(time.Second))
+			conn.Close()
+			return
+		case <-done:
+			return
+		}
+	}
+}
+
+// handleMessage applies a user data stream event. It returns an error if the
+// connection must be re-established.
+func (s *UserDataStream) handleMessage(message []byte) error {
+	var header struct {
+		EventType string `json:"e"`
+		EventTime int64  `json:"E"`
+	}
+	if err := json.Unmarshal(message, &header); err != nil {
+		log.Printf("⚠️ Ignoring Binance user data message: %v", err)
+		return nil
+	}
+
+	switch header.EventType {
+	case "outboundAccountPosition":
+		var event accountPositionEvent
+		if err := json.Unmarshal(message, &event); err != nil {
+			log.Printf("⚠️ Ignoring outboundAccountPosition: %v", err)
+			return nil
+		}
+		s.applyAccountPosition(&event)
+	case "executionReport":
+		var event executionReportEvent
+		if err := json.Unmarshal(message, &event); err != nil {
+			log.Printf("⚠️ Ignoring executionReport: %v", err)
+			return nil
+		}
+		update := event.toOrderUpdate()
+		if update.IsFill() {
+			s.learnPrice(update)
+		}
+		if s.handler != nil {
+			s.handler(update)
+		}
+	case "listenKeyExpired":
+		return fmt.Errorf("listenKey expired")
+	}
+	return nil
+}
+
+// setSnapshot replaces the account snapshot and marks the stream connected.
+func (s *UserDataStream) setSnapshot(balance *AccountBalance) {
+	s.stateMux.Lock()
+	defer s.stateMux.Unlock()
+
+	for _, asset := range balance.Assets {
+		if asset.Total > 0 && asset.ValueUSD > 0 {
+			s.prices[asset.Symbol] = asset.ValueUSD / asset.Total
+		}
+	}
+	s.snapshot = balance
+	s.connected = true
+}
+
+// setConnected updates the connection state.
+func (s *UserDataStream) setConnected(connected bool) {
+	s.stateMux.Lock()
+	defer s.stateMux.Unlock()
+	s.connected = connected
+}
+
+// learnPrice remembers the USD price of an asset traded against USDT, so balances
+// of assets that were bought after the snapshot was loaded can be valued.
+func (s *UserDataStream) learnPrice(update *OrderUpdate) {
+	base, quote, err := SplitSymbol(update.Symbol)
+	if err != nil || quote != "USDT" || update.LastPrice <= 0 {
+		return
+	}
+
+	s.stateMux.Lock()
+	defer s.stateMux.Unlock()
+	s.prices[base] = update.LastPrice
+}
+
+// applyAccountPosition updates the balances changed by an outboundAccountPosition event.
+// Assets are valued at their last known price until Snapshot revalues them; the 24h
+// change and price route are kept from the snapshot.
+func (s *UserDataStream) applyAccountPosition(event *accountPositionEvent) {
+	s.stateMux.Lock()
+	defer s.stateMux.Unlock()
+
+	if s.snapshot == nil {
+		return
+	}
+
+	for _, position := range event.Balances {
+		if !s.tracks(position.Asset) {
+			continue
+		}
+
+		free := stringToFloat64(position.Free)
+		locked := stringToFloat64(position.Locked)
+		updated := AssetBalance{
+			Symbol:   position.Asset,
+			Free:     fmt.Sprintf("%.8f", free),
+			Locked:   fmt.Sprintf("%.8f", locked),
+			Total:    free + locked,
+			ValueUSD: (free + locked) * s.prices[position.Asset],
+		}
+
+		found := false
+		for i := range s.snapshot.Assets {
+			if s.snapshot.Assets[i].Symbol == position.Asset {
+				updated.Change24h = s.snapshot.Assets[i].Change24h
+				updated.PriceRoute = s.snapshot.Assets[i].PriceRoute
+				s.snapshot.Assets[i] = updated
+				found = true
+				break
+			}
+		}
+		if !found {
+			s.snapshot.Assets = append(s.snapshot.Assets, updated)
+		}
+	}
+
+	s.snapshot.TotalBalance, s.snapshot.AvailableBalance = balanceTotals(s.snapshot.Assets)
+	s.snapshot.LastUpdated = time.UnixMilli(event.EventTime)
+}
+
+// balanceTotals sums the value of assets, and the value of their free part.
+func balanceTotals(assets []AssetBalance) (total, available float64) {
+	for _, asset := range assets {
+		total += asset.ValueUSD
+		if asset.Total > 0 {
+			available += asset.ValueUSD * stringToFloat64(asset.Free) / asset.Total
+		}
+	}
+	return total, available
+}
+
+// accountPositionEvent is an outboundAccountPosition event payload.
+type accountPositionEvent struct {
+	EventType  string `json:"e"`
+	EventTime  int64  `json:"E"`
+	UpdateTime int64  `json:"u"`
+	Balances   []struct {
+		Asset  string `json:"a"`
+		Free   string `json:"f"`
+		Locked string `json:"l"`
+	} `json:"B"`
+}
+
+// executionReportEvent is an executionReport event payload. encoding/json matches keys
+// case-insensitively, so the fields whose key differs only in case from a used one are
+// declared too, otherwise they would overwrite it.
+type executionReportEvent struct {
+	EventType         string `json:"e"`
+	EventTime         int64  `json:"E"`
+	Symbol            string `json:"s"`
+	Side              string `json:"S"`
+	ClientOrderID     string `json:"c"`
+	OrigClientOrderID string `json:"C"`
+	OrderType         string `json:"o"`
+	CreationTime      int64  `json:"O"`
+	TimeInForce       string `json:"f"`
+	IcebergQty        string `json:"F"`
+	Quantity          string `json:"q"`
+	QuoteOrderQty     string `json:"Q"`
+	Price             string `json:"p"`
+	StopPrice         string `json:"P"`
+	ExecutionType     string `json:"x"`
+	Status            string `json:"X"`
+	RejectReason      string `json:"r"`
+	OrderID           int64  `json:"i"`
+	Ignore            int64  `json:"I"`
+	LastQty           string `json:"l"`
+	LastPrice         string `json:"L"`
+	CumulativeQty     string `json:"z"`
+	CumulativeQuote   string `json:"Z"`
+	Commission        string `json:"n"`
+	CommissionAsset   string `json:"N"`
+	TransactionTime   int64  `json:"T"`
+	TradeID           int64  `json:"t"`
+}
+
+// toOrderUpdate converts the event to an OrderUpdate.
+func (e *executionReportEvent) toOrderUpdate() *OrderUpdate {
+	update := &OrderUpdate{
+		Symbol:             e.Symbol,
+		OrderID:            e.OrderID,
+		ClientOrderID:      e.ClientOrderID,
+		Side:               e.Side,
+		Type:               e.OrderType,
+		Quantity:           stringToFloat64(e.Quantity),
+		Price:              stringToFloat64(e.Price),
+		ExecutionType:      e.ExecutionType,
+		Status:             e.Status,
+		EventTime:          time.UnixMilli(e.EventTime),
+		LastQty:            stringToFloat64(e.LastQty),
+		LastPrice:          stringToFloat64(e.LastPrice),
+		CumulativeQty:      stringToFloat64(e.CumulativeQty),
+		CumulativeQuoteQty: stringToFloat64(e.CumulativeQuote),
+		Commission:         stringToFloat64(e.Commission),
+		CommissionAsset:    e.CommissionAsset,
+	}
+	if e.RejectReason != "NONE" {
+		update.RejectReason = e.RejectReason
+	}
+	if e.TradeID > 0 {
+		update.TradeID = e.TradeID
+	}
+	// Canceled orders keep the client order ID of the cancel request in c
+	if e.ExecutionType == ExecutionTypeCanceled && e.OrigClientOrderID != "" {
+		update.ClientOrderID = e.OrigClientOrderID
+	}
+	return update
+}
+
+// CreateListenKey starts a user data stream and returns its listenKey.
+// The endpoint only needs the API key, not a signature.
+func (c *BinanceClient) CreateListenKey() (string, error) {
+	var response struct {
+		ListenKey string `json:"listenKey"`
+	}
+
+	resp, err := c.httpClient.R().
+		SetResult(&response).
+		Post("/api/v3/userDataStream")
+	if err != nil {
+		return "", fmt.Errorf("error creating listenKey: %w", err)
+	}
+	if resp.StatusCode() != 200 {
+		return "", NewBinanceError(resp.StatusCode(), resp.String())
+	}
+	return response.ListenKey, nil
+}
+
+// KeepAliveListenKey extends the validity of a listenKey by 60 minutes.
+func (c *BinanceClient) KeepAliveListenKey(listenKey string) error {
+	return c.listenKeyRequest(resty.MethodPut, listenKey)
+}
+
+// CloseListenKey closes a user data stream.
+func (c *BinanceClient) CloseListenKey(listenKey string) error {
+	return c.listenKeyRequest(resty.MethodDelete, listenKey)
+}
+
+// listenKeyRequest sends a listenKey keepalive or close request.
+func (c *BinanceClient) listenKeyRequest(method, listenKey string) error {
+	resp, err := c.httpClient.R().
+		SetQueryParam("listenKey", listenKey).
+		Execute(method, "/api/v3/userDataStream")
+	if err != nil {
+		return fmt.Errorf("error updating listenKey: %w", err)
+	}
+	if resp.StatusCode() != 200 {
+		return NewBinanceError(resp.StatusCode(), resp.String())
+	}
+	return nil
+}
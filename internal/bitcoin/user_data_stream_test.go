@@ -0,0 +1,106 @@
+package bitcoin
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testExecutionReport = `{"e":"executionReport","E":1700000000000,"s":"BTCUSDT","c":"web_1","S":"SELL","o":"LIMIT","f":"GTC","q":"0.50000000","p":"60000.00","P":"0.00","F":"0.00","g":-1,"C":"","x":"TRADE","X":"FILLED","r":"NONE","i":42,"l":"0.50000000","z":"0.50000000","L":"60000.00","n":"30.00","N":"USDT","T":1700000000000,"t":7,"I":99,"w":false,"m":true,"M":true,"O":1699999990000,"Z":"30000.00","Y":"30000.00","Q":"0.00"}`
+	testAccountPosition = `{"e":"outboundAccountPosition","E":1700000000001,"u":1700000000000,"B":[{"a":"BTC","f":"0.50000000","l":"0.00000000"},{"a":"USDT","f":"30070.00","l":"0.00000000"}]}`
+)
+
+func TestUserDataStream_AppliesEvents(t *testing.T) {
+	upgrader := websocket.Upgrader{}
+	var btcPrice atomic.Int64
+	btcPrice.Store(50000)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/ws/test-listen-key" {
+			conn, err := upgrader.Upgrade(w, r, nil)
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+			conn.WriteMessage(websocket.TextMessage, []byte(testExecutionReport))
+			conn.WriteMessage(websocket.TextMessage, []byte(testAccountPosition))
+			// Keep the connection open until the client goes away
+			for {
+				if _, _, err := conn.ReadMessage(); err != nil {
+					return
+				}
+			}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/v3/time":
+			fmt.Fprintf(w, `{"serverTime":%d}`, time.Now().UnixMilli())
+		case "/api/v3/userDataStream":
+			w.Write([]byte(`{"listenKey":"test-listen-key"}`))
+		case "/api/v3/account":
+			w.Write([]byte(`{"canTrade":true,"balances":[{"asset":"BTC","free":"1.0","locked":"0"},{"asset":"USDT","free":"100","locked":"0"},{"asset":"ETH","free":"2","locked":"0"}]}`))
+		case "/api/v3/ticker/24hr":
+			fmt.Fprintf(w, `[{"symbol":"BTCUSDT","lastPrice":"%d","priceChangePercent":"2.0"}]`, btcPrice.Load())
+		}
+	}))
+	defer server.Close()
+
+	TickerCacheFor(server.URL).SetTTL(time.Nanosecond)
+	client := NewBinanceClient("key", "secret", server.URL, nil)
+	stream := NewUserDataStream(client, "ws"+strings.TrimPrefix(server.URL, "http"), []string{"BTC", "USDT"})
+
+	updates := make(chan *OrderUpdate, 1)
+	stream.OnOrderUpdate(func(update *OrderUpdate) {
+		updates <- update
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go stream.Run(ctx)
+
+	select {
+	case update := <-updates:
+		assert.True(t, update.IsFill())
+		assert.Equal(t, int64(42), update.OrderID)
+		assert.Equal(t, "web_1", update.ClientOrderID)
+		assert.Equal(t, SideSell, update.Side)
+		assert.Equal(t, OrderStatusFilled, update.Status)
+		assert.Equal(t, 0.5, update.LastQty)
+		assert.Equal(t, 60000.0, update.LastPrice)
+		assert.Equal(t, "USDT", update.CommissionAsset)
+		assert.Empty(t, update.RejectReason)
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for the order update")
+	}
+
+	// The remaining BTC is valued at the market price, USDT at par
+	require.Eventually(t, func() bool {
+		balance, ok := stream.Snapshot(nil)
+		return ok && balance.TotalBalance == 55070
+	}, 2*time.Second, 10*time.Millisecond)
+
+	// Without new account events the value still follows the market
+	btcPrice.Store(62000)
+	balance, ok := stream.Snapshot([]string{"btc"})
+	require.True(t, ok)
+	require.Len(t, balance.Assets, 1)
+	assert.Equal(t, 0.5, balance.Assets[0].Total)
+	assert.Equal(t, 31000.0, balance.Assets[0].ValueUSD)
+	assert.Equal(t, 2.0, balance.Assets[0].Change24h)
+	assert.Equal(t, "BTCUSDT", balance.Assets[0].PriceRoute)
+
+	// ETH isn't tracked by the stream, so the caller has to ask Binance
+	_, ok = stream.Snapshot([]string{"ETH"})
+	assert.False(t, ok)
+}
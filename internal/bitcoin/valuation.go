@@ -0,0 +1,203 @@
+// Package bitcoin provides functionality for interacting with cryptocurrency APIs.
+package bitcoin
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultTickerCacheTTL is how long a bulk 24hr ticker fetch is reused for valuations.
+const DefaultTickerCacheTTL = 10 * time.Second
+
+// valuationQuote is the asset account balances are valued in, taken as 1:1 with USD.
+const valuationQuote = "USDT"
+
+// valuationBridges are tried in order for assets that have no pair against valuationQuote.
+var valuationBridges = []string{"BTC", "BUSD"}
+
+var (
+	tickerCaches    = make(map[string]*TickerCache)
+	tickerCachesMux sync.Mutex
+)
+
+// AssetQuote is the USD price and 24h change of an asset, and the pairs used to derive them.
+type AssetQuote struct {
+	Price     float64 `json:"price"`
+	Change24h float64 `json:"change_24h"`
+	Route     string  `json:"route"` // e.g. "BTCUSDT" or "ETHBTC>BTCUSDT"
+}
+
+// TickerCache keeps the last bulk /api/v3/ticker/24hr response for a short time, so
+// valuing an account costs one request no matter how many assets it holds. Like
+// WeightLimiter, one cache is shared by every client of the same base URL.
+//
+// Example usage:
+//
+//	TickerCacheFor(cfg.BinanceBaseURL).SetTTL(5 * time.Second)
+//	quotes, err := client.GetAssetQuotes([]string{"BTC", "ETH", "USDT"})
+type TickerCache struct {
+	ttl time.Duration
+
+	tickers   map[string]Ticker24hResponse
+	fetchedAt time.Time
+	mux       sync.Mutex
+}
+
+// NewTickerCache creates a ticker cache. A non-positive ttl uses DefaultTickerCacheTTL.
+func NewTickerCache(ttl time.Duration) *TickerCache {
+	if ttl <= 0 {
+		ttl = DefaultTickerCacheTTL
+	}
+	return &TickerCache{ttl: ttl}
+}
+
+// TickerCacheFor returns the shared ticker cache for a Binance base URL, creating it if needed.
+// An empty baseURL refers to the production API.
+func TickerCacheFor(baseURL string) *TickerCache {
+	if baseURL == "" {
+		baseURL = DefaultBinanceBaseURL
+	}
+	baseURL = strings.TrimRight(baseURL, "/")
+
+	tickerCachesMux.Lock()
+	defer tickerCachesMux.Unlock()
+
+	cache, ok := tickerCaches[baseURL]
+	if !ok {
+		cache = NewTickerCache(DefaultTickerCacheTTL)
+		tickerCaches[baseURL] = cache
+	}
+	return cache
+}
+
+// SetTTL changes how long a fetch is reused. Non-positive values are ignored.
+func (t *TickerCache) SetTTL(ttl time.Duration) {
+	if ttl <= 0 {
+		return
+	}
+	t.mux.Lock()
+	defer t.mux.Unlock()
+	t.ttl = ttl
+}
+
+// get returns the 24hr ticker of every symbol, fetching them with client when the
+// cached copy has expired. Concurrent callers wait for a single fetch.
+func (t *TickerCache) get(client *BinanceClient) (map[string]Ticker24hResponse, error) {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+
+	if t.tickers != nil && time.Since(t.fetchedAt) < t.ttl {
+		return t.tickers, nil
+	}
+
+	var response []Ticker24hResponse
+	resp, err := client.httpClient.R().
+		SetResult(&response).
+		Get("/api/v3/ticker/24hr")
+	if err != nil {
+		return nil, fmt.Errorf("error fetching tickers from Binance: %w", err)
+	}
+	if resp.StatusCode() != 200 {
+		return nil, NewBinanceError(resp.StatusCode(), resp.String())
+	}
+
+	tickers := make(map[string]Ticker24hResponse, len(response))
+	for _, ticker := range response {
+		tickers[ticker.Symbol] = ticker
+	}
+	t.tickers = tickers
+	t.fetchedAt = time.Now()
+	return tickers, nil
+}
+
+// GetAssetQuotes returns the USD price and 24h change of each asset from one bulk ticker
+// request (cached for a few seconds, see TickerCache). Assets without a USDT pair are
+// valued through BTC or BUSD, e.g. ETHBTC × BTCUSDT, or through the inverse pair
+// (USDT<asset>). Assets with no route at all are left out of the result.
+//
+// Example usage:
+//
+//	quotes, err := client.GetAssetQuotes([]string{"BTC", "ETH"})
+//	if err != nil {
+//	    return err
+//	}
+//	fmt.Printf("ETH: $%.2f via %s\n", quotes["ETH"].Price, quotes["ETH"].Route)
+func (c *BinanceClient) GetAssetQuotes(assets []string) (map[string]AssetQuote, error) {
+	tickers, err := c.tickers.get(c)
+	if err != nil {
+		return nil, err
+	}
+
+	quotes := make(map[string]AssetQuote, len(assets))
+	for _, asset := range assets {
+		asset = strings.ToUpper(strings.TrimSpace(asset))
+		if quote, ok := quoteAsset(tickers, asset); ok {
+			quotes[asset] = quote
+		} else {
+			log.Printf("⚠️ No %s, BTC or BUSD market to value %s", valuationQuote, asset)
+		}
+	}
+	return quotes, nil
+}
+
+// quoteAsset values asset in USDT using the direct pair, a bridge asset or the inverse pair.
+func quoteAsset(tickers map[string]Ticker24hResponse, asset string) (AssetQuote, bool) {
+	if asset == valuationQuote {
+		return AssetQuote{Price: 1, Route: valuationQuote}, true
+	}
+
+	if quote, ok := pairQuote(tickers, asset+valuationQuote); ok {
+		return quote, true
+	}
+
+	for _, bridge := range valuationBridges {
+		if bridge == asset {
+			continue
+		}
+		first, ok := pairQuote(tickers, asset+bridge)
+		if !ok {
+			continue
+		}
+		second, ok := pairQuote(tickers, bridge+valuationQuote)
+		if !ok {
+			continue
+		}
+		return AssetQuote{
+			Price:     first.Price * second.Price,
+			Change24h: ((1+first.Change24h/100)*(1+second.Change24h/100) - 1) * 100,
+			Route:     first.Route + ">" + second.Route,
+		}, true
+	}
+
+	// Fiat and other quote-side assets are only listed as USDT<asset>
+	if inverse, ok := pairQuote(tickers, valuationQuote+asset); ok {
+		return AssetQuote{
+			Price:     1 / inverse.Price,
+			Change24h: (1/(1+inverse.Change24h/100) - 1) * 100,
+			Route:     "1/" + inverse.Route,
+		}, true
+	}
+
+	return AssetQuote{}, false
+}
+
+// pairQuote returns the last price and 24h change of a trading pair, if it trades.
+func pairQuote(tickers map[string]Ticker24hResponse, symbol string) (AssetQuote, bool) {
+	ticker, ok := tickers[symbol]
+	if !ok {
+		return AssetQuote{}, false
+	}
+	price := stringToFloat64(ticker.LastPrice)
+	if price <= 0 {
+		// Delisted pairs are still reported, with a zero price
+		return AssetQuote{}, false
+	}
+	return AssetQuote{
+		Price:     price,
+		Change24h: stringToFloat64(ticker.PriceChangePercent),
+		Route:     symbol,
+	}, true
+}
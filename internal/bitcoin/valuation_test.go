@@ -0,0 +1,52 @@
+package bitcoin
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetAssetQuotes(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[
+			{"symbol":"BTCUSDT","lastPrice":"50000.00","priceChangePercent":"10.0"},
+			{"symbol":"ETHBTC","lastPrice":"0.05","priceChangePercent":"-10.0"},
+			{"symbol":"XYZBUSD","lastPrice":"2.00","priceChangePercent":"0.0"},
+			{"symbol":"BUSDUSDT","lastPrice":"1.00","priceChangePercent":"0.0"},
+			{"symbol":"USDTTRY","lastPrice":"32.00","priceChangePercent":"0.0"},
+			{"symbol":"OLDUSDT","lastPrice":"0.00000000","priceChangePercent":"0.0"}
+		]`))
+	}))
+	defer server.Close()
+
+	client := NewBinanceClient("", "", server.URL, nil)
+
+	quotes, err := client.GetAssetQuotes([]string{"BTC", "ETH", "XYZ", "TRY", "USDT", "OLD", "COP"})
+	require.NoError(t, err)
+
+	assert.Equal(t, AssetQuote{Price: 50000, Change24h: 10, Route: "BTCUSDT"}, quotes["BTC"])
+	assert.Equal(t, "ETHBTC>BTCUSDT", quotes["ETH"].Route)
+	assert.InDelta(t, 2500, quotes["ETH"].Price, 1e-9)
+	assert.InDelta(t, -1, quotes["ETH"].Change24h, 1e-9)
+	assert.Equal(t, "XYZBUSD>BUSDUSDT", quotes["XYZ"].Route)
+	assert.Equal(t, 2.0, quotes["XYZ"].Price)
+	assert.Equal(t, "1/USDTTRY", quotes["TRY"].Route)
+	assert.InDelta(t, 1.0/32, quotes["TRY"].Price, 1e-12)
+	assert.Equal(t, 1.0, quotes["USDT"].Price)
+
+	// Delisted pairs and assets without any market are not priced at 0
+	assert.NotContains(t, quotes, "OLD")
+	assert.NotContains(t, quotes, "COP")
+
+	// The bulk response is reused within the TTL
+	_, err = client.GetAssetQuotes([]string{"BTC"})
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&requests))
+}
@@ -76,6 +76,11 @@ var (
 		Message: "Price API rate limit exceeded",
 	}
 
+	ErrPriceQuorumNotMet = &AppError{
+		Code:    "PRICE_QUORUM_NOT_MET",
+		Message: "Not enough price sources agree on the price",
+	}
+
 	// Database related errors
 	ErrDatabaseConnection = &AppError{
 		Code:    "DATABASE_CONNECTION",
@@ -0,0 +1,144 @@
+package expression
+
+import (
+	"fmt"
+	"math"
+)
+
+// Env holds the values an expression is evaluated with.
+type Env struct {
+	Variables  map[string]float64 // By variable name
+	Indicators map[string]float64 // By IndicatorRef.Key
+}
+
+// Eval evaluates the expression. && and || only evaluate their right side when
+// needed, so a missing value there doesn't matter when the left side decides.
+// Otherwise missing values are an error. Division by zero gives NaN or ±Inf, and
+// comparisons with NaN are false.
+//
+// Example usage:
+//
+//	ok, err := program.Eval(expression.Env{Variables: map[string]float64{"price": 59000}})
+func (p *Program) Eval(env Env) (bool, error) {
+	return evalBool(p.root, env)
+}
+
+func evalBool(n node, env Env) (bool, error) {
+	switch n := n.(type) {
+	case boolNode:
+		return n.value, nil
+
+	case unaryNode:
+		x, err := evalBool(n.x, env)
+		return !x, err
+
+	case binaryNode:
+		switch n.op {
+		case "&&", "||":
+			left, err := evalBool(n.left, env)
+			if err != nil || left == (n.op == "||") {
+				return left, err
+			}
+			return evalBool(n.right, env)
+		case "==", "!=":
+			if n.left.kind() == kindBool {
+				left, err := evalBool(n.left, env)
+				if err != nil {
+					return false, err
+				}
+				right, err := evalBool(n.right, env)
+				return (left == right) == (n.op == "=="), err
+			}
+		}
+
+		left, err := evalNumber(n.left, env)
+		if err != nil {
+			return false, err
+		}
+		right, err := evalNumber(n.right, env)
+		if err != nil {
+			return false, err
+		}
+		switch n.op {
+		case "<":
+			return left < right, nil
+		case "<=":
+			return left <= right, nil
+		case ">":
+			return left > right, nil
+		case ">=":
+			return left >= right, nil
+		case "==":
+			return left == right, nil
+		case "!=":
+			return left != right, nil
+		}
+	}
+	return false, fmt.Errorf("expression node %T isn't a condition", n)
+}
+
+func evalNumber(n node, env Env) (float64, error) {
+	switch n := n.(type) {
+	case numberNode:
+		return n.value, nil
+
+	case variableNode:
+		value, ok := env.Variables[n.name]
+		if !ok {
+			return 0, fmt.Errorf("no value for %s", n.name)
+		}
+		return value, nil
+
+	case indicatorNode:
+		value, ok := env.Indicators[n.key]
+		if !ok {
+			return 0, fmt.Errorf("no value for %s", n.key)
+		}
+		return value, nil
+
+	case unaryNode:
+		x, err := evalNumber(n.x, env)
+		return -x, err
+
+	case callNode:
+		args := make([]float64, len(n.args))
+		for i, arg := range n.args {
+			value, err := evalNumber(arg, env)
+			if err != nil {
+				return 0, err
+			}
+			args[i] = value
+		}
+		switch n.name {
+		case "abs":
+			return math.Abs(args[0]), nil
+		case "min":
+			return math.Min(args[0], args[1]), nil
+		case "max":
+			return math.Max(args[0], args[1]), nil
+		}
+
+	case binaryNode:
+		left, err := evalNumber(n.left, env)
+		if err != nil {
+			return 0, err
+		}
+		right, err := evalNumber(n.right, env)
+		if err != nil {
+			return 0, err
+		}
+		switch n.op {
+		case "+":
+			return left + right, nil
+		case "-":
+			return left - right, nil
+		case "*":
+			return left * right, nil
+		case "/":
+			return left / right, nil
+		case "%":
+			return math.Mod(left, right), nil
+		}
+	}
+	return 0, fmt.Errorf("expression node %T isn't a number", n)
+}
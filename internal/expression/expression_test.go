@@ -0,0 +1,102 @@
+package expression
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpression_Eval(t *testing.T) {
+	env := Env{
+		Variables:  map[string]float64{"price": 59000, "change_24h": -6, "hour_utc": 14, "volume_24h": 1200},
+		Indicators: map[string]float64{`rsi(14,"1h")`: 27.5, `ema(9,"15m")`: 59100, `ema(21,"15m")`: 58900},
+	}
+
+	tests := []struct {
+		source   string
+		expected bool
+	}{
+		{"price < 60000 && change_24h < -5 && hour_utc >= 13", true},
+		{"price < 60000 && change_24h < -7", false},
+		{"price > 70000 || hour_utc == 14", true},
+		{"!(price > 70000)", true},
+		{`rsi(14, "1h") < 30 && ema(9, "15m") > ema(21, "15m")`, true},
+		{`rsi(14) < 30`, true}, // The interval defaults to 1h
+		{"abs(change_24h) >= 5 && max(price, 1) == 59000 && min(2, 3) == 2", true},
+		{"price * 2 - 1000 / 10 == 117900", true},
+		{"hour_utc % 2 == 0 && -change_24h > 5", true},
+		{"(price < 60000) == true", true},
+		{"price / 0 > 1", true}, // +Inf
+		{"(price - price) / 0 > 1", false},
+		// The right side isn't evaluated, so its missing value doesn't matter
+		{"price > 70000 && trades_24h > 1", false},
+		{"price < 70000 || trades_24h > 1", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.source, func(t *testing.T) {
+			program, err := Compile(tt.source)
+			require.NoError(t, err)
+			result, err := program.Eval(env)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+
+	program, err := Compile("trades_24h > 1000")
+	require.NoError(t, err)
+	_, err = program.Eval(env)
+	assert.EqualError(t, err, "no value for trades_24h")
+}
+
+func TestExpression_CompileErrors(t *testing.T) {
+	tests := []struct {
+		source  string
+		column  int
+		message string
+	}{
+		{"", 1, "empty"},
+		{"price < 60000 && chnage_24h < -5", 18, `unknown variable "chnage_24h"`},
+		{"price < 60000 & hour_utc > 1", 15, `did you mean "&&"?`},
+		{"price < 60000 and hour_utc > 1", 15, `"and" isn't an operator, use &&`},
+		{"price + 1", 1, "must be a condition"},
+		{"price && hour_utc > 1", 1, "&& needs a condition, not a number"},
+		{"1 < price < 2", 11, "can't be chained"},
+		{"(price < 1", 11, `expected ")" to close the "(" at column 1`},
+		{`rsi(14, "2m") < 30`, 9, "interval must be one of"},
+		{"rsi(0) < 30", 5, "periods must be whole numbers"},
+		{"rsi(price) < 30", 5, "constant arguments"},
+		{"macd(26, 12) > 0", 6, "slow period must be greater"},
+		{"pow(price, 2) > 1", 1, `unknown function "pow"`},
+		{"rsi < 30", 1, `rsi is a function, e.g. rsi(period, "1h")`},
+		{`price < "60000"`, 9, "strings can only be the interval"},
+		{"price < 60000 €", 15, "unexpected character"},
+		{"min(price) < 1", 10, "min takes 2 arguments"},
+		{strings.Repeat("(", MaxDepth+1) + "price < 1" + strings.Repeat(")", MaxDepth+1), MaxDepth + 1, "nested more than"},
+		{strings.Repeat("x", MaxLength+1), MaxLength + 1, "longer than"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.source, func(t *testing.T) {
+			_, err := Compile(tt.source)
+			require.Error(t, err)
+			var compileErr *Error
+			require.ErrorAs(t, err, &compileErr)
+			assert.Equal(t, tt.column, compileErr.Column, compileErr.Message)
+			assert.Contains(t, compileErr.Message, tt.message)
+		})
+	}
+}
+
+func TestExpression_References(t *testing.T) {
+	program, err := Compile(`price < bb_lower(20, 2, "4h") && rsi(14) < 30 && rsi(14, "1h") < 35 && volume_24h > 0`)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"price", "volume_24h"}, program.Variables())
+	require.Len(t, program.Indicators(), 2)
+	assert.Equal(t, `bb_lower(20,2,"4h")`, program.Indicators()[0].Key())
+	assert.Equal(t, 9, program.Indicators()[0].Column)
+	assert.Equal(t, `rsi(14,"1h")`, program.Indicators()[1].Key())
+}
@@ -0,0 +1,157 @@
+package expression
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Limits of an expression, which keep compiling and evaluating it cheap.
+const (
+	MaxLength     = 1000 // Characters
+	MaxNodes      = 200  // Operators, operands and calls
+	MaxDepth      = 32   // Nesting of parentheses, operators and calls
+	MaxIndicators = 10   // Distinct indicator calls
+
+	// MaxPeriod is the longest indicator period, as in indicator alerts.
+	MaxPeriod = 200
+
+	// DefaultInterval is the candle interval of indicator calls without one.
+	DefaultInterval = "1h"
+)
+
+// Intervals are the candle intervals indicator calls can use.
+var Intervals = []string{"1m", "3m", "5m", "15m", "30m", "1h", "2h", "4h", "6h", "8h", "12h", "1d"}
+
+// Variable is a variable expressions can use.
+type Variable struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// Variables are the variables expressions can use: the tick (PriceData), its time and
+// the Binance 24h ticker behind it (TickerData). Ticks without a ticker, e.g. from
+// other exchanges, have no value for the *_24h ticker variables.
+var Variables = []Variable{
+	{"price", "Price of the tick, in the alert currency"},
+	{"prev_price", "Price of the previous tick of the symbol, in the alert currency"},
+	{"change_24h", "Price change over the last 24h, in %"},
+	{"hour_utc", "Hour of the tick, 0 to 23 (UTC)"},
+	{"minute_utc", "Minute of the tick, 0 to 59"},
+	{"weekday_utc", "Day of the week of the tick, 0 (Sunday) to 6 (UTC)"},
+	{"price_change_24h", "Price change over the last 24h, in USD"},
+	{"open_24h", "Price 24h ago, in USD"},
+	{"high_24h", "Highest price of the last 24h, in USD"},
+	{"low_24h", "Lowest price of the last 24h, in USD"},
+	{"prev_close_24h", "Close of the previous 24h window, in USD"},
+	{"avg_price_24h", "Volume weighted average price of the last 24h, in USD"},
+	{"volume_24h", "Traded volume of the last 24h, in the base asset"},
+	{"quote_volume_24h", "Traded volume of the last 24h, in the quote asset"},
+	{"trades_24h", "Number of trades of the last 24h"},
+}
+
+// Function is a function expressions can call.
+type Function struct {
+	Name        string `json:"name"`
+	Signature   string `json:"signature"`
+	Description string `json:"description"`
+
+	indicator bool                   // Computed on candles, with constant arguments and an optional interval
+	arity     int                    // Numeric arguments
+	check     func([]float64) string // Checks constant arguments, returns the problem or ""
+}
+
+// Functions are the functions expressions can call. Indicator functions take constant
+// arguments and an optional candle interval, "1h" by default, and are computed on the
+// candle still forming, like indicator alerts.
+var Functions = []Function{
+	{Name: "abs", Signature: "abs(x)", Description: "Absolute value", arity: 1},
+	{Name: "min", Signature: "min(a, b)", Description: "Smaller of two numbers", arity: 2},
+	{Name: "max", Signature: "max(a, b)", Description: "Larger of two numbers", arity: 2},
+	{Name: "sma", Signature: `sma(period, "1h")`, Description: "Simple moving average of the close", indicator: true, arity: 1, check: checkPeriods},
+	{Name: "ema", Signature: `ema(period, "1h")`, Description: "Exponential moving average of the close", indicator: true, arity: 1, check: checkPeriods},
+	{Name: "rsi", Signature: `rsi(period, "1h")`, Description: "Relative strength index, 0 to 100", indicator: true, arity: 1, check: checkPeriods},
+	{Name: "atr", Signature: `atr(period, "1h")`, Description: "Average true range", indicator: true, arity: 1, check: checkPeriods},
+	{Name: "macd", Signature: `macd(fast, slow, "1h")`, Description: "MACD line (signal period 9)", indicator: true, arity: 2, check: checkFastSlow},
+	{Name: "macd_signal", Signature: `macd_signal(fast, slow, "1h")`, Description: "MACD signal line", indicator: true, arity: 2, check: checkFastSlow},
+	{Name: "macd_hist", Signature: `macd_hist(fast, slow, "1h")`, Description: "MACD histogram (MACD - signal)", indicator: true, arity: 2, check: checkFastSlow},
+	{Name: "bb_upper", Signature: `bb_upper(period, deviations, "1h")`, Description: "Upper Bollinger band", indicator: true, arity: 2, check: checkBollinger},
+	{Name: "bb_middle", Signature: `bb_middle(period, deviations, "1h")`, Description: "Middle Bollinger band", indicator: true, arity: 2, check: checkBollinger},
+	{Name: "bb_lower", Signature: `bb_lower(period, deviations, "1h")`, Description: "Lower Bollinger band", indicator: true, arity: 2, check: checkBollinger},
+	{Name: "volume_avg", Signature: `volume_avg(period, "1h")`, Description: "Average volume of the last closed candles", indicator: true, arity: 1, check: checkPeriods},
+	{Name: "candle_volume", Signature: `candle_volume("1h")`, Description: "Volume of the last closed candle", indicator: true},
+}
+
+// IndicatorRef is an indicator call of an expression, e.g. rsi(14, "1h").
+type IndicatorRef struct {
+	Name     string    `json:"name"`
+	Args     []float64 `json:"args"`
+	Interval string    `json:"interval"`
+	Column   int       `json:"column"`
+}
+
+// Key identifies the call in Env.Indicators, e.g. `rsi(14,"1h")`.
+func (r IndicatorRef) Key() string {
+	parts := make([]string, 0, len(r.Args)+1)
+	for _, arg := range r.Args {
+		parts = append(parts, strconv.FormatFloat(arg, 'g', -1, 64))
+	}
+	parts = append(parts, strconv.Quote(r.Interval))
+	return fmt.Sprintf("%s(%s)", r.Name, strings.Join(parts, ","))
+}
+
+func lookupVariable(name string) bool {
+	for _, variable := range Variables {
+		if variable.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func lookupFunction(name string) *Function {
+	for i := range Functions {
+		if Functions[i].Name == name {
+			return &Functions[i]
+		}
+	}
+	return nil
+}
+
+func validInterval(interval string) bool {
+	for _, valid := range Intervals {
+		if interval == valid {
+			return true
+		}
+	}
+	return false
+}
+
+func checkPeriods(args []float64) string {
+	for _, period := range args {
+		if period != float64(int(period)) || period < 1 || period > MaxPeriod {
+			return fmt.Sprintf("periods must be whole numbers between 1 and %d", MaxPeriod)
+		}
+	}
+	return ""
+}
+
+func checkFastSlow(args []float64) string {
+	if problem := checkPeriods(args); problem != "" {
+		return problem
+	}
+	if args[1] <= args[0] {
+		return "the slow period must be greater than the fast one"
+	}
+	return ""
+}
+
+func checkBollinger(args []float64) string {
+	if problem := checkPeriods(args[:1]); problem != "" {
+		return problem
+	}
+	if args[1] <= 0 || args[1] > 5 {
+		return "deviations must be greater than 0 and at most 5"
+	}
+	return ""
+}
@@ -0,0 +1,152 @@
+// Package expression implements the sandboxed rule language of "expression" alerts,
+// e.g. `price < 60000 && change_24h < -5 && hour_utc >= 13`.
+//
+// Expressions are compiled once, which checks the syntax, the variable and function
+// names and the types, and then evaluated against an Env of variable and indicator
+// values. The language has no assignments, loops or user-defined functions: numbers,
+// booleans, the variables in Variables, arithmetic, comparisons, && || ! and the
+// whitelisted functions in Functions. Its size is bounded by MaxLength, MaxNodes and
+// MaxDepth, so evaluating an expression always takes a small, bounded time.
+//
+// Example usage:
+//
+//	program, err := expression.Compile(`price < 60000 && rsi(14, "1h") < 30`)
+//	if err != nil {
+//	    return err // *expression.Error with the column of the problem
+//	}
+//	ok, err := program.Eval(expression.Env{
+//	    Variables:  map[string]float64{"price": 59000},
+//	    Indicators: map[string]float64{program.Indicators()[0].Key(): 27.5},
+//	})
+package expression
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Error is a compile error, at a 1-based column of the expression.
+type Error struct {
+	Column  int    `json:"column"`
+	Message string `json:"message"`
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("column %d: %s", e.Column, e.Message)
+}
+
+func errorAt(column int, format string, args ...interface{}) *Error {
+	return &Error{Column: column, Message: fmt.Sprintf(format, args...)}
+}
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenNumber
+	tokenString
+	tokenIdent
+	tokenOperator // && || ! < <= > >= == != + - * / %
+	tokenLParen
+	tokenRParen
+	tokenComma
+)
+
+type token struct {
+	kind   tokenKind
+	text   string
+	number float64
+	column int
+}
+
+// describe names a token in error messages.
+func (t token) describe() string {
+	switch t.kind {
+	case tokenEOF:
+		return "end of expression"
+	case tokenString:
+		return strconv.Quote(t.text)
+	default:
+		return fmt.Sprintf("%q", t.text)
+	}
+}
+
+// operators are the operators, longest first so "<=" isn't read as "<".
+var operators = []string{"&&", "||", "<=", ">=", "==", "!=", "!", "<", ">", "+", "-", "*", "/", "%"}
+
+// tokenize splits an expression into tokens, ending with tokenEOF.
+func tokenize(source string) ([]token, error) {
+	runes := []rune(source)
+	var tokens []token
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		column := i + 1
+
+		switch {
+		case unicode.IsSpace(r):
+			i++
+
+		case unicode.IsDigit(r) || (r == '.' && i+1 < len(runes) && unicode.IsDigit(runes[i+1])):
+			start := i
+			for i < len(runes) && (unicode.IsDigit(runes[i]) || runes[i] == '.') {
+				i++
+			}
+			text := string(runes[start:i])
+			value, err := strconv.ParseFloat(text, 64)
+			if err != nil {
+				return nil, errorAt(column, "invalid number %q", text)
+			}
+			tokens = append(tokens, token{kind: tokenNumber, text: text, number: value, column: column})
+
+		case r == '_' || unicode.IsLetter(r):
+			start := i
+			for i < len(runes) && (runes[i] == '_' || unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i])) {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokenIdent, text: string(runes[start:i]), column: column})
+
+		case r == '"' || r == '\'':
+			end := i + 1
+			for end < len(runes) && runes[end] != r {
+				end++
+			}
+			if end == len(runes) {
+				return nil, errorAt(column, "unterminated string")
+			}
+			tokens = append(tokens, token{kind: tokenString, text: string(runes[i+1 : end]), column: column})
+			i = end + 1
+
+		case r == '(':
+			tokens = append(tokens, token{kind: tokenLParen, text: "(", column: column})
+			i++
+		case r == ')':
+			tokens = append(tokens, token{kind: tokenRParen, text: ")", column: column})
+			i++
+		case r == ',':
+			tokens = append(tokens, token{kind: tokenComma, text: ",", column: column})
+			i++
+
+		default:
+			matched := ""
+			for _, operator := range operators {
+				if strings.HasPrefix(string(runes[i:min(i+2, len(runes))]), operator) {
+					matched = operator
+					break
+				}
+			}
+			if matched == "" {
+				if r == '&' || r == '|' || r == '=' {
+					return nil, errorAt(column, "unexpected %q, did you mean %q?", string(r), strings.Repeat(string(r), 2))
+				}
+				return nil, errorAt(column, "unexpected character %q", string(r))
+			}
+			tokens = append(tokens, token{kind: tokenOperator, text: matched, column: column})
+			i += len([]rune(matched))
+		}
+	}
+
+	return append(tokens, token{kind: tokenEOF, column: len(runes) + 1}), nil
+}
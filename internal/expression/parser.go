@@ -0,0 +1,509 @@
+package expression
+
+import (
+	"fmt"
+	"strings"
+)
+
+type valueKind int
+
+const (
+	kindNumber valueKind = iota
+	kindBool
+)
+
+func (k valueKind) String() string {
+	if k == kindBool {
+		return "a condition"
+	}
+	return "a number"
+}
+
+// node is a node of the syntax tree. Its kind is checked when compiling, so
+// evaluation never meets a number where a condition is expected.
+type node interface {
+	kind() valueKind
+}
+
+type numberNode struct{ value float64 }
+
+type boolNode struct{ value bool }
+
+type variableNode struct{ name string }
+
+type indicatorNode struct{ key string }
+
+type callNode struct {
+	name string
+	args []node
+}
+
+type unaryNode struct {
+	op string // "-" or "!"
+	x  node
+}
+
+type binaryNode struct {
+	op          string
+	left, right node
+}
+
+func (numberNode) kind() valueKind    { return kindNumber }
+func (boolNode) kind() valueKind      { return kindBool }
+func (variableNode) kind() valueKind  { return kindNumber }
+func (indicatorNode) kind() valueKind { return kindNumber }
+func (callNode) kind() valueKind      { return kindNumber }
+
+func (n unaryNode) kind() valueKind {
+	if n.op == "!" {
+		return kindBool
+	}
+	return kindNumber
+}
+
+func (n binaryNode) kind() valueKind {
+	switch n.op {
+	case "+", "-", "*", "/", "%":
+		return kindNumber
+	default:
+		return kindBool
+	}
+}
+
+// wordOperators are operators people write as words, for a helpful error.
+var wordOperators = map[string]string{"and": "&&", "or": "||", "not": "!"}
+
+// Program is a compiled expression.
+type Program struct {
+	source     string
+	root       node
+	variables  []string
+	indicators []IndicatorRef
+}
+
+// Compile parses and checks an expression. Errors are *Error with the column of the
+// problem.
+//
+// Example usage:
+//
+//	program, err := expression.Compile("price < 60000 && hour_utc >= 13")
+func Compile(source string) (*Program, error) {
+	if strings.TrimSpace(source) == "" {
+		return nil, errorAt(1, "the expression is empty")
+	}
+	if length := len([]rune(source)); length > MaxLength {
+		return nil, errorAt(MaxLength+1, "the expression is longer than %d characters", MaxLength)
+	}
+
+	tokens, err := tokenize(source)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens, seenVariables: make(map[string]bool), seenIndicators: make(map[string]bool)}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if next := p.peek(); next.kind != tokenEOF {
+		if operator, ok := wordOperators[next.text]; ok && next.kind == tokenIdent {
+			return nil, errorAt(next.column, "%q isn't an operator, use %s", next.text, operator)
+		}
+		return nil, errorAt(next.column, "unexpected %s", next.describe())
+	}
+	if root.kind() != kindBool {
+		return nil, errorAt(1, "the expression must be a condition, e.g. price < 60000")
+	}
+
+	return &Program{source: source, root: root, variables: p.variables, indicators: p.indicators}, nil
+}
+
+// String returns the source of the expression.
+func (p *Program) String() string {
+	return p.source
+}
+
+// Variables returns the variables the expression uses, in order of appearance.
+func (p *Program) Variables() []string {
+	return p.variables
+}
+
+// Indicators returns the distinct indicator calls of the expression, in order of
+// appearance; their values go in Env.Indicators under IndicatorRef.Key.
+func (p *Program) Indicators() []IndicatorRef {
+	return p.indicators
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+	nodes  int
+	depth  int
+
+	variables      []string
+	seenVariables  map[string]bool
+	indicators     []IndicatorRef
+	seenIndicators map[string]bool
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokenEOF {
+		p.pos++
+	}
+	return t
+}
+
+// isOperator reports whether the next token is one of the operators.
+func (p *parser) isOperator(ops ...string) bool {
+	t := p.peek()
+	if t.kind != tokenOperator {
+		return false
+	}
+	for _, op := range ops {
+		if t.text == op {
+			return true
+		}
+	}
+	return false
+}
+
+// add counts a node against MaxNodes.
+func (p *parser) add(n node, column int) (node, error) {
+	p.nodes++
+	if p.nodes > MaxNodes {
+		return nil, errorAt(column, "the expression has more than %d operators and operands", MaxNodes)
+	}
+	return n, nil
+}
+
+// enter counts a nesting level against MaxDepth; leave undoes it.
+func (p *parser) enter(column int) error {
+	p.depth++
+	if p.depth > MaxDepth {
+		return errorAt(column, "the expression is nested more than %d levels deep", MaxDepth)
+	}
+	return nil
+}
+
+func (p *parser) leave() {
+	p.depth--
+}
+
+// expect checks the kind of an operand.
+func expect(n node, kind valueKind, column int, context string) error {
+	if n.kind() != kind {
+		return errorAt(column, "%s needs %s, not %s", context, kind, n.kind())
+	}
+	return nil
+}
+
+// parseOr parses a || b || ...
+func (p *parser) parseOr() (node, error) {
+	if err := p.enter(p.peek().column); err != nil {
+		return nil, err
+	}
+	defer p.leave()
+
+	return p.parseLogical("||", p.parseAnd)
+}
+
+// parseAnd parses a && b && ...
+func (p *parser) parseAnd() (node, error) {
+	return p.parseLogical("&&", p.parseNot)
+}
+
+func (p *parser) parseLogical(op string, operand func() (node, error)) (node, error) {
+	column := p.peek().column
+	left, err := operand()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.isOperator(op) {
+		if err := expect(left, kindBool, column, op); err != nil {
+			return nil, err
+		}
+		operator := p.next()
+		column = p.peek().column
+		right, err := operand()
+		if err != nil {
+			return nil, err
+		}
+		if err := expect(right, kindBool, column, op); err != nil {
+			return nil, err
+		}
+		if left, err = p.add(binaryNode{op: op, left: left, right: right}, operator.column); err != nil {
+			return nil, err
+		}
+	}
+	return left, nil
+}
+
+// parseNot parses !a
+func (p *parser) parseNot() (node, error) {
+	if !p.isOperator("!") {
+		return p.parseComparison()
+	}
+
+	operator := p.next()
+	if err := p.enter(operator.column); err != nil {
+		return nil, err
+	}
+	defer p.leave()
+
+	column := p.peek().column
+	x, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	if err := expect(x, kindBool, column, "!"); err != nil {
+		return nil, err
+	}
+	return p.add(unaryNode{op: "!", x: x}, operator.column)
+}
+
+// parseComparison parses a < b, a == b, ...; comparisons don't chain.
+func (p *parser) parseComparison() (node, error) {
+	column := p.peek().column
+	left, err := p.parseAdditive()
+	if err != nil {
+		return nil, err
+	}
+	if !p.isOperator("<", "<=", ">", ">=", "==", "!=") {
+		return left, nil
+	}
+
+	operator := p.next()
+	rightColumn := p.peek().column
+	right, err := p.parseAdditive()
+	if err != nil {
+		return nil, err
+	}
+
+	switch operator.text {
+	case "==", "!=":
+		if left.kind() != right.kind() {
+			return nil, errorAt(operator.column, "%s compares %s with %s", operator.text, left.kind(), right.kind())
+		}
+	default:
+		if err := expect(left, kindNumber, column, operator.text); err != nil {
+			return nil, err
+		}
+		if err := expect(right, kindNumber, rightColumn, operator.text); err != nil {
+			return nil, err
+		}
+	}
+
+	if p.isOperator("<", "<=", ">", ">=", "==", "!=") {
+		return nil, errorAt(p.peek().column, "comparisons can't be chained, join them with &&")
+	}
+	return p.add(binaryNode{op: operator.text, left: left, right: right}, operator.column)
+}
+
+// parseAdditive parses a + b - ...
+func (p *parser) parseAdditive() (node, error) {
+	return p.parseArithmetic([]string{"+", "-"}, p.parseMultiplicative)
+}
+
+// parseMultiplicative parses a * b / c % ...
+func (p *parser) parseMultiplicative() (node, error) {
+	return p.parseArithmetic([]string{"*", "/", "%"}, p.parseUnary)
+}
+
+func (p *parser) parseArithmetic(ops []string, operand func() (node, error)) (node, error) {
+	column := p.peek().column
+	left, err := operand()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.isOperator(ops...) {
+		operator := p.next()
+		if err := expect(left, kindNumber, column, operator.text); err != nil {
+			return nil, err
+		}
+		rightColumn := p.peek().column
+		right, err := operand()
+		if err != nil {
+			return nil, err
+		}
+		if err := expect(right, kindNumber, rightColumn, operator.text); err != nil {
+			return nil, err
+		}
+		if left, err = p.add(binaryNode{op: operator.text, left: left, right: right}, operator.column); err != nil {
+			return nil, err
+		}
+	}
+	return left, nil
+}
+
+// parseUnary parses -a
+func (p *parser) parseUnary() (node, error) {
+	if !p.isOperator("-") {
+		return p.parsePrimary()
+	}
+
+	operator := p.next()
+	if err := p.enter(operator.column); err != nil {
+		return nil, err
+	}
+	defer p.leave()
+
+	column := p.peek().column
+	x, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	if err := expect(x, kindNumber, column, "-"); err != nil {
+		return nil, err
+	}
+	return p.add(unaryNode{op: "-", x: x}, operator.column)
+}
+
+// parsePrimary parses numbers, true and false, variables, calls and parentheses.
+func (p *parser) parsePrimary() (node, error) {
+	t := p.next()
+	switch t.kind {
+	case tokenNumber:
+		return p.add(numberNode{value: t.number}, t.column)
+
+	case tokenLParen:
+		x, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.next(); closing.kind != tokenRParen {
+			return nil, errorAt(closing.column, "expected \")\" to close the \"(\" at column %d, found %s", t.column, closing.describe())
+		}
+		return x, nil
+
+	case tokenIdent:
+		switch t.text {
+		case "true", "false":
+			return p.add(boolNode{value: t.text == "true"}, t.column)
+		case "and", "or", "not":
+			return nil, errorAt(t.column, "%q isn't an operator, use %s", t.text, wordOperators[t.text])
+		}
+		if p.peek().kind == tokenLParen {
+			return p.parseCall(t)
+		}
+		if !lookupVariable(t.text) {
+			if lookupFunction(t.text) != nil {
+				return nil, errorAt(t.column, "%s is a function, e.g. %s", t.text, lookupFunction(t.text).Signature)
+			}
+			return nil, errorAt(t.column, "unknown variable %q", t.text)
+		}
+		if !p.seenVariables[t.text] {
+			p.seenVariables[t.text] = true
+			p.variables = append(p.variables, t.text)
+		}
+		return p.add(variableNode{name: t.text}, t.column)
+
+	case tokenString:
+		return nil, errorAt(t.column, "strings can only be the interval of an indicator, e.g. rsi(14, \"1h\")")
+
+	case tokenEOF:
+		return nil, errorAt(t.column, "unexpected end of expression")
+
+	default:
+		return nil, errorAt(t.column, "unexpected %s", t.describe())
+	}
+}
+
+// parseCall parses a call to one of Functions; name was read and "(" is next.
+func (p *parser) parseCall(name token) (node, error) {
+	function := lookupFunction(name.text)
+	if function == nil {
+		return nil, errorAt(name.column, "unknown function %q", name.text)
+	}
+	if err := p.enter(name.column); err != nil {
+		return nil, err
+	}
+	defer p.leave()
+
+	open := p.next()
+	if function.indicator {
+		return p.parseIndicator(function, name, open)
+	}
+
+	var args []node
+	for p.peek().kind != tokenRParen {
+		if len(args) > 0 {
+			if comma := p.next(); comma.kind != tokenComma {
+				return nil, errorAt(comma.column, "expected \",\" or \")\" in %s, found %s", function.Signature, comma.describe())
+			}
+		}
+		column := p.peek().column
+		arg, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if err := expect(arg, kindNumber, column, function.Name); err != nil {
+			return nil, err
+		}
+		args = append(args, arg)
+	}
+	closing := p.next()
+	if len(args) != function.arity {
+		return nil, errorAt(closing.column, "%s takes %d arguments: %s", function.Name, function.arity, function.Signature)
+	}
+	return p.add(callNode{name: function.Name, args: args}, name.column)
+}
+
+// parseIndicator parses the constant arguments of an indicator call: its numbers and
+// an optional interval.
+func (p *parser) parseIndicator(function *Function, name, open token) (node, error) {
+	ref := IndicatorRef{Name: function.Name, Interval: DefaultInterval, Column: name.column}
+	usage := fmt.Sprintf("expected %s, with constant arguments", function.Signature)
+
+	hasInterval := false
+	argsColumn := p.peek().column
+	for p.peek().kind != tokenRParen {
+		if len(ref.Args) > 0 || hasInterval {
+			if comma := p.next(); comma.kind != tokenComma {
+				return nil, errorAt(comma.column, "%s", usage)
+			}
+		}
+
+		arg := p.next()
+		switch {
+		case hasInterval:
+			return nil, errorAt(arg.column, "the interval must be the last argument of %s", function.Name)
+		case arg.kind == tokenNumber:
+			ref.Args = append(ref.Args, arg.number)
+		case arg.kind == tokenString:
+			if !validInterval(arg.text) {
+				return nil, errorAt(arg.column, "interval must be one of %s", strings.Join(Intervals, ", "))
+			}
+			ref.Interval = arg.text
+			hasInterval = true
+		default:
+			return nil, errorAt(arg.column, "%s", usage)
+		}
+	}
+	p.next()
+
+	if len(ref.Args) != function.arity {
+		return nil, errorAt(open.column, "%s", usage)
+	}
+	if function.check != nil {
+		if problem := function.check(ref.Args); problem != "" {
+			return nil, errorAt(argsColumn, "%s: %s", function.Name, problem)
+		}
+	}
+
+	key := ref.Key()
+	if !p.seenIndicators[key] {
+		if len(p.indicators) == MaxIndicators {
+			return nil, errorAt(name.column, "the expression uses more than %d indicators", MaxIndicators)
+		}
+		p.seenIndicators[key] = true
+		p.indicators = append(p.indicators, ref)
+	}
+	return p.add(indicatorNode{key: key}, name.column)
+}
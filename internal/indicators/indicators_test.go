@@ -0,0 +1,117 @@
+package indicators
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIndicators_KnownValues(t *testing.T) {
+	sma := NewSMA(3)
+	for _, v := range []float64{1, 2, 3, 4, 5} {
+		sma.Update(v)
+	}
+	value, ok := sma.Value()
+	require.True(t, ok)
+	assert.Equal(t, 4.0, value)
+
+	// Seeded with the average of 2, 4 and 6, then smoothed by 2/(3+1)
+	ema := NewEMA(3)
+	for _, v := range []float64{2, 4, 6, 8} {
+		ema.Update(v)
+	}
+	value, ok = ema.Value()
+	require.True(t, ok)
+	assert.Equal(t, 6.0, value)
+
+	rsi := NewRSI(2)
+	for _, v := range []float64{1, 2, 3} {
+		rsi.Update(v)
+	}
+	value, ok = rsi.Value()
+	require.True(t, ok)
+	assert.Equal(t, 100.0, value)
+	rsi.Update(2)
+	value, _ = rsi.Value()
+	assert.Equal(t, 50.0, value)
+
+	bollinger := NewBollinger(3, 2)
+	for _, v := range []float64{1, 2, 3} {
+		bollinger.Update(v)
+	}
+	bands, ok := bollinger.Value()
+	require.True(t, ok)
+	assert.Equal(t, 2.0, bands.Middle)
+	assert.InDelta(t, 3.633, bands.Upper, 0.001)
+	assert.InDelta(t, 0.367, bands.Lower, 0.001)
+
+	// A gap up makes the true range reach back to the previous close
+	atr := NewATR(2)
+	for _, bar := range []Bar{{High: 10, Low: 8, Close: 9}, {High: 11, Low: 9, Close: 10}, {High: 15, Low: 13, Close: 14}} {
+		atr.Update(bar)
+	}
+	value, ok = atr.Value()
+	require.True(t, ok)
+	assert.Equal(t, 3.5, value)
+}
+
+func TestIndicators_PeekMatchesUpdate(t *testing.T) {
+	closes := []float64{100, 102, 101, 105, 107, 104, 103, 108, 110, 109, 111, 115, 113, 112, 116}
+
+	sma, ema, rsi := NewSMA(5), NewEMA(5), NewRSI(5)
+	macd, bollinger, atr := NewMACD(3, 6, 3), NewBollinger(5, 2), NewATR(5)
+	for i, v := range closes {
+		bar := Bar{High: v + 1, Low: v - 1, Close: v}
+
+		smaPeek, smaOK := sma.Peek(v)
+		emaPeek, emaOK := ema.Peek(v)
+		rsiPeek, rsiOK := rsi.Peek(v)
+		macdPeek, macdOK := macd.Peek(v)
+		bandsPeek, bandsOK := bollinger.Peek(v)
+		atrPeek, atrOK := atr.Peek(bar)
+
+		// Peeking twice gives the same answer, so nothing was changed
+		again, _ := sma.Peek(v)
+		require.Equal(t, smaPeek, again)
+
+		sma.Update(v)
+		ema.Update(v)
+		rsi.Update(v)
+		macd.Update(v)
+		bollinger.Update(v)
+		atr.Update(bar)
+
+		smaValue, ok := sma.Value()
+		assert.Equal(t, ok, smaOK, "sma ready at %d", i)
+		assert.InDelta(t, smaValue, smaPeek, 1e-9)
+		emaValue, ok := ema.Value()
+		assert.Equal(t, ok, emaOK, "ema ready at %d", i)
+		assert.InDelta(t, emaValue, emaPeek, 1e-9)
+		rsiValue, ok := rsi.Value()
+		assert.Equal(t, ok, rsiOK, "rsi ready at %d", i)
+		assert.InDelta(t, rsiValue, rsiPeek, 1e-9)
+		macdValue, ok := macd.Value()
+		assert.Equal(t, ok, macdOK, "macd ready at %d", i)
+		assert.InDelta(t, macdValue.Histogram, macdPeek.Histogram, 1e-9)
+		bands, ok := bollinger.Value()
+		assert.Equal(t, ok, bandsOK, "bollinger ready at %d", i)
+		assert.InDelta(t, bands.Upper, bandsPeek.Upper, 1e-9)
+		atrValue, ok := atr.Value()
+		assert.Equal(t, ok, atrOK, "atr ready at %d", i)
+		assert.InDelta(t, atrValue, atrPeek, 1e-9)
+	}
+
+	// Readiness follows the periods: MACD(3, 6, 3) needs 6 closes plus 2 more for its signal
+	_, ok := NewMACD(3, 6, 3).Value()
+	assert.False(t, ok)
+	macd = NewMACD(3, 6, 3)
+	for _, v := range closes[:7] {
+		macd.Update(v)
+	}
+	_, ok = macd.Value()
+	assert.False(t, ok)
+	macd.Update(closes[7])
+	_, ok = macd.Value()
+	assert.True(t, ok)
+}
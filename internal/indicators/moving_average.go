@@ -0,0 +1,151 @@
+// Package indicators implements technical indicators that are updated incrementally,
+// one closed candle at a time, instead of being recomputed over the whole series.
+//
+// Every indicator has Update, which folds in the value of a closed candle, and Peek,
+// which returns the value the indicator would have if the next candle closed at the
+// given value, without changing it. Peek is what lets an alert evaluate the candle
+// still forming on every tick.
+//
+// Example usage:
+//
+//	rsi := indicators.NewRSI(14)
+//	for _, candle := range closedCandles {
+//	    rsi.Update(candle.Close)
+//	}
+//	if value, ok := rsi.Peek(currentPrice); ok && value < 30 {
+//	    log.Printf("Oversold: RSI %.1f", value)
+//	}
+package indicators
+
+// window is a fixed-size ring of the last values, with their running sum and sum of
+// squares, shared by the indicators that need the values leaving the window.
+type window struct {
+	values []float64
+	next   int // Index of the oldest value once the window is full
+	count  int
+	sum    float64
+	sumSq  float64
+}
+
+func newWindow(size int) window {
+	if size < 1 {
+		size = 1
+	}
+	return window{values: make([]float64, size)}
+}
+
+// add pushes v, dropping the oldest value when the window is full.
+func (w *window) add(v float64) {
+	if w.count == len(w.values) {
+		old := w.values[w.next]
+		w.sum -= old
+		w.sumSq -= old * old
+	} else {
+		w.count++
+	}
+	w.values[w.next] = v
+	w.next = (w.next + 1) % len(w.values)
+	w.sum += v
+	w.sumSq += v * v
+}
+
+// sumsWith returns the sums the window would have after adding v, and whether it
+// would then be full.
+func (w *window) sumsWith(v float64) (sum, sumSq float64, full bool) {
+	sum, sumSq = w.sum+v, w.sumSq+v*v
+	if w.count == len(w.values) {
+		old := w.values[w.next]
+		sum -= old
+		sumSq -= old * old
+		return sum, sumSq, true
+	}
+	return sum, sumSq, w.count+1 == len(w.values)
+}
+
+// SMA is the simple moving average of the last Period values.
+//
+// Example usage:
+//
+//	sma := NewSMA(20)
+//	sma.Update(50000)
+//	if value, ok := sma.Value(); ok {
+//	    log.Printf("SMA(20): %.2f", value)
+//	}
+type SMA struct {
+	window window
+}
+
+// NewSMA creates a simple moving average over period values.
+func NewSMA(period int) *SMA {
+	return &SMA{window: newWindow(period)}
+}
+
+// Update adds the value of a closed candle.
+func (s *SMA) Update(v float64) {
+	s.window.add(v)
+}
+
+// Value returns the current average; false until Period values were added.
+func (s *SMA) Value() (float64, bool) {
+	if s.window.count < len(s.window.values) {
+		return 0, false
+	}
+	return s.window.sum / float64(s.window.count), true
+}
+
+// Peek returns the average after adding v, without adding it.
+func (s *SMA) Peek(v float64) (float64, bool) {
+	sum, _, full := s.window.sumsWith(v)
+	if !full {
+		return 0, false
+	}
+	return sum / float64(len(s.window.values)), true
+}
+
+// EMA is the exponential moving average with smoothing 2/(Period+1), seeded with
+// the simple average of the first Period values.
+//
+// Example usage:
+//
+//	ema := NewEMA(21)
+//	ema.Update(50000)
+type EMA struct {
+	period int
+	k      float64
+	sum    float64 // Sum of the seed values
+	count  int
+	value  float64
+}
+
+// NewEMA creates an exponential moving average over period values.
+func NewEMA(period int) *EMA {
+	if period < 1 {
+		period = 1
+	}
+	return &EMA{period: period, k: 2 / float64(period+1)}
+}
+
+// Update adds the value of a closed candle.
+func (e *EMA) Update(v float64) {
+	switch {
+	case e.count < e.period-1:
+		e.sum += v
+	case e.count == e.period-1:
+		e.value = (e.sum + v) / float64(e.period)
+	default:
+		e.value += e.k * (v - e.value)
+	}
+	e.count++
+}
+
+// Value returns the current average; false until Period values were added.
+func (e *EMA) Value() (float64, bool) {
+	return e.value, e.count >= e.period
+}
+
+// Peek returns the average after adding v, without adding it.
+func (e *EMA) Peek(v float64) (float64, bool) {
+	next := *e
+	next.Update(v)
+	return next.Value()
+}
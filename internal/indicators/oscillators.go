@@ -0,0 +1,129 @@
+package indicators
+
+// RSI is Wilder's relative strength index over Period price changes, from 0 to 100.
+//
+// Example usage:
+//
+//	rsi := NewRSI(14)
+//	rsi.Update(50000)
+//	if value, ok := rsi.Value(); ok && value > 70 {
+//	    log.Printf("Overbought: RSI %.1f", value)
+//	}
+type RSI struct {
+	period   int
+	previous float64
+	count    int // Values added so far
+	avgGain  float64
+	avgLoss  float64
+}
+
+// NewRSI creates a relative strength index over period changes.
+func NewRSI(period int) *RSI {
+	if period < 1 {
+		period = 1
+	}
+	return &RSI{period: period}
+}
+
+// Update adds the close of a closed candle.
+func (r *RSI) Update(v float64) {
+	if r.count > 0 {
+		gain, loss := 0.0, 0.0
+		if change := v - r.previous; change > 0 {
+			gain = change
+		} else {
+			loss = -change
+		}
+
+		n := float64(r.period)
+		if r.count <= r.period {
+			// Seed with the simple average of the first Period changes
+			r.avgGain += gain / n
+			r.avgLoss += loss / n
+		} else {
+			r.avgGain = (r.avgGain*(n-1) + gain) / n
+			r.avgLoss = (r.avgLoss*(n-1) + loss) / n
+		}
+	}
+	r.previous = v
+	r.count++
+}
+
+// Value returns the current index; false until Period changes were added.
+func (r *RSI) Value() (float64, bool) {
+	if r.count <= r.period {
+		return 0, false
+	}
+	if r.avgLoss == 0 {
+		if r.avgGain == 0 {
+			return 50, true
+		}
+		return 100, true
+	}
+	return 100 - 100/(1+r.avgGain/r.avgLoss), true
+}
+
+// Peek returns the index after adding v, without adding it.
+func (r *RSI) Peek(v float64) (float64, bool) {
+	next := *r
+	next.Update(v)
+	return next.Value()
+}
+
+// MACDValue is a MACD reading: the MACD line (fast EMA - slow EMA), its signal line
+// and the histogram between them.
+type MACDValue struct {
+	MACD      float64 `json:"macd"`
+	Signal    float64 `json:"signal"`
+	Histogram float64 `json:"histogram"`
+}
+
+// MACD is the moving average convergence divergence, e.g. MACD(12, 26, 9).
+//
+// Example usage:
+//
+//	macd := NewMACD(12, 26, 9)
+//	macd.Update(50000)
+//	if value, ok := macd.Value(); ok && value.Histogram > 0 {
+//	    log.Printf("MACD above its signal line")
+//	}
+type MACD struct {
+	fast   EMA
+	slow   EMA
+	signal EMA
+}
+
+// NewMACD creates a MACD with the given fast, slow and signal periods.
+func NewMACD(fast, slow, signal int) *MACD {
+	return &MACD{fast: *NewEMA(fast), slow: *NewEMA(slow), signal: *NewEMA(signal)}
+}
+
+// Update adds the close of a closed candle.
+func (m *MACD) Update(v float64) {
+	m.fast.Update(v)
+	m.slow.Update(v)
+
+	fast, fastOK := m.fast.Value()
+	slow, slowOK := m.slow.Value()
+	if fastOK && slowOK {
+		m.signal.Update(fast - slow)
+	}
+}
+
+// Value returns the current reading; false until the signal line has enough values.
+func (m *MACD) Value() (MACDValue, bool) {
+	signal, ok := m.signal.Value()
+	if !ok {
+		return MACDValue{}, false
+	}
+	fast, _ := m.fast.Value()
+	slow, _ := m.slow.Value()
+	return MACDValue{MACD: fast - slow, Signal: signal, Histogram: fast - slow - signal}, true
+}
+
+// Peek returns the reading after adding v, without adding it.
+func (m *MACD) Peek(v float64) (MACDValue, bool) {
+	next := *m
+	next.Update(v)
+	return next.Value()
+}
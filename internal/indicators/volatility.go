@@ -0,0 +1,118 @@
+package indicators
+
+import "math"
+
+// Bands are Bollinger bands: the moving average and K standard deviations around it.
+type Bands struct {
+	Upper  float64 `json:"upper"`
+	Middle float64 `json:"middle"`
+	Lower  float64 `json:"lower"`
+}
+
+// Bollinger computes Bollinger bands over the last Period closes, using the
+// population standard deviation.
+//
+// Example usage:
+//
+//	bollinger := NewBollinger(20, 2)
+//	bollinger.Update(50000)
+//	if bands, ok := bollinger.Peek(price); ok && price > bands.Upper {
+//	    log.Printf("Price above the upper band")
+//	}
+type Bollinger struct {
+	window window
+	k      float64
+}
+
+// NewBollinger creates Bollinger bands over period closes, k standard deviations wide.
+func NewBollinger(period int, k float64) *Bollinger {
+	return &Bollinger{window: newWindow(period), k: k}
+}
+
+// Update adds the close of a closed candle.
+func (b *Bollinger) Update(v float64) {
+	b.window.add(v)
+}
+
+// Value returns the current bands; false until Period closes were added.
+func (b *Bollinger) Value() (Bands, bool) {
+	if b.window.count < len(b.window.values) {
+		return Bands{}, false
+	}
+	return b.bands(b.window.sum, b.window.sumSq), true
+}
+
+// Peek returns the bands after adding v, without adding it.
+func (b *Bollinger) Peek(v float64) (Bands, bool) {
+	sum, sumSq, full := b.window.sumsWith(v)
+	if !full {
+		return Bands{}, false
+	}
+	return b.bands(sum, sumSq), true
+}
+
+func (b *Bollinger) bands(sum, sumSq float64) Bands {
+	n := float64(len(b.window.values))
+	mean := sum / n
+	// Rounding can leave a tiny negative variance for flat series
+	deviation := math.Sqrt(math.Max(sumSq/n-mean*mean, 0))
+	return Bands{Upper: mean + b.k*deviation, Middle: mean, Lower: mean - b.k*deviation}
+}
+
+// Bar is the high, low and close of a candle, as needed by ATR.
+type Bar struct {
+	High  float64
+	Low   float64
+	Close float64
+}
+
+// ATR is Wilder's average true range over Period candles.
+//
+// Example usage:
+//
+//	atr := NewATR(14)
+//	atr.Update(Bar{High: 50500, Low: 49500, Close: 50000})
+type ATR struct {
+	period    int
+	prevClose float64
+	count     int
+	value     float64
+}
+
+// NewATR creates an average true range over period candles.
+func NewATR(period int) *ATR {
+	if period < 1 {
+		period = 1
+	}
+	return &ATR{period: period}
+}
+
+// Update adds a closed candle.
+func (a *ATR) Update(bar Bar) {
+	trueRange := bar.High - bar.Low
+	if a.count > 0 {
+		trueRange = math.Max(trueRange, math.Max(math.Abs(bar.High-a.prevClose), math.Abs(bar.Low-a.prevClose)))
+	}
+
+	n := float64(a.period)
+	if a.count < a.period {
+		// Seed with the simple average of the first Period true ranges
+		a.value += trueRange / n
+	} else {
+		a.value = (a.value*(n-1) + trueRange) / n
+	}
+	a.prevClose = bar.Close
+	a.count++
+}
+
+// Value returns the current average; false until Period candles were added.
+func (a *ATR) Value() (float64, bool) {
+	return a.value, a.count >= a.period
+}
+
+// Peek returns the average after adding bar, without adding it.
+func (a *ATR) Peek(bar Bar) (float64, bool) {
+	next := *a
+	next.Update(bar)
+	return next.Value()
+}
@@ -13,6 +13,7 @@ import (
 //
 //	entry := PriceCacheEntry{Price: 30000, Currency: "USD", Timestamp: time.Now()}
 type PriceCacheEntry struct {
+	Symbol             string    `json:"symbol"`
 	Price              float64   `json:"price"`
 	PriceChangePercent float64   `json:"price_change_percent"`
 	Currency           string    `json:"currency"`
@@ -41,11 +42,13 @@ type AlertService interface {
 	ResetAlert(alertID uint) error
 
 	// Price operations
-	GetCurrentPrice() (*bitcoin.PriceData, error)
-	GetPriceHistory(limit int) ([]PriceCacheEntry, error)
+	GetCurrentPrice(symbol string) (*bitcoin.PriceData, error)
+	GetLastPrice(symbol string) *bitcoin.PriceData
+	GetPriceHistory(symbol string, limit int) ([]PriceCacheEntry, error)
 	GetCurrentPercentage() float64
 
 	// System operations
 	GetStats() (map[string]interface{}, error)
 	IsMonitoring() bool
+	IsStreaming() bool
 }
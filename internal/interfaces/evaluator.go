@@ -2,10 +2,61 @@ package interfaces
 
 import (
 	"github.com/cgallonv/btc-alerta-de-precio/internal/bitcoin"
+	"github.com/cgallonv/btc-alerta-de-precio/internal/expression"
 	"github.com/cgallonv/btc-alerta-de-precio/internal/storage"
 )
 
-// AlertEvaluator defines the interface for evaluating alert conditions
+// AlertEvaluator defines the interface for evaluating alert conditions.
+// evalCtx carries the per-alert state the tick is evaluated with; nil means no
+// previous tick and no indicator, order book or reference data.
 type AlertEvaluator interface {
-	ShouldTrigger(alert *storage.Alert, priceData *bitcoin.PriceData) bool
+	ShouldTrigger(alert *storage.Alert, priceData *bitcoin.PriceData, evalCtx *EvaluationContext) bool
+}
+
+// EvaluationContext is the state an alert is evaluated with besides the tick itself.
+// The alert manager builds one per alert and tick; fields an alert type doesn't use
+// are left empty.
+//
+// Example usage:
+//
+//	evalCtx := &EvaluationContext{Previous: previousTick, Indicator: reading}
+//	shouldTrigger := evaluator.ShouldTrigger(alert, priceData, evalCtx)
+type EvaluationContext struct {
+	// Previous is the last tick evaluated for the same symbol, in the alert's currency
+	Previous *bitcoin.PriceData
+
+	// OrderBook is the symbol's order book at the time of the tick, when the book is tracked
+	OrderBook *bitcoin.OrderBookSnapshot
+
+	// Reference is the past price a windowed change alert compares against
+	Reference *bitcoin.PricePoint
+
+	// Indicator is an indicator alert's reading
+	Indicator *bitcoin.IndicatorReading
+
+	// Readings are the readings of a composite alert's indicator conditions, by condition path
+	Readings map[string]*bitcoin.IndicatorReading
+
+	// Values are the indicator values of an expression alert, by IndicatorRef.Key
+	Values map[string]float64
+
+	// Program is an expression alert's compiled expression, so it isn't compiled again
+	Program *expression.Program
+}
+
+// ChangePercent returns the change from the reference price to price, in percent.
+// It returns false when the context carries no reference.
+func (c *EvaluationContext) ChangePercent(price float64) (float64, bool) {
+	if c == nil || c.Reference == nil || c.Reference.Price <= 0 {
+		return 0, false
+	}
+	return (price - c.Reference.Price) / c.Reference.Price * 100, true
+}
+
+// PreviousTick returns the previous tick, or nil when there is none.
+func (c *EvaluationContext) PreviousTick() *bitcoin.PriceData {
+	if c == nil {
+		return nil
+	}
+	return c.Previous
 }
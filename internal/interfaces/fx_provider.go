@@ -0,0 +1,14 @@
+package interfaces
+
+// FXRateProvider defines the interface for fetching fiat exchange rates.
+// Rates are expressed as units of the currency per US dollar (e.g. COP ≈ 4000).
+//
+// Example usage:
+//
+//	var provider FXRateProvider = bitcoin.NewBinanceFXProvider(client)
+//	rate, err := provider.GetUSDRate("EUR")
+type FXRateProvider interface {
+	// Name returns the provider name reported with each rate
+	Name() string
+	GetUSDRate(currency string) (float64, error)
+}
@@ -0,0 +1,18 @@
+package interfaces
+
+import (
+	"github.com/cgallonv/btc-alerta-de-precio/internal/bitcoin"
+)
+
+// PriceProvider defines the interface for fetching current prices from an exchange.
+// Symbols use Binance naming (e.g. "BTCUSDT"); implementations translate them as needed.
+//
+// Example usage:
+//
+//	var provider PriceProvider = bitcoin.NewCoinbaseClient("")
+//	price, err := provider.GetSymbolPrice("BTCUSDT")
+type PriceProvider interface {
+	// Name returns the provider name reported in PriceData.Source
+	Name() string
+	GetSymbolPrice(symbol string) (*bitcoin.PriceData, error)
+}
@@ -18,7 +18,12 @@ type ConfigProvider interface {
 	IsEmailNotificationsEnabled() bool
 
 	IsTelegramNotificationsEnabled() bool
+	IsPriceStreamEnabled() bool
+	IsPriceAggregationEnabled() bool
+	GetPriceOutlierBand() float64
+	GetPriceQuorum() int
 	GetVAPIDPublicKey() string
 	GetString(key string) string
 	GetDefaultSymbols() []string
+	GetPriceProviders() []string
 }
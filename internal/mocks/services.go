@@ -2,6 +2,7 @@ package mocks
 
 import (
 	"github.com/cgallonv/btc-alerta-de-precio/internal/bitcoin"
+	"github.com/cgallonv/btc-alerta-de-precio/internal/interfaces"
 	"github.com/cgallonv/btc-alerta-de-precio/internal/notifications"
 	"github.com/cgallonv/btc-alerta-de-precio/internal/storage"
 	"time"
@@ -27,6 +28,39 @@ func (m *MockPriceClient) GetPriceHistory(days int) ([]bitcoin.PriceData, error)
 	return args.Get(0).([]bitcoin.PriceData), args.Error(1)
 }
 
+// MockPriceProvider is a mock implementation of interfaces.PriceProvider
+type MockPriceProvider struct {
+	mock.Mock
+}
+
+func (m *MockPriceProvider) Name() string {
+	args := m.Called()
+	return args.String(0)
+}
+
+func (m *MockPriceProvider) GetSymbolPrice(symbol string) (*bitcoin.PriceData, error) {
+	args := m.Called(symbol)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*bitcoin.PriceData), args.Error(1)
+}
+
+// MockFXRateProvider is a mock implementation of interfaces.FXRateProvider
+type MockFXRateProvider struct {
+	mock.Mock
+}
+
+func (m *MockFXRateProvider) Name() string {
+	args := m.Called()
+	return args.String(0)
+}
+
+func (m *MockFXRateProvider) GetUSDRate(currency string) (float64, error) {
+	args := m.Called(currency)
+	return args.Get(0).(float64), args.Error(1)
+}
+
 // MockNotificationSender is a mock implementation of interfaces.NotificationSender
 type MockNotificationSender struct {
 	mock.Mock
@@ -47,8 +81,8 @@ type MockAlertEvaluator struct {
 	mock.Mock
 }
 
-func (m *MockAlertEvaluator) ShouldTrigger(alert *storage.Alert, currentPrice, previousPrice float64) bool {
-	args := m.Called(alert, currentPrice, previousPrice)
+func (m *MockAlertEvaluator) ShouldTrigger(alert *storage.Alert, priceData *bitcoin.PriceData, evalCtx *interfaces.EvaluationContext) bool {
+	args := m.Called(alert, priceData, evalCtx)
 	return args.Bool(0)
 }
 
@@ -71,3 +105,43 @@ func (m *MockConfigProvider) IsTelegramNotificationsEnabled() bool {
 	args := m.Called()
 	return args.Bool(0)
 }
+
+func (m *MockConfigProvider) IsPriceStreamEnabled() bool {
+	args := m.Called()
+	return args.Bool(0)
+}
+
+func (m *MockConfigProvider) IsPriceAggregationEnabled() bool {
+	args := m.Called()
+	return args.Bool(0)
+}
+
+func (m *MockConfigProvider) GetPriceOutlierBand() float64 {
+	args := m.Called()
+	return args.Get(0).(float64)
+}
+
+func (m *MockConfigProvider) GetPriceQuorum() int {
+	args := m.Called()
+	return args.Int(0)
+}
+
+func (m *MockConfigProvider) GetPriceProviders() []string {
+	args := m.Called()
+	return args.Get(0).([]string)
+}
+
+func (m *MockConfigProvider) GetVAPIDPublicKey() string {
+	args := m.Called()
+	return args.String(0)
+}
+
+func (m *MockConfigProvider) GetString(key string) string {
+	args := m.Called(key)
+	return args.String(0)
+}
+
+func (m *MockConfigProvider) GetDefaultSymbols() []string {
+	args := m.Called()
+	return args.Get(0).([]string)
+}
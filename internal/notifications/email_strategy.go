@@ -83,7 +83,7 @@ func (e *EmailStrategy) generateHTMLBody(data *NotificationData) string {
             <h1>🚨 Bitcoin Price Alert</h1>
         </div>
         
-        <div class="price">$%.2f USD</div>
+        <div class="price">%s</div>
         
         <div class="message">%s</div>
         
@@ -99,5 +99,5 @@ func (e *EmailStrategy) generateHTMLBody(data *NotificationData) string {
     </div>
 </body>
 </html>
-	`, data.Title, data.Price, data.Message, data.Alert.GetDescription(), data.Alert.Name)
+	`, data.Title, data.FormattedPrice(), data.Message, data.Condition(), data.Alert.Name)
 }
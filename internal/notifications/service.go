@@ -20,6 +20,7 @@ type NotificationData struct {
 	Title       string
 	Message     string
 	Price       float64
+	Currency    string // Currency of Price, USD when empty
 	Alert       *storage.Alert
 	IsTest      bool
 	AlertID     uint
@@ -28,6 +29,45 @@ type NotificationData struct {
 	Percentage  float64
 	Email       string
 	EnableEmail bool
+
+	// Reference price a windowed change alert compared against, in Currency
+	ReferencePrice float64
+	ReferenceTime  time.Time
+
+	// When the condition was met, for notifications deferred to the alert's schedule
+	DeferredAt time.Time
+}
+
+// FormattedPrice returns Price in its currency, e.g. "$60000.00" or "240000000.00 COP".
+func (d *NotificationData) FormattedPrice() string {
+	if d.Currency == "" || d.Currency == storage.DefaultAlertCurrency {
+		return fmt.Sprintf("$%.2f", d.Price)
+	}
+	return fmt.Sprintf("%.2f %s", d.Price, d.Currency)
+}
+
+// Condition describes the alert condition, with the reference price it was compared
+// against when there is one, e.g. "Bitcoin price change of -3.00% within 15 minutes
+// (from $70000.00 at 14:05:12, -3.10%)", and when it was met if the notification was
+// deferred to the alert's schedule.
+func (d *NotificationData) Condition() string {
+	description := d.Alert.GetDescription()
+	if !d.ReferenceTime.IsZero() && d.ReferencePrice > 0 {
+		reference := *d
+		reference.Price = d.ReferencePrice
+		change := (d.Price - d.ReferencePrice) / d.ReferencePrice * 100
+		description = fmt.Sprintf("%s (from %s at %s, %+.2f%%)", description,
+			reference.FormattedPrice(), d.ReferenceTime.Format("15:04:05"), change)
+	}
+	if !d.DeferredAt.IsZero() {
+		deferredAt := d.DeferredAt
+		if d.Alert.Schedule != nil {
+			deferredAt = deferredAt.In(d.Alert.Schedule.Location())
+		}
+		description = fmt.Sprintf("%s (met at %s, outside the alert's schedule)", description,
+			deferredAt.Format("2006-01-02 15:04 MST"))
+	}
+	return description
 }
 
 func NewService(cfg *config.Config, db *storage.Database) *Service {
@@ -49,6 +89,30 @@ func (s *Service) SendAlert(data *NotificationData) error {
 	return manager.SendAlert(data)
 }
 
+// SendOrderFill notifies an order fill by email (to OrderFillEmail) and Telegram.
+// price is the price of the fill and status the order status after it (FILLED or PARTIALLY_FILLED).
+func (s *Service) SendOrderFill(symbol, side string, quantity, price float64, status string) error {
+	fill := &storage.Alert{
+		Name:           fmt.Sprintf("%s %.8f %s @ $%.2f (%s)", side, quantity, symbol, price, status),
+		Symbol:         symbol,
+		Type:           storage.AlertTypeOrderFill,
+		Email:          s.config.OrderFillEmail,
+		EnableEmail:    s.config.OrderFillEmail != "",
+		EnableTelegram: true,
+	}
+
+	manager := NewNotificationManager(
+		NewEmailStrategy(s.config),
+		NewTelegramStrategy(s.config),
+	)
+	return manager.SendAlert(&NotificationData{
+		Title:   fmt.Sprintf("✅ Order filled - %s %s", side, symbol),
+		Message: fmt.Sprintf("Your %s order for %.8f %s was filled at $%.2f (%s).", side, quantity, symbol, price, status),
+		Price:   price,
+		Alert:   fill,
+	})
+}
+
 // Telegram Notifications
 func (s *Service) sendTelegramNotification(data *NotificationData) error {
 	if s.config.TelegramBotToken == "" || s.config.TelegramChatID == "" {
@@ -60,12 +124,12 @@ func (s *Service) sendTelegramNotification(data *NotificationData) error {
 	// Crear mensaje con formato HTML
 	message := fmt.Sprintf(
 		"🚨 <b>BITCOIN ALERT - %s</b> 🚨\n\n"+
-			"💰 <b>Precio:</b> $%.2f\n"+
+			"💰 <b>Precio:</b> %s\n"+
 			"📊 <b>Condición:</b> %s\n"+
 			"⏰ <b>Hora:</b> %s\n\n"+
 			"🤖 <i>Enviado por BTC Price Alert</i>",
 		data.Alert.Name,
-		data.Price,
+		data.FormattedPrice(),
 		data.Alert.GetDescription(),
 		time.Now().Format("15:04:05 02/01/2006"),
 	)
@@ -35,13 +35,13 @@ func (t *TelegramStrategy) Send(data *NotificationData) error {
 	// Create message with HTML formatting
 	message := fmt.Sprintf(
 		"🚨 <b>BITCOIN ALERT - %s</b> 🚨\n\n"+
-			"💰 <b>Price:</b> $%.2f\n"+
+			"💰 <b>Price:</b> %s\n"+
 			"📊 <b>Condition:</b> %s\n"+
 			"⏰ <b>Time:</b> %s\n\n"+
 			"🤖 <i>Sent by BTC Price Alert</i>",
 		data.Alert.Name,
-		data.Price,
-		data.Alert.GetDescription(),
+		data.FormattedPrice(),
+		data.Condition(),
 		time.Now().Format("15:04:05 02/01/2006"),
 	)
 
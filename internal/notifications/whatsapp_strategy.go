@@ -63,8 +63,8 @@ func (w *WhatsAppStrategy) Send(data *NotificationData) error {
 					"type": "body",
 					"parameters": []map[string]interface{}{
 						{"type": "text", "text": data.Alert.Name},
-						{"type": "text", "text": fmt.Sprintf("$%.2f", data.Price)},
-						{"type": "text", "text": data.Alert.GetDescription()},
+						{"type": "text", "text": data.FormattedPrice()},
+						{"type": "text", "text": data.Condition()},
 						{"type": "text", "text": time.Now().Format("15:04:05 02/01/2006")},
 					},
 				},
@@ -0,0 +1,171 @@
+package storage
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// MaxConditionDepth is how deeply the groups of a "composite" alert can be nested.
+const MaxConditionDepth = 4
+
+// MaxConditions is how many simple conditions a "composite" alert can have.
+const MaxConditions = 10
+
+// conditionTypes son los tipos de alerta que pueden usarse como condición simple. Los
+// demás necesitan datos que solo se adjuntan a alertas de su tipo (libro de órdenes,
+// precio de referencia)
+var conditionTypes = []string{"above", "below", "cross_up", "cross_down", "change", "indicator"}
+
+// Condition es un nodo del árbol de condiciones de una alerta "composite". Un grupo
+// tiene Op ("and" u "or") y Conditions; una condición simple tiene Type y los campos
+// de ese tipo, con los mismos nombres que en Alert, y se evalúa como una alerta de ese
+// tipo sobre el símbolo y la moneda de la alerta compuesta.
+//
+// Example usage:
+//
+//	// Precio bajo 60k Y RSI(14) en 1h bajo 30 Y volumen sobre su media de 20 velas
+//	alert.Type = "composite"
+//	alert.Conditions = &Condition{Op: "and", Conditions: []Condition{
+//	    {Type: "below", TargetPrice: 60000},
+//	    {Type: "indicator", Indicator: "rsi", Interval: "1h", IndicatorCondition: "below", Threshold: 30},
+//	    {Type: "indicator", Indicator: "volume", Interval: "1h", IndicatorCondition: "above"},
+//	}}
+type Condition struct {
+	// Grupo
+	Op         string      `json:"op,omitempty"`         // "and" u "or"
+	Conditions []Condition `json:"conditions,omitempty"` // Condiciones del grupo
+
+	// Condición simple
+	Type               string  `json:"type,omitempty"` // "above", "below", "cross_up", "cross_down", "change" o "indicator"
+	TargetPrice        float64 `json:"target_price,omitempty"`
+	Percentage         float64 `json:"percentage,omitempty"`
+	Indicator          string  `json:"indicator,omitempty"`
+	Interval           string  `json:"interval,omitempty"`
+	Period             int     `json:"period,omitempty"`
+	SlowPeriod         int     `json:"slow_period,omitempty"`
+	IndicatorCondition string  `json:"indicator_condition,omitempty"`
+	Threshold          float64 `json:"threshold,omitempty"`
+}
+
+// IsGroup indica si la condición es un grupo AND/OR
+func (c *Condition) IsGroup() bool {
+	return c.Op != "" || len(c.Conditions) > 0
+}
+
+// Alert devuelve una condición simple como alerta de su tipo, con el símbolo, la
+// moneda y el ID de la alerta compuesta, lista para evaluarse como cualquier alerta
+func (c *Condition) Alert(parent *Alert) *Alert {
+	return &Alert{
+		ID:                 parent.ID,
+		Name:               parent.Name,
+		Symbol:             parent.Symbol,
+		Currency:           parent.Currency,
+		Type:               c.Type,
+		TargetPrice:        c.TargetPrice,
+		Percentage:         c.Percentage,
+		Indicator:          c.Indicator,
+		Interval:           c.Interval,
+		Period:             c.Period,
+		SlowPeriod:         c.SlowPeriod,
+		IndicatorCondition: c.IndicatorCondition,
+		Threshold:          c.Threshold,
+		IsActive:           true,
+	}
+}
+
+// ConditionPath devuelve la ruta de la condición index de un grupo ("" es la raíz,
+// "1" su segunda condición y "1.0" la primera condición de esta)
+func ConditionPath(parent string, index int) string {
+	if parent == "" {
+		return strconv.Itoa(index)
+	}
+	return parent + "." + strconv.Itoa(index)
+}
+
+// Walk recorre las condiciones simples del árbol con su ruta, en orden
+//
+// Example usage:
+//
+//	alert.Conditions.Walk("", func(path string, condition *Condition) {
+//	    log.Printf("%s: %s", path, condition.Type)
+//	})
+func (c *Condition) Walk(path string, visit func(path string, condition *Condition)) {
+	if !c.IsGroup() {
+		visit(path, c)
+		return
+	}
+	for i := range c.Conditions {
+		c.Conditions[i].Walk(ConditionPath(path, i), visit)
+	}
+}
+
+// validate valida el árbol a partir de esta condición, contando las condiciones simples
+func (c *Condition) validate(parent *Alert, path string, depth int, conditions *int) error {
+	if c.IsGroup() {
+		if c.Op != "and" && c.Op != "or" {
+			return fmt.Errorf("condition group %s: op must be 'and' or 'or'", conditionName(path))
+		}
+		if c.Type != "" {
+			return fmt.Errorf("condition group %s: a group can't also have a type", conditionName(path))
+		}
+		if len(c.Conditions) == 0 {
+			return fmt.Errorf("condition group %s: a group needs at least one condition", conditionName(path))
+		}
+		if depth > MaxConditionDepth {
+			return fmt.Errorf("condition groups can't be nested more than %d levels deep", MaxConditionDepth)
+		}
+		for i := range c.Conditions {
+			if err := c.Conditions[i].validate(parent, ConditionPath(path, i), depth+1, conditions); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	*conditions++
+	if *conditions > MaxConditions {
+		return fmt.Errorf("composite alerts can have at most %d conditions", MaxConditions)
+	}
+
+	valid := false
+	for _, conditionType := range conditionTypes {
+		valid = valid || c.Type == conditionType
+	}
+	if !valid {
+		return fmt.Errorf("condition %s: type must be one of %s", conditionName(path), strings.Join(conditionTypes, ", "))
+	}
+	if c.Type == "change" && c.Percentage == 0 {
+		return fmt.Errorf("condition %s: change percentage must not be 0", conditionName(path))
+	}
+	if err := c.Alert(parent).Validate(); err != nil {
+		return fmt.Errorf("condition %s: %w", conditionName(path), err)
+	}
+	return nil
+}
+
+// describe describe el árbol en texto, con los grupos anidados entre paréntesis
+// ("price below $60000.00 AND (RSI(14) on 1h below 30.00 OR ...)")
+func (c *Condition) describe(parent *Alert, root bool) string {
+	if !c.IsGroup() {
+		return c.Alert(parent).conditionDescription()
+	}
+
+	parts := make([]string, 0, len(c.Conditions))
+	for i := range c.Conditions {
+		parts = append(parts, c.Conditions[i].describe(parent, false))
+	}
+	description := strings.Join(parts, " "+strings.ToUpper(c.Op)+" ")
+	if root || len(parts) == 1 {
+		return description
+	}
+	return "(" + description + ")"
+}
+
+// conditionName nombra una condición en los errores de validación
+func conditionName(path string) string {
+	if path == "" {
+		return "root"
+	}
+	return path
+}
@@ -0,0 +1,35 @@
+package migrations
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+
+	"github.com/cgallonv/btc-alerta-de-precio/internal/storage/models"
+)
+
+// MigrateCandles creates or updates the candles table schema and its indexes.
+//
+// Example usage:
+//
+//	if err := migrations.MigrateCandles(db); err != nil {
+//	    log.Fatalf("Failed to migrate candles: %v", err)
+//	}
+func MigrateCandles(db *gorm.DB) error {
+	if err := db.AutoMigrate(&models.Candle{}); err != nil {
+		return fmt.Errorf("failed to migrate candles table: %w", err)
+	}
+
+	for _, idx := range (models.Candle{}).Indexes() {
+		indexName := fmt.Sprintf("idx_%s_%s", "candles", idx[0])
+		if err := db.Exec(fmt.Sprintf(
+			"CREATE INDEX IF NOT EXISTS %s ON candles (%s)",
+			indexName,
+			idx[0],
+		)).Error; err != nil {
+			return fmt.Errorf("failed to create index %s: %w", indexName, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,35 @@
+package migrations
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+
+	"github.com/cgallonv/btc-alerta-de-precio/internal/storage/models"
+)
+
+// MigrateTrades creates or updates the trades table schema and its indexes.
+//
+// Example usage:
+//
+//	if err := migrations.MigrateTrades(db); err != nil {
+//	    log.Fatalf("Failed to migrate trades: %v", err)
+//	}
+func MigrateTrades(db *gorm.DB) error {
+	if err := db.AutoMigrate(&models.Trade{}); err != nil {
+		return fmt.Errorf("failed to migrate trades table: %w", err)
+	}
+
+	for _, idx := range (models.Trade{}).Indexes() {
+		indexName := fmt.Sprintf("idx_%s_%s", "trades", idx[0])
+		if err := db.Exec(fmt.Sprintf(
+			"CREATE INDEX IF NOT EXISTS %s ON trades (%s)",
+			indexName,
+			idx[0],
+		)).Error; err != nil {
+			return fmt.Errorf("failed to create index %s: %w", indexName, err)
+		}
+	}
+
+	return nil
+}
@@ -2,21 +2,78 @@ package storage
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
 	"gorm.io/gorm"
+
+	"github.com/cgallonv/btc-alerta-de-precio/internal/expression"
 )
 
+// DefaultAlertSymbol is the trading pair used by alerts created without a symbol.
+const DefaultAlertSymbol = "BTCUSDT"
+
+// DefaultAlertCurrency is the currency of TargetPrice for alerts created without one.
+const DefaultAlertCurrency = "USD"
+
+// DefaultDepthPercent is the band around the mid price used by order book alerts without DepthPercent.
+const DefaultDepthPercent = 1.0
+
+// MaxWindowMinutes is the longest lookback of a "change_window" alert (24h).
+const MaxWindowMinutes = 24 * 60
+
+// MaxIndicatorPeriod is the longest period of an "indicator" alert, in candles.
+const MaxIndicatorPeriod = 200
+
+// DefaultIndicatorInterval is the candle interval of indicator alerts without Interval.
+const DefaultIndicatorInterval = "1h"
+
+// indicatorPeriods are the default periods of each indicator: the period (or fast
+// period) and, for crossovers and MACD, the slow period.
+var indicatorPeriods = map[string][2]int{
+	"rsi":       {14, 0},
+	"atr":       {14, 0},
+	"sma":       {20, 0},
+	"ema":       {20, 0},
+	"sma_cross": {9, 21},
+	"ema_cross": {9, 21},
+	"macd":      {12, 26},
+	"bollinger": {20, 0},
+	"volume":    {20, 0},
+}
+
+// indicatorIntervals are the candle intervals indicator alerts can use.
+var indicatorIntervals = []string{"1m", "3m", "5m", "15m", "30m", "1h", "2h", "4h", "6h", "8h", "12h", "1d"}
+
+// Modos de disparo de una alerta.
+const (
+	TriggerModeOnce      = "once"      // Se dispara una vez, hasta que se resetea
+	TriggerModeRecurring = "recurring" // Se dispara cada vez que se cumple, respetando CooldownSeconds
+	TriggerModeMaxCount  = "max_count" // Se dispara hasta MaxTriggers veces, respetando CooldownSeconds
+)
+
+// AlertTypeOrderFill marks the notifications sent for order fills. They aren't stored
+// alerts; the Alert only carries the recipient and channels of the notification.
+const AlertTypeOrderFill = "order_fill"
+
 type Alert struct {
-	ID          uint      `json:"id" gorm:"primaryKey"`
-	Name        string    `json:"name" gorm:"not null"`
-	Type        string    `json:"type" gorm:"not null"` // "above", "below", "change"
-	TargetPrice float64   `json:"target_price"`
-	Percentage  float64   `json:"percentage"` // Para alertas de cambio porcentual
-	IsActive    bool      `json:"is_active" gorm:"default:true"`
-	Email       string    `json:"email"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	ID            uint    `json:"id" gorm:"primaryKey"`
+	Name          string  `json:"name" gorm:"not null"`
+	Symbol        string  `json:"symbol" gorm:"default:'BTCUSDT';index"` // Binance trading pair, e.g. "ETHUSDT"
+	Type          string  `json:"type" gorm:"not null"`                  // "above", "below", "cross_up", "cross_down", "change", "change_window", "imbalance", "spread", "liquidity", "indicator", "composite", "expression"
+	TargetPrice   float64 `json:"target_price"`
+	Currency      string  `json:"currency" gorm:"default:'USD'"` // Moneda de TargetPrice (USD, COP, EUR...)
+	Percentage    float64 `json:"percentage"`                    // Para alertas de cambio porcentual e imbalance del libro
+	WindowMinutes int     `json:"window_minutes"`                // Ventana de las alertas "change_window", en minutos
+	IsActive      bool    `json:"is_active" gorm:"default:true"`
+	Email         string  `json:"email"`
+
+	// Alertas del libro de órdenes
+	Threshold    float64 `json:"threshold"`     // Spread en bps ("spread"), cantidad base ("liquidity") o nivel del indicador ("indicator")
+	DepthPercent float64 `json:"depth_percent"` // Banda alrededor del precio medio, en % (por defecto 1)
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
 
 	// Configuración de notificaciones
 	EnableEmail bool `json:"enable_email" gorm:"default:true"`
@@ -28,9 +85,53 @@ type Alert struct {
 	WhatsAppNumber string `json:"whatsapp_number" gorm:"size:20"` // International format: +1234567890
 	Language       string `json:"language" gorm:"default:'es'"`   // 'es' or 'en' for template selection
 
+	// Modo de disparo
+	TriggerMode     string `json:"trigger_mode" gorm:"default:'once'"` // "once", "recurring" o "max_count"
+	CooldownSeconds int64  `json:"cooldown_seconds"`                   // Espera mínima entre disparos
+	MaxTriggers     int    `json:"max_triggers"`                       // Disparos permitidos en modo "max_count"
+
+	// Histéresis de alertas "above"/"below": tras dispararse la alerta queda desarmada
+	// hasta que el precio vuelve a cruzar el objetivo por más de HysteresisPercent
+	HysteresisPercent float64 `json:"hysteresis_percent"` // Banda en % del precio objetivo (0 = sin histéresis)
+	Disarmed          bool    `json:"disarmed"`           // Disparada y esperando que el precio salga de la banda
+
+	// Alertas de indicadores técnicos ("indicator"), p. ej. RSI(14) en 1h por debajo de 30.
+	// Threshold es el nivel de RSI y ATR, y el ancho en desviaciones de Bollinger (2 por defecto)
+	Indicator          string `json:"indicator"`           // "rsi", "sma", "ema", "sma_cross", "ema_cross", "macd", "bollinger", "atr" o "volume"
+	Interval           string `json:"interval"`            // Intervalo de las velas (1m, 15m, 1h...), 1h por defecto
+	Period             int    `json:"period"`              // Periodo del indicador (media rápida en cruces y MACD)
+	SlowPeriod         int    `json:"slow_period"`         // Media lenta en cruces y MACD
+	IndicatorCondition string `json:"indicator_condition"` // "above", "below", "cross_above" o "cross_below"
+
+	// Alertas compuestas ("composite"): árbol de condiciones AND/OR guardado como JSON
+	Conditions *Condition `json:"conditions,omitempty" gorm:"type:text;serializer:json"`
+
+	// Alertas de reglas ("expression"), p. ej. `price < 60000 && change_24h < -5 && hour_utc >= 13`.
+	// Ver el paquete expression para las variables y funciones disponibles
+	Expression string `json:"expression" gorm:"type:text"`
+
+	// Horario: días, franjas y zona horaria en que se evalúa la alerta (nil = siempre).
+	// Con Outside "defer", la condición cumplida fuera de horario queda pendiente en
+	// DeferredAt y DeferredPrice (en la moneda de la alerta) hasta que abre la franja
+	Schedule      *Schedule  `json:"schedule,omitempty" gorm:"type:text;serializer:json"`
+	DeferredAt    *time.Time `json:"deferred_at"`
+	DeferredPrice float64    `json:"deferred_price"`
+
 	// Tracking de activaciones
-	LastTriggered *time.Time `json:"last_triggered"`
-	TriggerCount  int        `json:"trigger_count" gorm:"default:0"`
+	LastTriggered     *time.Time `json:"last_triggered"`
+	TriggerCount      int        `json:"trigger_count" gorm:"default:0"`
+	ResetTriggerCount int        `json:"-" gorm:"default:0"` // TriggerCount en el último reset
+
+	// Estado del modo de disparo, calculado al leer la alerta (no se guarda):
+	// próximo momento en que puede dispararse (nil si puede hacerlo ya) y si
+	// necesita un reset para volver a dispararse
+	NextEligibleAt *time.Time `json:"next_eligible_at" gorm:"-"`
+	Exhausted      bool       `json:"exhausted" gorm:"-"`
+
+	// Estado del horario, calculado al leer la alerta: si está dentro de horario y, si
+	// no, cuándo abre la próxima franja
+	InSchedule      bool       `json:"in_schedule" gorm:"-"`
+	ScheduleOpensAt *time.Time `json:"schedule_opens_at" gorm:"-"`
 }
 
 type PriceHistory struct {
@@ -59,13 +160,16 @@ type NotificationLog struct {
 }
 
 // Métodos para Alert
+
+// ShouldTrigger evalúa la alerta con el precio actual y el del tick anterior
+// (0 si no hay tick anterior)
 func (a *Alert) ShouldTrigger(currentPrice float64, previousPrice float64) bool {
 	if !a.IsActive {
 		return false
 	}
 
-	// One-Shot: Solo disparar si nunca se ha activado antes
-	if a.LastTriggered != nil {
+	// Respetar el modo de disparo (una vez, recurrente o con máximo)
+	if !a.CanTrigger(time.Now()) {
 		return false
 	}
 
@@ -74,6 +178,8 @@ func (a *Alert) ShouldTrigger(currentPrice float64, previousPrice float64) bool
 		return currentPrice >= a.TargetPrice
 	case "below":
 		return currentPrice <= a.TargetPrice
+	case "cross_up", "cross_down":
+		return a.IsCrossing(previousPrice, currentPrice)
 	case "change":
 		if previousPrice == 0 {
 			return false
@@ -85,29 +191,388 @@ func (a *Alert) ShouldTrigger(currentPrice float64, previousPrice float64) bool
 	}
 }
 
+// GetSymbol devuelve el par de la alerta, usando BTCUSDT para alertas antiguas sin símbolo
+func (a *Alert) GetSymbol() string {
+	symbol := NormalizeSymbol(a.Symbol)
+	if symbol == "" {
+		return DefaultAlertSymbol
+	}
+	return symbol
+}
+
+// GetCurrency devuelve la moneda de la alerta, usando USD para alertas antiguas sin moneda
+func (a *Alert) GetCurrency() string {
+	currency := strings.ToUpper(strings.TrimSpace(a.Currency))
+	if currency == "" {
+		return DefaultAlertCurrency
+	}
+	return currency
+}
+
+// IsPriceAlert indica si la alerta compara el precio con TargetPrice
+func (a *Alert) IsPriceAlert() bool {
+	return a.Type == "above" || a.Type == "below" || a.Type == "cross_up" || a.Type == "cross_down"
+}
+
+// IsCrossing indica si el precio cruzó TargetPrice entre el tick anterior y el actual
+// en la dirección de la alerta. Sin tick anterior (0) no hay cruce, así que una alerta
+// creada con el precio ya al otro lado del objetivo no se dispara hasta que lo cruce.
+func (a *Alert) IsCrossing(previousPrice, currentPrice float64) bool {
+	if previousPrice <= 0 {
+		return false
+	}
+	switch a.Type {
+	case "cross_up":
+		return previousPrice < a.TargetPrice && currentPrice >= a.TargetPrice
+	case "cross_down":
+		return previousPrice > a.TargetPrice && currentPrice <= a.TargetPrice
+	default:
+		return false
+	}
+}
+
+// IsOrderBookAlert indica si la alerta se evalúa sobre el libro de órdenes del símbolo
+func (a *Alert) IsOrderBookAlert() bool {
+	return a.Type == "imbalance" || a.Type == "spread" || a.Type == "liquidity"
+}
+
+// GetInterval devuelve el intervalo de las velas de una alerta "indicator"
+func (a *Alert) GetInterval() string {
+	if a.Interval == "" {
+		return DefaultIndicatorInterval
+	}
+	return a.Interval
+}
+
+// GetPeriod devuelve el periodo del indicador, o el de por defecto si no se definió
+func (a *Alert) GetPeriod() int {
+	if a.Period <= 0 {
+		return indicatorPeriods[a.Indicator][0]
+	}
+	return a.Period
+}
+
+// GetSlowPeriod devuelve la media lenta de cruces y MACD, o la de por defecto
+func (a *Alert) GetSlowPeriod() int {
+	if a.SlowPeriod <= 0 {
+		return indicatorPeriods[a.Indicator][1]
+	}
+	return a.SlowPeriod
+}
+
+// GetBollingerDeviations devuelve el ancho de las bandas de Bollinger en desviaciones estándar
+func (a *Alert) GetBollingerDeviations() float64 {
+	if a.Threshold <= 0 {
+		return 2
+	}
+	return a.Threshold
+}
+
+// indicatorDescription describe la condición de una alerta "indicator", p. ej.
+// "RSI(14) on 1h below 30.00"
+func (a *Alert) indicatorDescription() string {
+	condition := strings.Replace(a.IndicatorCondition, "cross_", "crossing ", 1)
+	interval := a.GetInterval()
+	period, slow := a.GetPeriod(), a.GetSlowPeriod()
+
+	switch a.Indicator {
+	case "rsi", "atr":
+		return fmt.Sprintf("%s(%d) on %s %s %.2f", strings.ToUpper(a.Indicator), period, interval, condition, a.Threshold)
+	case "sma", "ema":
+		return fmt.Sprintf("price %s %s(%d) on %s", condition, strings.ToUpper(a.Indicator), period, interval)
+	case "sma_cross", "ema_cross":
+		average := strings.ToUpper(strings.TrimSuffix(a.Indicator, "_cross"))
+		return fmt.Sprintf("%s(%d) %s %s(%d) on %s", average, period, condition, average, slow, interval)
+	case "macd":
+		return fmt.Sprintf("MACD(%d,%d,9) %s its signal on %s", period, slow, condition, interval)
+	case "bollinger":
+		band := "upper"
+		if strings.HasSuffix(a.IndicatorCondition, "below") {
+			band = "lower"
+		}
+		return fmt.Sprintf("close %s the %s Bollinger band (%d, %.1f) on %s", condition, band, period, a.GetBollingerDeviations(), interval)
+	case "volume":
+		return fmt.Sprintf("volume %s its %d-period average on %s", condition, period, interval)
+	default:
+		return "unknown indicator"
+	}
+}
+
+// validateIndicator valida los campos de una alerta "indicator"
+func (a *Alert) validateIndicator() error {
+	if _, ok := indicatorPeriods[a.Indicator]; !ok {
+		return fmt.Errorf("indicator must be 'rsi', 'sma', 'ema', 'sma_cross', 'ema_cross', 'macd', 'bollinger', 'atr' or 'volume'")
+	}
+
+	switch a.IndicatorCondition {
+	case "above", "below", "cross_above", "cross_below":
+	default:
+		return fmt.Errorf("indicator condition must be 'above', 'below', 'cross_above' or 'cross_below'")
+	}
+
+	validInterval := false
+	for _, interval := range indicatorIntervals {
+		validInterval = validInterval || interval == a.GetInterval()
+	}
+	if !validInterval {
+		return fmt.Errorf("interval must be one of %s", strings.Join(indicatorIntervals, ", "))
+	}
+
+	if a.Period < 0 || a.SlowPeriod < 0 || a.GetPeriod() > MaxIndicatorPeriod || a.GetSlowPeriod() > MaxIndicatorPeriod {
+		return fmt.Errorf("indicator periods must be between 1 and %d", MaxIndicatorPeriod)
+	}
+	if a.GetSlowPeriod() > 0 && a.GetSlowPeriod() <= a.GetPeriod() {
+		return fmt.Errorf("slow period must be greater than the period")
+	}
+
+	switch a.Indicator {
+	case "rsi":
+		if a.Threshold <= 0 || a.Threshold >= 100 {
+			return fmt.Errorf("RSI level must be between 0 and 100")
+		}
+	case "atr":
+		if a.Threshold <= 0 {
+			return fmt.Errorf("ATR level must be greater than 0")
+		}
+	case "bollinger":
+		if a.Threshold < 0 || a.Threshold > 5 {
+			return fmt.Errorf("Bollinger deviations must be between 0 and 5")
+		}
+	}
+	return nil
+}
+
+// GetWindow devuelve la ventana de una alerta "change_window"
+func (a *Alert) GetWindow() time.Duration {
+	return time.Duration(a.WindowMinutes) * time.Minute
+}
+
+// GetDepthPercent devuelve la banda del libro de órdenes, usando DefaultDepthPercent si no se definió
+func (a *Alert) GetDepthPercent() float64 {
+	if a.DepthPercent <= 0 {
+		return DefaultDepthPercent
+	}
+	return a.DepthPercent
+}
+
+// MatchesSymbol indica si un tick del símbolo dado aplica a esta alerta
+func (a *Alert) MatchesSymbol(symbol string) bool {
+	if symbol == "" {
+		symbol = DefaultAlertSymbol
+	}
+	return a.GetSymbol() == NormalizeSymbol(symbol)
+}
+
 func (a *Alert) GetDescription() string {
+	asset := "Bitcoin"
+	if a.GetSymbol() != DefaultAlertSymbol {
+		asset = a.GetSymbol()
+	}
+
+	description := a.conditionDescription()
+	if description == "" {
+		return "Unknown alert type"
+	}
+	return fmt.Sprintf("%s %s", asset, description)
+}
+
+// conditionDescription describe la condición de la alerta sin el activo ("price above $50000.00"),
+// o devuelve "" si el tipo no es válido
+func (a *Alert) conditionDescription() string {
 	switch a.Type {
 	case "above":
-		return fmt.Sprintf("Bitcoin price above $%.2f", a.TargetPrice)
+		return fmt.Sprintf("price above %s", a.formatTargetPrice())
 	case "below":
-		return fmt.Sprintf("Bitcoin price below $%.2f", a.TargetPrice)
+		return fmt.Sprintf("price below %s", a.formatTargetPrice())
+	case "cross_up":
+		return fmt.Sprintf("price crossing up %s", a.formatTargetPrice())
+	case "cross_down":
+		return fmt.Sprintf("price crossing down %s", a.formatTargetPrice())
 	case "change":
-		return fmt.Sprintf("Bitcoin price change of %.2f%%", a.Percentage)
+		return fmt.Sprintf("price change of %.2f%%", a.Percentage)
+	case "change_window":
+		return fmt.Sprintf("price change of %.2f%% within %d minutes", a.Percentage, a.WindowMinutes)
+	case "indicator":
+		return a.indicatorDescription()
+	case "composite":
+		if a.Conditions == nil {
+			return "with no conditions"
+		}
+		return a.Conditions.describe(a, true)
+	case "expression":
+		return fmt.Sprintf("rule: %s", a.Expression)
+	case "imbalance":
+		if a.Percentage < 0 {
+			return fmt.Sprintf("order book ask imbalance of %.2f%% or more (within %.2f%%)", -a.Percentage, a.GetDepthPercent())
+		}
+		return fmt.Sprintf("order book bid imbalance of %.2f%% or more (within %.2f%%)", a.Percentage, a.GetDepthPercent())
+	case "spread":
+		return fmt.Sprintf("spread above %.2f bps", a.Threshold)
+	case "liquidity":
+		return fmt.Sprintf("bid liquidity within %.2f%% below %g", a.GetDepthPercent(), a.Threshold)
+	case AlertTypeOrderFill:
+		return "order filled"
 	default:
-		return "Unknown alert type"
+		return ""
 	}
 }
 
+// formatTargetPrice formatea el precio objetivo en la moneda de la alerta ("$50000.00", "250000000.00 COP")
+func (a *Alert) formatTargetPrice() string {
+	if currency := a.GetCurrency(); currency != DefaultAlertCurrency {
+		return fmt.Sprintf("%.2f %s", a.TargetPrice, currency)
+	}
+	return fmt.Sprintf("$%.2f", a.TargetPrice)
+}
+
+// GetTriggerMode devuelve el modo de disparo, usando "once" para alertas antiguas sin modo
+func (a *Alert) GetTriggerMode() string {
+	mode := strings.ToLower(strings.TrimSpace(a.TriggerMode))
+	if mode == "" {
+		return TriggerModeOnce
+	}
+	return mode
+}
+
+// GetCooldown devuelve la espera mínima entre disparos
+func (a *Alert) GetCooldown() time.Duration {
+	return time.Duration(a.CooldownSeconds) * time.Second
+}
+
+// TriggersSinceReset devuelve cuántas veces se disparó la alerta desde el último reset
+func (a *Alert) TriggersSinceReset() int {
+	return a.TriggerCount - a.ResetTriggerCount
+}
+
+// IsExhausted indica si la alerta ya no se disparará hasta que se resetee.
+// Con histéresis, las alertas "once" se rearman solas y nunca se agotan.
+func (a *Alert) IsExhausted() bool {
+	switch a.GetTriggerMode() {
+	case TriggerModeRecurring:
+		return false
+	case TriggerModeMaxCount:
+		return a.TriggersSinceReset() >= a.MaxTriggers
+	default:
+		return a.LastTriggered != nil && !a.UsesHysteresis()
+	}
+}
+
+// CanTrigger indica si el modo de disparo y la histéresis permiten que la alerta se
+// dispare en now, sin evaluar su condición
+func (a *Alert) CanTrigger(now time.Time) bool {
+	if !a.IsActive || a.Disarmed || a.IsExhausted() {
+		return false
+	}
+	next := a.nextEligibleAt()
+	return next == nil || !now.Before(*next)
+}
+
+// nextEligibleAt devuelve el fin del cooldown del último disparo, o nil si no hay espera
+func (a *Alert) nextEligibleAt() *time.Time {
+	if a.LastTriggered == nil || a.CooldownSeconds <= 0 || a.GetTriggerMode() == TriggerModeOnce {
+		return nil
+	}
+	next := a.LastTriggered.Add(a.GetCooldown())
+	return &next
+}
+
+// updateTriggerState calcula NextEligibleAt, Exhausted, InSchedule y ScheduleOpensAt
+// para mostrarlos en la UI
+func (a *Alert) updateTriggerState(now time.Time) {
+	a.InSchedule = a.InScheduleAt(now)
+	a.ScheduleOpensAt = nil
+	if !a.InSchedule {
+		if opens, ok := a.Schedule.NextOpen(now); ok {
+			a.ScheduleOpensAt = &opens
+		}
+	}
+
+	a.NextEligibleAt = nil
+	a.Exhausted = a.IsExhausted()
+	if a.Exhausted {
+		return
+	}
+	if next := a.nextEligibleAt(); next != nil && now.Before(*next) {
+		a.NextEligibleAt = next
+	}
+}
+
+// InScheduleAt indica si la alerta puede evaluarse en t según su horario
+func (a *Alert) InScheduleAt(t time.Time) bool {
+	return a.Schedule == nil || a.Schedule.Contains(t)
+}
+
+// DefersOutsideSchedule indica si la condición cumplida fuera de horario se notifica
+// al abrir la siguiente franja en lugar de ignorarse
+func (a *Alert) DefersOutsideSchedule() bool {
+	return a.Schedule != nil && a.Schedule.GetOutside() == ScheduleDefer
+}
+
+// Defer deja pendiente la notificación de una condición cumplida fuera de horario, con
+// el precio de ese momento. Solo se guarda la primera hasta que se entregue
+func (a *Alert) Defer(price float64, at time.Time) {
+	if a.DeferredAt != nil {
+		return
+	}
+	a.DeferredAt = &at
+	a.DeferredPrice = price
+}
+
+// UsesHysteresis indica si la alerta se desarma al dispararse y se rearma sola
+func (a *Alert) UsesHysteresis() bool {
+	return (a.Type == "above" || a.Type == "below") && a.HysteresisPercent > 0
+}
+
+// RearmPrice devuelve el precio que debe cruzarse para rearmar la alerta: por debajo
+// del objetivo en alertas "above" y por encima en alertas "below"
+func (a *Alert) RearmPrice() float64 {
+	if a.Type == "below" {
+		return a.TargetPrice * (1 + a.HysteresisPercent/100)
+	}
+	return a.TargetPrice * (1 - a.HysteresisPercent/100)
+}
+
+// ShouldRearm indica si una alerta desarmada debe rearmarse con el precio actual
+// (en la moneda de la alerta)
+func (a *Alert) ShouldRearm(price float64) bool {
+	if !a.Disarmed {
+		return false
+	}
+	if !a.UsesHysteresis() {
+		return true // La histéresis se quitó mientras estaba desarmada
+	}
+	if a.Type == "below" {
+		return price >= a.RearmPrice()
+	}
+	return price <= a.RearmPrice()
+}
+
+// Rearm vuelve a armar una alerta desarmada por histéresis
+func (a *Alert) Rearm() {
+	a.Disarmed = false
+	a.updateTriggerState(time.Now())
+}
+
 func (a *Alert) MarkTriggered() {
 	now := time.Now()
 	a.LastTriggered = &now
 	a.TriggerCount++
+	a.Disarmed = a.UsesHysteresis()
+	a.DeferredAt = nil
+	a.DeferredPrice = 0
+	a.updateTriggerState(now)
 }
 
 // ResetAlert resetea una alerta para poder dispararse de nuevo
 func (a *Alert) Reset() {
 	a.LastTriggered = nil
-	// TriggerCount se mantiene como historial
+	a.NextEligibleAt = nil
+	a.Exhausted = false
+	a.Disarmed = false
+	a.DeferredAt = nil
+	a.DeferredPrice = 0
+	// TriggerCount se mantiene como historial; el máximo de "max_count" cuenta desde aquí
+	a.ResetTriggerCount = a.TriggerCount
 }
 
 // Validaciones
@@ -116,11 +581,47 @@ func (a *Alert) Validate() error {
 		return fmt.Errorf("alert name is required")
 	}
 
-	if a.Type != "above" && a.Type != "below" && a.Type != "change" {
-		return fmt.Errorf("alert type must be 'above', 'below', or 'change'")
+	for _, r := range NormalizeSymbol(a.Symbol) {
+		if (r < 'A' || r > 'Z') && (r < '0' || r > '9') {
+			return fmt.Errorf("symbol must contain only letters and digits (e.g. BTCUSDT)")
+		}
+	}
+
+	if !a.IsPriceAlert() && a.Type != "change" && a.Type != "change_window" && a.Type != "indicator" && a.Type != "composite" && a.Type != "expression" && !a.IsOrderBookAlert() {
+		return fmt.Errorf("alert type must be 'above', 'below', 'cross_up', 'cross_down', 'change', 'change_window', 'imbalance', 'spread', 'liquidity', 'indicator', 'composite' or 'expression'")
+	}
+
+	switch a.GetTriggerMode() {
+	case TriggerModeOnce:
+	case TriggerModeRecurring:
+		if a.CooldownSeconds <= 0 {
+			return fmt.Errorf("recurring alerts need a cooldown greater than 0")
+		}
+	case TriggerModeMaxCount:
+		if a.MaxTriggers < 1 {
+			return fmt.Errorf("max_count alerts need max_triggers of at least 1")
+		}
+	default:
+		return fmt.Errorf("trigger mode must be 'once', 'recurring' or 'max_count'")
+	}
+
+	if a.CooldownSeconds < 0 {
+		return fmt.Errorf("cooldown must not be negative")
+	}
+
+	if a.HysteresisPercent < 0 || a.HysteresisPercent > 50 {
+		return fmt.Errorf("hysteresis percent must be between 0 and 50")
 	}
 
-	if (a.Type == "above" || a.Type == "below") && a.TargetPrice <= 0 {
+	currency := a.GetCurrency()
+	if len(currency) != 3 || strings.Trim(currency, "ABCDEFGHIJKLMNOPQRSTUVWXYZ") != "" {
+		return fmt.Errorf("currency must be a 3-letter ISO code (e.g. USD, COP, EUR)")
+	}
+	if currency != DefaultAlertCurrency && !IsUSDQuoted(a.GetSymbol()) {
+		return fmt.Errorf("currency %s needs a symbol quoted in USDT, USDC or BUSD, %s is not", currency, a.GetSymbol())
+	}
+
+	if a.IsPriceAlert() && a.TargetPrice <= 0 {
 		return fmt.Errorf("target price must be greater than 0")
 	}
 
@@ -128,6 +629,56 @@ func (a *Alert) Validate() error {
 		return fmt.Errorf("percentage must be between -100 and 100")
 	}
 
+	if a.Type == "change_window" {
+		if a.Percentage == 0 || a.Percentage < -100 || a.Percentage > 100 {
+			return fmt.Errorf("window change percentage must be between -100 and 100, and not 0")
+		}
+		if a.WindowMinutes < 1 || a.WindowMinutes > MaxWindowMinutes {
+			return fmt.Errorf("window minutes must be between 1 and %d", MaxWindowMinutes)
+		}
+	}
+
+	if a.Type == "indicator" {
+		if err := a.validateIndicator(); err != nil {
+			return err
+		}
+	}
+
+	if a.Type == "composite" {
+		if a.Conditions == nil {
+			return fmt.Errorf("composite alerts need conditions")
+		}
+		leaves := 0
+		if err := a.Conditions.validate(a, "", 1, &leaves); err != nil {
+			return err
+		}
+	}
+
+	if a.Type == "expression" {
+		// Compilar valida la sintaxis, las variables, las funciones y sus argumentos
+		if _, err := expression.Compile(a.Expression); err != nil {
+			return fmt.Errorf("invalid expression: %w", err)
+		}
+	}
+
+	if a.Schedule != nil {
+		if err := a.Schedule.validate(); err != nil {
+			return err
+		}
+	}
+
+	if a.Type == "imbalance" && (a.Percentage == 0 || a.Percentage < -100 || a.Percentage > 100) {
+		return fmt.Errorf("imbalance percentage must be between -100 and 100, and not 0")
+	}
+
+	if (a.Type == "spread" || a.Type == "liquidity") && a.Threshold <= 0 {
+		return fmt.Errorf("threshold must be greater than 0")
+	}
+
+	if a.DepthPercent < 0 || a.DepthPercent > 50 {
+		return fmt.Errorf("depth percent must be between 0 and 50")
+	}
+
 	if a.EnableEmail && a.Email == "" {
 		return fmt.Errorf("email is required when email notifications are enabled")
 	}
@@ -137,10 +688,42 @@ func (a *Alert) Validate() error {
 
 // Hook para GORM - ejecutar antes de crear
 func (a *Alert) BeforeCreate(tx *gorm.DB) error {
+	a.Symbol = a.GetSymbol()
+	a.Currency = a.GetCurrency()
+	a.TriggerMode = a.GetTriggerMode()
 	return a.Validate()
 }
 
 // Hook para GORM - ejecutar antes de actualizar
 func (a *Alert) BeforeUpdate(tx *gorm.DB) error {
+	a.Symbol = a.GetSymbol()
+	a.Currency = a.GetCurrency()
+	a.TriggerMode = a.GetTriggerMode()
 	return a.Validate()
 }
+
+// Hook para GORM - ejecutar después de leer
+func (a *Alert) AfterFind(tx *gorm.DB) error {
+	a.updateTriggerState(time.Now())
+	return nil
+}
+
+// NormalizeSymbol convierte un símbolo a su forma canónica de Binance ("btcusdt " -> "BTCUSDT")
+func NormalizeSymbol(symbol string) string {
+	return strings.ToUpper(strings.TrimSpace(symbol))
+}
+
+// usdQuoteAssets son los activos de cotización que valen un dólar: solo el precio de los
+// símbolos cotizados en ellos se puede convertir a otra moneda con las tasas de cambio
+var usdQuoteAssets = []string{"USDT", "USDC", "BUSD", "FDUSD", "TUSD", "USD"}
+
+// IsUSDQuoted indica si el símbolo cotiza en dólares ("BTCUSDT" sí, "ETHBTC" no)
+func IsUSDQuoted(symbol string) bool {
+	symbol = NormalizeSymbol(symbol)
+	for _, quote := range usdQuoteAssets {
+		if strings.HasSuffix(symbol, quote) && len(symbol) > len(quote) {
+			return true
+		}
+	}
+	return false
+}
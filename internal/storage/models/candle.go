@@ -0,0 +1,33 @@
+package models
+
+import (
+	"time"
+)
+
+// Candle is an OHLCV kline of a symbol at a given interval (1m, 1h, 1d...).
+// A candle is identified by its symbol, interval and open time.
+type Candle struct {
+	ID                  uint      `json:"id" gorm:"primaryKey"`
+	Symbol              string    `json:"symbol" gorm:"not null;uniqueIndex:idx_candles_symbol_interval_open_time"`
+	Interval            string    `json:"interval" gorm:"not null;uniqueIndex:idx_candles_symbol_interval_open_time"`
+	OpenTime            time.Time `json:"open_time" gorm:"not null;uniqueIndex:idx_candles_symbol_interval_open_time"`
+	CloseTime           time.Time `json:"close_time"`
+	Open                float64   `json:"open"`
+	High                float64   `json:"high"`
+	Low                 float64   `json:"low"`
+	Close               float64   `json:"close"`
+	Volume              float64   `json:"volume"`       // Base asset volume
+	QuoteVolume         float64   `json:"quote_volume"` // Quote asset volume
+	Trades              int64     `json:"trades"`
+	TakerBuyVolume      float64   `json:"taker_buy_volume"`
+	TakerBuyQuoteVolume float64   `json:"taker_buy_quote_volume"`
+	CreatedAt           time.Time `json:"created_at"`
+	UpdatedAt           time.Time `json:"updated_at"`
+}
+
+// Indexes returns the fields that should be indexed in the database
+func (Candle) Indexes() [][]string {
+	return [][]string{
+		{"open_time"},
+	}
+}
@@ -28,6 +28,10 @@ type TickerData struct {
 	LastTradeID        int64     `json:"last_trade_id"`
 	TotalTrades        int64     `json:"total_trades"`
 	CreatedAt          time.Time `json:"created_at"`
+
+	// Per-source quotes of an aggregated price point to the aggregate row
+	AggregateID *uint `json:"aggregate_id,omitempty"`
+	Rejected    bool  `json:"rejected"` // Quote deviated too far from the median
 }
 
 // Indexes returns the fields that should be indexed in the database
@@ -37,5 +41,6 @@ func (TickerData) Indexes() [][]string {
 		{"timestamp"},
 		{"source"},
 		{"symbol", "timestamp"},
+		{"aggregate_id"},
 	}
 }
@@ -0,0 +1,33 @@
+package models
+
+import (
+	"time"
+)
+
+// Trade is a fill of one of the account's Binance orders, imported from myTrades.
+// TradeID is Binance's trade ID, unique per symbol.
+type Trade struct {
+	ID              uint      `json:"id" gorm:"primaryKey"`
+	TradeID         int64     `json:"trade_id" gorm:"not null;uniqueIndex:idx_trades_symbol_trade_id"`
+	Symbol          string    `json:"symbol" gorm:"not null;uniqueIndex:idx_trades_symbol_trade_id"`
+	OrderID         int64     `json:"order_id"`
+	BaseAsset       string    `json:"base_asset" gorm:"not null"`
+	QuoteAsset      string    `json:"quote_asset" gorm:"not null"`
+	Price           float64   `json:"price"`
+	Qty             float64   `json:"qty"`
+	QuoteQty        float64   `json:"quote_qty"`
+	Commission      float64   `json:"commission"`
+	CommissionAsset string    `json:"commission_asset"`
+	IsBuyer         bool      `json:"is_buyer"`
+	IsMaker         bool      `json:"is_maker"`
+	Time            time.Time `json:"time"`
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+// Indexes returns the fields that should be indexed in the database
+func (Trade) Indexes() [][]string {
+	return [][]string{
+		{"base_asset"},
+		{"time"},
+	}
+}
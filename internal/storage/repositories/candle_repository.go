@@ -0,0 +1,80 @@
+package repositories
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/cgallonv/btc-alerta-de-precio/internal/storage/models"
+)
+
+// CandleRepository handles storage operations for OHLCV candles.
+type CandleRepository struct {
+	db *gorm.DB
+}
+
+// NewCandleRepository creates a new CandleRepository instance
+func NewCandleRepository(db *gorm.DB) *CandleRepository {
+	return &CandleRepository{db: db}
+}
+
+// StoreCandles saves candles. A candle that is already stored is overwritten, so a
+// candle saved while still open is replaced by its final values.
+func (r *CandleRepository) StoreCandles(candles []models.Candle) error {
+	if len(candles) == 0 {
+		return nil
+	}
+	return r.db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "symbol"}, {Name: "interval"}, {Name: "open_time"}},
+		DoUpdates: clause.AssignmentColumns([]string{
+			"close_time", "open", "high", "low", "close", "volume", "quote_volume",
+			"trades", "taker_buy_volume", "taker_buy_quote_volume", "updated_at",
+		}),
+	}).CreateInBatches(candles, 100).Error
+}
+
+// GetCandles returns the candles of a symbol and interval opened within [start, end],
+// oldest first. A zero start or end leaves that side of the range open. If limit is
+// positive, only the most recent limit candles of the range are returned.
+func (r *CandleRepository) GetCandles(symbol, interval string, start, end time.Time, limit int) ([]models.Candle, error) {
+	query := r.db.Where("symbol = ? AND interval = ?", symbol, interval)
+	if !start.IsZero() {
+		query = query.Where("open_time >= ?", start)
+	}
+	if !end.IsZero() {
+		query = query.Where("open_time <= ?", end)
+	}
+
+	var candles []models.Candle
+	if limit > 0 {
+		query = query.Order("open_time DESC").Limit(limit)
+	} else {
+		query = query.Order("open_time")
+	}
+	if err := query.Find(&candles).Error; err != nil {
+		return nil, err
+	}
+
+	if limit > 0 {
+		for i, j := 0, len(candles)-1; i < j; i, j = i+1, j-1 {
+			candles[i], candles[j] = candles[j], candles[i]
+		}
+	}
+	return candles, nil
+}
+
+// GetLastCandle returns the most recent candle of a symbol and interval, or nil if there is none
+func (r *CandleRepository) GetLastCandle(symbol, interval string) (*models.Candle, error) {
+	var candle models.Candle
+	err := r.db.Where("symbol = ? AND interval = ?", symbol, interval).
+		Order("open_time DESC").
+		First(&candle).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &candle, nil
+}
@@ -9,7 +9,9 @@ import (
 	"github.com/cgallonv/btc-alerta-de-precio/internal/storage/models"
 )
 
-// TickerRepository handles storage operations for ticker data
+// TickerRepository handles storage operations for ticker data.
+// Per-source quotes stored with an aggregated price are excluded from the
+// history and statistics queries so each tick is only counted once.
 type TickerRepository struct {
 	db *gorm.DB
 }
@@ -36,10 +38,40 @@ func (r *TickerRepository) Store(ticker *models.TickerData) error {
 	return r.db.Create(ticker).Error
 }
 
+// StoreAggregate saves an aggregated price and the per-source quotes it was built from.
+// The quotes are linked to the aggregate row through AggregateID so an alert can be
+// audited later. Both are written in a single transaction.
+func (r *TickerRepository) StoreAggregate(aggregate *models.TickerData, quotes []models.TickerData) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		txRepo := &TickerRepository{db: tx}
+		if err := txRepo.Store(aggregate); err != nil {
+			return fmt.Errorf("error storing aggregate ticker: %w", err)
+		}
+
+		for i := range quotes {
+			quotes[i].AggregateID = &aggregate.ID
+			if err := txRepo.Store(&quotes[i]); err != nil {
+				return fmt.Errorf("error storing %s quote: %w", quotes[i].Source, err)
+			}
+		}
+		return nil
+	})
+}
+
+// GetAggregateQuotes returns the per-source quotes stored with an aggregated price
+func (r *TickerRepository) GetAggregateQuotes(aggregateID uint) ([]models.TickerData, error) {
+	var quotes []models.TickerData
+	err := r.db.Where("aggregate_id = ?", aggregateID).Order("source").Find(&quotes).Error
+	if err != nil {
+		return nil, err
+	}
+	return quotes, nil
+}
+
 // GetLatest returns the most recent ticker data for a given symbol
 func (r *TickerRepository) GetLatest(symbol string) (*models.TickerData, error) {
 	var ticker models.TickerData
-	err := r.db.Where("symbol = ?", symbol).Order("timestamp desc").First(&ticker).Error
+	err := r.db.Where("symbol = ? AND aggregate_id IS NULL", symbol).Order("timestamp desc").First(&ticker).Error
 	if err != nil {
 		return nil, err
 	}
@@ -49,7 +81,7 @@ func (r *TickerRepository) GetLatest(symbol string) (*models.TickerData, error)
 // GetHistory returns historical ticker data for a given symbol and time range
 func (r *TickerRepository) GetHistory(symbol string, start, end time.Time, limit int) ([]models.TickerData, error) {
 	var tickers []models.TickerData
-	query := r.db.Where("symbol = ? AND timestamp BETWEEN ? AND ?", symbol, start, end)
+	query := r.db.Where("symbol = ? AND timestamp BETWEEN ? AND ? AND aggregate_id IS NULL", symbol, start, end)
 
 	if limit > 0 {
 		query = query.Limit(limit)
@@ -71,12 +103,38 @@ func (r *TickerRepository) GetPriceRange(symbol string, start, end time.Time) (h
 
 	err = r.db.Model(&models.TickerData{}).
 		Select("MAX(high_price) as high_price, MIN(low_price) as low_price").
-		Where("symbol = ? AND timestamp BETWEEN ? AND ?", symbol, start, end).
+		Where("symbol = ? AND timestamp BETWEEN ? AND ? AND aggregate_id IS NULL", symbol, start, end).
 		Scan(&result).Error
 
 	return result.HighPrice, result.LowPrice, err
 }
 
+// GetPriceExtremes returns the oldest ticker and the ones with the lowest and highest last
+// price for a symbol within a time range, or nils when there are none
+func (r *TickerRepository) GetPriceExtremes(symbol string, start, end time.Time) (oldest, lowest, highest *models.TickerData, err error) {
+	extremes := []struct {
+		order  string
+		ticker **models.TickerData
+	}{
+		{"timestamp asc", &oldest},
+		{"last_price asc", &lowest},
+		{"last_price desc", &highest},
+	}
+	for _, extreme := range extremes {
+		var tickers []models.TickerData
+		err := r.db.Where("symbol = ? AND timestamp BETWEEN ? AND ? AND aggregate_id IS NULL", symbol, start, end).
+			Order(extreme.order).Limit(1).Find(&tickers).Error
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		if len(tickers) == 0 {
+			return nil, nil, nil, nil
+		}
+		*extreme.ticker = &tickers[0]
+	}
+	return oldest, lowest, highest, nil
+}
+
 // GetAveragePrice calculates the volume-weighted average price for a symbol within a time range
 func (r *TickerRepository) GetAveragePrice(symbol string, start, end time.Time) (float64, error) {
 	var result struct {
@@ -85,7 +143,7 @@ func (r *TickerRepository) GetAveragePrice(symbol string, start, end time.Time)
 
 	err := r.db.Model(&models.TickerData{}).
 		Select("SUM(last_price * volume) / SUM(volume) as vwap").
-		Where("symbol = ? AND timestamp BETWEEN ? AND ?", symbol, start, end).
+		Where("symbol = ? AND timestamp BETWEEN ? AND ? AND aggregate_id IS NULL", symbol, start, end).
 		Scan(&result).Error
 
 	return result.VWAP, err
@@ -101,7 +159,7 @@ func (r *TickerRepository) GetVolumeStats(symbol string, start, end time.Time) (
 
 	err = r.db.Model(&models.TickerData{}).
 		Select("SUM(volume) as total_volume, SUM(quote_volume) as quote_volume, SUM(total_trades) as total_trades").
-		Where("symbol = ? AND timestamp BETWEEN ? AND ?", symbol, start, end).
+		Where("symbol = ? AND timestamp BETWEEN ? AND ? AND aggregate_id IS NULL", symbol, start, end).
 		Scan(&result).Error
 
 	return result.TotalVolume, result.QuoteVolume, result.TotalTrades, err
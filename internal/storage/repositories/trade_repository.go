@@ -0,0 +1,47 @@
+package repositories
+
+import (
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/cgallonv/btc-alerta-de-precio/internal/storage/models"
+)
+
+// TradeRepository handles storage operations for imported account trades.
+type TradeRepository struct {
+	db *gorm.DB
+}
+
+// NewTradeRepository creates a new TradeRepository instance
+func NewTradeRepository(db *gorm.DB) *TradeRepository {
+	return &TradeRepository{db: db}
+}
+
+// StoreTrades saves trades, skipping any already stored, and returns how many were new
+func (r *TradeRepository) StoreTrades(trades []models.Trade) (int64, error) {
+	if len(trades) == 0 {
+		return 0, nil
+	}
+	result := r.db.Clauses(clause.OnConflict{DoNothing: true}).CreateInBatches(trades, 100)
+	return result.RowsAffected, result.Error
+}
+
+// GetLastTradeID returns the highest Binance trade ID stored for a symbol, or 0 if there is none
+func (r *TradeRepository) GetLastTradeID(symbol string) (int64, error) {
+	var lastID int64
+	err := r.db.Model(&models.Trade{}).
+		Where("symbol = ?", symbol).
+		Select("COALESCE(MAX(trade_id), 0)").
+		Scan(&lastID).Error
+	return lastID, err
+}
+
+// GetTrades returns every stored trade in execution order
+func (r *TradeRepository) GetTrades() ([]models.Trade, error) {
+	var trades []models.Trade
+	err := r.db.Order("time, trade_id").Find(&trades).Error
+	if err != nil {
+		return nil, err
+	}
+	return trades, nil
+}
@@ -0,0 +1,246 @@
+package storage
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Qué hace una alerta con horario cuando su condición se cumple fuera de él.
+const (
+	ScheduleDrop  = "drop"  // Se ignora
+	ScheduleDefer = "defer" // Se notifica cuando abre la siguiente franja
+)
+
+// MaxScheduleWindows is how many time windows a schedule can have.
+const MaxScheduleWindows = 10
+
+// scheduleDays son los días de un horario, en el orden de time.Weekday
+var scheduleDays = []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}
+
+// scheduleLocations guarda las zonas horarias ya cargadas por nombre, para no leer la
+// base de zonas en cada tick
+var scheduleLocations sync.Map
+
+// Schedule es el horario de una alerta: los días de la semana y las franjas horarias en
+// que se evalúa, en una zona horaria IANA. Sin días se evalúa todos los días y sin
+// franjas todo el día. Una franja que termina antes de empezar cruza la medianoche y
+// pertenece al día en que empieza: "fri" 22:00-02:00 incluye la madrugada del sábado.
+//
+// Example usage:
+//
+//	// Horario del mercado de EE. UU.; lo que ocurra fuera de él se notifica al abrir
+//	alert.Schedule = &Schedule{
+//	    Days:     []string{"mon", "tue", "wed", "thu", "fri"},
+//	    Windows:  []TimeWindow{{Start: "09:30", End: "16:00"}},
+//	    TimeZone: "America/New_York",
+//	    Outside:  ScheduleDefer,
+//	}
+type Schedule struct {
+	Days     []string     `json:"days,omitempty"`      // "mon", "tue"... "sun"; vacío = todos los días
+	Windows  []TimeWindow `json:"windows,omitempty"`   // Vacío = todo el día
+	TimeZone string       `json:"time_zone,omitempty"` // Zona IANA ("America/New_York"), UTC por defecto
+	Outside  string       `json:"outside,omitempty"`   // "drop" (por defecto) o "defer"
+}
+
+// TimeWindow es una franja horaria de Start a End ("HH:MM", End excluido). End puede
+// ser "24:00" para llegar hasta la medianoche.
+type TimeWindow struct {
+	Start string `json:"start"`
+	End   string `json:"end"`
+}
+
+// IsEmpty indica si el horario no restringe nada (todos los días, todo el día)
+func (s *Schedule) IsEmpty() bool {
+	return len(s.Days) == 0 && len(s.Windows) == 0
+}
+
+// GetOutside devuelve qué hacer fuera de horario, "drop" por defecto
+func (s *Schedule) GetOutside() string {
+	if s.Outside == "" {
+		return ScheduleDrop
+	}
+	return s.Outside
+}
+
+// Location devuelve la zona horaria del horario, UTC si no tiene o no es válida
+func (s *Schedule) Location() *time.Location {
+	if s.TimeZone == "" {
+		return time.UTC
+	}
+	location, err := loadLocation(s.TimeZone)
+	if err != nil {
+		return time.UTC
+	}
+	return location
+}
+
+// loadLocation carga una zona horaria IANA, una sola vez por nombre
+func loadLocation(name string) (*time.Location, error) {
+	if location, ok := scheduleLocations.Load(name); ok {
+		return location.(*time.Location), nil
+	}
+	location, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, err
+	}
+	scheduleLocations.Store(name, location)
+	return location, nil
+}
+
+// Contains indica si t está dentro del horario
+func (s *Schedule) Contains(t time.Time) bool {
+	local := t.In(s.Location())
+	today := local.Weekday()
+	if len(s.Windows) == 0 {
+		return s.hasDay(today)
+	}
+
+	minute := local.Hour()*60 + local.Minute()
+	yesterday := (today + 6) % 7
+	for _, window := range s.Windows {
+		start, end, err := window.minutes()
+		if err != nil {
+			continue
+		}
+		if start < end {
+			if s.hasDay(today) && minute >= start && minute < end {
+				return true
+			}
+			continue
+		}
+		// La franja cruza la medianoche: empezó hoy o ayer
+		if (s.hasDay(today) && minute >= start) || (s.hasDay(yesterday) && minute < end) {
+			return true
+		}
+	}
+	return false
+}
+
+// NextOpen devuelve el próximo momento desde t en que el horario está abierto (t si ya
+// lo está), o false si no abre en la próxima semana
+func (s *Schedule) NextOpen(t time.Time) (time.Time, bool) {
+	if s.Contains(t) {
+		return t, true
+	}
+
+	location := s.Location()
+	local := t.In(location)
+	var next time.Time
+	for d := 0; d <= 7; d++ {
+		day := time.Date(local.Year(), local.Month(), local.Day()+d, 0, 0, 0, 0, location)
+		if !s.hasDay(day.Weekday()) {
+			continue
+		}
+		starts := []int{0}
+		if len(s.Windows) > 0 {
+			starts = starts[:0]
+			for _, window := range s.Windows {
+				if start, _, err := window.minutes(); err == nil {
+					starts = append(starts, start)
+				}
+			}
+		}
+		for _, start := range starts {
+			candidate := time.Date(day.Year(), day.Month(), day.Day(), start/60, start%60, 0, 0, location)
+			if candidate.After(t) && (next.IsZero() || candidate.Before(next)) {
+				next = candidate
+			}
+		}
+		if !next.IsZero() {
+			return next, true
+		}
+	}
+	return next, false
+}
+
+// hasDay indica si el horario incluye el día de la semana
+func (s *Schedule) hasDay(day time.Weekday) bool {
+	if len(s.Days) == 0 {
+		return true
+	}
+	for _, name := range s.Days {
+		if strings.ToLower(name) == scheduleDays[day] {
+			return true
+		}
+	}
+	return false
+}
+
+// validate valida los días, las franjas, la zona horaria y la acción fuera de horario
+func (s *Schedule) validate() error {
+	for _, day := range s.Days {
+		valid := false
+		for _, name := range scheduleDays {
+			valid = valid || strings.ToLower(day) == name
+		}
+		if !valid {
+			return fmt.Errorf("schedule days must be %s", strings.Join(scheduleDays, ", "))
+		}
+	}
+
+	if len(s.Windows) > MaxScheduleWindows {
+		return fmt.Errorf("a schedule can have at most %d windows", MaxScheduleWindows)
+	}
+	for _, window := range s.Windows {
+		start, end, err := window.minutes()
+		if err != nil {
+			return err
+		}
+		if start == end {
+			return fmt.Errorf("schedule window %s-%s is empty", window.Start, window.End)
+		}
+	}
+
+	if s.TimeZone != "" {
+		if _, err := loadLocation(s.TimeZone); err != nil {
+			return fmt.Errorf("unknown schedule time zone %q, use an IANA name such as America/New_York", s.TimeZone)
+		}
+	}
+
+	switch s.GetOutside() {
+	case ScheduleDrop, ScheduleDefer:
+		return nil
+	default:
+		return fmt.Errorf("schedule outside must be 'drop' or 'defer'")
+	}
+}
+
+// minutes devuelve el inicio y el fin de la franja en minutos desde la medianoche
+func (w TimeWindow) minutes() (int, int, error) {
+	start, err := parseClock(w.Start, false)
+	if err != nil {
+		return 0, 0, err
+	}
+	end, err := parseClock(w.End, true)
+	if err != nil {
+		return 0, 0, err
+	}
+	return start, end, nil
+}
+
+// parseClock convierte "HH:MM" en minutos desde la medianoche; "24:00" solo como fin
+func parseClock(clock string, end bool) (int, error) {
+	invalid := fmt.Errorf("schedule times must be HH:MM between 00:00 and 23:59 (or 24:00 as the end), got %q", clock)
+	hours, minutes, ok := strings.Cut(clock, ":")
+	if !ok || len(hours) != 2 || len(minutes) != 2 {
+		return 0, invalid
+	}
+	h, err := strconv.Atoi(hours)
+	if err != nil {
+		return 0, invalid
+	}
+	m, err := strconv.Atoi(minutes)
+	if err != nil || m < 0 || m > 59 {
+		return 0, invalid
+	}
+	if h == 24 && m == 0 && end {
+		return 24 * 60, nil
+	}
+	if h < 0 || h > 23 {
+		return 0, invalid
+	}
+	return h*60 + m, nil
+}
@@ -0,0 +1,92 @@
+package storage
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchedule_Contains(t *testing.T) {
+	overnight := &Schedule{Days: []string{"fri"}, Windows: []TimeWindow{{Start: "22:00", End: "02:00"}}}
+	evening := &Schedule{Windows: []TimeWindow{{Start: "18:00", End: "24:00"}}}
+	market := &Schedule{
+		Days:     []string{"mon", "tue", "wed", "thu", "fri"},
+		Windows:  []TimeWindow{{Start: "09:30", End: "16:00"}},
+		TimeZone: "America/New_York",
+	}
+
+	tests := []struct {
+		name     string
+		schedule *Schedule
+		at       time.Time
+		want     bool
+	}{
+		// 2026-03-06 is a Friday
+		{"overnight before start", overnight, utc(2026, 3, 6, 21, 59), false},
+		{"overnight start", overnight, utc(2026, 3, 6, 22, 0), true},
+		{"overnight saturday early morning", overnight, utc(2026, 3, 7, 1, 30), true},
+		{"overnight end excluded", overnight, utc(2026, 3, 7, 2, 0), false},
+		{"overnight saturday night", overnight, utc(2026, 3, 7, 23, 0), false},
+		{"overnight friday early morning", overnight, utc(2026, 3, 6, 1, 0), false},
+		{"24:00 end last minute", evening, utc(2026, 3, 6, 23, 59), true},
+		{"24:00 end midnight", evening, utc(2026, 3, 7, 0, 0), false},
+		// New York is UTC-5 until 2026-03-08 and UTC-4 after
+		{"new york open before DST", market, utc(2026, 3, 6, 14, 30), true},
+		{"new york 08:30 before DST", market, utc(2026, 3, 6, 13, 30), false},
+		{"new york open after DST", market, utc(2026, 3, 9, 13, 30), true},
+		{"new york 15:30 after DST", market, utc(2026, 3, 9, 19, 30), true},
+		{"new york 16:00 after DST", market, utc(2026, 3, 9, 20, 0), false},
+		{"new york sunday", market, utc(2026, 3, 8, 15, 0), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.schedule.Contains(tt.at))
+		})
+	}
+}
+
+func TestSchedule_NextOpen(t *testing.T) {
+	sundays := &Schedule{Days: []string{"sun"}, Windows: []TimeWindow{{Start: "10:00", End: "11:00"}}}
+	overnight := &Schedule{Days: []string{"fri"}, Windows: []TimeWindow{{Start: "22:00", End: "02:00"}}}
+	mondays := &Schedule{Days: []string{"mon"}}
+	market := &Schedule{
+		Days:     []string{"mon", "tue", "wed", "thu", "fri"},
+		Windows:  []TimeWindow{{Start: "09:30", End: "16:00"}},
+		TimeZone: "America/New_York",
+	}
+
+	tests := []struct {
+		name     string
+		schedule *Schedule
+		from     time.Time
+		want     time.Time
+	}{
+		{"already open", sundays, utc(2026, 3, 8, 10, 30), utc(2026, 3, 8, 10, 30)},
+		{"later the same day", sundays, utc(2026, 3, 8, 9, 0), utc(2026, 3, 8, 10, 0)},
+		{"saturday to sunday", sundays, utc(2026, 3, 7, 12, 0), utc(2026, 3, 8, 10, 0)},
+		{"across the week boundary", sundays, utc(2026, 3, 8, 12, 0), utc(2026, 3, 15, 10, 0)},
+		{"after an overnight window", overnight, utc(2026, 3, 7, 3, 0), utc(2026, 3, 13, 22, 0)},
+		{"whole day", mondays, utc(2026, 3, 10, 8, 0), utc(2026, 3, 16, 0, 0)},
+		{"weekend and DST change", market, utc(2026, 3, 6, 21, 0), utc(2026, 3, 9, 13, 30)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			next, ok := tt.schedule.NextOpen(tt.from)
+			require.True(t, ok)
+			assert.True(t, tt.want.Equal(next), "got %s", next.UTC())
+		})
+	}
+}
+
+func TestSchedule_Location(t *testing.T) {
+	schedule := &Schedule{TimeZone: "America/New_York"}
+	assert.Same(t, schedule.Location(), (&Schedule{TimeZone: "America/New_York"}).Location())
+	assert.Equal(t, time.UTC, (&Schedule{}).Location())
+	assert.Equal(t, time.UTC, (&Schedule{TimeZone: "Mars/Olympus"}).Location())
+}
+
+func utc(year int, month time.Month, day, hour, minute int) time.Time {
+	return time.Date(year, month, day, hour, minute, 0, 0, time.UTC)
+}
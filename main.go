@@ -24,8 +24,8 @@ type AlertServiceAdapter struct {
 	db *storage.Database
 }
 
-func (a *AlertServiceAdapter) GetPriceHistory(limit int) ([]interfaces.PriceCacheEntry, error) {
-	history, err := a.AlertManager.GetPriceHistory(limit)
+func (a *AlertServiceAdapter) GetPriceHistory(symbol string, limit int) ([]interfaces.PriceCacheEntry, error) {
+	history, err := a.AlertManager.GetPriceHistory(symbol, limit)
 	return history, err
 }
 
@@ -33,13 +33,6 @@ func (a *AlertServiceAdapter) GetStats() (map[string]interface{}, error) {
 	return a.db.GetStats()
 }
 
-// AlertEvaluator implements the AlertEvaluator interface
-type AlertEvaluator struct{}
-
-func (e *AlertEvaluator) ShouldTrigger(alert *storage.Alert, priceData *bitcoin.PriceData) bool {
-	return alert.ShouldTrigger(priceData.Price, 0)
-}
-
 func main() {
 	// Set Gin to release mode
 	gin.SetMode(gin.ReleaseMode)
@@ -60,12 +53,49 @@ func main() {
 	if err := migrations.MigrateTickerData(db.DB()); err != nil {
 		log.Fatalf("Error running migrations: %v", err)
 	}
+	if err := migrations.MigrateTrades(db.DB()); err != nil {
+		log.Fatalf("Error running migrations: %v", err)
+	}
+	if err := migrations.MigrateCandles(db.DB()); err != nil {
+		log.Fatalf("Error running migrations: %v", err)
+	}
 
 	// Create repositories
 	tickerRepo := repositories.NewTickerRepository(db.DB())
+	tradeRepo := repositories.NewTradeRepository(db.DB())
+	candleRepo := repositories.NewCandleRepository(db.DB())
 
 	// Create storage handlers
 	tickerStorage := bitcoin.NewTickerStorage(tickerRepo)
+	tradeHistory := bitcoin.NewTradeHistory(tradeRepo)
+	candleStorage := bitcoin.NewCandleStorage(candleRepo)
+
+	// Share the configured request weight budget, retry policy, circuit breaker,
+	// server clock, ticker cache and exchange info with every Binance client
+	bitcoin.WeightLimiterFor(cfg.BinanceBaseURL).SetLimit(cfg.BinanceWeightLimit)
+	bitcoin.SetRetryPolicy(bitcoin.RetryPolicy{
+		MaxRetries: cfg.BinanceMaxRetries,
+		BaseDelay:  cfg.BinanceRetryBaseDelay,
+		MaxDelay:   cfg.BinanceRetryMaxDelay,
+	})
+	bitcoin.CircuitBreakerFor(cfg.BinanceBaseURL).Configure(cfg.BinanceBreakerThreshold, cfg.BinanceBreakerCooldown)
+	bitcoin.ServerClockFor(cfg.BinanceBaseURL).Configure(cfg.BinanceRecvWindow, cfg.BinanceTimeSyncInterval)
+	bitcoin.TickerCacheFor(cfg.BinanceBaseURL).SetTTL(cfg.TickerCacheTTL)
+	bitcoin.SetTradingDryRun(cfg.TradingDryRun)
+
+	// Load the symbol list used to validate alerts, orders and the default symbols
+	exchangeInfo := bitcoin.ExchangeInfoFor(cfg.BinanceBaseURL)
+	exchangeInfo.SetRefreshInterval(cfg.ExchangeInfoRefresh)
+	exchangeInfoClient := bitcoin.NewBinanceClient("", "", cfg.BinanceBaseURL, nil)
+	if err := exchangeInfo.Refresh(exchangeInfoClient); err != nil {
+		log.Printf("⚠️ Could not load exchange info, symbols won't be validated until it loads: %v", err)
+	}
+	// Only a warning: fiat currencies such as COP aren't traded on Binance but their
+	// balances are still valued with FX rates
+	if _, unknown := exchangeInfo.KnownAssets(cfg.BinanceDefaultSymbols); len(unknown) > 0 {
+		log.Printf("⚠️ Assets in BINANCE_DEFAULT_SYMBOLS not listed on Binance (expected for fiat currencies): %v", unknown)
+	}
+	exchangeInfo.Start(context.Background(), exchangeInfoClient)
 
 	// Create adapters
 	configAdapter := adapters.NewConfigAdapter(cfg)
@@ -73,24 +103,89 @@ func main() {
 	// Create services
 	notificationService := notifications.NewService(cfg, db)
 
+	// Create price providers (tried in order when the Binance stream is down,
+	// or combined into a median price when aggregation is enabled)
+	var priceProvider interfaces.PriceProvider
+	if cfg.PriceAggregationEnabled {
+		// The aggregator stores each quote next to the aggregate, so providers don't store their own
+		providers := adapters.NewPriceProviders(configAdapter, nil)
+		priceProvider = alerts.NewPriceAggregator(providers, configAdapter.GetPriceOutlierBand(), configAdapter.GetPriceQuorum(), tickerStorage)
+	} else {
+		priceProvider = alerts.NewPriceProviderChain(adapters.NewPriceProviders(configAdapter, tickerStorage)...)
+	}
+	log.Printf("🔧 Price providers: %s", priceProvider.Name())
+
 	// Create alert manager (which creates its own price monitor)
 	alertManager, err := alerts.NewAlertManager(
 		configAdapter,
 		notificationService,
-		&AlertEvaluator{},
+		adapters.NewCompositeAlertEvaluator(adapters.NewAlertEvaluator()),
 		db,
 		db,
+		priceProvider,
 		tickerStorage,
 	)
 	if err != nil {
 		log.Fatalf("Error creating alert manager: %v", err)
 	}
 
+	// Track the order books of symbols with order book alerts (imbalance, spread, liquidity)
+	orderBooks := bitcoin.NewOrderBooks(bitcoin.NewBinanceClient("", "", cfg.BinanceBaseURL, nil), cfg.BinanceStreamURL)
+	orderBooks.Start(context.Background())
+	alertManager.SetOrderBooks(orderBooks)
+
+	// Convert prices for alerts and balances in other currencies: Binance fiat
+	// markets first, configured static rates for currencies Binance doesn't list
+	fxRates := alerts.NewFXRates(cfg.FXRatesTTL,
+		bitcoin.NewBinanceFXProvider(bitcoin.NewBinanceClient("", "", cfg.BinanceBaseURL, nil)),
+		alerts.NewStaticFXProvider(cfg.FXStaticRates),
+	)
+	alertManager.SetFXRates(fxRates)
+
+	// Technical indicator alerts, seeded from the stored candles
+	indicatorEngine := alerts.NewIndicatorEngine(candleStorage, bitcoin.NewBinanceClient("", "", cfg.BinanceBaseURL, nil))
+	alertManager.SetIndicatorEngine(indicatorEngine)
+
 	// Start alert manager (which starts price monitoring)
 	if err := alertManager.Start(context.Background()); err != nil {
 		log.Printf("Error starting alert manager: %v", err)
 	}
 
+	// Record OHLCV candles from the kline stream, filling the gap since the last run
+	if cfg.KlineStreamEnabled {
+		klineStream := bitcoin.NewKlineStream(cfg.BinanceStreamURL, bitcoin.PairsFor(cfg.BinanceDefaultSymbols, "USDT"), cfg.KlineInterval)
+		candleStorage.Start(context.Background(), bitcoin.NewBinanceClient("", "", cfg.BinanceBaseURL, nil), klineStream, cfg.CandleBackfillWindow)
+	}
+
+	// Import account trades for cost basis and PnL
+	if cfg.BinanceAPIKey != "" && cfg.BinanceAPISecret != "" && cfg.TradeImportInterval > 0 {
+		tradeClient := bitcoin.NewBinanceClient(cfg.BinanceAPIKey, cfg.BinanceAPISecret, cfg.BinanceBaseURL, nil)
+		tradeHistory.Start(context.Background(), tradeClient, bitcoin.PairsFor(cfg.BinanceDefaultSymbols, "USDT"), cfg.TradeImportInterval)
+	}
+
+	// Keep the account balance up to date from the Binance user data stream
+	var userStream *bitcoin.UserDataStream
+	if cfg.BinanceAPIKey != "" && cfg.BinanceAPISecret != "" && cfg.UserDataStreamEnabled {
+		streamClient := bitcoin.NewBinanceClient(cfg.BinanceAPIKey, cfg.BinanceAPISecret, cfg.BinanceBaseURL, nil)
+		userStream = bitcoin.NewUserDataStream(streamClient, cfg.BinanceStreamURL, cfg.BinanceDefaultSymbols)
+		userStream.OnOrderUpdate(func(update *bitcoin.OrderUpdate) {
+			if !update.IsFill() {
+				return
+			}
+			log.Printf("💱 Order %d %s %s filled %.8f @ %.8f (%s)",
+				update.OrderID, update.Side, update.Symbol, update.LastQty, update.LastPrice, update.Status)
+			if !cfg.OrderFillNotifications {
+				return
+			}
+			go func() {
+				if err := notificationService.SendOrderFill(update.Symbol, update.Side, update.LastQty, update.LastPrice, update.Status); err != nil {
+					log.Printf("❌ Error sending order fill notification: %v", err)
+				}
+			}()
+		})
+		go userStream.Run(context.Background())
+	}
+
 	// Create alert service adapter
 	alertService := &AlertServiceAdapter{
 		AlertManager: alertManager,
@@ -98,7 +193,14 @@ func main() {
 	}
 
 	// Create API handler
-	handler := api.NewHandler(alertService, configAdapter)
+	handler := api.NewHandler(alertService, configAdapter, tradeHistory)
+	if userStream != nil {
+		handler.SetUserDataStream(userStream)
+	}
+	handler.SetOrderBooks(orderBooks)
+	handler.SetCandleStorage(candleStorage)
+	handler.SetFXRates(fxRates)
+	handler.SetIndicatorEngine(indicatorEngine)
 
 	// Create router
 	router := gin.Default()
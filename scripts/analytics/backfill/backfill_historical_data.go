@@ -7,6 +7,7 @@ import (
 	"github.com/cgallonv/btc-alerta-de-precio/config"
 	"github.com/cgallonv/btc-alerta-de-precio/internal/bitcoin"
 	"github.com/cgallonv/btc-alerta-de-precio/internal/storage"
+	"github.com/cgallonv/btc-alerta-de-precio/internal/storage/migrations"
 	"github.com/cgallonv/btc-alerta-de-precio/internal/storage/repositories"
 )
 
@@ -24,21 +25,25 @@ func main() {
 	}
 	defer db.Close()
 
+	if err := migrations.MigrateCandles(db.DB()); err != nil {
+		log.Fatalf("Failed to migrate candles: %v", err)
+	}
+
 	// Initialize repositories and services
-	tickerRepo := repositories.NewTickerRepository(db.DB())
-	tickerStorage := bitcoin.NewTickerStorage(tickerRepo)
+	candleStorage := bitcoin.NewCandleStorage(repositories.NewCandleRepository(db.DB()))
 	binanceClient := bitcoin.NewBinanceClient(
 		cfg.BinanceAPIKey,
 		cfg.BinanceAPISecret,
 		cfg.BinanceBaseURL,
-		tickerStorage,
+		nil,
 	)
 
 	// Calculate time range for past 60 days
 	endTime := time.Now()
 	startTime := endTime.Add(-60 * 24 * time.Hour)
 
-	// Fetch historical data in chunks to avoid rate limits
+	// Fetch historical data in chunks to avoid rate limits. Chunks already stored are
+	// skipped, so an interrupted backfill resumes from the last stored candle.
 	chunkDuration := 24 * time.Hour
 	currentStart := startTime
 
@@ -50,25 +55,18 @@ func main() {
 
 		log.Printf("Fetching data from %s to %s", currentStart.Format(time.RFC3339), currentEnd.Format(time.RFC3339))
 
-		// Fetch historical klines
-		tickers, err := binanceClient.GetHistoricalKlines("BTCUSDT", "1m", currentStart, currentEnd)
+		stored, err := candleStorage.Backfill(binanceClient, "BTCUSDT", "1m", currentStart, currentEnd)
 		if err != nil {
 			log.Printf("Error fetching historical data: %v", err)
 			time.Sleep(5 * time.Second) // Wait before retrying
 			continue
 		}
 
-		// Store each ticker
-		for _, ticker := range tickers {
-			if err := tickerStorage.StoreTicker24h("BTCUSDT", &ticker); err != nil {
-				log.Printf("Error storing ticker: %v", err)
-				continue
-			}
-		}
-
-		log.Printf("Successfully stored %d records", len(tickers))
+		log.Printf("Successfully stored %d candles", stored)
 		currentStart = currentEnd
-		time.Sleep(1 * time.Second) // Rate limiting
+		if stored > 0 {
+			time.Sleep(1 * time.Second) // Rate limiting
+		}
 	}
 
 	log.Println("Historical data backfill completed!")
@@ -102,4 +102,18 @@ SELECT
 FROM ticker_data;
 
 -- Exit SQLite
-.quit
\ No newline at end of file
+.quit
+
+-- Audit aggregated prices: per-source quotes behind each median price
+SELECT 
+    a.timestamp,
+    a.symbol,
+    a.last_price as median_price,
+    q.source,
+    q.last_price as quote_price,
+    q.rejected
+FROM ticker_data a
+JOIN ticker_data q ON q.aggregate_id = a.id
+WHERE a.source = 'Aggregate'
+ORDER BY a.timestamp DESC, q.source
+LIMIT 30;
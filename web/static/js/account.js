@@ -1,19 +1,33 @@
 (function() {
+    // Display currency, chosen per browser; values arrive in USD with the rate to convert them
+    const currencyStorageKey = 'displayCurrency';
+    let display = { currency: 'USD', rate: 1 };
+
     // Balance update
     async function updateBalance() {
         try {
-            const response = await apiCall('/account/balance');
+            const currency = localStorage.getItem(currencyStorageKey);
+            const query = currency ? `?currency=${encodeURIComponent(currency)}` : '';
+            const response = await apiCall('/account/balance' + query);
+            display = {
+                currency: response.data.display_currency || 'USD',
+                rate: response.data.fx_rate || 1
+            };
+            const currencySelect = document.getElementById('display-currency');
+            if (currencySelect) {
+                currencySelect.value = display.currency;
+            }
             
             // Update total balance
             const totalBalanceElement = document.querySelector('.balance-item h3.bitcoin-color');
             if (totalBalanceElement) {
-                totalBalanceElement.textContent = formatCurrency(response.data.TotalBalance);
+                totalBalanceElement.textContent = formatDisplay(response.data.TotalBalance);
             }
 
             // Update available balance
             const availableBalanceElement = document.querySelector('.balance-item h3.text-success');
             if (availableBalanceElement) {
-                availableBalanceElement.textContent = formatCurrency(response.data.AvailableBalance);
+                availableBalanceElement.textContent = formatDisplay(response.data.AvailableBalance);
             }
 
             // Update last updated timestamp
@@ -22,6 +36,10 @@
                 lastUpdatedElement.textContent = new Date(response.data.LastUpdated).toLocaleString();
             }
 
+            // Update profit and loss
+            updatePnL('[data-field="unrealized-pnl"]', response.data.unrealized_pnl);
+            updatePnL('[data-field="realized-pnl"]', response.data.realized_pnl);
+
             // Update account status
             updateAccountStatus(response.data);
 
@@ -39,6 +57,19 @@
         }
     }
 
+    function updatePnL(selector, value) {
+        const element = document.querySelector(selector);
+        if (!element) return;
+
+        element.textContent = formatDisplay(value);
+        element.classList.toggle('text-success', value >= 0);
+        element.classList.toggle('text-danger', value < 0);
+    }
+
+    function pnlClass(value) {
+        return value >= 0 ? 'text-success' : 'text-danger';
+    }
+
     function updateAccountStatus(data) {
         // Update account type
         const accountTypeElement = document.querySelector('[data-field="account-type"]');
@@ -131,27 +162,38 @@
                 <td>${asset.free}</td>
                 <td>${asset.locked}</td>
                 <td>${formatNumber(asset.total)}</td>
-                <td>${formatCurrency(asset.value_usd)}</td>
+                <td>${formatDisplay(asset.value_usd)}</td>
                 <td class="${asset.change_24h > 0 ? 'text-success' : 'text-danger'}">
                     ${formatNumber(asset.change_24h)}%
                 </td>
+                <td>${asset.avg_cost ? formatDisplay(asset.avg_cost) : '-'}</td>
+                <td class="${pnlClass(asset.unrealized_pnl)}">${formatDisplay(asset.unrealized_pnl)}</td>
+                <td class="${pnlClass(asset.realized_pnl)}">${formatDisplay(asset.realized_pnl)}</td>
             `;
             tableBody.appendChild(row);
         });
     }
 
-    function formatCurrency(value) {
+    function formatCurrency(value, currency = 'USD') {
         if (typeof value !== 'number' || isNaN(value)) {
             value = 0;
         }
         return new Intl.NumberFormat('en-US', {
             style: 'currency',
-            currency: 'USD',
+            currency: currency,
             minimumFractionDigits: 2,
             maximumFractionDigits: 2
         }).format(value);
     }
 
+    // Formats a USD value in the display currency
+    function formatDisplay(valueUSD) {
+        if (typeof valueUSD !== 'number' || isNaN(valueUSD)) {
+            valueUSD = 0;
+        }
+        return formatCurrency(valueUSD * display.rate, display.currency);
+    }
+
     function formatNumber(value) {
         if (typeof value !== 'number' || isNaN(value)) {
             value = 0;
@@ -200,11 +242,89 @@
         alert('Budget history feature coming soon');
     };
 
+    // Load orders with the selected date range
+    window.loadOrders = async function() {
+        const params = new URLSearchParams();
+        const from = document.getElementById('ordersFrom')?.value;
+        const to = document.getElementById('ordersTo')?.value;
+        if (from) params.set('from', from);
+        if (to) params.set('to', to);
+
+        try {
+            const response = await apiCall('/account/orders?' + params.toString());
+            updateOrdersTable(response.data);
+        } catch (error) {
+            console.error('Error loading orders:', error);
+        }
+    };
+
+    function updateOrdersTable(orders) {
+        const tableBody = document.querySelector('.orders-list');
+        if (!tableBody) return;
+
+        tableBody.innerHTML = '';
+
+        if (orders.length === 0) {
+            tableBody.innerHTML = `
+                <tr class="orders-empty">
+                    <td colspan="6" class="text-center text-muted py-3">
+                        <i class="fas fa-clipboard-list fa-2x mb-2"></i>
+                        <p>No orders found</p>
+                    </td>
+                </tr>
+            `;
+            return;
+        }
+
+        const badges = { completed: 'bg-success', cancelled: 'bg-danger', open: 'bg-warning' };
+        // Cancels are refused while trading is in dry-run mode
+        const dryRun = document.querySelector('.orders-history')?.dataset.tradingDryRun === 'true';
+        orders.forEach(order => {
+            const row = document.createElement('tr');
+            row.dataset.orderType = order.status;
+            row.dataset.orderId = order.id;
+            row.innerHTML = `
+                <td>${new Date(order.date).toLocaleString()}</td>
+                <td>${order.symbol} ${order.type}</td>
+                <td>${order.amount.toFixed(8)}</td>
+                <td>${formatCurrency(order.price)}</td>
+                <td><span class="badge ${badges[order.status]}">${order.status}</span></td>
+                <td>
+                    ${order.status === 'open' ? (dryRun ? `
+                        <button class="btn btn-sm btn-outline-danger" disabled title="Trading is in dry-run mode">
+                            Cancel
+                        </button>` : `
+                        <button class="btn btn-sm btn-outline-danger" onclick="cancelOrder('${order.id}', '${order.symbol}')">
+                            Cancel
+                        </button>`) : ''}
+                </td>
+            `;
+            tableBody.appendChild(row);
+        });
+
+        // Keep the selected status filter
+        document.querySelector('[data-filter].active')?.click();
+    }
+
     // Cancel order
-    window.cancelOrder = function(orderId) {
-        if (confirm('Are you sure you want to cancel this order?')) {
-            // TODO: Implement API call to cancel order
-            alert('Order cancelled successfully');
+    window.cancelOrder = async function(orderId, symbol) {
+        if (!confirm('Are you sure you want to cancel this order?')) {
+            return;
+        }
+
+        try {
+            await apiCall(`/orders/${orderId}?symbol=${encodeURIComponent(symbol)}`, { method: 'DELETE' });
+            showNotification('Order cancelled successfully', 'success');
+
+            const row = document.querySelector(`[data-order-id="${orderId}"]`);
+            if (row) {
+                row.dataset.orderType = 'cancelled';
+                row.querySelector('.badge').className = 'badge bg-danger';
+                row.querySelector('.badge').textContent = 'cancelled';
+                row.querySelector('button')?.remove();
+            }
+        } catch (error) {
+            console.error('Error cancelling order:', error);
         }
     };
 
@@ -224,6 +344,12 @@
         });
     };
 
+    // Remember the display currency in this browser and reload the balance in it
+    window.changeDisplayCurrency = function(currency) {
+        localStorage.setItem(currencyStorageKey, currency);
+        refreshAccountData();
+    };
+
     // Initialize
     document.addEventListener('DOMContentLoaded', function() {
         setupOrderFilters();
@@ -22,17 +22,34 @@ function initializeTopBar() {
         }
     };
 
-    // Manejar estado de conexión
-    const updateConnectionStatus = (isOnline) => {
+    // Manejar estado de conexión (isDegraded: servidor activo pero sin precios en vivo de Binance)
+    let wasDegraded = false;
+    const updateConnectionStatus = (isOnline, isDegraded = false) => {
         const indicator = document.getElementById('connectionIndicator');
         if (!indicator) return;
 
         // Preparar la nueva clase y contenido
-        const newClass = isOnline ? 'badge bg-success online' : 'badge bg-danger offline';
-        const newContent = isOnline ? 
+        let newClass = isOnline ? 'badge bg-success online' : 'badge bg-danger offline';
+        let newContent = isOnline ? 
             '<i class="fas fa-wifi"></i> Conectado' : 
             '<i class="fas fa-exclamation-triangle"></i> Desconectado';
 
+        if (isOnline && isDegraded) {
+            newClass = 'badge bg-warning degraded';
+            newContent = '<i class="fas fa-exclamation-circle"></i> Precio degradado';
+            indicator.title = 'Binance no responde: el precio puede no estar actualizado';
+        } else {
+            indicator.title = '';
+        }
+
+        // Avisar solo cuando cambia el estado del feed de precios
+        if (isOnline && isDegraded !== wasDegraded) {
+            wasDegraded = isDegraded;
+            showNotification(isDegraded ?
+                'Binance no responde, el precio puede estar desactualizado' :
+                'Precio en vivo restaurado', isDegraded ? 'warning' : 'success');
+        }
+
         // Aplicar cambios con animación
         indicator.style.opacity = '0';
         setTimeout(() => {
@@ -59,7 +76,7 @@ function initializeTopBar() {
         try {
             console.log('🔍 Verificando conexión con el servidor...');
             const response = await fetch('/api/v1/health', { 
-                method: 'HEAD',
+                method: 'GET',
                 cache: 'no-cache'
             });
             
@@ -67,7 +84,12 @@ function initializeTopBar() {
             lastServerCheck = Date.now();
             if (response.ok || response.status === 404) {
                 console.log('✅ Servidor respondiendo correctamente');
-                updateConnectionStatus(true);
+                const health = response.ok ? await response.json().catch(() => null) : null;
+                const isDegraded = health?.data?.price_feed === 'degraded';
+                if (isDegraded) {
+                    console.warn('⚠️ Feed de precios degradado:', health.data.binance_circuit);
+                }
+                updateConnectionStatus(true, isDegraded);
             } else {
                 console.warn('⚠️ Error en respuesta del servidor:', response.status);
                 throw new Error('Server error');
@@ -136,6 +158,34 @@ function setupEventListeners() {
         if (alertForm) {
             alertForm.addEventListener('submit', createAlert);
             
+            // Autocompletar símbolos desde la lista de Binance
+            const alertSymbol = document.getElementById('alertSymbol');
+            if (alertSymbol) {
+                let symbolTimeout;
+                alertSymbol.addEventListener('input', function() {
+                    clearTimeout(symbolTimeout);
+                    symbolTimeout = setTimeout(() => loadSymbolSuggestions(this.value), 250);
+                });
+            }
+
+            // Cambio de modo de disparo
+            const triggerMode = document.getElementById('triggerMode');
+            if (triggerMode) {
+                triggerMode.addEventListener('change', function() {
+                    document.getElementById('triggerModeGroup').style.display = this.value === 'once' ? 'none' : 'flex';
+                    document.getElementById('maxTriggersGroup').style.display = this.value === 'max_count' ? 'block' : 'none';
+                });
+            }
+
+            // Horario de la alerta, en la zona horaria del navegador por defecto
+            const useSchedule = document.getElementById('useSchedule');
+            if (useSchedule) {
+                document.getElementById('scheduleTimeZone').value = Intl.DateTimeFormat().resolvedOptions().timeZone || '';
+                useSchedule.addEventListener('change', function() {
+                    document.getElementById('scheduleGroup').style.display = this.checked ? 'block' : 'none';
+                });
+            }
+
             // Cambio de tipo de alerta
             const alertType = document.getElementById('alertType');
             if (alertType) {
@@ -144,6 +194,17 @@ function setupEventListeners() {
                 });
             }
 
+            // Cambio de indicador: nivel, periodo lento y periodos por defecto
+            const indicator = document.getElementById('indicator');
+            if (indicator) {
+                indicator.addEventListener('change', function() {
+                    const periods = INDICATOR_PERIODS[this.value];
+                    document.getElementById('indicatorPeriod').placeholder = periods[0];
+                    document.getElementById('indicatorSlowPeriod').placeholder = periods[1] || '';
+                    toggleAlertFields(document.getElementById('alertType').value);
+                });
+            }
+
             // Toggle WhatsApp number field
             const enableWhatsApp = document.getElementById('enableWhatsApp');
             if (enableWhatsApp) {
@@ -164,20 +225,73 @@ function setupEventListeners() {
     }
 }
 
+// Periodos por defecto de cada indicador (rápido y lento), como en el servidor
+const INDICATOR_PERIODS = {
+    rsi: [14], atr: [14], sma: [20], ema: [20], bollinger: [20],
+    sma_cross: [9, 21], ema_cross: [9, 21], macd: [12, 26]
+};
+
 function toggleAlertFields(alertType) {
     const priceGroup = document.getElementById('priceGroup');
     const percentageGroup = document.getElementById('percentageGroup');
-    
-    if (alertType === 'change') {
-        priceGroup.style.display = 'none';
-        percentageGroup.style.display = 'block';
-        document.getElementById('targetPrice').required = false;
-        document.getElementById('percentage').required = true;
-    } else {
-        priceGroup.style.display = 'block';
-        percentageGroup.style.display = 'none';
-        document.getElementById('targetPrice').required = true;
-        document.getElementById('percentage').required = false;
+    const thresholdGroup = document.getElementById('thresholdGroup');
+    const depthPercentGroup = document.getElementById('depthPercentGroup');
+    const usesPercentage = ['change', 'change_window', 'imbalance'].includes(alertType);
+    const indicator = document.getElementById('indicator').value;
+    const usesIndicator = alertType === 'indicator';
+    // RSI and ATR compare against a level, Bollinger uses it as the band width
+    const usesLevel = usesIndicator && ['rsi', 'atr', 'bollinger'].includes(indicator);
+    const usesThreshold = alertType === 'spread' || alertType === 'liquidity' || usesLevel;
+    const usesConditions = alertType === 'composite';
+    const usesExpression = alertType === 'expression';
+    const usesPrice = !usesPercentage && !usesThreshold && !usesIndicator && !usesConditions && !usesExpression;
+
+    priceGroup.style.display = usesPrice ? 'block' : 'none';
+    // Crossing alerts only fire on a new crossing, so they don't need hysteresis
+    document.getElementById('hysteresisGroup').style.display =
+        alertType === 'cross_up' || alertType === 'cross_down' ? 'none' : 'block';
+    percentageGroup.style.display = usesPercentage ? 'block' : 'none';
+    thresholdGroup.style.display = usesThreshold ? 'block' : 'none';
+    document.getElementById('windowGroup').style.display = alertType === 'change_window' ? 'block' : 'none';
+    document.getElementById('indicatorGroup').style.display = usesIndicator ? 'block' : 'none';
+    document.getElementById('conditionsGroup').style.display = usesConditions ? 'block' : 'none';
+    document.getElementById('expressionGroup').style.display = usesExpression ? 'block' : 'none';
+    document.getElementById('expression').required = usesExpression;
+    document.getElementById('slowPeriodGroup').style.display =
+        ['sma_cross', 'ema_cross', 'macd'].includes(indicator) ? 'block' : 'none';
+    depthPercentGroup.style.display = alertType === 'imbalance' || alertType === 'liquidity' ? 'block' : 'none';
+    document.getElementById('targetPrice').required = usesPrice;
+    document.getElementById('percentage').required = usesPercentage;
+    // Bollinger alerts default to 2 standard deviations
+    document.getElementById('threshold').required = usesThreshold && !(usesIndicator && indicator === 'bollinger');
+
+    // Imbalance and windowed change thresholds can be negative (ask-heavy books, drops)
+    document.getElementById('percentage').min = alertType === 'imbalance' || alertType === 'change_window' ? '-100' : '0.1';
+    document.getElementById('thresholdLabel').textContent = usesLevel ?
+        { rsi: 'Nivel de RSI (0-100)', atr: 'Nivel de ATR', bollinger: 'Desviaciones estándar (2 por defecto)' }[indicator] :
+        alertType === 'spread' ? 'Spread (bps)' : 'Cantidad mínima (moneda base)';
+}
+
+// Sugerencias de símbolos para el campo de la alerta (sin tocar el indicador de conexión)
+async function loadSymbolSuggestions(query) {
+    const list = document.getElementById('symbolSuggestions');
+    query = query.trim().toUpperCase();
+    if (!list || query.length < 2) return;
+
+    try {
+        const response = await fetch(`/api/v1/symbols?q=${encodeURIComponent(query)}&limit=20`);
+        const data = await response.json();
+        if (!data.success) return;
+
+        list.innerHTML = '';
+        (data.data || []).forEach(symbol => {
+            const option = document.createElement('option');
+            option.value = symbol.symbol;
+            option.label = `${symbol.base_asset}/${symbol.quote_asset}`;
+            list.appendChild(option);
+        });
+    } catch (error) {
+        console.error('Error loading symbols:', error);
     }
 }
 
@@ -552,15 +666,23 @@ function displayAlerts(alerts) {
                     <div>
                         <h6 class="card-title">
                             <i class="fas fa-bell"></i> ${alert.name}
+                            <span class="badge bg-dark ms-2">${alert.symbol || 'BTCUSDT'}</span>
                             <span class="badge ${
-                                alert.last_triggered ? 'bg-warning' : 
-                                alert.is_active ? 'bg-success' : 'bg-secondary'
+                                !alert.is_active ? 'bg-secondary' :
+                                alert.exhausted ? 'bg-warning' :
+                                alert.disarmed ? 'bg-warning' :
+                                alert.next_eligible_at ? 'bg-info' : 'bg-success'
                             } ms-2">
                                 ${
-                                    alert.last_triggered ? 'Disparada' : 
-                                    alert.is_active ? 'Activa' : 'Inactiva'
+                                    !alert.is_active ? 'Inactiva' :
+                                    alert.exhausted ? 'Disparada' :
+                                    alert.disarmed ? 'Desarmada' :
+                                    alert.next_eligible_at ? 'En espera' : 'Activa'
                                 }
                             </span>
+                            ${alert.schedule ?
+                                `<span class="badge ${alert.in_schedule ? 'bg-success' : 'bg-secondary'} ms-1"><i class="fas fa-clock"></i> ${alert.in_schedule ? 'En horario' : 'Fuera de horario'}</span>` : ''
+                            }
                         </h6>
                         <p class="card-text text-muted mb-1">
                             ${getAlertDescription(alert)}
@@ -592,6 +714,8 @@ function displayAlerts(alerts) {
                             `<br><small class="text-info">Activada ${alert.trigger_count} vez${alert.trigger_count > 1 ? 'es' : ''}</small>` : 
                             ''
                         }
+                        <br><small class="text-muted">${getTriggerModeDescription(alert)}</small>
+                        ${alert.schedule ? `<br><small class="text-muted">${getScheduleDescription(alert)}</small>` : ''}
                     </div>
                     <div class="btn-group-vertical btn-group-sm">
                         <button class="btn btn-outline-primary" onclick="testAlert(${alert.id})" title="Probar">
@@ -623,9 +747,13 @@ function displayAlerts(alerts) {
 function getAlertDescription(alert) {
     switch (alert.type) {
         case 'above':
-            return `Precio por encima de $${alert.target_price.toLocaleString()}`;
+            return `Precio por encima de ${formatAlertPrice(alert)}`;
         case 'below':
-            return `Precio por debajo de $${alert.target_price.toLocaleString()}`;
+            return `Precio por debajo de ${formatAlertPrice(alert)}`;
+        case 'cross_up':
+            return `Precio cruza hacia arriba ${formatAlertPrice(alert)}`;
+        case 'cross_down':
+            return `Precio cruza hacia abajo ${formatAlertPrice(alert)}`;
         case 'change':
             if (alert.percentage > 0) {
                 return `Subida de ${alert.percentage}% o más`;
@@ -634,11 +762,133 @@ function getAlertDescription(alert) {
             } else {
                 return `Cambio de ${alert.percentage}% en el precio`;
             }
+        case 'change_window':
+            return alert.percentage > 0 ?
+                `Subida de ${alert.percentage}% o más en ${alert.window_minutes} min` :
+                `Bajada de ${Math.abs(alert.percentage)}% o más en ${alert.window_minutes} min`;
+        case 'imbalance':
+            return alert.percentage > 0 ?
+                `Libro con ${alert.percentage}% más compras (±${alert.depth_percent || 1}%)` :
+                `Libro con ${Math.abs(alert.percentage)}% más ventas (±${alert.depth_percent || 1}%)`;
+        case 'spread':
+            return `Spread por encima de ${alert.threshold} bps`;
+        case 'liquidity':
+            return `Liquidez de compra en ${alert.depth_percent || 1}% por debajo de ${alert.threshold}`;
+        case 'indicator':
+            return getIndicatorDescription(alert);
+        case 'composite':
+            return alert.conditions ? getConditionDescription(alert, alert.conditions, true) : 'Sin condiciones';
+        case 'expression':
+            return `Regla: ${alert.expression}`;
         default:
             return 'Tipo de alerta desconocido';
     }
 }
 
+// Descripción de una alerta de indicador ("RSI(14) en 1h por debajo de 30")
+function getIndicatorDescription(alert) {
+    const periods = INDICATOR_PERIODS[alert.indicator] || [];
+    const period = alert.period || periods[0];
+    const slow = alert.slow_period || periods[1];
+    const interval = alert.interval || '1h';
+    const condition = {
+        above: 'por encima de', below: 'por debajo de',
+        cross_above: 'cruza hacia arriba', cross_below: 'cruza hacia abajo'
+    }[alert.indicator_condition];
+
+    switch (alert.indicator) {
+        case 'rsi':
+        case 'atr':
+            return `${alert.indicator.toUpperCase()}(${period}) en ${interval} ${condition} ${alert.threshold}`;
+        case 'sma':
+        case 'ema':
+            return `Precio ${condition} ${alert.indicator.toUpperCase()}(${period}) en ${interval}`;
+        case 'sma_cross':
+        case 'ema_cross': {
+            const average = alert.indicator.replace('_cross', '').toUpperCase();
+            return `${average}(${period}) ${condition} ${average}(${slow}) en ${interval}`;
+        }
+        case 'macd':
+            return `MACD(${period},${slow},9) ${condition} su señal en ${interval}`;
+        case 'bollinger': {
+            const band = alert.indicator_condition.endsWith('below') ? 'inferior' : 'superior';
+            return `Cierre ${condition} la banda ${band} de Bollinger (${period}, ${alert.threshold || 2}) en ${interval}`;
+        }
+        default:
+            return 'Indicador desconocido';
+    }
+}
+
+// Descripción del árbol de condiciones de una alerta compuesta, con los grupos anidados entre paréntesis
+function getConditionDescription(alert, condition, root) {
+    if (!condition.op) {
+        return getAlertDescription({ ...condition, symbol: alert.symbol, currency: alert.currency });
+    }
+    const parts = (condition.conditions || []).map(child => getConditionDescription(alert, child, false));
+    const description = parts.join(condition.op === 'or' ? ' O ' : ' Y ');
+    return root || parts.length === 1 ? description : `(${description})`;
+}
+
+// Horario de la alerta ("Lun, Mar 09:30-16:00 (America/New_York)"), cuándo abre y si
+// tiene una notificación pendiente de fuera de horario
+const SCHEDULE_DAYS = { mon: 'Lun', tue: 'Mar', wed: 'Mié', thu: 'Jue', fri: 'Vie', sat: 'Sáb', sun: 'Dom' };
+
+function getScheduleDescription(alert) {
+    const schedule = alert.schedule;
+    const days = (schedule.days || []).length ? schedule.days.map(day => SCHEDULE_DAYS[day] || day).join(', ') : 'Todos los días';
+    const windows = (schedule.windows || []).map(window => `${window.start}-${window.end}`).join(', ') || 'todo el día';
+
+    let description = `<i class="fas fa-clock"></i> ${days} ${windows} (${schedule.time_zone || 'UTC'})`;
+    description += schedule.outside === 'defer' ? ' • Fuera de horario se notifica al abrir' : ' • Fuera de horario se ignora';
+    if (!alert.in_schedule && alert.schedule_opens_at) {
+        description += ` • Abre: ${new Date(alert.schedule_opens_at).toLocaleString('es-ES')}`;
+    }
+    if (alert.deferred_at) {
+        description += ` • Notificación pendiente desde ${new Date(alert.deferred_at).toLocaleString('es-ES')}`;
+    }
+    return description;
+}
+
+// Modo de disparo y próximo momento en que la alerta puede dispararse
+function getTriggerModeDescription(alert) {
+    const cooldown = alert.cooldown_seconds >= 3600 ?
+        `${+(alert.cooldown_seconds / 3600).toFixed(1)} h` :
+        `${Math.round((alert.cooldown_seconds || 0) / 60)} min`;
+
+    let description;
+    switch (alert.trigger_mode) {
+        case 'recurring':
+            description = `<i class="fas fa-sync"></i> Recurrente, cada ${cooldown} como mínimo`;
+            break;
+        case 'max_count':
+            description = `<i class="fas fa-list-ol"></i> Hasta ${alert.max_triggers} veces` +
+                (alert.cooldown_seconds > 0 ? `, cada ${cooldown} como mínimo` : '');
+            break;
+        default:
+            description = '<i class="fas fa-dot-circle"></i> Una vez';
+    }
+
+    if (alert.hysteresis_percent > 0 && (alert.type === 'above' || alert.type === 'below')) {
+        description += ` • Histéresis ${alert.hysteresis_percent}%`;
+        if (alert.disarmed) {
+            const factor = alert.type === 'above' ? 1 - alert.hysteresis_percent / 100 : 1 + alert.hysteresis_percent / 100;
+            const rearmPrice = formatAlertPrice({ ...alert, target_price: +(alert.target_price * factor).toFixed(2) });
+            description += `, se rearma cuando el precio ${alert.type === 'above' ? 'baje de' : 'suba de'} ${rearmPrice}`;
+        }
+    }
+    if (alert.next_eligible_at) {
+        description += ` • Próximo disparo posible: ${new Date(alert.next_eligible_at).toLocaleString('es-ES')}`;
+    }
+    return description;
+}
+
+// Precio objetivo en la moneda de la alerta ("$50,000" o "250,000,000 COP")
+function formatAlertPrice(alert) {
+    const currency = alert.currency || 'USD';
+    const amount = alert.target_price.toLocaleString();
+    return currency === 'USD' ? `$${amount}` : `${amount} ${currency}`;
+}
+
 // Crear nueva alerta
 async function createAlert(event) {
     event.preventDefault();
@@ -646,6 +896,7 @@ async function createAlert(event) {
     const formData = new FormData(event.target);
     const alertData = {
         name: document.getElementById('alertName').value,
+        symbol: document.getElementById('alertSymbol').value.trim().toUpperCase(),
         type: document.getElementById('alertType').value,
         email: document.getElementById('alertEmail').value,
         enable_email: document.getElementById('enableEmail').checked,
@@ -656,10 +907,55 @@ async function createAlert(event) {
         is_active: true
     };
     
-    if (alertData.type === 'change') {
+    if (['change', 'change_window', 'imbalance'].includes(alertData.type)) {
         alertData.percentage = parseFloat(document.getElementById('percentage').value);
+    } else if (alertData.type === 'spread' || alertData.type === 'liquidity') {
+        alertData.threshold = parseFloat(document.getElementById('threshold').value);
+    } else if (alertData.type === 'composite') {
+        try {
+            alertData.conditions = JSON.parse(document.getElementById('conditions').value);
+        } catch (error) {
+            showNotification('Las condiciones no son un JSON válido: ' + error.message, 'warning');
+            return;
+        }
+    } else if (alertData.type === 'expression') {
+        alertData.expression = document.getElementById('expression').value.trim();
+    } else if (alertData.type === 'indicator') {
+        alertData.indicator = document.getElementById('indicator').value;
+        alertData.interval = document.getElementById('indicatorInterval').value;
+        alertData.period = parseInt(document.getElementById('indicatorPeriod').value) || 0;
+        alertData.slow_period = parseInt(document.getElementById('indicatorSlowPeriod').value) || 0;
+        alertData.indicator_condition = document.getElementById('indicatorCondition').value;
+        alertData.threshold = parseFloat(document.getElementById('threshold').value) || 0;
     } else {
         alertData.target_price = parseFloat(document.getElementById('targetPrice').value);
+        alertData.currency = document.getElementById('alertCurrency').value;
+        if (alertData.type === 'above' || alertData.type === 'below') {
+            alertData.hysteresis_percent = parseFloat(document.getElementById('hysteresisPercent').value) || 0;
+        }
+    }
+    if (alertData.type === 'change_window') {
+        alertData.window_minutes = parseInt(document.getElementById('windowMinutes').value) || 15;
+    }
+    if (alertData.type === 'imbalance' || alertData.type === 'liquidity') {
+        alertData.depth_percent = parseFloat(document.getElementById('depthPercent').value) || 1;
+    }
+
+    if (document.getElementById('useSchedule').checked) {
+        alertData.schedule = {
+            days: [...document.querySelectorAll('#scheduleDays input:checked')].map(input => input.value),
+            windows: [{ start: document.getElementById('scheduleStart').value, end: document.getElementById('scheduleEnd').value }],
+            time_zone: document.getElementById('scheduleTimeZone').value.trim(),
+            outside: document.getElementById('scheduleOutside').value
+        };
+    }
+
+    alertData.trigger_mode = document.getElementById('triggerMode').value;
+    if (alertData.trigger_mode !== 'once') {
+        alertData.cooldown_seconds = (parseInt(document.getElementById('cooldownMinutes').value) || 0) * 60;
+    }
+    if (alertData.trigger_mode === 'max_count') {
+        alertData.max_triggers = parseInt(document.getElementById('maxTriggers').value) || 1;
     }
 
     // Validar número de WhatsApp si está habilitado
@@ -682,6 +978,35 @@ async function createAlert(event) {
     }
 }
 
+// Validar la regla de una alerta "expression" y señalar la columna del error
+async function validateExpression() {
+    const source = document.getElementById('expression').value;
+    const result = document.getElementById('expressionResult');
+
+    try {
+        const response = await apiCall('/alerts/validate-expression', {
+            method: 'POST',
+            body: JSON.stringify({ expression: source })
+        });
+        const validation = response.data;
+        if (validation.valid) {
+            const indicators = (validation.indicators || []).map(ref => ref.name);
+            result.className = 'form-text text-success';
+            result.style.whiteSpace = '';
+            result.textContent = '✅ Regla válida' + (indicators.length ? ` (indicadores: ${indicators.join(', ')})` : '');
+            return;
+        }
+
+        const error = validation.errors[0];
+        const line = source.replace(/\s/g, ' ');
+        result.className = 'form-text text-danger font-monospace';
+        result.textContent = `❌ Columna ${error.column}: ${error.message}\n${line}\n${' '.repeat(error.column - 1)}^`;
+        result.style.whiteSpace = 'pre';
+    } catch (error) {
+        console.error('Error validating expression:', error);
+    }
+}
+
 // Probar alerta
 async function testAlert(alertId) {
     try {
@@ -739,7 +1064,7 @@ async function editAlert(alertId) {
         
         if (!editValueLabel || !editValueHelp || !editValueInput) return;
         
-        if (alert.type === 'above' || alert.type === 'below') {
+        if (['above', 'below', 'cross_up', 'cross_down'].includes(alert.type)) {
             editValueLabel.textContent = 'Precio Objetivo ($)';
             editValueHelp.textContent = 'Ingresa el nuevo precio objetivo en dólares';
             editValueInput.value = alert.target_price;
@@ -752,6 +1077,40 @@ async function editAlert(alertId) {
             editValueInput.step = '0.1';
             editValueInput.min = '0.1';
             editValueInput.max = '100';
+        } else if (alert.type === 'change_window') {
+            editValueLabel.textContent = `Cambio en ${alert.window_minutes} min (%)`;
+            editValueHelp.textContent = 'Positivo para subidas, negativo para bajadas';
+            editValueInput.value = alert.percentage;
+            editValueInput.step = '0.1';
+            editValueInput.min = '-100';
+            editValueInput.max = '100';
+        } else if (alert.type === 'imbalance') {
+            editValueLabel.textContent = 'Desbalance (%)';
+            editValueHelp.textContent = 'Positivo para más compras, negativo para más ventas';
+            editValueInput.value = alert.percentage;
+            editValueInput.step = '0.1';
+            editValueInput.min = '-100';
+            editValueInput.max = '100';
+        } else if (alert.type === 'composite') {
+            editValueLabel.textContent = 'Condiciones';
+            editValueHelp.textContent = getAlertDescription(alert) + ' (se editan con PUT /api/v1/alerts/{id} y "conditions")';
+            editValueInput.value = '';
+        } else if (alert.type === 'expression') {
+            editValueLabel.textContent = 'Regla';
+            editValueHelp.textContent = getAlertDescription(alert) + ' (se edita con PUT /api/v1/alerts/{id} y "expression")';
+            editValueInput.value = '';
+        } else if (alert.type === 'indicator') {
+            editValueLabel.textContent = 'Nivel o desviaciones';
+            editValueHelp.textContent = getIndicatorDescription(alert);
+            editValueInput.value = alert.threshold;
+            editValueInput.step = 'any';
+            editValueInput.min = '0';
+        } else if (alert.type === 'spread' || alert.type === 'liquidity') {
+            editValueLabel.textContent = alert.type === 'spread' ? 'Spread (bps)' : 'Cantidad mínima (moneda base)';
+            editValueHelp.textContent = 'Ingresa el nuevo umbral';
+            editValueInput.value = alert.threshold;
+            editValueInput.step = 'any';
+            editValueInput.min = '0';
         }
 
         // Configurar opciones de notificación
@@ -800,7 +1159,7 @@ async function saveEditAlert() {
     const alertType = document.getElementById('editAlertType').value;
     const newValue = parseFloat(document.getElementById('editValue').value);
     
-    if (!newValue || newValue <= 0) {
+    if (alertType !== 'composite' && alertType !== 'expression' && (!newValue || (newValue <= 0 && alertType !== 'imbalance'))) {
         showNotification('Por favor ingresa un valor válido', 'error');
         return;
     }
@@ -822,10 +1181,12 @@ async function saveEditAlert() {
             language: document.getElementById('editLanguage').value
         };
         
-        if (alertType === 'above' || alertType === 'below') {
+        if (['above', 'below', 'cross_up', 'cross_down'].includes(alertType)) {
             updateData.target_price = newValue;
-        } else if (alertType === 'change') {
+        } else if (['change', 'change_window', 'imbalance'].includes(alertType)) {
             updateData.percentage = newValue;
+        } else if (['spread', 'liquidity', 'indicator'].includes(alertType)) {
+            updateData.threshold = newValue;
         }
         
         await apiCall(`/alerts/${alertId}`, {
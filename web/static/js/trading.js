@@ -71,6 +71,9 @@ document.addEventListener('DOMContentLoaded', function() {
         });
     });
 
+    // Show the values of the indicators enabled by default
+    refreshIndicators();
+
     // Initialize position size calculator
     initializePositionCalculator();
 });
@@ -87,8 +90,55 @@ function updateStrategySettings(type) {
 }
 
 function toggleIndicator(indicatorId, enabled) {
-    // TODO: Toggle indicator on chart
     console.log('Indicator', indicatorId, enabled ? 'enabled' : 'disabled');
+    refreshIndicators();
+}
+
+// Valores de los indicadores marcados, en velas de 1h
+async function refreshIndicators() {
+    const container = document.getElementById('indicatorValues');
+    if (!container) return;
+
+    const enabled = Array.from(document.querySelectorAll('.indicator-toggle:checked')).map(checkbox => checkbox.id);
+    if (enabled.length === 0) {
+        container.innerHTML = '';
+        return;
+    }
+
+    let snapshot;
+    try {
+        const response = await fetch(`/api/v1/indicators?symbol=${TRADING_SYMBOL}&interval=1h`);
+        const result = await response.json();
+        if (result.error === 'loading') {
+            // Las velas se cargan en segundo plano, reintentar en unos segundos
+            container.textContent = 'Cargando indicadores...';
+            setTimeout(refreshIndicators, 2000);
+            return;
+        }
+        if (!result.success) {
+            container.textContent = result.error;
+            return;
+        }
+        snapshot = result.data;
+    } catch (error) {
+        console.error('Error loading indicators:', error);
+        return;
+    }
+
+    const format = value => value === undefined ? 'n/a' : value.toLocaleString(undefined, { maximumFractionDigits: 2 });
+    const lines = {
+        maIndicator: `SMA(20): ${format(snapshot.sma_20)}`,
+        emaIndicator: `EMA(21): ${format(snapshot.ema_21)}`,
+        rsiIndicator: `RSI(14): ${format(snapshot.rsi_14)}`,
+        macdIndicator: snapshot.macd ?
+            `MACD(12,26,9): ${format(snapshot.macd.macd)} / ${format(snapshot.macd.signal)} (${format(snapshot.macd.histogram)})` :
+            'MACD(12,26,9): n/a',
+        bollingerIndicator: snapshot.bollinger_20 ?
+            `Bollinger(20, 2): ${format(snapshot.bollinger_20.lower)} - ${format(snapshot.bollinger_20.upper)}` :
+            'Bollinger(20, 2): n/a'
+    };
+    container.innerHTML = enabled.map(id => `<div>${lines[id]}</div>`).join('') +
+        `<div>${snapshot.symbol} ${snapshot.interval}, ${format(snapshot.price)}</div>`;
 }
 
 function initializePositionCalculator() {
@@ -115,15 +165,92 @@ document.getElementById('sellBtn')?.addEventListener('click', () => {
     openPosition('short');
 });
 
-function openPosition(type) {
-    const size = document.getElementById('positionSize').value;
-    const stopLoss = document.getElementById('stopLoss').value;
-    const takeProfit = document.getElementById('takeProfit').value;
+// Símbolo operado desde la página de trading
+const TRADING_SYMBOL = 'BTCUSDT';
 
-    // TODO: Implement position opening logic
-    console.log('Opening', type, 'position:', {
-        size,
-        stopLoss,
-        takeProfit
-    });
-}
\ No newline at end of file
+async function openPosition(type) {
+    const size = parseFloat(document.getElementById('positionSize').value);
+    const stopLoss = parseFloat(document.getElementById('stopLoss').value);
+    const takeProfit = parseFloat(document.getElementById('takeProfit').value);
+
+    if (!(size > 0)) {
+        showNotification('Ingresa un tamaño de posición válido', 'warning');
+        return;
+    }
+
+    const side = type === 'long' ? 'BUY' : 'SELL';
+    const exitSide = type === 'long' ? 'SELL' : 'BUY';
+
+    let entry;
+    try {
+        const config = await apiCall('/config');
+        const action = side === 'BUY' ? 'Comprar' : 'Vender';
+        const mode = config.data.trading_dry_run ? ' (modo simulación)' : '';
+        if (!confirm(`${action} ${size} BTC a mercado${mode}?`)) {
+            return;
+        }
+
+        // Orden de entrada a mercado
+        entry = await apiCall('/orders', {
+            method: 'POST',
+            body: JSON.stringify({ symbol: TRADING_SYMBOL, side: side, type: 'MARKET', quantity: size })
+        });
+        showNotification(entry.message, entry.data.dry_run ? 'info' : 'success');
+        updatePositionBadge(type, entry.data);
+    } catch (error) {
+        console.error('Error opening position:', error); // apiCall ya mostró la notificación
+        return;
+    }
+
+    if (!(stopLoss > 0) && !(takeProfit > 0)) {
+        return;
+    }
+
+    try {
+        // Precio de entrada: promedio de ejecución, o el precio actual en modo simulación
+        let entryPrice = entry.data.avg_price;
+        if (!entryPrice) {
+            const price = await apiCall(`/price?symbol=${TRADING_SYMBOL}`);
+            entryPrice = price.data.price;
+        }
+
+        // Los porcentajes se aplican en contra (stop) o a favor (take profit) de la posición
+        const direction = type === 'long' ? 1 : -1;
+        const stopPrice = stopLoss > 0 ? roundPrice(entryPrice * (1 - direction * stopLoss / 100)) : 0;
+        const stopLimitPrice = roundPrice(stopPrice * (1 - direction * 0.001)); // Margen para que se ejecute
+        const takeProfitPrice = takeProfit > 0 ? roundPrice(entryPrice * (1 + direction * takeProfit / 100)) : 0;
+
+        let endpoint = '/orders';
+        let order;
+        if (stopPrice && takeProfitPrice) {
+            endpoint = '/orders/oco';
+            order = { symbol: TRADING_SYMBOL, side: exitSide, quantity: size,
+                price: takeProfitPrice, stop_price: stopPrice, stop_limit_price: stopLimitPrice };
+        } else if (stopPrice) {
+            order = { symbol: TRADING_SYMBOL, side: exitSide, type: 'STOP_LOSS_LIMIT', quantity: size,
+                price: stopLimitPrice, stop_price: stopPrice };
+        } else {
+            order = { symbol: TRADING_SYMBOL, side: exitSide, type: 'LIMIT', quantity: size,
+                price: takeProfitPrice };
+        }
+
+        const exit = await apiCall(endpoint, { method: 'POST', body: JSON.stringify(order) });
+        showNotification(exit.message, exit.data.dry_run ? 'info' : 'success');
+    } catch (error) {
+        showNotification('Posición abierta, pero falló la orden de salida: ' + error.message, 'danger');
+    }
+}
+
+// Redondear al tick de precio de BTCUSDT (0.01)
+function roundPrice(price) {
+    return Math.round(price * 100) / 100;
+}
+
+function updatePositionBadge(type, order) {
+    const badge = document.getElementById('currentPosition');
+    if (!badge) return;
+
+    badge.className = 'position-badge ' + type;
+    badge.textContent = (type === 'long' ? 'Long' : 'Short') + ' ' + order.orig_qty + ' BTC' +
+        (order.dry_run ? ' (simulación)' : '');
+}